@@ -8,6 +8,7 @@ import (
 	"github.com/splitio/split-synchronizer/v5/splitio"
 	"github.com/splitio/split-synchronizer/v5/splitio/common"
 	cconf "github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
 	"github.com/splitio/split-synchronizer/v5/splitio/log"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/conf"
@@ -61,18 +62,32 @@ func main() {
 		os.Exit(exitCodeConfigError)
 	}
 
-	logger := log.BuildFromConfig(&cfg.Logging, "Split-Proxy", &cfg.Integrations.Slack)
-	err = proxy.Start(logger, cfg)
+	logger := log.BuildFromConfig(&cfg.Logging, "Split-Proxy")
+	var notif *notifier.Notifier
+	if !cfg.Offline.Enabled { // no outbound connections allowed in offline mode
+		notif = notifier.BuildFromConfig(&cfg.Integrations, "Split Proxy", log.Component(logger, log.ComponentNotifier))
+	}
+	if notif != nil {
+		log.AttachHook(logger, notif)
+	}
+
+	err = proxy.Start(logger, cfg, notif)
 
 	if err == nil {
 		return
 	}
 
+	exitCode := common.ExitUndefined
 	var initError *common.InitializationError
 	if errors.As(err, &initError) {
 		logger.Error("Failed to initialize the split sync: ", initError)
-		os.Exit(initError.ExitCode())
+		exitCode = initError.ExitCode()
+	} else {
+		logger.Error("Split sync stopped unexpectedly: ", err)
 	}
 
-	os.Exit(common.ExitUndefined)
+	if notif != nil {
+		notif.Stop() // flush the notifications for the messages above before exiting
+	}
+	os.Exit(exitCode)
 }
@@ -1,16 +1,23 @@
 package admin
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 
+	"github.com/splitio/go-split-commons/v4/synchronizer"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/admin/auth"
 	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
 	"github.com/splitio/split-synchronizer/v5/splitio/admin/controllers"
 	"github.com/splitio/split-synchronizer/v5/splitio/common"
 	cstorage "github.com/splitio/split-synchronizer/v5/splitio/common/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +26,7 @@ import (
 const baseAdminPath = "/admin"
 const baseInfoPath = "/info"
 const baseShutdownPath = "/shutdown"
+const maxAuditEntries = 500
 
 // Options encapsulates dependencies & config options for the Admin server
 type Options struct {
@@ -28,6 +36,13 @@ type Options struct {
 	Proxy             bool
 	Username          string
 	Password          string
+	Tokens            []string
+	TokensFile        string
+	AuditLogFile      string
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	SecureHC          bool
 	Logger            logging.LoggerInterface
 	Storages          adminCommon.Storages
 	ImpressionsEvCalc evcalc.Monitor
@@ -35,20 +50,53 @@ type Options struct {
 	Runtime           common.Runtime
 	HcAppMonitor      application.MonitorIterface
 	HcServicesMonitor services.MonitorIterface
+	Probes            *probes.Evaluator
 	Snapshotter       cstorage.Snapshotter
+	Synchronizer      synchronizer.Synchronizer
+	DataWiper         cstorage.DataWiper
+	ImpressionsTask   adminCommon.EvictionTask
+	EventsTask        adminCommon.EvictionTask
+	Sampler           adminCommon.SamplingMonitor
+	LogLevels         adminCommon.LogLevels
+	ChangeHistory     adminCommon.ChangeHistory
+	ClientAPIKeys     adminCommon.ClientAPIKeys
+	HTTPCache         adminCommon.HTTPCache
+	Fleet             adminCommon.FleetInventory
 	FullConfig        interface{}
 }
 
 // NewServer instantiates a new admin server
 func NewServer(options *Options) (*http.Server, error) {
+	authenticator, err := auth.NewAuthenticator(options.Username, options.Password, options.Tokens, options.TokensFile, options.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up admin authentication: %w", err)
+	}
+
+	audit, err := auth.NewAuditLog(options.AuditLogFile, maxAuditEntries, options.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if options.Runtime != nil {
+		options.Runtime.RegisterShutdownHook(func() {
+			if err := audit.Close(); err != nil {
+				options.Logger.Error("error closing admin audit log: ", err)
+			}
+		})
+	}
+
+	tlsConfig, err := buildTLSConfig(options.TLSCertFile, options.TLSKeyFile, options.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up admin tls: %w", err)
+	}
+
+	authMiddleware := auth.Middleware(authenticator, audit)
 	router := gin.New()
-	admin := router.Group(baseAdminPath)
-	info := router.Group(baseInfoPath)
-	shutdown := router.Group(baseShutdownPath)
-	if options.Username != "" && options.Password != "" {
-		admin = router.Group(baseAdminPath, gin.BasicAuth(gin.Accounts{options.Username: options.Password}))
-		info = router.Group(baseInfoPath, gin.BasicAuth(gin.Accounts{options.Username: options.Password}))
-		shutdown = router.Group(baseShutdownPath, gin.BasicAuth(gin.Accounts{options.Username: options.Password}))
+	admin := router.Group(baseAdminPath, authMiddleware)
+	info := router.Group(baseInfoPath, authMiddleware)
+	shutdown := router.Group(baseShutdownPath, authMiddleware)
+	var health gin.IRouter = router
+	if options.SecureHC {
+		health = router.Group("/", authMiddleware)
 	}
 
 	dashboardController, err := controllers.NewDashboardController(
@@ -60,6 +108,11 @@ func NewServer(options *Options) (*http.Server, error) {
 		options.EventsEvCalc,
 		options.Runtime,
 		options.HcAppMonitor,
+		options.Sampler,
+		options.ChangeHistory != nil,
+		options.ClientAPIKeys,
+		options.HTTPCache,
+		options.Fleet != nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error instantiating dashboard controller: %w", err)
@@ -73,13 +126,14 @@ func NewServer(options *Options) (*http.Server, error) {
 		options.Logger,
 		options.HcAppMonitor,
 		options.HcServicesMonitor,
+		options.Probes,
 	)
-	healthcheckController.Register(router)
+	healthcheckController.Register(health)
 
 	infoController := controllers.NewInfoController(options.Proxy, options.Runtime, options.FullConfig)
 	infoController.Register(info)
 
-	observabilityController, err := controllers.NewObservabilityController(options.Proxy, options.Logger, options.Storages)
+	observabilityController, err := controllers.NewObservabilityController(options.Proxy, options.Logger, options.Storages, options.HTTPCache)
 	if err != nil {
 		return nil, fmt.Errorf("error instantiating observability controller: %w", err)
 	}
@@ -90,8 +144,96 @@ func NewServer(options *Options) (*http.Server, error) {
 		snapshotController.Register(admin)
 	}
 
+	if options.Synchronizer != nil {
+		syncController := controllers.NewSyncController(
+			options.Logger,
+			options.Synchronizer,
+			options.Storages.SplitStorage,
+			options.Storages.SegmentStorage,
+			options.DataWiper,
+		)
+		syncController.Register(admin)
+	}
+
+	if options.ImpressionsTask != nil || options.EventsTask != nil {
+		queueController := controllers.NewQueueController(
+			options.Logger,
+			options.ImpressionsTask,
+			options.EventsTask,
+			options.Storages,
+		)
+		queueController.Register(admin)
+	}
+
+	if options.LogLevels != nil {
+		loggingController := controllers.NewLoggingController(options.Logger, options.LogLevels)
+		loggingController.Register(admin)
+	}
+
+	if options.ChangeHistory != nil {
+		changeLogController := controllers.NewChangeLogController(options.Logger, options.ChangeHistory)
+		changeLogController.Register(admin)
+	}
+
+	if options.ClientAPIKeys != nil {
+		clientKeysController := controllers.NewClientKeysController(options.Logger, options.ClientAPIKeys)
+		clientKeysController.Register(admin)
+	}
+
+	if options.HTTPCache != nil {
+		cacheController := controllers.NewCacheController(options.Logger, options.HTTPCache)
+		cacheController.Register(admin)
+	}
+
+	if options.Fleet != nil {
+		fleetController := controllers.NewFleetController(options.Logger, options.Fleet)
+		fleetController.Register(admin)
+	}
+
+	accessController := controllers.NewAccessController(options.Logger, authenticator, audit)
+	accessController.Register(admin)
+
 	return &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", options.Host, options.Port),
-		Handler: router,
+		Addr:      fmt.Sprintf("%s:%d", options.Host, options.Port),
+		Handler:   router,
+		TLSConfig: tlsConfig,
 	}, nil
 }
+
+// ListenAndServe starts the admin server, using TLS if it has been configured
+func ListenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "") // certificates are already loaded in the tls config
+	}
+	return server.ListenAndServe()
+}
+
+func buildTLSConfig(certFile string, keyFile string, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, errors.New("client certificate verification requires a server certificate & key")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading certificate & key: %w", err)
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		pemData, err := ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no valid certificates found in %s", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// AuditEntry records an admin request that changed state, accessed a sensitive endpoint or was rejected
+type AuditEntry struct {
+	Timestamp  int64  `json:"timestamp"`
+	Identity   string `json:"identity"`
+	Role       string `json:"role"`
+	AuthMethod string `json:"authMethod"`
+	ClientCN   string `json:"clientCN,omitempty"`
+	RemoteAddr string `json:"remoteAddr"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+}
+
+// AuditLog keeps the latest audit entries in memory and appends every entry to a file (or the logger if no file is set)
+type AuditLog struct {
+	entries []AuditEntry
+	next    int
+	full    bool
+	writer  io.WriteCloser
+	logger  logging.LoggerInterface
+	mutex   sync.Mutex
+}
+
+// NewAuditLog constructs a new audit log keeping up to `maxEntries` in memory
+func NewAuditLog(path string, maxEntries int, logger logging.LoggerInterface) (*AuditLog, error) {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+
+	audit := &AuditLog{entries: make([]AuditEntry, maxEntries), logger: logger}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening admin audit log file: %w", err)
+		}
+		audit.writer = file
+	}
+	return audit, nil
+}
+
+// Record stores an entry
+func (a *AuditLog) Record(entry AuditEntry) {
+	serialized, _ := json.Marshal(entry)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.entries[a.next] = entry
+	a.next = (a.next + 1) % len(a.entries)
+	a.full = a.full || a.next == 0
+
+	if a.writer == nil {
+		a.logger.Info("admin audit: ", string(serialized))
+		return
+	}
+
+	if _, err := a.writer.Write(append(serialized, '\n')); err != nil {
+		a.logger.Error("error writing admin audit log entry: ", err)
+	}
+}
+
+// Entries returns the entries kept in memory, newest first
+func (a *AuditLog) Entries() []AuditEntry {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	count := a.next
+	if a.full {
+		count = len(a.entries)
+	}
+
+	result := make([]AuditEntry, 0, count)
+	for idx := 1; idx <= count; idx++ {
+		result = append(result, a.entries[(a.next-idx+len(a.entries))%len(a.entries)])
+	}
+	return result
+}
+
+// Close closes the audit file if any. Entries recorded afterwards are sent to the logger
+func (a *AuditLog) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.writer == nil {
+		return nil
+	}
+	err := a.writer.Close()
+	a.writer = nil
+	return err
+}
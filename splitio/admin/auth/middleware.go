@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentityKey is the gin context key holding the identity of the authenticated caller
+const IdentityKey = "adminIdentity"
+
+// Middleware authenticates requests, checks that the caller's role is allowed to access the endpoint
+// and records state-changing, sensitive & rejected requests in the audit log
+func Middleware(authenticator *Authenticator, audit *AuditLog) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		required := RequiredRole(ctx.Request.Method, ctx.Request.URL.Path)
+		entry := AuditEntry{
+			Timestamp:  time.Now().UnixNano() / int64(time.Millisecond),
+			RemoteAddr: ctx.ClientIP(),
+			Method:     ctx.Request.Method,
+			Path:       ctx.Request.URL.Path,
+		}
+		if tlsState := ctx.Request.TLS; tlsState != nil && len(tlsState.PeerCertificates) > 0 {
+			entry.ClientCN = tlsState.PeerCertificates[0].Subject.CommonName
+		}
+
+		identity, ok := authenticator.Authenticate(ctx.Request)
+		if !ok {
+			entry.Status = http.StatusUnauthorized
+			audit.Record(entry)
+			ctx.Header("WWW-Authenticate", `Basic realm="Authorization Required"`)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid credentials"})
+			return
+		}
+
+		entry.Identity, entry.Role, entry.AuthMethod = identity.Name, identity.Role.String(), identity.Method
+		if identity.Role < required {
+			entry.Status = http.StatusForbidden
+			audit.Record(entry)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "role " + identity.Role.String() + " cannot access this endpoint"})
+			return
+		}
+
+		ctx.Set(IdentityKey, identity)
+		if required == RoleReadOnly {
+			ctx.Next()
+			return
+		}
+
+		if strings.HasPrefix(entry.Path, "/shutdown") {
+			// shutdown handlers may never return, so the attempt is recorded upfront (with no status)
+			audit.Record(entry)
+			ctx.Next()
+			return
+		}
+
+		ctx.Next()
+		entry.Status = ctx.Writer.Status()
+		audit.Record(entry)
+	}
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestRequiredRole(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		role   Role
+	}{
+		{http.MethodGet, "/admin/dashboard", RoleReadOnly},
+		{http.MethodGet, "/info/config", RoleReadOnly},
+		{http.MethodPost, "/admin/queues/impressions/flush", RoleOperator},
+		{http.MethodPost, "/admin/sync/splits", RoleOperator},
+		{http.MethodPut, "/admin/logging/levels/sync", RoleOperator},
+		{http.MethodPost, "/admin/queues/events/drop", RoleAdmin},
+		{http.MethodGet, "/admin/snapshot", RoleAdmin},
+		{http.MethodGet, "/admin/clientkeys", RoleReadOnly},
+		{http.MethodPost, "/admin/clientkeys", RoleAdmin},
+		{http.MethodDelete, "/admin/clientkeys/abc", RoleAdmin},
+		{http.MethodGet, "/shutdown/stop/graceful", RoleAdmin},
+		{http.MethodGet, "/admin/access/audit", RoleAdmin},
+	}
+	for _, c := range cases {
+		if role := RequiredRole(c.method, c.path); role != c.role {
+			t.Errorf("%s %s should require %s. Got: %s", c.method, c.path, c.role, role)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	logger := logging.NewLogger(nil)
+	authenticator, _ := NewAuthenticator("", "", []string{"ro:read-only:roToken", "op:operator:opToken"}, "", logger)
+	audit, _ := NewAuditLog("", 10, logger)
+
+	router := gin.New()
+	group := router.Group("/admin", Middleware(authenticator, audit))
+	ok := func(ctx *gin.Context) { ctx.String(http.StatusOK, "ok") }
+	group.GET("/dashboard", ok)
+	group.POST("/sync/splits", ok)
+	group.POST("/queues/events/drop", ok)
+
+	do := func(method string, path string, token string) int {
+		resp := httptest.NewRecorder()
+		request, _ := http.NewRequest(method, path, nil)
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(resp, request)
+		return resp.Code
+	}
+
+	if code := do(http.MethodGet, "/admin/dashboard", ""); code != 401 {
+		t.Error("unauthenticated requests should be rejected. Got: ", code)
+	}
+	if code := do(http.MethodGet, "/admin/dashboard", "roToken"); code != 200 {
+		t.Error("read-only tokens should access the dashboard. Got: ", code)
+	}
+	if code := do(http.MethodPost, "/admin/sync/splits", "roToken"); code != 403 {
+		t.Error("read-only tokens should not force a sync. Got: ", code)
+	}
+	if code := do(http.MethodPost, "/admin/sync/splits", "opToken"); code != 200 {
+		t.Error("operator tokens should force a sync. Got: ", code)
+	}
+	if code := do(http.MethodPost, "/admin/queues/events/drop", "opToken"); code != 403 {
+		t.Error("operator tokens should not drop queues. Got: ", code)
+	}
+
+	entries := audit.Entries()
+	if len(entries) != 4 {
+		t.Error("4 audit entries expected (read requests are not audited). Got: ", entries)
+		return
+	}
+	if e := entries[0]; e.Identity != "op" || e.Path != "/admin/queues/events/drop" || e.Status != 403 {
+		t.Error("invalid latest entry: ", e)
+	}
+	if e := entries[1]; e.Identity != "op" || e.Role != "operator" || e.AuthMethod != MethodToken || e.Status != 200 {
+		t.Error("invalid sync entry: ", e)
+	}
+	if e := entries[3]; e.Identity != "" || e.Status != 401 {
+		t.Error("invalid unauthenticated entry: ", e)
+	}
+}
+
+func TestAuditLogRing(t *testing.T) {
+	audit, _ := NewAuditLog("", 2, logging.NewLogger(nil))
+	for _, path := range []string{"/a", "/b", "/c"} {
+		audit.Record(AuditEntry{Path: path})
+	}
+
+	entries := audit.Entries()
+	if len(entries) != 2 || entries[0].Path != "/c" || entries[1].Path != "/b" {
+		t.Error("only the 2 newest entries should be kept. Got: ", entries)
+	}
+}
+
+func TestAuditLogClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+	audit, err := NewAuditLog(path, 2, logging.NewLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit.Record(AuditEntry{Path: "/a"})
+	if err := audit.Close(); err != nil {
+		t.Error("no error expected when closing. Got: ", err)
+	}
+	audit.Record(AuditEntry{Path: "/b"}) // logged once the file is closed
+	if err := audit.Close(); err != nil {
+		t.Error("closing twice should not fail. Got: ", err)
+	}
+
+	contents, _ := ioutil.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(contents)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], "/a") {
+		t.Error("only the entry recorded before closing should be in the file. Got: ", string(contents))
+	}
+	if entries := audit.Entries(); len(entries) != 2 {
+		t.Error("entries should still be kept in memory. Got: ", entries)
+	}
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role determines which admin endpoints an identity can access. Higher roles include the lower ones
+type Role int
+
+// Roles
+const (
+	RoleNone Role = iota
+	RoleReadOnly
+	RoleOperator
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleReadOnly: "read-only",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+// String returns the name of the role
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(r))
+}
+
+// ParseRole parses a role name
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if role != RoleNone && strings.EqualFold(name, roleName) {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role '%s'. Must be one of [read-only, operator, admin]", name)
+}
+
+// RequiredRole returns the minimum role needed to perform a request:
+//   - admin: shutdown, snapshot download, queue drops, access & client apikey management
+//   - operator: every other non-read request (flush, resync, pause/resume, log levels, ...)
+//   - read-only: dashboard, observability, info & every other read request
+func RequiredRole(method string, path string) Role {
+	switch {
+	case strings.HasPrefix(path, "/shutdown"),
+		path == "/admin/snapshot",
+		strings.HasPrefix(path, "/admin/access/"),
+		strings.HasPrefix(path, "/admin/clientkeys") && method != http.MethodGet && method != http.MethodHead,
+		strings.HasPrefix(path, "/admin/queues/") && strings.HasSuffix(path, "/drop"):
+		return RoleAdmin
+	case method != http.MethodGet && method != http.MethodHead:
+		return RoleOperator
+	default:
+		return RoleReadOnly
+	}
+}
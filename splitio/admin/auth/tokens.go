@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Authentication methods
+const (
+	MethodAnonymous = "anonymous"
+	MethodBasic     = "basic"
+	MethodToken     = "token"
+)
+
+// Identity is the result of authenticating a request
+type Identity struct {
+	Name   string
+	Role   Role
+	Method string
+}
+
+// Authenticator validates basic auth credentials & bearer tokens. Tokens are defined as `<name>:<role>:<token>`
+// either in config or in a file (one per line, '#' starts a comment)
+type Authenticator struct {
+	username   string
+	password   string
+	fromConfig []string
+	tokensFile string
+	tokens     atomic.Value // map[[sha256.Size]byte]Identity
+	logger     logging.LoggerInterface
+}
+
+// NewAuthenticator constructs a new authenticator and loads the tokens
+func NewAuthenticator(username string, password string, tokens []string, tokensFile string, logger logging.LoggerInterface) (*Authenticator, error) {
+	authenticator := &Authenticator{
+		username:   username,
+		password:   password,
+		fromConfig: tokens,
+		tokensFile: tokensFile,
+		logger:     logger,
+	}
+
+	if _, err := authenticator.Reload(); err != nil {
+		return nil, err
+	}
+	return authenticator, nil
+}
+
+// Enabled returns true if any credential has been configured. When disabled, every request is granted the admin role
+func (a *Authenticator) Enabled() bool {
+	return (a.username != "" && a.password != "") || len(a.currentTokens()) > 0 || a.tokensFile != ""
+}
+
+// Reload parses the tokens from config & the tokens file again. Current tokens are kept if parsing fails
+func (a *Authenticator) Reload() (int, error) {
+	tokens := make(map[[sha256.Size]byte]Identity)
+	for _, raw := range a.fromConfig {
+		if err := parseToken(raw, tokens); err != nil {
+			return 0, fmt.Errorf("invalid admin token in config: %w", err)
+		}
+	}
+
+	if a.tokensFile != "" {
+		if err := parseTokensFile(a.tokensFile, tokens); err != nil {
+			return 0, err
+		}
+	}
+
+	a.tokens.Store(tokens)
+	return len(tokens), nil
+}
+
+// Authenticate returns the identity associated to the credentials in the request. Tokens are accepted either
+// as bearer tokens or as basic auth passwords (so that the dashboard can be used from a browser)
+func (a *Authenticator) Authenticate(request *http.Request) (*Identity, bool) {
+	if !a.Enabled() {
+		return &Identity{Name: MethodAnonymous, Role: RoleAdmin, Method: MethodAnonymous}, true
+	}
+
+	if header := request.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return a.lookup(strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")))
+	}
+
+	username, password, ok := request.BasicAuth()
+	if !ok {
+		return nil, false
+	}
+
+	if a.username != "" && a.password != "" &&
+		subtle.ConstantTimeCompare([]byte(username), []byte(a.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(a.password)) == 1 {
+		return &Identity{Name: username, Role: RoleAdmin, Method: MethodBasic}, true
+	}
+
+	return a.lookup(password)
+}
+
+func (a *Authenticator) lookup(token string) (*Identity, bool) {
+	if token == "" {
+		return nil, false
+	}
+
+	identity, ok := a.currentTokens()[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, false
+	}
+	return &identity, true
+}
+
+func (a *Authenticator) currentTokens() map[[sha256.Size]byte]Identity {
+	tokens, _ := a.tokens.Load().(map[[sha256.Size]byte]Identity)
+	return tokens
+}
+
+func parseTokensFile(path string, dst map[[sha256.Size]byte]Identity) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening admin tokens file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+		if err := parseToken(raw, dst); err != nil {
+			return fmt.Errorf("invalid admin token in %s:%d: %w", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func parseToken(raw string, dst map[[sha256.Size]byte]Identity) error {
+	if raw == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return fmt.Errorf("tokens must have the form <name>:<role>:<token>")
+	}
+
+	role, err := ParseRole(parts[1])
+	if err != nil {
+		return fmt.Errorf("token '%s': %w", parts[0], err)
+	}
+
+	hash := sha256.Sum256([]byte(parts[2]))
+	if existing, ok := dst[hash]; ok {
+		return fmt.Errorf("token '%s' is the same as token '%s'", parts[0], existing.Name)
+	}
+	dst[hash] = Identity{Name: parts[0], Role: role, Method: MethodToken}
+	return nil
+}
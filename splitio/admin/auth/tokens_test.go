@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestAuthenticator(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "admintokens")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tokens")
+	ioutil.WriteFile(path, []byte("# operators\nci:operator:ciToken\n\n"), 0600)
+
+	authenticator, err := NewAuthenticator("user", "pass", []string{"dashboard:read-only:roToken", ""}, path, logging.NewLogger(nil))
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+		return
+	}
+
+	check := func(setup func(r *http.Request), expectedName string, expectedRole Role) {
+		t.Helper()
+		request, _ := http.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+		setup(request)
+		identity, ok := authenticator.Authenticate(request)
+		if expectedRole == RoleNone {
+			if ok {
+				t.Error("request should not be authenticated. Got: ", identity)
+			}
+			return
+		}
+		if !ok || identity.Name != expectedName || identity.Role != expectedRole {
+			t.Error("invalid identity: ", identity, ok)
+		}
+	}
+
+	check(func(r *http.Request) {}, "", RoleNone)
+	check(func(r *http.Request) { r.SetBasicAuth("user", "pass") }, "user", RoleAdmin)
+	check(func(r *http.Request) { r.SetBasicAuth("user", "wrong") }, "", RoleNone)
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer roToken") }, "dashboard", RoleReadOnly)
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer ciToken") }, "ci", RoleOperator)
+	check(func(r *http.Request) { r.SetBasicAuth("anything", "ciToken") }, "ci", RoleOperator)
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer unknown") }, "", RoleNone)
+
+	ioutil.WriteFile(path, []byte("root:admin:rootToken\n"), 0600)
+	if count, err := authenticator.Reload(); err != nil || count != 2 {
+		t.Error("2 tokens should be loaded. Got: ", count, err)
+	}
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer rootToken") }, "root", RoleAdmin)
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer ciToken") }, "", RoleNone)
+
+	ioutil.WriteFile(path, []byte("broken:superuser:x\n"), 0600)
+	if _, err := authenticator.Reload(); err == nil {
+		t.Error("an invalid role should fail the reload")
+	}
+	check(func(r *http.Request) { r.Header.Set("Authorization", "Bearer rootToken") }, "root", RoleAdmin)
+}
+
+func TestAuthenticatorValidation(t *testing.T) {
+	for _, tokens := range [][]string{{"noRole"}, {"a:admin:"}, {"a:god:x"}, {"a:admin:x", "b:operator:x"}} {
+		if _, err := NewAuthenticator("", "", tokens, "", logging.NewLogger(nil)); err == nil {
+			t.Error("tokens should be rejected: ", tokens)
+		}
+	}
+
+	authenticator, _ := NewAuthenticator("", "", nil, "", logging.NewLogger(nil))
+	request, _ := http.NewRequest(http.MethodGet, "/shutdown/stop/force", nil)
+	if identity, ok := authenticator.Authenticate(request); !ok || identity.Role != RoleAdmin {
+		t.Error("requests should be granted admin access when no credentials are configured. Got: ", identity)
+	}
+}
@@ -1,6 +1,14 @@
 package common
 
-import "github.com/splitio/go-split-commons/v4/storage"
+import (
+	"errors"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
+)
 
 // Storages wraps storages in one struct
 type Storages struct {
@@ -10,3 +18,92 @@ type Storages struct {
 	EventStorage          storage.EventMultiSdkConsumer
 	ImpressionStorage     storage.ImpressionMultiSdkConsumer
 }
+
+// EvictionTask defines the interface of a task that evicts data from a queue and can be paused, resumed & flushed on demand
+type EvictionTask interface {
+	Pause()
+	Resume()
+	IsPaused() bool
+	Flush(n int64) (int, error)
+}
+
+// QueueDropper defines the interface of a storage that can have its queue dropped. A size of -1 drops all the elements
+type QueueDropper interface {
+	Drop(size int64) error
+}
+
+// SamplingMonitor defines the interface of a component that reports how many impressions & events have been sampled out,
+// in total and per rule
+type SamplingMonitor interface {
+	SampledOut() (impressions int64, events int64, byRule map[string]int64)
+}
+
+// LogLevels defines the interface of a component that allows querying & updating log levels at runtime
+type LogLevels interface {
+	Levels() map[string]string
+	SetLevel(component string, level string) error
+	ResetLevel(component string)
+}
+
+// ChangeHistory defines the interface of a component that can be queried for split & segment changes
+type ChangeHistory interface {
+	Query(query changelog.Query) ([]changelog.Entry, error)
+}
+
+// Errors returned by ClientAPIKeys implementations
+var (
+	ErrClientAPIKeyNotFound  = errors.New("apikey not found")
+	ErrClientAPIKeyDuplicate = errors.New("apikey already exists")
+	ErrClientAPIKeyExpired   = errors.New("expiration date is in the past")
+)
+
+// ClientAPIKeyStatus is the view of a client apikey exposed by the admin endpoints
+type ClientAPIKeyStatus struct {
+	ID        string `json:"id"`
+	Hint      string `json:"hint"`
+	Label     string `json:"label"`
+	Source    string `json:"source"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	RevokedAt int64  `json:"revokedAt,omitempty"`
+	Active    bool   `json:"active"`
+	Usage     int64  `json:"usage"`
+	LastUsed  int64  `json:"lastUsed,omitempty"`
+}
+
+// ClientAPIKeys defines the interface of a component that manages the apikeys sdks use to connect to the proxy
+type ClientAPIKeys interface {
+	List() []ClientAPIKeyStatus
+	Add(apikey string, label string, expiresAt time.Time) (string, *ClientAPIKeyStatus, error)
+	Revoke(id string) (*ClientAPIKeyStatus, error)
+}
+
+// HTTPCacheFamilyStats contains the counters of a family of cached responses (ie: splitChanges)
+type HTTPCacheFamilyStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Purged    int64 `json:"purged"`
+	Entries   int64 `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// HTTPCacheStats contains the current size & limits of a response cache along with per-family counters
+type HTTPCacheStats struct {
+	Entries    int64                           `json:"entries"`
+	Bytes      int64                           `json:"bytes"`
+	MaxEntries int64                           `json:"maxEntries"`
+	MaxBytes   int64                           `json:"maxBytes"`
+	Families   map[string]HTTPCacheFamilyStats `json:"families"`
+}
+
+// HTTPCache defines the interface of a response cache that can be inspected & purged
+type HTTPCache interface {
+	Stats() HTTPCacheStats
+	Purge(surrogate string) int
+}
+
+// FleetInventory defines the interface of a component that keeps track of the sdk instances sending data through this instance
+type FleetInventory interface {
+	Summary() fleet.Summary
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/admin/auth"
+)
+
+// AccessController bundles endpoints used to inspect & manage admin access
+type AccessController struct {
+	logger        logging.LoggerInterface
+	authenticator *auth.Authenticator
+	audit         *auth.AuditLog
+}
+
+// NewAccessController constructs a new access controller
+func NewAccessController(logger logging.LoggerInterface, authenticator *auth.Authenticator, audit *auth.AuditLog) *AccessController {
+	return &AccessController{logger: logger, authenticator: authenticator, audit: audit}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *AccessController) Register(router gin.IRouter) {
+	router.GET("/whoami", c.whoami)
+	router.GET("/access/audit", c.auditEntries)
+	router.POST("/access/tokens/reload", c.reloadTokens)
+}
+
+func (c *AccessController) whoami(ctx *gin.Context) {
+	identity, _ := ctx.Get(auth.IdentityKey)
+	if parsed, ok := identity.(*auth.Identity); ok {
+		ctx.JSON(http.StatusOK, gin.H{"name": parsed.Name, "role": parsed.Role.String(), "method": parsed.Method})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"name": auth.MethodAnonymous, "role": auth.RoleAdmin.String(), "method": auth.MethodAnonymous})
+}
+
+func (c *AccessController) auditEntries(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.audit.Entries())
+}
+
+func (c *AccessController) reloadTokens(ctx *gin.Context) {
+	count, err := c.authenticator.Reload()
+	if err != nil {
+		c.logger.Error("error reloading admin tokens: ", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.logger.Info(fmt.Sprintf("%d admin tokens loaded", count))
+	ctx.JSON(http.StatusOK, gin.H{"tokens": count})
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
+)
+
+// CacheController bundles endpoints used to inspect & purge the proxy's response cache
+type CacheController struct {
+	logger logging.LoggerInterface
+	cache  adminCommon.HTTPCache
+}
+
+// NewCacheController constructs a new cache controller
+func NewCacheController(logger logging.LoggerInterface, cache adminCommon.HTTPCache) *CacheController {
+	return &CacheController{logger: logger, cache: cache}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *CacheController) Register(router gin.IRouter) {
+	router.GET("/cache", c.stats)
+	router.DELETE("/cache", c.purge)
+}
+
+func (c *CacheController) stats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.cache.Stats())
+}
+
+// purge removes the entries referenced by the `surrogate` (ie: `sp`) or `segment` query parameters,
+// or every entry if none is supplied
+func (c *CacheController) purge(ctx *gin.Context) {
+	surrogate := ctx.Query("surrogate")
+	if segment := ctx.Query("segment"); segment != "" {
+		if surrogate != "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "only one of 'surrogate' & 'segment' can be supplied"})
+			return
+		}
+		surrogate = caching.MakeSurrogateForSegmentChanges(segment)
+	}
+
+	purged := c.cache.Purge(surrogate)
+	if surrogate == "" {
+		c.logger.Info(fmt.Sprintf("HTTP cache purged from admin endpoint (%d entries)", purged))
+	} else {
+		c.logger.Info(fmt.Sprintf("HTTP cache entries for surrogate '%s' purged from admin endpoint (%d entries)", surrogate, purged))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"purged": purged})
+}
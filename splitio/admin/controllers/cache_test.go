@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
+)
+
+type httpCacheMock struct {
+	purged []string
+}
+
+func (m *httpCacheMock) Stats() adminCommon.HTTPCacheStats {
+	return adminCommon.HTTPCacheStats{Entries: 3, Families: map[string]adminCommon.HTTPCacheFamilyStats{caching.FamilySplits: {Hits: 5}}}
+}
+
+func (m *httpCacheMock) Purge(surrogate string) int {
+	m.purged = append(m.purged, surrogate)
+	return 2
+}
+
+func TestCacheController(t *testing.T) {
+	cache := &httpCacheMock{}
+	ctrl := NewCacheController(logging.NewLogger(nil), cache)
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/cache", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	var stats adminCommon.HTTPCacheStats
+	if err := json.Unmarshal(resp.Body.Bytes(), &stats); err != nil || stats.Entries != 3 || stats.Families[caching.FamilySplits].Hits != 5 {
+		t.Error("invalid stats: ", stats, err)
+	}
+
+	for _, query := range []string{"", "?surrogate=sp", "?segment=seg1"} {
+		resp = httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodDelete, "/cache"+query, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		if resp.Code != 200 || resp.Body.String() != `{"purged":2}` {
+			t.Error("unexpected response: ", resp.Code, resp.Body.String())
+		}
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/cache?surrogate=sp&segment=seg1", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 400 {
+		t.Error("status should be 400. Got: ", resp.Code)
+	}
+
+	expected := []string{"", caching.SplitSurrogate, caching.MakeSurrogateForSegmentChanges("seg1")}
+	if len(cache.purged) != len(expected) {
+		t.Fatal("unexpected purges: ", cache.purged)
+	}
+	for idx := range expected {
+		if cache.purged[idx] != expected[idx] {
+			t.Error("unexpected purge: ", cache.purged[idx])
+		}
+	}
+}
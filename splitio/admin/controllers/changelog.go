@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+const maxChangeLogLimit = 1000
+
+// ChangeLogController bundles endpoints used to browse the history of split & segment changes
+type ChangeLogController struct {
+	logger  logging.LoggerInterface
+	history adminCommon.ChangeHistory
+}
+
+// NewChangeLogController constructs a new change log controller
+func NewChangeLogController(logger logging.LoggerInterface, history adminCommon.ChangeHistory) *ChangeLogController {
+	return &ChangeLogController{logger: logger, history: history}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *ChangeLogController) Register(router gin.IRouter) {
+	router.GET("/changes", c.changes)
+	router.GET("/changes/splits/:name", c.changesFor(changelog.KindSplit))
+	router.GET("/changes/segments/:name", c.changesFor(changelog.KindSegment))
+}
+
+func (c *ChangeLogController) changes(ctx *gin.Context) {
+	query, err := parseChangeLogQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch kind := ctx.Query("kind"); kind {
+	case "", changelog.KindSplit, changelog.KindSegment:
+		query.Kind = kind
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid kind '%s'. Must be one of [split, segment]", kind)})
+		return
+	}
+	query.Name = ctx.Query("name")
+	c.respond(ctx, query)
+}
+
+func (c *ChangeLogController) changesFor(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, err := parseChangeLogQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.Kind = kind
+		query.Name = ctx.Param("name")
+		c.respond(ctx, query)
+	}
+}
+
+func (c *ChangeLogController) respond(ctx *gin.Context, query *changelog.Query) {
+	entries, err := c.history.Query(*query)
+	if err != nil {
+		c.logger.Error("error querying change history: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "error reading change history"})
+		return
+	}
+
+	if entries == nil {
+		entries = []changelog.Entry{}
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
+
+func parseChangeLogQuery(ctx *gin.Context) (*changelog.Query, error) {
+	var query changelog.Query
+	var err error
+	if query.Since, err = parseTimeParam(ctx.Query("since")); err != nil {
+		return nil, fmt.Errorf("invalid 'since': %w", err)
+	}
+	if query.Until, err = parseTimeParam(ctx.Query("until")); err != nil {
+		return nil, fmt.Errorf("invalid 'until': %w", err)
+	}
+
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxChangeLogLimit {
+			return nil, fmt.Errorf("limit must be an integer between 1 and %d", maxChangeLogLimit)
+		}
+		query.Limit = limit
+	}
+	return &query, nil
+}
+
+// parseTimeParam accepts either a unix timestamp in milliseconds or an RFC3339 date
+func parseTimeParam(raw string) (int64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return ms, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return 0, fmt.Errorf("must be a unix timestamp in milliseconds or an RFC3339 date")
+	}
+	return parsed.UnixNano() / int64(time.Millisecond), nil
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+func TestChangeLogController(t *testing.T) {
+	storage := changelog.NewInMemoryStorage()
+	storage.Append([]changelog.Entry{
+		{Kind: changelog.KindSplit, Name: "split1", Action: changelog.ActionAdded, Timestamp: 1600000000000},
+		{Kind: changelog.KindSegment, Name: "segment1", Action: changelog.ActionUpdated, Timestamp: 1700000000000},
+	})
+	ctrl := NewChangeLogController(logging.NewLogger(nil), changelog.NewRecorder(storage, 0, 0, logging.NewLogger(nil)))
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	get := func(path string) (int, []changelog.Entry) {
+		resp := httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		var entries []changelog.Entry
+		json.Unmarshal(resp.Body.Bytes(), &entries)
+		return resp.Code, entries
+	}
+
+	if code, entries := get("/changes"); code != 200 || len(entries) != 2 || entries[0].Name != "segment1" {
+		t.Error("both entries should be returned. Got: ", code, entries)
+	}
+
+	if code, entries := get("/changes/splits/split1"); code != 200 || len(entries) != 1 || entries[0].Name != "split1" {
+		t.Error("only the split entry should be returned. Got: ", code, entries)
+	}
+
+	if code, entries := get("/changes/segments/split1"); code != 200 || len(entries) != 0 {
+		t.Error("no entries should be returned. Got: ", code, entries)
+	}
+
+	if code, entries := get("/changes?since=2021-01-01T00:00:00Z"); code != 200 || len(entries) != 1 || entries[0].Name != "segment1" {
+		t.Error("only the segment entry should be returned. Got: ", code, entries)
+	}
+
+	if code, entries := get("/changes?until=1650000000000&kind=split"); code != 200 || len(entries) != 1 || entries[0].Name != "split1" {
+		t.Error("only the split entry should be returned. Got: ", code, entries)
+	}
+
+	for _, path := range []string{"/changes?since=yesterday", "/changes?limit=0", "/changes?limit=5000", "/changes?kind=something"} {
+		if code, _ := get(path); code != 400 {
+			t.Error("status code should be 400 for ", path, ". Got: ", code)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+// ClientKeysController bundles endpoints used to manage the apikeys sdks use to connect to the proxy
+type ClientKeysController struct {
+	logger logging.LoggerInterface
+	keys   adminCommon.ClientAPIKeys
+}
+
+// NewClientKeysController constructs a new client apikeys controller
+func NewClientKeysController(logger logging.LoggerInterface, keys adminCommon.ClientAPIKeys) *ClientKeysController {
+	return &ClientKeysController{logger: logger, keys: keys}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *ClientKeysController) Register(router gin.IRouter) {
+	router.GET("/clientkeys", c.list)
+	router.POST("/clientkeys", c.add)
+	router.DELETE("/clientkeys/:id", c.revoke)
+}
+
+type addClientKeyRequest struct {
+	APIKey    string `json:"apikey"`
+	Label     string `json:"label"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+func (c *ClientKeysController) list(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.keys.List())
+}
+
+func (c *ClientKeysController) add(ctx *gin.Context) {
+	var request addClientKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	var expiresAt time.Time
+	expiresAtMs, err := parseTimeParam(request.ExpiresAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiresAt: " + err.Error()})
+		return
+	}
+	if expiresAtMs > 0 {
+		expiresAt = time.Unix(expiresAtMs/1000, (expiresAtMs%1000)*int64(time.Millisecond))
+	}
+
+	apikey, status, err := c.keys.Add(request.APIKey, request.Label, expiresAt)
+	switch {
+	case errors.Is(err, adminCommon.ErrClientAPIKeyDuplicate), errors.Is(err, adminCommon.ErrClientAPIKeyExpired):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.logger.Error("error adding client apikey: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "error adding client apikey"})
+		return
+	}
+
+	c.logger.Info("client apikey ", status.Hint, " added with label '", status.Label, "'")
+	ctx.JSON(http.StatusCreated, gin.H{"apikey": apikey, "key": status})
+}
+
+func (c *ClientKeysController) revoke(ctx *gin.Context) {
+	status, err := c.keys.Revoke(ctx.Param("id"))
+	switch {
+	case errors.Is(err, adminCommon.ErrClientAPIKeyNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.logger.Error("error revoking client apikey: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "error revoking client apikey"})
+		return
+	}
+
+	c.logger.Info("client apikey ", status.Hint, " revoked")
+	ctx.JSON(http.StatusOK, status)
+}
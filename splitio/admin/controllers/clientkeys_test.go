@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
+)
+
+func TestClientKeysController(t *testing.T) {
+	manager := apikeys.NewManager([]string{"staticKey"}, nil, nil)
+	ctrl := NewClientKeysController(logging.NewLogger(nil), apikeys.NewAdminView(manager))
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	do := func(method string, path string, body string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(method, path, strings.NewReader(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(resp, ctx.Request)
+		return resp
+	}
+
+	resp = do("POST", "/clientkeys", `{"label": "android", "expiresAt": "2100-01-01T00:00:00Z"}`)
+	if resp.Code != 201 {
+		t.Error("key should have been created. Got: ", resp.Code, resp.Body.String())
+	}
+
+	var created struct {
+		APIKey string                         `json:"apikey"`
+		Key    adminCommon.ClientAPIKeyStatus `json:"key"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	if created.APIKey == "" || created.Key.Label != "android" || created.Key.ExpiresAt != 4102444800000 {
+		t.Error("wrong response: ", resp.Body.String())
+	}
+
+	if !manager.IsValid(created.APIKey) {
+		t.Error("created key should be valid")
+	}
+
+	for _, body := range []string{`{"expiresAt": "tomorrow"}`, `{"expiresAt": "2000-01-01T00:00:00Z"}`, `{"apikey": "staticKey"}`, `{`} {
+		if resp := do("POST", "/clientkeys", body); resp.Code != 400 {
+			t.Error("status should be 400 for ", body, ". Got: ", resp.Code)
+		}
+	}
+
+	var listed []adminCommon.ClientAPIKeyStatus
+	json.Unmarshal(do("GET", "/clientkeys", "").Body.Bytes(), &listed)
+	if len(listed) != 2 || listed[0].Source != apikeys.SourceConfig || listed[1].ID != created.Key.ID {
+		t.Error("wrong listing: ", listed)
+	}
+
+	if resp := do("DELETE", "/clientkeys/"+listed[0].ID, ""); resp.Code != 200 {
+		t.Error("static keys should be revocable. Got: ", resp.Code)
+	}
+
+	if resp := do("DELETE", "/clientkeys/unknown", ""); resp.Code != 404 {
+		t.Error("unknown keys should return 404. Got: ", resp.Code)
+	}
+
+	if resp := do("DELETE", "/clientkeys/"+created.Key.ID, ""); resp.Code != 200 {
+		t.Error("key should have been revoked. Got: ", resp.Code)
+	}
+
+	if manager.IsValid(created.APIKey) {
+		t.Error("revoked key should not be valid")
+	}
+}
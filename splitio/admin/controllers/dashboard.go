@@ -29,6 +29,11 @@ type DashboardController struct {
 	eventsEvCalc      evcalc.Monitor
 	runtime           common.Runtime
 	appMonitor        application.MonitorIterface
+	sampler           adminCommon.SamplingMonitor
+	changeHistory     bool
+	clientKeys        adminCommon.ClientAPIKeys
+	httpCache         adminCommon.HTTPCache
+	fleet             bool
 }
 
 // NewDashboardController instantiates a new dashboard controller
@@ -41,6 +46,11 @@ func NewDashboardController(
 	eventsEvCalc evcalc.Monitor,
 	runtime common.Runtime,
 	appMonitor application.MonitorIterface,
+	sampler adminCommon.SamplingMonitor,
+	changeHistory bool,
+	clientKeys adminCommon.ClientAPIKeys,
+	httpCache adminCommon.HTTPCache,
+	fleet bool,
 ) (*DashboardController, error) {
 
 	toReturn := &DashboardController{
@@ -52,6 +62,11 @@ func NewDashboardController(
 		eventsEvCalc:      eventsEvCalc,
 		impressionsEvCalc: impressionEvCalc,
 		appMonitor:        appMonitor,
+		sampler:           sampler,
+		changeHistory:     changeHistory,
+		clientKeys:        clientKeys,
+		httpCache:         httpCache,
+		fleet:             fleet,
 	}
 
 	var err error
@@ -66,6 +81,7 @@ func NewDashboardController(
 func (c *DashboardController) Register(router gin.IRouter) {
 	router.GET("/dashboard", c.dashboard)
 	router.GET("/dashboard/segmentKeys/:segment", c.segmentKeys)
+	router.GET("/dashboard/keySegments/:key", c.keySegments)
 	router.GET("/dashboard/stats", c.stats)
 }
 
@@ -99,6 +115,22 @@ func (c *DashboardController) segmentKeys(ctx *gin.Context) {
 	ctx.JSON(200, bundleSegmentKeysInfo(segmentName, c.storages.SegmentStorage))
 }
 
+// keySegments returns the segments a given key belongs to
+func (c *DashboardController) keySegments(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		ctx.AbortWithStatus(400)
+		return
+	}
+	segments, err := bundleKeySegments(key, c.storages.SplitStorage, c.storages.SegmentStorage)
+	if err != nil {
+		c.logger.Error("error looking up segments for key: ", err)
+		ctx.AbortWithStatus(500)
+		return
+	}
+	ctx.JSON(200, segments)
+}
+
 // \} -- end of endpoint functions
 
 func (c *DashboardController) renderDashboard() ([]byte, error) {
@@ -107,6 +139,8 @@ func (c *DashboardController) renderDashboard() ([]byte, error) {
 		DashboardTitle: c.title,
 		Version:        splitio.Version,
 		ProxyMode:      c.proxy,
+		ChangeHistory:  c.changeHistory,
+		Fleet:          c.fleet,
 		RefreshTime:    30000,
 		Stats:          *c.gatherStats(),
 		Health:         c.appMonitor.GetHealthStatus(),
@@ -139,6 +173,15 @@ func (c *DashboardController) gatherStats() *dashboard.GlobalStats {
 		eventsLambda = c.eventsEvCalc.Lambda()
 	}
 
+	var sampledImpressions, sampledEvents int64
+	var sampledByRule map[string]int64
+	if c.sampler != nil {
+		sampledImpressions, sampledEvents, sampledByRule = c.sampler.SampledOut()
+	}
+
+	rateLimited, rateLimitedByRule := getProxyRateLimited(c.storages.LocalTelemetryStorage)
+	rejectedPayloads, rejectedPayloadsBySDK := getProxyRejectedPayloads(c.storages.LocalTelemetryStorage)
+
 	return &dashboard.GlobalStats{
 		Splits:                 bundleSplitInfo(c.storages.SplitStorage),
 		Segments:               bundleSegmentInfo(c.storages.SplitStorage, c.storages.SegmentStorage),
@@ -148,6 +191,16 @@ func (c *DashboardController) gatherStats() *dashboard.GlobalStats {
 		EventsQueueSize:        getEventsSize(c.storages.EventStorage),
 		ImpressionsLambda:      impressionsLambda,
 		EventsLambda:           eventsLambda,
+		SampledImpressions:     sampledImpressions,
+		SampledEvents:          sampledEvents,
+		SampledByRule:          sampledByRule,
+		RateLimited:            rateLimited,
+		RateLimitedByRule:      rateLimitedByRule,
+		RejectedPayloads:       rejectedPayloads,
+		RejectedPayloadsBySDK:  rejectedPayloadsBySDK,
+		LegacyTelemetryBySDK:   getProxyLegacyTelemetry(c.storages.LocalTelemetryStorage),
+		ClientKeys:             bundleClientKeys(c.clientKeys),
+		HTTPCache:              bundleHTTPCache(c.httpCache),
 		RequestsOk:             proxyOkReqs,
 		RequestsErrored:        proxyErrorReqs,
 		SdksTotalRequests:      proxyOkReqs + proxyErrorReqs,
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
+)
+
+// FleetController bundles endpoints used to inspect the sdk instances sending data through this instance
+type FleetController struct {
+	logger    logging.LoggerInterface
+	inventory adminCommon.FleetInventory
+}
+
+// NewFleetController constructs a new fleet controller
+func NewFleetController(logger logging.LoggerInterface, inventory adminCommon.FleetInventory) *FleetController {
+	return &FleetController{logger: logger, inventory: inventory}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *FleetController) Register(router gin.IRouter) {
+	router.GET("/fleet", c.summary)
+}
+
+// summary returns the tracked instances. They can be narrowed with the `language` & `outdated` query parameters
+func (c *FleetController) summary(ctx *gin.Context) {
+	summary := c.inventory.Summary()
+
+	language := ctx.Query("language")
+	outdated := false
+	if raw := ctx.Query("outdated"); raw != "" {
+		var err error
+		if outdated, err = strconv.ParseBool(raw); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "'outdated' must be a boolean"})
+			return
+		}
+	}
+
+	if language == "" && !outdated {
+		ctx.JSON(http.StatusOK, summary)
+		return
+	}
+
+	instances := make([]fleet.Instance, 0, len(summary.Instances))
+	for _, instance := range summary.Instances {
+		if (language == "" || instance.Language == language) && (!outdated || instance.Outdated) {
+			instances = append(instances, instance)
+		}
+	}
+
+	versions := make([]fleet.VersionSummary, 0, len(summary.Versions))
+	for _, version := range summary.Versions {
+		if (language == "" || version.Language == language) && (!outdated || version.Outdated) {
+			versions = append(versions, version)
+		}
+	}
+
+	summary.Instances = instances
+	summary.Versions = versions
+	ctx.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
+)
+
+func TestFleetController(t *testing.T) {
+	inventory := fleet.NewInventory(fleet.Config{MinVersions: map[string]string{"go": "6.0.0"}})
+	inventory.Track(dtos.Metadata{SDKVersion: "go-5.0.0", MachineName: "m1"}, fleet.ActivityImpressions, 10)
+	inventory.Track(dtos.Metadata{SDKVersion: "go-6.2.0", MachineName: "m2"}, fleet.ActivitySDK, 1)
+	inventory.Track(dtos.Metadata{SDKVersion: "java-4.0.0", MachineName: "m3"}, fleet.ActivityEvents, 3)
+
+	ctrl := NewFleetController(logging.NewLogger(nil), inventory)
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	doRequest := func(query string) (int, fleet.Summary) {
+		resp := httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodGet, "/fleet"+query, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		var summary fleet.Summary
+		json.Unmarshal(resp.Body.Bytes(), &summary)
+		return resp.Code, summary
+	}
+
+	code, summary := doRequest("")
+	if code != 200 || len(summary.Instances) != 3 || len(summary.Versions) != 3 || summary.Outdated != 1 || summary.MinVersions["go"] != "6.0.0" {
+		t.Error("unexpected response: ", code, summary)
+	}
+
+	code, summary = doRequest("?outdated=true")
+	if code != 200 || len(summary.Instances) != 1 || summary.Instances[0].SDKVersion != "go-5.0.0" || len(summary.Versions) != 1 {
+		t.Error("only outdated instances should be returned: ", code, summary)
+	}
+
+	code, summary = doRequest("?language=go")
+	if code != 200 || len(summary.Instances) != 2 || len(summary.Versions) != 2 {
+		t.Error("only go instances should be returned: ", code, summary)
+	}
+
+	if code, _ = doRequest("?outdated=sarasa"); code != 400 {
+		t.Error("invalid filters should be rejected: ", code)
+	}
+}
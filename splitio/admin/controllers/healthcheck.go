@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/splitio/go-toolkit/v5/logging"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
 )
 
@@ -14,6 +15,7 @@ type HealthCheckController struct {
 	logger              logging.LoggerInterface
 	appMonitor          application.MonitorIterface
 	dependenciesMonitor services.MonitorIterface
+	probes              *probes.Evaluator
 }
 
 func (c *HealthCheckController) appHealth(ctx *gin.Context) {
@@ -29,10 +31,35 @@ func (c *HealthCheckController) dependenciesHealth(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, c.dependenciesMonitor.GetHealthStatus())
 }
 
+func (c *HealthCheckController) live(ctx *gin.Context) {
+	respondProbe(ctx, c.probes.Live())
+}
+
+func (c *HealthCheckController) ready(ctx *gin.Context) {
+	respondProbe(ctx, c.probes.Ready())
+}
+
+func (c *HealthCheckController) startup(ctx *gin.Context) {
+	respondProbe(ctx, c.probes.Startup())
+}
+
+func respondProbe(ctx *gin.Context, result probes.Result) {
+	if result.Ok {
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+	ctx.JSON(http.StatusServiceUnavailable, result)
+}
+
 // Register the dashboard endpoints
 func (c *HealthCheckController) Register(router gin.IRouter) {
 	router.GET("/health/application", c.appHealth)
 	router.GET("/health/dependencies", c.dependenciesHealth)
+	if c.probes != nil {
+		router.GET("/health/live", c.live)
+		router.GET("/health/ready", c.ready)
+		router.GET("/health/startup", c.startup)
+	}
 }
 
 // NewHealthCheckController instantiates a new HealthCheck controller
@@ -40,10 +67,12 @@ func NewHealthCheckController(
 	logger logging.LoggerInterface,
 	appMonitor application.MonitorIterface,
 	dependenciesMonitor services.MonitorIterface,
+	probes *probes.Evaluator,
 ) *HealthCheckController {
 	return &HealthCheckController{
 		logger:              logger,
 		appMonitor:          appMonitor,
 		dependenciesMonitor: dependenciesMonitor,
+		probes:              probes,
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/splitio/go-toolkit/v5/logging"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
 )
 
 type monitorMock struct {
@@ -34,7 +35,7 @@ func TestApplicationHealthCheckEndpointErr(t *testing.T) {
 		}
 	}
 
-	ctrl := NewHealthCheckController(logging.NewLogger(nil), appHC, nil)
+	ctrl := NewHealthCheckController(logging.NewLogger(nil), appHC, nil, nil)
 
 	resp := httptest.NewRecorder()
 	ctx, router := gin.CreateTestContext(resp)
@@ -67,7 +68,7 @@ func TestApplicationHealthCheckEndpointOk(t *testing.T) {
 		}
 	}
 
-	ctrl := NewHealthCheckController(logging.NewLogger(nil), appHC, nil)
+	ctrl := NewHealthCheckController(logging.NewLogger(nil), appHC, nil, nil)
 
 	resp := httptest.NewRecorder()
 	ctx, router := gin.CreateTestContext(resp)
@@ -90,3 +91,38 @@ func TestApplicationHealthCheckEndpointOk(t *testing.T) {
 		t.Error("there should be no error ", err)
 	}
 }
+
+func TestProbeEndpoints(t *testing.T) {
+	appHC := &monitorMock{}
+	appHC.statusCall = func() application.HealthDto {
+		return application.HealthDto{Healthy: true}
+	}
+
+	evaluator := probes.NewEvaluator(probes.Config{}, appHC, nil, nil, nil)
+	ctrl := NewHealthCheckController(logging.NewLogger(nil), appHC, nil, evaluator)
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/health/startup", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 503 {
+		t.Error("status code should be 503 before startup. Got: ", resp.Code)
+	}
+
+	evaluator.MarkStarted()
+	for _, path := range []string{"/health/live", "/health/ready", "/health/startup"} {
+		resp = httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		if resp.Code != 200 {
+			t.Error("status code should be 200 for ", path, ". Got: ", resp.Code)
+		}
+
+		var result probes.Result
+		if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil || !result.Ok {
+			t.Error("invalid probe result: ", err, result)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/splitio/go-split-commons/v4/storage"
 	"github.com/splitio/go-split-commons/v4/telemetry"
 
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
 	"github.com/splitio/split-synchronizer/v5/splitio/admin/views/dashboard"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
 	proxyStorage "github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
@@ -99,6 +102,40 @@ func bundleSegmentKeysInfo(name string, segmentStorage storage.SegmentStorageCon
 	return segmentKeys
 }
 
+// bundleKeySegments returns the sorted list of segments containing a key. The proxy keeps a key -> segments index,
+// which is used if available. Otherwise every segment referenced by splits is checked
+func bundleKeySegments(key string, splitStorage storage.SplitStorage, segmentStorage storage.SegmentStorageConsumer) ([]string, error) {
+	if indexed, ok := segmentStorage.(interface {
+		SegmentsFor(string) ([]string, error)
+	}); ok {
+		segments, err := indexed.SegmentsFor(key)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching segments for key: %w", err)
+		}
+		sorted := make([]string, len(segments)) // don't reorder the storage's own slice
+		copy(sorted, segments)
+		sort.Strings(sorted)
+		return sorted, nil
+	}
+
+	segments := make([]string, 0)
+	for _, name := range splitStorage.SegmentNames().List() {
+		strName, ok := name.(string)
+		if !ok {
+			continue
+		}
+		contained, err := segmentStorage.SegmentContainsKey(strName, key)
+		if err != nil {
+			return nil, fmt.Errorf("error checking key in segment %s: %w", strName, err)
+		}
+		if contained {
+			segments = append(segments, strName)
+		}
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
+
 func successfulRequests(t storage.TelemetryPeeker) int64 {
 	var count int64
 	for _, resource := range []int{telemetry.SplitSync, telemetry.SegmentSync, telemetry.ImpressionSync, telemetry.ImpressionCountSync,
@@ -249,7 +286,7 @@ func getProxyRequestCount(metrics storage.TelemetryRuntimeConsumer) (ok int64, e
 	resources := []int{proxyStorage.AuthEndpoint, proxyStorage.SplitChangesEndpoint, proxyStorage.SegmentChangesEndpoint,
 		proxyStorage.MySegmentsEndpoint, proxyStorage.ImpressionsBulkEndpoint, proxyStorage.ImpressionsBulkBeaconEndpoint,
 		proxyStorage.ImpressionsCountEndpoint, proxyStorage.ImpressionsBulkBeaconEndpoint, proxyStorage.EventsBulkEndpoint,
-		proxyStorage.EventsBulkBeaconEndpoint}
+		proxyStorage.EventsBulkBeaconEndpoint, proxyStorage.MySegmentsBulkEndpoint}
 	var okCount int64
 	var errorCount int64
 	for _, res := range resources {
@@ -264,3 +301,101 @@ func getProxyRequestCount(metrics storage.TelemetryRuntimeConsumer) (ok int64, e
 
 	return okCount, errorCount
 }
+
+func getProxyRateLimited(metrics storage.TelemetryRuntimeConsumer) (total int64, byRule map[string]int64) {
+	asPeeker, ok := metrics.(proxyStorage.ProxyTelemetryPeeker)
+	if !ok { // This will be the case when runnning in producer mode
+		return 0, nil
+	}
+
+	byRule = asPeeker.PeekRateLimited()
+	for _, count := range byRule {
+		total += count
+	}
+	return total, byRule
+}
+
+func getProxyRejectedPayloads(metrics storage.TelemetryRuntimeConsumer) (total int64, bySDK map[string]int64) {
+	asPeeker, ok := metrics.(proxyStorage.ProxyTelemetryPeeker)
+	if !ok { // This will be the case when runnning in producer mode
+		return 0, nil
+	}
+
+	bySDK = asPeeker.PeekRejectedPayloads()
+	for _, count := range bySDK {
+		total += count
+	}
+	return total, bySDK
+}
+
+func getProxyLegacyTelemetry(metrics storage.TelemetryRuntimeConsumer) map[string]int64 {
+	asPeeker, ok := metrics.(proxyStorage.ProxyTelemetryPeeker)
+	if !ok { // This will be the case when runnning in producer mode
+		return nil
+	}
+	return asPeeker.PeekLegacyTelemetry()
+}
+
+func bundleClientKeys(keys adminCommon.ClientAPIKeys) []dashboard.ClientKeySummary {
+	if keys == nil {
+		return nil
+	}
+
+	formatMillis := func(ms int64) string {
+		if ms == 0 {
+			return ""
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)).UTC().Format(time.UnixDate)
+	}
+
+	statuses := keys.List()
+	summaries := make([]dashboard.ClientKeySummary, 0, len(statuses))
+	for _, status := range statuses {
+		summaries = append(summaries, dashboard.ClientKeySummary{
+			ID:        status.ID,
+			Hint:      status.Hint,
+			Label:     status.Label,
+			Source:    status.Source,
+			Active:    status.Active,
+			Usage:     status.Usage,
+			LastUsed:  formatMillis(status.LastUsed),
+			ExpiresAt: formatMillis(status.ExpiresAt),
+		})
+	}
+	return summaries
+}
+
+func bundleHTTPCache(cache adminCommon.HTTPCache) *dashboard.HTTPCacheSummary {
+	if cache == nil {
+		return nil
+	}
+
+	stats := cache.Stats()
+	names := make([]string, 0, len(stats.Families))
+	for name := range stats.Families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]dashboard.HTTPCacheFamilyRow, 0, len(names))
+	for _, name := range names {
+		family := stats.Families[name]
+		rows = append(rows, dashboard.HTTPCacheFamilyRow{
+			Name:      name,
+			Hits:      family.Hits,
+			Misses:    family.Misses,
+			Evictions: family.Evictions,
+			Purged:    family.Purged,
+			Entries:   family.Entries,
+			Bytes:     family.Bytes,
+		})
+	}
+
+	return &dashboard.HTTPCacheSummary{
+		Entries:    stats.Entries,
+		Bytes:      stats.Bytes,
+		MaxEntries: stats.MaxEntries,
+		MaxBytes:   stats.MaxBytes,
+		Families:   rows,
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-split-commons/v4/storage/inmemory/mutexmap"
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+)
+
+type indexedSegmentStorageMock struct {
+	storage.SegmentStorageConsumer
+	segments []string
+}
+
+func (m *indexedSegmentStorageMock) SegmentsFor(key string) ([]string, error) {
+	return m.segments, nil
+}
+
+func splitWithSegments(name string, segments ...string) dtos.SplitDTO {
+	conditions := make([]dtos.ConditionDTO, 0, len(segments))
+	for _, segment := range segments {
+		conditions = append(conditions, dtos.ConditionDTO{MatcherGroup: dtos.MatcherGroupDTO{Matchers: []dtos.MatcherDTO{{
+			MatcherType:        "IN_SEGMENT",
+			UserDefinedSegment: &dtos.UserDefinedSegmentMatcherDataDTO{SegmentName: segment},
+		}}}})
+	}
+	return dtos.SplitDTO{Name: name, Conditions: conditions}
+}
+
+func TestBundleKeySegments(t *testing.T) {
+	splits := mutexmap.NewMMSplitStorage()
+	splits.Update([]dtos.SplitDTO{splitWithSegments("split1", "segment3", "segment1"), splitWithSegments("split2", "segment2")}, nil, 1)
+
+	segments := mutexmap.NewMMSegmentStorage()
+	segments.Update("segment1", set.NewSet("key1", "key2"), set.NewSet(), 1)
+	segments.Update("segment2", set.NewSet("key2"), set.NewSet(), 1)
+	segments.Update("segment3", set.NewSet("key1"), set.NewSet(), 1)
+
+	result, err := bundleKeySegments("key1", splits, segments)
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if len(result) != 2 || result[0] != "segment1" || result[1] != "segment3" {
+		t.Error("unexpected segments: ", result)
+	}
+
+	result, err = bundleKeySegments("key3", splits, segments)
+	if err != nil || result == nil || len(result) != 0 {
+		t.Error("an empty non-nil list should be returned for unknown keys. Got: ", result, err)
+	}
+
+	indexed := &indexedSegmentStorageMock{segments: []string{"segmentB", "segmentA"}}
+	result, err = bundleKeySegments("key1", splits, indexed)
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if len(result) != 2 || result[0] != "segmentA" || result[1] != "segmentB" {
+		t.Error("unexpected segments: ", result)
+	}
+	if indexed.segments[0] != "segmentB" {
+		t.Error("the storage's slice should not be reordered")
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+// LoggingController bundles endpoints used to query & update log levels at runtime
+type LoggingController struct {
+	logger logging.LoggerInterface
+	levels adminCommon.LogLevels
+}
+
+// NewLoggingController constructs a new logging controller
+func NewLoggingController(logger logging.LoggerInterface, levels adminCommon.LogLevels) *LoggingController {
+	return &LoggingController{logger: logger, levels: levels}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *LoggingController) Register(router gin.IRouter) {
+	router.GET("/logging/levels", c.get)
+	router.PUT("/logging/levels/:component", c.set)
+	router.DELETE("/logging/levels/:component", c.reset)
+}
+
+func (c *LoggingController) get(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.levels.Levels())
+}
+
+func (c *LoggingController) set(ctx *gin.Context) {
+	var body struct {
+		Level string `json:"level"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil || body.Level == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "body must be a json object with a 'level' property"})
+		return
+	}
+
+	component := ctx.Param("component")
+	if err := c.levels.SetLevel(component, body.Level); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.logger.Info(fmt.Sprintf("Log level for %s set to %s from admin endpoint", component, body.Level))
+	ctx.JSON(http.StatusOK, c.levels.Levels())
+}
+
+func (c *LoggingController) reset(ctx *gin.Context) {
+	component := ctx.Param("component")
+	c.levels.ResetLevel(component)
+	c.logger.Info(fmt.Sprintf("Log level for %s reset from admin endpoint", component))
+	ctx.JSON(http.StatusOK, c.levels.Levels())
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/log"
+)
+
+func TestLoggingController(t *testing.T) {
+	levels := log.NewLevelRegistry(logging.LevelInfo)
+	ctrl := NewLoggingController(logging.NewLogger(nil), levels)
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	ctx.Request, _ = http.NewRequest(http.MethodPut, "/logging/levels/pipelined", strings.NewReader(`{"level": "debug"}`))
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("status code should be 200. Got: ", resp.Code)
+	}
+
+	var result map[string]string
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil || result["pipelined"] != "debug" || result["global"] != "info" {
+		t.Error("invalid levels: ", result, err)
+	}
+
+	for _, body := range []string{`{"level": "loud"}`, `{}`} {
+		resp = httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodPut, "/logging/levels/pipelined", strings.NewReader(body))
+		router.ServeHTTP(resp, ctx.Request)
+		if resp.Code != 400 {
+			t.Error("status code should be 400. Got: ", resp.Code)
+		}
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/logging/levels/pipelined", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if levels.Levels()["pipelined"] != "info" {
+		t.Error("pipelined level should be reset to the global one")
+	}
+}
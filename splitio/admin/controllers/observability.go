@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/splitio/split-synchronizer/v5/splitio/admin/common"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/observability"
@@ -42,6 +44,7 @@ type ProxyObservabilityController struct {
 	telemetry pstorage.TimeslicedProxyEndpointTelemetry
 	splits    observability.ObservableSplitStorage
 	segments  observability.ObservableSegmentStorage
+	httpCache common.HTTPCache
 }
 
 // Register mounts the controller endpoints onto the supplied router
@@ -50,16 +53,80 @@ func (c *ProxyObservabilityController) Register(router gin.IRouter) {
 }
 
 func (c *ProxyObservabilityController) observability(ctx *gin.Context) {
-	ctx.JSON(200, gin.H{
+	from, err := parseTimeParam(ctx.Query("from"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid 'from': %s", err)})
+		return
+	}
+	to, err := parseTimeParam(ctx.Query("to"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid 'to': %s", err)})
+		return
+	}
+
+	stats := c.telemetry.TimeslicedReport()
+	if from != 0 || to != 0 {
+		// time slices are keyed in seconds. `to` is rounded up so that the slice containing it is included
+		stats, err = c.telemetry.TimeslicedReportBetween(from/1000, (to+999)/1000)
+		if err != nil {
+			c.logger.Error("error fetching time sliced telemetry: ", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "error fetching time sliced telemetry"})
+			return
+		}
+	}
+
+	totals := c.telemetry.TotalMetricsReport()
+	if endpoints := parseEndpointsParam(ctx.Query("endpoints")); endpoints != nil {
+		for idx := range stats {
+			stats[idx].Resources = filterResources(stats[idx].Resources, endpoints)
+		}
+		totals = filterResources(totals, endpoints)
+	}
+
+	response := gin.H{
 		"activeSplits":            c.splits.SplitNames(),
 		"activeSegments":          c.segments.NamesAndCount(),
-		"proxyEndpointStats":      c.telemetry.TimeslicedReport(),
-		"proxyEndpointStatsTotal": c.telemetry.TotalMetricsReport(),
-	})
+		"proxyEndpointStats":      stats,
+		"proxyEndpointStatsTotal": totals,
+	}
+	if c.httpCache != nil {
+		response["httpCache"] = c.httpCache.Stats()
+	}
+	ctx.JSON(200, response)
+}
+
+// parseEndpointsParam splits a comma-separated list of resource names. Returns nil if none is supplied
+func parseEndpointsParam(raw string) map[string]struct{} {
+	var endpoints map[string]struct{}
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if endpoints == nil {
+				endpoints = make(map[string]struct{})
+			}
+			endpoints[name] = struct{}{}
+		}
+	}
+	return endpoints
+}
+
+func filterResources(resources map[string]pstorage.ForResource, endpoints map[string]struct{}) map[string]pstorage.ForResource {
+	filtered := make(map[string]pstorage.ForResource, len(endpoints))
+	for name, data := range resources {
+		if _, ok := endpoints[name]; ok {
+			filtered[name] = data
+		}
+	}
+	return filtered
 }
 
 // NewObservabilityController constructs and returns the appropriate struct dependeing on whether the app is split-proxy or split-sync
-func NewObservabilityController(proxy bool, logger logging.LoggerInterface, storagePack common.Storages) (ObservabilityController, error) {
+// The http cache is optional & only reported by the proxy
+func NewObservabilityController(
+	proxy bool,
+	logger logging.LoggerInterface,
+	storagePack common.Storages,
+	httpCache common.HTTPCache,
+) (ObservabilityController, error) {
 
 	splitStorage, ok := storagePack.SplitStorage.(observability.ObservableSplitStorage)
 	if !ok {
@@ -90,6 +157,7 @@ func NewObservabilityController(proxy bool, logger logging.LoggerInterface, stor
 		splits:    splitStorage,
 		segments:  segmentStorage,
 		telemetry: telemetry,
+		httpCache: httpCache,
 	}, nil
 
 }
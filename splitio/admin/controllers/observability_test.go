@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	pstorage "github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
+)
+
+type observableSplitStorageMock struct{ mocks.MockSplitStorage }
+
+func (m *observableSplitStorageMock) Count() int { return 0 }
+
+type observableSegmentStorageMock struct{ mocks.MockSegmentStorage }
+
+func (m *observableSegmentStorageMock) NamesAndCount() map[string]int { return map[string]int{} }
+
+func TestProxyObservabilityFilters(t *testing.T) {
+	dbw, err := persistent.NewBoltWrapper(persistent.BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+	logger := logging.NewLogger(nil)
+	collection := persistent.NewTelemetryTimeSliceCollection(dbw, logger)
+
+	// a slice from a previous run, no longer in memory
+	collection.Save([]persistent.TelemetryTimeSlice{{
+		TimeSlice: 1600000000,
+		Width:     60,
+		Endpoints: map[string]persistent.TelemetryTimeSliceEndpoint{"auth": {StatusCodes: map[int]int64{200: 3}}},
+	}})
+
+	telemetry := pstorage.NewTimeslicedProxyEndpointTelemetry(pstorage.NewProxyTelemetryFacade(), 60, 10)
+	telemetry.SetPersistentStorage(collection)
+	telemetry.IncrEndpointStatus(pstorage.SplitChangesEndpoint, 200)
+	telemetry.IncrEndpointStatus(pstorage.AuthEndpoint, 401)
+
+	ctrl := &ProxyObservabilityController{
+		logger:    logger,
+		telemetry: telemetry,
+		splits:    &observableSplitStorageMock{mocks.MockSplitStorage{SplitNamesCall: func() []string { return []string{} }}},
+		segments:  &observableSegmentStorageMock{},
+	}
+
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	type response struct {
+		Stats  pstorage.TimeSliceData          `json:"proxyEndpointStats"`
+		Totals map[string]pstorage.ForResource `json:"proxyEndpointStatsTotal"`
+	}
+	get := func(path string) (int, response) {
+		resp := httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		var parsed response
+		json.Unmarshal(resp.Body.Bytes(), &parsed)
+		return resp.Code, parsed
+	}
+
+	if code, res := get("/observability"); code != 200 || len(res.Stats) != 1 || len(res.Stats[0].Resources) != 13 || len(res.Totals) != 13 {
+		t.Error("only the in-memory slice should be returned with all endpoints. Got: ", code, res)
+	}
+
+	if code, res := get("/observability?from=1599999990000&to=1600000030000"); code != 200 || len(res.Stats) != 1 || res.Stats[0].TimeSlice != 1600000000 {
+		t.Error("only the persisted slice should be returned. Got: ", code, res)
+	} else if res.Stats[0].Resources["auth"].StatusCodes[200] != 3 {
+		t.Error("wrong persisted slice data: ", res.Stats[0])
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if code, res := get("/observability?endpoints=auth,%20splitChanges&from=1599999990000"); code != 200 || len(res.Stats) != 2 || len(res.Totals) != 2 {
+		t.Error("both slices should be returned with only 2 endpoints. Got: ", code, res)
+	} else if last := res.Stats[1]; len(last.Resources) != 2 || last.Resources["auth"].StatusCodes[401] != 1 || last.TimeSlice > now/1000 {
+		t.Error("wrong in-memory slice data: ", last)
+	}
+
+	for _, path := range []string{"/observability?from=yesterday", "/observability?to=abc"} {
+		if code, _ := get(path); code != 400 {
+			t.Error("status code should be 400 for ", path, ". Got: ", code)
+		}
+	}
+}
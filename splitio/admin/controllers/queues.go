@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+const (
+	queueImpressions = "impressions"
+	queueEvents      = "events"
+)
+
+// QueueStatus contains the state of a queue & the task evicting it
+type QueueStatus struct {
+	Paused bool  `json:"paused"`
+	Size   int64 `json:"size"`
+}
+
+// QueueController bundles endpoints used to pause, resume, flush & drop impressions/events queues
+type QueueController struct {
+	logger   logging.LoggerInterface
+	tasks    map[string]adminCommon.EvictionTask
+	counters map[string]func() int64
+	droppers map[string]adminCommon.QueueDropper
+}
+
+// NewQueueController constructs a new queue controller. Dropping is only enabled for storages implementing `QueueDropper`
+func NewQueueController(
+	logger logging.LoggerInterface,
+	impressionsTask adminCommon.EvictionTask,
+	eventsTask adminCommon.EvictionTask,
+	storages adminCommon.Storages,
+) *QueueController {
+	c := &QueueController{
+		logger:   logger,
+		tasks:    map[string]adminCommon.EvictionTask{queueImpressions: impressionsTask, queueEvents: eventsTask},
+		counters: make(map[string]func() int64),
+		droppers: make(map[string]adminCommon.QueueDropper),
+	}
+
+	if storages.ImpressionStorage != nil {
+		c.counters[queueImpressions] = storages.ImpressionStorage.Count
+		if dropper, ok := storages.ImpressionStorage.(adminCommon.QueueDropper); ok {
+			c.droppers[queueImpressions] = dropper
+		}
+	}
+
+	if storages.EventStorage != nil {
+		c.counters[queueEvents] = storages.EventStorage.Count
+		if dropper, ok := storages.EventStorage.(adminCommon.QueueDropper); ok {
+			c.droppers[queueEvents] = dropper
+		}
+	}
+
+	return c
+}
+
+// Register mounts the endpoints int he provided router
+func (c *QueueController) Register(router gin.IRouter) {
+	router.GET("/queues", c.status)
+	router.POST("/queues/:queue/pause", c.pause)
+	router.POST("/queues/:queue/resume", c.resume)
+	router.POST("/queues/:queue/flush", c.flush)
+	router.POST("/queues/:queue/drop", c.drop)
+}
+
+func (c *QueueController) status(ctx *gin.Context) {
+	result := make(map[string]QueueStatus, len(c.tasks))
+	for name, task := range c.tasks {
+		var status QueueStatus
+		if task != nil {
+			status.Paused = task.IsPaused()
+		}
+		if counter, ok := c.counters[name]; ok {
+			status.Size = counter()
+		}
+		result[name] = status
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (c *QueueController) pause(ctx *gin.Context) {
+	task, ok := c.getTask(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Warning(fmt.Sprintf("Eviction of %s paused from admin endpoint", ctx.Param("queue")))
+	task.Pause()
+	ctx.JSON(http.StatusOK, QueueStatus{Paused: task.IsPaused()})
+}
+
+func (c *QueueController) resume(ctx *gin.Context) {
+	task, ok := c.getTask(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Info(fmt.Sprintf("Eviction of %s resumed from admin endpoint", ctx.Param("queue")))
+	task.Resume()
+	ctx.JSON(http.StatusOK, QueueStatus{Paused: task.IsPaused()})
+}
+
+func (c *QueueController) flush(ctx *gin.Context) {
+	task, ok := c.getTask(ctx)
+	if !ok {
+		return
+	}
+
+	size, err := strconv.ParseInt(ctx.Query("size"), 10, 64)
+	if err != nil || size <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "size must be a positive integer"})
+		return
+	}
+
+	flushed, err := task.Flush(size)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("error flushing %s: ", ctx.Param("queue")), err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error flushing queue: %s", err)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"flushed": flushed})
+}
+
+func (c *QueueController) drop(ctx *gin.Context) {
+	name := ctx.Param("queue")
+	if _, ok := c.getTask(ctx); !ok {
+		return
+	}
+
+	dropper, ok := c.droppers[name]
+	if !ok {
+		ctx.JSON(http.StatusNotImplemented, gin.H{"error": fmt.Sprintf("dropping %s is not supported by the storage", name)})
+		return
+	}
+
+	// The queue name must be repeated in the confirm parameter to avoid accidentally dropping the wrong queue
+	if ctx.Query("confirm") != name {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("queue drop must be confirmed by passing confirm=%s", name)})
+		return
+	}
+
+	c.logger.Warning(fmt.Sprintf("Dropping %s queue as requested from admin endpoint", name))
+	if err := dropper.Drop(-1); err != nil {
+		c.logger.Error(fmt.Sprintf("error dropping %s: ", name), err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error dropping queue: %s", err)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"dropped": name})
+}
+
+func (c *QueueController) getTask(ctx *gin.Context) (adminCommon.EvictionTask, bool) {
+	name := ctx.Param("queue")
+	task, ok := c.tasks[name]
+	if !ok || task == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("unknown queue '%s'", name)})
+		return nil, false
+	}
+	return task, true
+}
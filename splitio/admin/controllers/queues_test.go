@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+type evictionTaskMock struct {
+	paused    bool
+	flushCall func(n int64) (int, error)
+}
+
+func (m *evictionTaskMock) Pause()                     { m.paused = true }
+func (m *evictionTaskMock) Resume()                    { m.paused = false }
+func (m *evictionTaskMock) IsPaused() bool             { return m.paused }
+func (m *evictionTaskMock) Flush(n int64) (int, error) { return m.flushCall(n) }
+
+type impressionQueueMock struct {
+	count    int64
+	dropCall func(size int64) error
+}
+
+func (m *impressionQueueMock) Count() int64                           { return m.count }
+func (m *impressionQueueMock) PopNRaw(int64) ([]string, int64, error) { return nil, 0, nil }
+func (m *impressionQueueMock) PopNWithMetadata(n int64) ([]dtos.ImpressionQueueObject, error) {
+	return nil, nil
+}
+func (m *impressionQueueMock) Drop(size int64) error { return m.dropCall(size) }
+
+func TestQueueController(t *testing.T) {
+	impTask := &evictionTaskMock{flushCall: func(n int64) (int, error) {
+		if n != 10 {
+			t.Error("flush size should be 10. Got: ", n)
+		}
+		return 7, nil
+	}}
+	evTask := &evictionTaskMock{}
+
+	var dropped int64
+	impStorage := &impressionQueueMock{count: 123, dropCall: func(size int64) error {
+		dropped = size
+		return nil
+	}}
+
+	ctrl := NewQueueController(logging.NewLogger(nil), impTask, evTask, adminCommon.Storages{ImpressionStorage: impStorage})
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	doRequest := func(path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodPost, path, nil)
+		router.ServeHTTP(resp, ctx.Request)
+		return resp
+	}
+
+	if resp := doRequest("/queues/impressions/pause"); resp.Code != 200 || !impTask.paused || evTask.paused {
+		t.Error("only impressions should be paused. Got: ", resp.Code, impTask.paused, evTask.paused)
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/queues", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	var status map[string]QueueStatus
+	if err := json.Unmarshal(resp.Body.Bytes(), &status); err != nil {
+		t.Error("there should be no error ", err)
+	}
+	if !status["impressions"].Paused || status["impressions"].Size != 123 || status["events"].Paused {
+		t.Error("wrong status: ", status)
+	}
+
+	if resp := doRequest("/queues/impressions/resume"); resp.Code != 200 || impTask.paused {
+		t.Error("impressions should be resumed. Got: ", resp.Code, impTask.paused)
+	}
+
+	if resp := doRequest("/queues/impressions/flush?size=10"); resp.Code != 200 || resp.Body.String() != `{"flushed":7}` {
+		t.Error("wrong flush response: ", resp.Code, resp.Body.String())
+	}
+
+	if resp := doRequest("/queues/impressions/flush?size=abc"); resp.Code != 400 {
+		t.Error("status code should be 400. Got: ", resp.Code)
+	}
+
+	if resp := doRequest("/queues/something/pause"); resp.Code != 404 {
+		t.Error("status code should be 404. Got: ", resp.Code)
+	}
+
+	if resp := doRequest("/queues/impressions/drop"); resp.Code != 400 || dropped != 0 {
+		t.Error("drop without confirmation should fail. Got: ", resp.Code)
+	}
+
+	if resp := doRequest("/queues/impressions/drop?confirm=events"); resp.Code != 400 || dropped != 0 {
+		t.Error("drop with wrong confirmation should fail. Got: ", resp.Code)
+	}
+
+	if resp := doRequest("/queues/impressions/drop?confirm=impressions"); resp.Code != 200 || dropped != -1 {
+		t.Error("the whole queue should be dropped. Got: ", resp.Code, dropped)
+	}
+
+	if resp := doRequest("/queues/events/drop?confirm=events"); resp.Code != 501 {
+		t.Error("status code should be 501 for storages that cannot drop. Got: ", resp.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-split-commons/v4/synchronizer"
+	"github.com/splitio/go-toolkit/v5/logging"
+	gtSync "github.com/splitio/go-toolkit/v5/sync"
+
+	cstorage "github.com/splitio/split-synchronizer/v5/splitio/common/storage"
+)
+
+// SyncResult is the response returned after a forced synchronization, containing the resulting change numbers
+type SyncResult struct {
+	Splits   *int64           `json:"splits,omitempty"`
+	Segments map[string]int64 `json:"segments,omitempty"`
+}
+
+// SyncController bundles endpoints used to force the synchronization of splits & segments
+type SyncController struct {
+	logger         logging.LoggerInterface
+	synchronizer   synchronizer.Synchronizer
+	splitStorage   storage.SplitStorageConsumer
+	segmentStorage storage.SegmentStorageConsumer
+	wiper          cstorage.DataWiper
+	inProgress     *gtSync.AtomicBool
+}
+
+// NewSyncController constructs a new sync controller
+func NewSyncController(
+	logger logging.LoggerInterface,
+	synchronizer synchronizer.Synchronizer,
+	splitStorage storage.SplitStorageConsumer,
+	segmentStorage storage.SegmentStorageConsumer,
+	wiper cstorage.DataWiper,
+) *SyncController {
+	return &SyncController{
+		logger:         logger,
+		synchronizer:   synchronizer,
+		splitStorage:   splitStorage,
+		segmentStorage: segmentStorage,
+		wiper:          wiper,
+		inProgress:     gtSync.NewAtomicBool(false),
+	}
+}
+
+// Register mounts the endpoints int he provided router
+func (c *SyncController) Register(router gin.IRouter) {
+	router.POST("/sync/splits", c.syncSplits)
+	router.POST("/sync/segments/:name", c.syncSegment)
+	if c.wiper != nil {
+		router.POST("/sync/full", c.fullResync)
+	}
+}
+
+func (c *SyncController) syncSplits(ctx *gin.Context) {
+	if !c.inProgress.TestAndSet() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "another synchronization is in progress"})
+		return
+	}
+	defer c.inProgress.Unset()
+
+	c.logger.Info("Split synchronization requested from admin endpoint")
+	if err := c.synchronizer.SynchronizeSplits(nil); err != nil {
+		c.logger.Error("error synchronizing splits: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error synchronizing splits: %s", err)})
+		return
+	}
+
+	cn, _ := c.splitStorage.ChangeNumber()
+	ctx.JSON(http.StatusOK, SyncResult{Splits: &cn})
+}
+
+func (c *SyncController) syncSegment(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if !c.splitStorage.SegmentNames().Has(name) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("segment '%s' is not referenced by any split", name)})
+		return
+	}
+
+	if !c.inProgress.TestAndSet() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "another synchronization is in progress"})
+		return
+	}
+	defer c.inProgress.Unset()
+
+	c.logger.Info(fmt.Sprintf("Synchronization of segment '%s' requested from admin endpoint", name))
+	if err := c.synchronizer.SynchronizeSegment(name, nil); err != nil {
+		c.logger.Error(fmt.Sprintf("error synchronizing segment '%s': ", name), err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error synchronizing segment: %s", err)})
+		return
+	}
+
+	cn, _ := c.segmentStorage.ChangeNumber(name)
+	ctx.JSON(http.StatusOK, SyncResult{Segments: map[string]int64{name: cn}})
+}
+
+func (c *SyncController) fullResync(ctx *gin.Context) {
+	if !c.inProgress.TestAndSet() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "another synchronization is in progress"})
+		return
+	}
+	defer c.inProgress.Unset()
+
+	c.logger.Warning("Full wipe & resync requested from admin endpoint")
+	if err := c.wiper.WipeAll(); err != nil {
+		c.logger.Error("error wiping storage: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error wiping storage: %s", err)})
+		return
+	}
+
+	if err := c.synchronizer.SyncAll(); err != nil {
+		c.logger.Error("error resynchronizing after wiping storage: ", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error synchronizing data: %s", err)})
+		return
+	}
+
+	cn, _ := c.splitStorage.ChangeNumber()
+	result := SyncResult{Splits: &cn, Segments: make(map[string]int64)}
+	for _, name := range c.splitStorage.SegmentNames().List() {
+		if strName, ok := name.(string); ok {
+			result.Segments[strName], _ = c.segmentStorage.ChangeNumber(strName)
+		}
+	}
+	ctx.JSON(http.StatusOK, result)
+}
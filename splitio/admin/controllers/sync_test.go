@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+	syncMocks "github.com/splitio/go-split-commons/v4/synchronizer/mocks"
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+type wiperMock struct {
+	wipeAllCall func() error
+}
+
+func (w *wiperMock) WipeAll() error { return w.wipeAllCall() }
+
+func TestSyncSplitsAndSegments(t *testing.T) {
+	var splitCN int64 = 10
+	splitStorage := &mocks.MockSplitStorage{
+		ChangeNumberCall: func() (int64, error) { return splitCN, nil },
+		SegmentNamesCall: func() *set.ThreadUnsafeSet { return set.NewSet("segment1") },
+	}
+	segmentStorage := &mocks.MockSegmentStorage{
+		ChangeNumberCall: func(name string) (int64, error) { return 20, nil },
+	}
+	synchronizer := &syncMocks.MockSynchronizer{
+		SynchronizeSplitsCall: func(till *int64) error {
+			splitCN = 15
+			return nil
+		},
+		SynchronizeSegmentCall: func(name string, till *int64) error {
+			if name != "segment1" {
+				t.Error("wrong segment name: ", name)
+			}
+			return nil
+		},
+	}
+
+	ctrl := NewSyncController(logging.NewLogger(nil), synchronizer, splitStorage, segmentStorage, nil)
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/splits", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("status code should be 200. Got: ", resp.Code)
+	}
+
+	var result SyncResult
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Error("there should be no error ", err)
+	}
+	if result.Splits == nil || *result.Splits != 15 {
+		t.Error("split change number should be 15. Got: ", result.Splits)
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/segments/segment1", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("status code should be 200. Got: ", resp.Code)
+	}
+
+	result = SyncResult{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Error("there should be no error ", err)
+	}
+	if result.Splits != nil || result.Segments["segment1"] != 20 {
+		t.Error("wrong result: ", result)
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/segments/unknown", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 404 {
+		t.Error("status code should be 404 for unreferenced segments. Got: ", resp.Code)
+	}
+
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/full", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 404 {
+		t.Error("full resync should not be available without a wiper. Got: ", resp.Code)
+	}
+
+	synchronizer.SynchronizeSplitsCall = func(till *int64) error { return errors.New("something") }
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/splits", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 500 {
+		t.Error("status code should be 500. Got: ", resp.Code)
+	}
+}
+
+func TestFullResync(t *testing.T) {
+	var calls []string
+	var splitCN int64 = 10
+	splitStorage := &mocks.MockSplitStorage{
+		ChangeNumberCall: func() (int64, error) { return splitCN, nil },
+		SegmentNamesCall: func() *set.ThreadUnsafeSet { return set.NewSet("segment1", "segment2") },
+	}
+	segmentStorage := &mocks.MockSegmentStorage{
+		ChangeNumberCall: func(name string) (int64, error) {
+			if name == "segment1" {
+				return 1, nil
+			}
+			return 2, nil
+		},
+	}
+	synchronizer := &syncMocks.MockSynchronizer{
+		SyncAllCall: func() error {
+			calls = append(calls, "sync")
+			splitCN = 30
+			return nil
+		},
+	}
+	wiper := &wiperMock{wipeAllCall: func() error {
+		calls = append(calls, "wipe")
+		splitCN = -1
+		return nil
+	}}
+
+	ctrl := NewSyncController(logging.NewLogger(nil), synchronizer, splitStorage, segmentStorage, wiper)
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+	ctrl.Register(router)
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/full", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("status code should be 200. Got: ", resp.Code)
+	}
+
+	if len(calls) != 2 || calls[0] != "wipe" || calls[1] != "sync" {
+		t.Error("storage should be wiped before synchronizing. Got: ", calls)
+	}
+
+	var result SyncResult
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Error("there should be no error ", err)
+	}
+	if result.Splits == nil || *result.Splits != 30 || result.Segments["segment1"] != 1 || result.Segments["segment2"] != 2 {
+		t.Error("wrong result: ", result)
+	}
+
+	calls = nil
+	wiper.wipeAllCall = func() error { return errors.New("something") }
+	resp = httptest.NewRecorder()
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/sync/full", nil)
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 500 {
+		t.Error("status code should be 500. Got: ", resp.Code)
+	}
+	if len(calls) != 0 {
+		t.Error("no sync should be attempted if wiping fails")
+	}
+}
@@ -0,0 +1,50 @@
+package dashboard
+
+const changeHistory = `
+{{define "ChangeHistory"}}
+  <div role="tabpanel" class="tab-pane" id="change-history">
+    <div class="row">
+      <div class="col-md-12">
+        <div class="bg-primary metricBox">
+          <div class="row">
+            <div class="col-md-2">
+              <select class="form-control" id="change_history_kind">
+                <option value="">Splits &amp; segments</option>
+                <option value="split">Splits</option>
+                <option value="segment">Segments</option>
+              </select>
+            </div>
+            <div class="col-md-4">
+              <input type="text" class="form-control" id="change_history_name" placeholder="Split or segment name">
+            </div>
+            <div class="col-md-2">
+              <input type="number" class="form-control" id="change_history_limit" min="1" max="1000" value="100">
+            </div>
+            <div class="col-md-2">
+              <button type="button" class="btn btn-default" onclick="refreshChangeHistory()">Search</button>
+            </div>
+          </div>
+        </div>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-12">
+        <table class="table table-condensed table-hover" id="change_history_rows">
+          <thead>
+            <tr>
+              <th>Date</th>
+              <th>Kind</th>
+              <th>Name</th>
+              <th>Action</th>
+              <th>Change number</th>
+              <th>Changes</th>
+            </tr>
+          </thead>
+          <tbody></tbody>
+        </table>
+      </div>
+    </div>
+  </div>
+{{end}}
+`
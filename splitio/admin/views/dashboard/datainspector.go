@@ -98,6 +98,22 @@ const dataInspector = `
             <div class="col-md-12">
               <div class="bg-primary metricBox">
                 <!-- <h4>Segments in proxy</h4> -->
+                <div class="row">
+                  <div class="col-md-4 col-md-offset-8">
+                    <div class="input-group">
+                      <input type="text" id="keySegmentsInput" class="form-control" placeholder="Find segments containing a key">
+                      <span class="input-group-btn">
+                        <button class="btn btn-default" type="button" onclick="javascript:getKeySegments();">
+  		        <span class="glyphicon glyphicon-search" aria-hidden="true"></span>
+  		      </button>
+                        <button class="btn btn-default" type="button" onclick="javascript:resetKeySegments();">
+  		        <span class="glyphicon glyphicon-remove" aria-hidden="true"></span>
+  		      </button>
+                      </span>
+                    </div>
+                    <p id="keySegmentsResult"></p>
+                  </div>
+                </div>
                 <table id="segment_rows" class="table table-condensed table-hover">
                   <thead>
                     <tr>
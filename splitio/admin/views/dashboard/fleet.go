@@ -0,0 +1,63 @@
+package dashboard
+
+const fleet = `
+{{define "Fleet"}}
+  <div role="tabpanel" class="tab-pane" id="fleet">
+    <div class="row">
+      <div class="col-md-12">
+        <div class="bg-primary metricBox">
+          <div class="row">
+            <div class="col-md-3">
+              <h4>Instances: <span id="fleet_instances_count">0</span></h4>
+            </div>
+            <div class="col-md-3">
+              <h4>Outdated: <span id="fleet_outdated_count">0</span></h4>
+            </div>
+            <div class="col-md-4">
+              <h4>Min versions: <span id="fleet_min_versions">none</span></h4>
+            </div>
+            <div class="col-md-2">
+              <div class="checkbox">
+                <label><input type="checkbox" id="fleet_outdated_only" onchange="refreshFleet()"> Outdated only</label>
+              </div>
+            </div>
+          </div>
+        </div>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-4">
+        <table class="table table-condensed table-hover" id="fleet_versions">
+          <thead>
+            <tr>
+              <th>Language</th>
+              <th>Version</th>
+              <th>Instances</th>
+              <th></th>
+            </tr>
+          </thead>
+          <tbody></tbody>
+        </table>
+      </div>
+      <div class="col-md-8">
+        <table class="table table-condensed table-hover" id="fleet_instances">
+          <thead>
+            <tr>
+              <th>SDK</th>
+              <th>Machine name</th>
+              <th>Machine IP</th>
+              <th>Impressions mode</th>
+              <th>Config</th>
+              <th>Activity</th>
+              <th>Rate (per min)</th>
+              <th>Last seen</th>
+            </tr>
+          </thead>
+          <tbody></tbody>
+        </table>
+      </div>
+    </div>
+  </div>
+{{end}}
+`
@@ -52,6 +52,28 @@ const mainScript = `
       })
     }
   
+    function getKeySegments(){
+      var key = $("#keySegmentsInput").val().trim();
+      if (key == "") {
+        return;
+      }
+      $("#keySegmentsResult").html("Looking up key...");
+      $.get("/admin/dashboard/keySegments/"+encodeURIComponent(key), function(data) {
+        if (data.length == 0) {
+          $("#keySegmentsResult").html("<b>" + escapeHTML(key) + "</b> is not part of any segment");
+          return;
+        }
+        $("#keySegmentsResult").html("<b>" + escapeHTML(key) + "</b> is part of: " + data.map(escapeHTML).join(", "));
+      }).fail(function() {
+        $("#keySegmentsResult").html("Error looking up key");
+      });
+    }
+  
+    function resetKeySegments(){
+      $("#keySegmentsInput").val("");
+      $("#keySegmentsResult").html("");
+    }
+  
     function filterSegmentKeys(segmentName){
       $("tr.segmentKeyItem").removeClass("filterDisplayNone");
       var filter = $("#filterSegmentKeyInput-"+segmentName).val();
@@ -308,6 +330,11 @@ const mainScript = `
     $('#impressions_lambda_section').html(stats.impressionsLambda);
     $('#events_queue_value_section').html(stats.eventsQueueSize);
     $('#events_lambda_section').html(stats.eventsLambda);
+    $('#impressions_sampled_section').html(stats.sampledImpressions);
+    $('#events_sampled_section').html(stats.sampledEvents);
+    const rules = Object.keys(stats.sampledByRule || {}).sort();
+    $('#sampling_rules_table').toggleClass('hidden', rules.length == 0);
+    $('#sampling_rules_rows').html(rules.map(rule => '<tr><td>' + rule + '</td><td>' + stats.sampledByRule[rule] + '</td></tr>').join(''));
     $('#uptime').html(stats.uptime);
     $('#logged_errors').html(stats.loggedErrors);
     $('#sdks_total_requests').html(stats.sdksTotalRequests);
@@ -322,10 +349,52 @@ const mainScript = `
     $('#events_lambda').html(stats.eventsLambda);
     $('#requests_ok').html(stats.requestsOk);
     $('#requests_error').html(stats.requestsErrored);
+    $('#requests_rate_limited').html(stats.rateLimited);
+    const limitRules = Object.keys(stats.rateLimitedByRule || {}).sort();
+    $('#rate_limit_rules_table').toggleClass('hidden', limitRules.length == 0);
+    $('#rate_limit_rules_rows').html(limitRules.map(rule => '<tr><td>' + rule + '</td><td>' + stats.rateLimitedByRule[rule] + '</td></tr>').join(''));
+    $('#rejected_payloads').html(stats.rejectedPayloads);
+    const rejectingSdks = Object.keys(stats.rejectedPayloadsBySdk || {}).sort();
+    $('#rejected_payloads_table').toggleClass('hidden', rejectingSdks.length == 0);
+    $('#rejected_payloads_rows').html(rejectingSdks.map(sdk => '<tr><td>' + escapeHTML(sdk) + '</td><td>' + stats.rejectedPayloadsBySdk[sdk] + '</td></tr>').join(''));
+    const legacySdks = Object.keys(stats.legacyTelemetryBySdk || {}).sort();
+    $('#legacy_telemetry_table').toggleClass('hidden', legacySdks.length == 0);
+    $('#legacy_telemetry_rows').html(legacySdks.map(sdk => '<tr><td>' + escapeHTML(sdk) + '</td><td>' + stats.legacyTelemetryBySdk[sdk] + '</td></tr>').join(''));
+    const clientKeys = stats.clientKeys || [];
+    $('#client_keys_table').toggleClass('hidden', clientKeys.length == 0);
+    $('#client_keys_rows').html(clientKeys.map(key => '<tr' + (key.active ? '' : ' class="text-muted"') + '><td>' + key.hint + '</td><td>' + escapeHTML(key.label) +
+      '</td><td>' + key.source + '</td><td>' + (key.active ? 'active' : 'inactive') + '</td><td>' + (key.expiresAt || '-') +
+      '</td><td>' + key.usage + '</td><td>' + (key.lastUsed || '-') + '</td></tr>').join(''));
+    updateHTTPCache(stats.httpCache);
     $('#backend_requests_ok').html(stats.backendRequestsOk);
     $('#backend_requests_error').html(stats.backendRequestsErrored);
   };
 
+  function formatBytes(bytes) {
+    const units = ['B', 'KB', 'MB', 'GB'];
+    let idx = 0;
+    while (bytes >= 1024 && idx < units.length - 1) {
+      bytes /= 1024;
+      idx++;
+    }
+    return (idx == 0 ? bytes : bytes.toFixed(1)) + ' ' + units[idx];
+  }
+
+  function updateHTTPCache(cache) {
+    $('#http_cache_section').toggleClass('hidden', cache == null);
+    if (cache == null) {
+      return;
+    }
+    $('#http_cache_entries').html(cache.entries + (cache.maxEntries > 0 ? ' / ' + cache.maxEntries : ''));
+    $('#http_cache_usage').html(formatBytes(cache.bytes) + (cache.maxBytes > 0 ? ' / ' + formatBytes(cache.maxBytes) : ''));
+    $('#http_cache_rows').html((cache.families || []).map(family => {
+      const lookups = family.hits + family.misses;
+      const ratio = lookups > 0 ? (100 * family.hits / lookups).toFixed(1) + '%' : '-';
+      return '<tr><td>' + family.name + '</td><td>' + family.hits + '</td><td>' + family.misses + '</td><td>' + ratio +
+        '</td><td>' + family.evictions + '</td><td>' + family.purged + '</td><td>' + family.entries + '</td><td>' + formatBytes(family.bytes) + '</td></tr>';
+    }).join(''));
+  }
+
   function updateHealthCards(health) {
       if (health.healthySince != null) {
         const dateHealthy = new Date(Date.parse(health.healthySince)).toLocaleString()
@@ -416,6 +485,136 @@ const mainScript = `
     $.getJSON("/admin/dashboard/stats", processStats);
   };
 
+  {{if not .ProxyMode}}
+  function updateQueueStatus(status) {
+    ['impressions', 'events'].forEach(function(queue) {
+      if (!status[queue]) return;
+      const paused = status[queue].paused;
+      $('#' + queue + '_queue_status').text(paused ? 'PAUSED' : 'RUNNING')
+        .toggleClass('label-warning', paused)
+        .toggleClass('label-success', !paused);
+      $('#' + queue + '_queue_toggle').text(paused ? 'Resume' : 'Pause');
+    });
+  };
+
+  function refreshQueueStatus() {
+    $.getJSON("/admin/queues", updateQueueStatus);
+  };
+
+  function toggleQueue(queue) {
+    const action = $('#' + queue + '_queue_toggle').text() == 'Resume' ? 'resume' : 'pause';
+    $.post("/admin/queues/" + queue + "/" + action, refreshQueueStatus);
+  };
+
+  function flushQueue(queue) {
+    const size = parseInt($('#' + queue + '_queue_flush_size').val(), 10);
+    $.post("/admin/queues/" + queue + "/flush?size=" + size, function(data) {
+      console.log("Flushed " + data.flushed + " " + queue);
+      refreshStats();
+    }).fail(function(xhr) { alert(xhr.responseJSON ? xhr.responseJSON.error : "error flushing " + queue); });
+  };
+
+  function dropQueue(queue) {
+    const confirmation = prompt("All " + queue + " in the queue will be lost. Type '" + queue + "' to confirm.");
+    if (confirmation != queue) return;
+    $.post("/admin/queues/" + queue + "/drop?confirm=" + encodeURIComponent(confirmation), function() {
+      refreshStats();
+    }).fail(function(xhr) { alert(xhr.responseJSON ? xhr.responseJSON.error : "error dropping " + queue); });
+  };
+  {{end}}
+
+  function escapeHTML(text) {
+    return $('<div>').text(text === undefined || text === null ? '' : String(text)).html();
+  };
+
+  {{if .ChangeHistory}}
+  function formatChange(entry) {
+    if (entry.kind == 'segment') {
+      return '+' + (entry.keysAdded || 0) + ' / -' + (entry.keysRemoved || 0) + ' keys';
+    }
+    return (entry.changes || []).map(function(change) {
+      return '<div><b>' + escapeHTML(change.field) + '</b>: <del>' + escapeHTML(change.before) + '</del> &rarr; ' + escapeHTML(change.after) + '</div>';
+    }).join('');
+  };
+
+  function updateChangeHistory(entries) {
+    $('#change_history_rows tbody').html(entries.map(function(entry) {
+      return '<tr>' +
+        '<td>' + new Date(entry.timestamp).toISOString() + '</td>' +
+        '<td>' + escapeHTML(entry.kind) + '</td>' +
+        '<td>' + escapeHTML(entry.name) + '</td>' +
+        '<td>' + escapeHTML(entry.action) + '</td>' +
+        '<td>' + entry.changeNumber + '</td>' +
+        '<td>' + formatChange(entry) + '</td>' +
+      '</tr>';
+    }).join(''));
+  };
+
+  function refreshChangeHistory() {
+    $.getJSON("/admin/changes", {
+      kind: $('#change_history_kind').val(),
+      name: $('#change_history_name').val(),
+      limit: $('#change_history_limit').val(),
+    }, updateChangeHistory);
+  };
+  {{end}}
+
+  {{if .Fleet}}
+  function formatActivity(activity) {
+    return Object.keys(activity || {}).sort().map(function(kind) {
+      return escapeHTML(kind) + ': ' + activity[kind];
+    }).join('<br>');
+  };
+
+  function formatFleetConfig(instance) {
+    if (!instance.config) {
+      return '-';
+    }
+    return escapeHTML(instance.config.operationMode) +
+      (instance.config.storage ? ' (' + escapeHTML(instance.config.storage) + ')' : '') +
+      (instance.config.streamingEnabled ? ', streaming' : ', polling') +
+      (instance.config.impressionListener ? ', impression listener' : '') +
+      (instance.config.httpProxy ? ', http proxy' : '');
+  };
+
+  function updateFleet(summary) {
+    var minVersions = Object.keys(summary.minVersions || {}).sort().map(function(language) {
+      return escapeHTML(language) + ' &ge; ' + escapeHTML(summary.minVersions[language]);
+    });
+    $('#fleet_min_versions').html(minVersions.length > 0 ? minVersions.join(', ') : 'none');
+    $('#fleet_instances_count').html(summary.instances.length);
+    $('#fleet_outdated_count').html(summary.outdated);
+
+    $('#fleet_versions tbody').html(summary.versions.map(function(version) {
+      return '<tr' + (version.outdated ? ' class="danger"' : '') + '>' +
+        '<td>' + escapeHTML(version.language) + '</td>' +
+        '<td>' + escapeHTML(version.version) + '</td>' +
+        '<td>' + version.instances + '</td>' +
+        '<td>' + (version.outdated ? '<span class="label label-danger">Outdated</span>' : '') + '</td>' +
+      '</tr>';
+    }).join(''));
+
+    $('#fleet_instances tbody').html(summary.instances.map(function(instance) {
+      return '<tr' + (instance.outdated ? ' class="danger"' : '') + '>' +
+        '<td>' + escapeHTML(instance.sdkVersion) + '</td>' +
+        '<td>' + escapeHTML(instance.machineName) + '</td>' +
+        '<td>' + escapeHTML(instance.machineIp) + '</td>' +
+        '<td>' + escapeHTML(instance.impressionsMode || '-') + '</td>' +
+        '<td>' + formatFleetConfig(instance) + '</td>' +
+        '<td>' + formatActivity(instance.activity) + '</td>' +
+        '<td>' + instance.ratePerMinute.toFixed(2) + '</td>' +
+        '<td>' + new Date(instance.lastSeen).toISOString() + '</td>' +
+      '</tr>';
+    }).join(''));
+  };
+
+  function refreshFleet() {
+    $.getJSON("/admin/fleet", {
+      outdated: $('#fleet_outdated_only').is(':checked') ? 'true' : '',
+    }, updateFleet);
+  };
+  {{end}}
+
   function refreshHealth() {
     $.ajax({
 	dataType: "json",
@@ -448,11 +647,26 @@ const mainScript = `
   
     processStats(initialData.stats);
     updateHealthCards(initialData.health);
+    {{if not .ProxyMode}}
+    refreshQueueStatus();
+    {{end}}
+    {{if .ChangeHistory}}
+    refreshChangeHistory();
+    {{end}}
+    {{if .Fleet}}
+    refreshFleet();
+    {{end}}
 
   
     setInterval(function() {
       refreshStats();
       refreshHealth();
+      {{if not .ProxyMode}}
+      refreshQueueStatus();
+      {{end}}
+      {{if .Fleet}}
+      refreshFleet();
+      {{end}}
     }, {{.RefreshTime}});
   });
 
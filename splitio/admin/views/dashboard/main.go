@@ -67,6 +67,8 @@ const main = `
       {{if .ProxyMode}}{{template "SdkStats" .}}{{end}}
       {{if not .ProxyMode}}{{template "QueueManager" .}}{{end}}
       {{template "DataInspector" .}}
+      {{if .ChangeHistory}}{{template "ChangeHistory" .}}{{end}}
+      {{if .Fleet}}{{template "Fleet" .}}{{end}}
     </div>
   </div>
    {{template "MainScript" .}}
@@ -79,6 +81,8 @@ type RootObject struct {
 	DashboardTitle string
 	Version        string
 	ProxyMode      bool
+	ChangeHistory  bool
+	Fleet          bool
 	RefreshTime    int64
 	Stats          GlobalStats           `json:"stats"`
 	Health         application.HealthDto `json:"health"`
@@ -87,23 +91,65 @@ type RootObject struct {
 
 // GlobalStats runtime stats used to render the dashboard
 type GlobalStats struct {
-	BackendTotalRequests   int64            `json:"backendTotalRequests"`
-	RequestsOk             int64            `json:"requestsOk"`
-	RequestsErrored        int64            `json:"requestsErrored"`
-	BackendRequestsOk      int64            `json:"backendRequestsOk"`
-	BackendRequestsErrored int64            `json:"backendRequestsErrored"`
-	SdksTotalRequests      int64            `json:"sdksTotalRequests"`
-	LoggedErrors           int64            `json:"loggedErrors"`
-	LoggedMessages         []string         `json:"loggedMessages"`
-	Splits                 []SplitSummary   `json:"splits"`
-	Segments               []SegmentSummary `json:"segments"`
-	Latencies              []ChartJSData    `json:"latencies"`
-	BackendLatencies       []ChartJSData    `json:"backendLatencies"`
-	ImpressionsQueueSize   int64            `json:"impressionsQueueSize"`
-	ImpressionsLambda      float64          `json:"impressionsLambda"`
-	EventsQueueSize        int64            `json:"eventsQueueSize"`
-	EventsLambda           float64          `json:"eventsLambda"`
-	Uptime                 int64            `json:"uptime"`
+	BackendTotalRequests   int64              `json:"backendTotalRequests"`
+	RequestsOk             int64              `json:"requestsOk"`
+	RequestsErrored        int64              `json:"requestsErrored"`
+	BackendRequestsOk      int64              `json:"backendRequestsOk"`
+	BackendRequestsErrored int64              `json:"backendRequestsErrored"`
+	SdksTotalRequests      int64              `json:"sdksTotalRequests"`
+	LoggedErrors           int64              `json:"loggedErrors"`
+	LoggedMessages         []string           `json:"loggedMessages"`
+	Splits                 []SplitSummary     `json:"splits"`
+	Segments               []SegmentSummary   `json:"segments"`
+	Latencies              []ChartJSData      `json:"latencies"`
+	BackendLatencies       []ChartJSData      `json:"backendLatencies"`
+	ImpressionsQueueSize   int64              `json:"impressionsQueueSize"`
+	ImpressionsLambda      float64            `json:"impressionsLambda"`
+	EventsQueueSize        int64              `json:"eventsQueueSize"`
+	EventsLambda           float64            `json:"eventsLambda"`
+	SampledImpressions     int64              `json:"sampledImpressions"`
+	SampledEvents          int64              `json:"sampledEvents"`
+	SampledByRule          map[string]int64   `json:"sampledByRule"`
+	RateLimited            int64              `json:"rateLimited"`
+	RateLimitedByRule      map[string]int64   `json:"rateLimitedByRule"`
+	RejectedPayloads       int64              `json:"rejectedPayloads"`
+	RejectedPayloadsBySDK  map[string]int64   `json:"rejectedPayloadsBySdk"`
+	LegacyTelemetryBySDK   map[string]int64   `json:"legacyTelemetryBySdk"`
+	ClientKeys             []ClientKeySummary `json:"clientKeys"`
+	HTTPCache              *HTTPCacheSummary  `json:"httpCache"`
+	Uptime                 int64              `json:"uptime"`
+}
+
+// HTTPCacheSummary encapsulates a view of the proxy's response cache usage
+type HTTPCacheSummary struct {
+	Entries    int64                `json:"entries"`
+	Bytes      int64                `json:"bytes"`
+	MaxEntries int64                `json:"maxEntries"`
+	MaxBytes   int64                `json:"maxBytes"`
+	Families   []HTTPCacheFamilyRow `json:"families"`
+}
+
+// HTTPCacheFamilyRow encapsulates the cache counters of a family of endpoints
+type HTTPCacheFamilyRow struct {
+	Name      string `json:"name"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+	Evictions int64  `json:"evictions"`
+	Purged    int64  `json:"purged"`
+	Entries   int64  `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// ClientKeySummary encapsulates a view of the apikeys sdks use to connect to the proxy, along with their usage
+type ClientKeySummary struct {
+	ID        string `json:"id"`
+	Hint      string `json:"hint"`
+	Label     string `json:"label"`
+	Source    string `json:"source"`
+	Active    bool   `json:"active"`
+	Usage     int64  `json:"usage"`
+	LastUsed  string `json:"lastUsed"`
+	ExpiresAt string `json:"expiresAt"`
 }
 
 // SplitSummary encapsulates a minimalistic view of split properties to be presented in the dashboard
@@ -175,6 +221,8 @@ func AssembleDashboardTemplate() (*template.Template, error) {
 		upstreamStats,
 		queueManager,
 		dataInspector,
+		changeHistory,
+		fleet,
 		menu,
 		mainScript,
 		// Main layout
@@ -32,6 +32,20 @@ const menu = `
         <span class="glyphicon glyphicon-search" aria-hidden="true"></span>&nbsp;Data inspector
       </a>
     </li>
+    {{if .ChangeHistory}}
+      <li role="presentation">
+        <a href="#change-history" aria-controls="change-history" role="tab" data-toggle="tab">
+	  <span class="glyphicon glyphicon-time" aria-hidden="true"></span>&nbsp;Change history
+	</a>
+      </li>
+    {{end}}
+    {{if .Fleet}}
+      <li role="presentation">
+        <a href="#fleet" aria-controls="fleet" role="tab" data-toggle="tab">
+	  <span class="glyphicon glyphicon-th-list" aria-hidden="true"></span>&nbsp;SDK fleet
+	</a>
+      </li>
+    {{end}}
   </ul>
 {{end}}
 `
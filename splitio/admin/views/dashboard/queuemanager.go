@@ -33,6 +33,55 @@ const queueManager = `
         </div>
       </div>
     </div>
+
+    <div class="row">
+      <div class="col-md-6">
+        <div class="gray1Box metricBox">
+          <h4>Impressions Sampled Out</h4>
+          <h1 id="impressions_sampled_section" class="centerText"></h1>
+        </div>
+      </div>
+      <div class="col-md-6">
+        <div class="gray1Box metricBox">
+          <h4>Events Sampled Out</h4>
+          <h1 id="events_sampled_section" class="centerText"></h1>
+        </div>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-12">
+        <table class="table table-condensed table-hover" id="sampling_rules_table">
+          <thead><tr><th>Sampling rule</th><th>Sampled out</th></tr></thead>
+          <tbody id="sampling_rules_rows"></tbody>
+        </table>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-6">
+        <div class="gray1Box metricBox">
+          <h4>Impressions Eviction <span id="impressions_queue_status" class="label label-default"></span></h4>
+          <div class="centerText">
+            <button type="button" class="btn btn-default" onclick="toggleQueue('impressions')" id="impressions_queue_toggle">Pause</button>
+            <input type="number" min="1" value="500" id="impressions_queue_flush_size" style="width: 90px;"/>
+            <button type="button" class="btn btn-default" onclick="flushQueue('impressions')">Flush</button>
+            <button type="button" class="btn btn-danger" onclick="dropQueue('impressions')">Drop queue</button>
+          </div>
+        </div>
+      </div>
+      <div class="col-md-6">
+        <div class="gray1Box metricBox">
+          <h4>Events Eviction <span id="events_queue_status" class="label label-default"></span></h4>
+          <div class="centerText">
+            <button type="button" class="btn btn-default" onclick="toggleQueue('events')" id="events_queue_toggle">Pause</button>
+            <input type="number" min="1" value="500" id="events_queue_flush_size" style="width: 90px;"/>
+            <button type="button" class="btn btn-default" onclick="flushQueue('events')">Flush</button>
+            <button type="button" class="btn btn-danger" onclick="dropQueue('events')">Drop queue</button>
+          </div>
+        </div>
+      </div>
+    </div>
     </br>
     </br>
     </br>
@@ -307,6 +307,70 @@ const sdkStats = `
         </div>
       </div>
     </div>
+
+    <div class="row">
+      <div class="col-md-4">
+        <div class="gray1Box metricBox">
+          <h4>Rate-limited Requests</h4>
+          <h1 id="requests_rate_limited" class="centerText"></h1>
+        </div>
+      </div>
+      <div class="col-md-8">
+        <table class="table table-condensed table-hover" id="rate_limit_rules_table">
+          <thead><tr><th>Rate limit rule</th><th>Rejected</th></tr></thead>
+          <tbody id="rate_limit_rules_rows"></tbody>
+        </table>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-4">
+        <div class="gray1Box metricBox">
+          <h4>Rejected Payloads</h4>
+          <h1 id="rejected_payloads" class="centerText"></h1>
+        </div>
+      </div>
+      <div class="col-md-8">
+        <table class="table table-condensed table-hover" id="rejected_payloads_table">
+          <thead><tr><th>SDK version</th><th>Rejected</th></tr></thead>
+          <tbody id="rejected_payloads_rows"></tbody>
+        </table>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-12">
+        <table class="table table-condensed table-hover" id="legacy_telemetry_table">
+          <thead><tr><th>SDK version (legacy metrics)</th><th>Calls</th></tr></thead>
+          <tbody id="legacy_telemetry_rows"></tbody>
+        </table>
+      </div>
+    </div>
+
+    <div class="row">
+      <div class="col-md-12">
+        <table class="table table-condensed table-hover" id="client_keys_table">
+          <thead><tr><th>Client apikey</th><th>Label</th><th>Source</th><th>Status</th><th>Expires</th><th>Requests</th><th>Last used</th></tr></thead>
+          <tbody id="client_keys_rows"></tbody>
+        </table>
+      </div>
+    </div>
+
+    <div class="row hidden" id="http_cache_section">
+      <div class="col-md-4">
+        <div class="gray1Box metricBox">
+          <h4>HTTP Cache</h4>
+          <h1 id="http_cache_entries" class="centerText"></h1>
+          <p id="http_cache_usage" class="centerText"></p>
+        </div>
+      </div>
+      <div class="col-md-8">
+        <table class="table table-condensed table-hover">
+          <thead><tr><th>Family</th><th>Hits</th><th>Misses</th><th>Hit ratio</th><th>Evictions</th><th>Purged</th><th>Entries</th><th>Size</th></tr></thead>
+          <tbody id="http_cache_rows"></tbody>
+        </table>
+      </div>
+    </div>
   </div>
 {{end}}
 `
@@ -0,0 +1,35 @@
+package changehook
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+)
+
+// BuildFromConfig constructs a dispatcher for the configured endpoints. Returns nil if no endpoint is configured
+func BuildFromConfig(cfg *conf.ChangeWebhook, splitStorage storage.SplitStorageConsumer, logger logging.LoggerInterface) (*Dispatcher, error) {
+	var endpoints []string
+	for _, endpoint := range cfg.Endpoints {
+		if endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	return NewDispatcher(Options{
+		Endpoints:          endpoints,
+		Secret:             cfg.Secret,
+		IncludeDefinitions: cfg.IncludeDefinitions,
+		MaxSegmentKeys:     cfg.MaxSegmentKeys,
+		MaxRetries:         cfg.MaxRetries,
+		RetryBackoff:       time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		QueueSize:          cfg.QueueSize,
+		HTTPClient:         &http.Client{Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+	}, splitStorage, logger)
+}
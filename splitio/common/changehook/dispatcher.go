@@ -0,0 +1,382 @@
+package changehook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/go-toolkit/v5/struct/traits/lifecycle"
+)
+
+// Event types
+const (
+	EventSplits   = "splits.updated"
+	EventSegments = "segments.updated"
+)
+
+// Headers sent along with every delivery
+const (
+	HeaderEvent     = "X-Split-Event"
+	HeaderDelivery  = "X-Split-Delivery"
+	HeaderTimestamp = "X-Split-Timestamp"
+	HeaderSignature = "X-Split-Signature"
+)
+
+const (
+	defaultMaxSegmentKeys = 10000
+	defaultDrainTimeout   = 5 * time.Second
+)
+
+// ErrNoEndpoints is returned when attempting to construct a dispatcher without endpoints
+var ErrNoEndpoints = errors.New("at least one endpoint is required")
+
+// ErrInvalidQueueSize is returned when attempting to construct a dispatcher with an invalid queue size
+var ErrInvalidQueueSize = errors.New("queue size must be at least 1")
+
+// ErrAlreadyRunning is returned when attempting to start an already running dispatcher
+var ErrAlreadyRunning = errors.New("dispatcher is already running")
+
+// ErrNotRunning is returned when attempting to stop a non-running dispatcher
+var ErrNotRunning = errors.New("dispatcher is not running")
+
+// SplitChange describes an updated split
+type SplitChange struct {
+	Name         string         `json:"name"`
+	Status       string         `json:"status"`
+	ChangeNumber int64          `json:"changeNumber"`
+	Definition   *dtos.SplitDTO `json:"definition,omitempty"`
+}
+
+// SegmentChange describes an updated segment. When the number of updated keys exceeds the configured max, only the first
+// ones are included and KeysTruncated is set
+type SegmentChange struct {
+	Name          string   `json:"name"`
+	ChangeNumber  int64    `json:"changeNumber"`
+	UpdatedKeys   int      `json:"updatedKeys"`
+	Keys          []string `json:"keys,omitempty"`
+	KeysTruncated bool     `json:"keysTruncated,omitempty"`
+}
+
+// Event is the payload posted to the endpoints
+type Event struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	Timestamp    int64           `json:"timestamp"`
+	ChangeNumber int64           `json:"changeNumber"`
+	Splits       []SplitChange   `json:"splits,omitempty"`
+	Segments     []SegmentChange `json:"segments,omitempty"`
+}
+
+// Options for the dispatcher
+type Options struct {
+	Endpoints          []string
+	Secret             string
+	IncludeDefinitions bool
+	MaxSegmentKeys     int // max number of segment keys included in an event. Defaults to 10000
+	MaxRetries         int
+	RetryBackoff       time.Duration
+	QueueSize          int
+	DrainTimeout       time.Duration // max time spent delivering queued events when stopping. Defaults to 5 seconds
+	HTTPClient         *http.Client
+}
+
+// Stats contains the amount of events submitted to the dispatcher & how they ended up
+type Stats struct {
+	Submitted int64 `json:"submitted"`
+	Dropped   int64 `json:"dropped"`
+	Delivered int64 `json:"delivered"`
+	Retried   int64 `json:"retried"`
+	Failed    int64 `json:"failed"`
+}
+
+// Dispatcher posts split & segment changes to a set of endpoints in the background
+type Dispatcher struct {
+	lifecycle          lifecycle.Manager
+	endpoints          []string
+	secret             []byte
+	includeDefinitions bool
+	maxSegmentKeys     int
+	maxRetries         int
+	retryBackoff       time.Duration
+	drainTimeout       time.Duration
+	httpClient         *http.Client
+	splitStorage       storage.SplitStorageConsumer
+	logger             logging.LoggerInterface
+	queue              chan Event
+	sequence           uint64
+	submitted          int64
+	dropped            int64
+	delivered          int64
+	retried            int64
+	failed             int64
+}
+
+// NewDispatcher constructs a new change dispatcher. The split storage is used to fetch the status (and optionally the
+// definition) of updated splits
+func NewDispatcher(opts Options, splitStorage storage.SplitStorageConsumer, logger logging.LoggerInterface) (*Dispatcher, error) {
+	if len(opts.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	if opts.QueueSize < 1 {
+		return nil, ErrInvalidQueueSize
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if opts.MaxSegmentKeys <= 0 {
+		opts.MaxSegmentKeys = defaultMaxSegmentKeys
+	}
+
+	if opts.DrainTimeout <= 0 {
+		opts.DrainTimeout = defaultDrainTimeout
+	}
+
+	dispatcher := &Dispatcher{
+		endpoints:          opts.Endpoints,
+		secret:             []byte(opts.Secret),
+		includeDefinitions: opts.IncludeDefinitions,
+		maxSegmentKeys:     opts.MaxSegmentKeys,
+		maxRetries:         opts.MaxRetries,
+		retryBackoff:       opts.RetryBackoff,
+		drainTimeout:       opts.DrainTimeout,
+		httpClient:         opts.HTTPClient,
+		splitStorage:       splitStorage,
+		logger:             logger,
+		queue:              make(chan Event, opts.QueueSize),
+	}
+	dispatcher.lifecycle.Setup()
+	return dispatcher, nil
+}
+
+// SplitsChanged queues a notification for the supplied splits. Splits no longer present in the storage are reported as archived
+func (d *Dispatcher) SplitsChanged(names []string, changeNumber int64) {
+	names = dedupe(names)
+	if len(names) == 0 {
+		return
+	}
+
+	current := d.splitStorage.FetchMany(names)
+	changes := make([]SplitChange, 0, len(names))
+	for _, name := range names {
+		change := SplitChange{Name: name, Status: "ARCHIVED", ChangeNumber: changeNumber}
+		if split := current[name]; split != nil {
+			change.Status = split.Status
+			change.ChangeNumber = split.ChangeNumber
+			if d.includeDefinitions {
+				change.Definition = split
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	d.submit(Event{Type: EventSplits, ChangeNumber: changeNumber, Splits: changes})
+}
+
+// SegmentChanged queues a notification for a segment whose keys were updated
+func (d *Dispatcher) SegmentChanged(name string, changeNumber int64, updatedKeys []string) {
+	change := SegmentChange{Name: name, ChangeNumber: changeNumber, UpdatedKeys: len(updatedKeys)}
+	if d.includeDefinitions {
+		change.Keys = updatedKeys
+		if len(updatedKeys) > d.maxSegmentKeys {
+			change.Keys, change.KeysTruncated = updatedKeys[:d.maxSegmentKeys], true
+		}
+	}
+
+	d.submit(Event{Type: EventSegments, ChangeNumber: changeNumber, Segments: []SegmentChange{change}})
+}
+
+// Stats returns the number of events submitted, dropped because of a full queue, delivered & failed since startup
+func (d *Dispatcher) Stats() Stats {
+	return Stats{
+		Submitted: atomic.LoadInt64(&d.submitted),
+		Dropped:   atomic.LoadInt64(&d.dropped),
+		Delivered: atomic.LoadInt64(&d.delivered),
+		Retried:   atomic.LoadInt64(&d.retried),
+		Failed:    atomic.LoadInt64(&d.failed),
+	}
+}
+
+// Start the bg task that will take events from the queue and post them
+func (d *Dispatcher) Start() error {
+	if !d.lifecycle.BeginInitialization() {
+		return ErrAlreadyRunning
+	}
+
+	go func() {
+		defer d.lifecycle.ShutdownComplete()
+		if !d.lifecycle.InitializationComplete() {
+			d.drain()
+			return
+		}
+
+		for {
+			select {
+			case <-d.lifecycle.ShutdownRequested():
+				d.drain()
+				return
+			case event := <-d.queue:
+				d.deliver(context.Background(), &event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// drain delivers the events still queued, without retries, until the queue is empty or the drain timeout expires
+func (d *Dispatcher) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), d.drainTimeout)
+	defer cancel()
+	for {
+		select {
+		case event := <-d.queue:
+			if ctx.Err() != nil {
+				d.discardQueued(1)
+				return
+			}
+			d.deliver(ctx, &event)
+		default:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) discardQueued(alreadyTaken int64) {
+	dropped := alreadyTaken
+	for len(d.queue) > 0 {
+		<-d.queue
+		dropped++
+	}
+	atomic.AddInt64(&d.dropped, dropped)
+	d.logger.Warning(fmt.Sprintf("change webhook drain timed out, dropping %d queued events", dropped))
+}
+
+// Stop the bg task. Queued events are delivered for up to the drain timeout, pending retries are aborted
+func (d *Dispatcher) Stop(blocking bool) error {
+	if !d.lifecycle.BeginShutdown() {
+		return ErrNotRunning
+	}
+
+	if blocking {
+		d.lifecycle.AwaitShutdownComplete()
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) submit(event Event) {
+	atomic.AddInt64(&d.submitted, 1)
+	event.ID = strconv.FormatUint(atomic.AddUint64(&d.sequence, 1), 10) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	event.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	select {
+	case d.queue <- event:
+	default:
+		atomic.AddInt64(&d.dropped, 1)
+		d.logger.Warning(fmt.Sprintf("change webhook queue is full, dropping %s event", event.Type))
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.logger.Error("error serializing change event: ", err)
+		atomic.AddInt64(&d.failed, 1)
+		return
+	}
+
+	for _, endpoint := range d.endpoints {
+		if err := d.postWithRetries(ctx, endpoint, event, body); err != nil {
+			atomic.AddInt64(&d.failed, 1)
+			d.logger.Error(fmt.Sprintf("error posting %s event to %s: %s", event.Type, endpoint, err))
+			continue
+		}
+		atomic.AddInt64(&d.delivered, 1)
+	}
+}
+
+func (d *Dispatcher) postWithRetries(ctx context.Context, endpoint string, event *Event, body []byte) error {
+	backoff := d.retryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := d.post(ctx, endpoint, event, body)
+		if err == nil || !retryable || attempt >= d.maxRetries {
+			return err
+		}
+
+		atomic.AddInt64(&d.retried, 1)
+		d.logger.Debug(fmt.Sprintf("retrying %s event delivery to %s in %s: %s", event.Type, endpoint, backoff, err))
+		select {
+		case <-d.lifecycle.ShutdownRequested():
+			return fmt.Errorf("shutdown requested while retrying: %w", err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends the event & returns whether the request can be retried in case it fails
+func (d *Dispatcher) post(ctx context.Context, endpoint string, event *Event, body []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, event.Type)
+	request.Header.Set(HeaderDelivery, event.ID)
+	request.Header.Set(HeaderTimestamp, timestamp)
+	if len(d.secret) > 0 {
+		request.Header.Set(HeaderSignature, "sha256="+Sign(d.secret, timestamp, body))
+	}
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+	response.Body.Close()
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return true, fmt.Errorf("endpoint returned status code %d", response.StatusCode)
+	default:
+		return false, fmt.Errorf("endpoint returned status code %d", response.StatusCode)
+	}
+}
+
+// Sign computes the hex-encoded HMAC-SHA256 of `<timestamp>.<body>` using the supplied secret
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,281 @@
+package changehook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/segment"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/split"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+type delivery struct {
+	headers http.Header
+	event   Event
+	body    []byte
+}
+
+func splitStorageMock() *mocks.MockSplitStorage {
+	return &mocks.MockSplitStorage{
+		FetchManyCall: func(splitNames []string) map[string]*dtos.SplitDTO {
+			result := make(map[string]*dtos.SplitDTO)
+			for _, name := range splitNames {
+				if name == "split1" {
+					result[name] = &dtos.SplitDTO{Name: name, Status: "ACTIVE", ChangeNumber: 5, DefaultTreatment: "off"}
+				} else {
+					result[name] = nil
+				}
+			}
+			return result
+		},
+	}
+}
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	var calls int64
+	received := make(chan delivery, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- delivery{headers: r.Header, event: event, body: body}
+	}))
+	defer ts.Close()
+
+	dispatcher, err := NewDispatcher(Options{
+		Endpoints:          []string{ts.URL},
+		Secret:             "someSecret",
+		IncludeDefinitions: true,
+		MaxRetries:         2,
+		RetryBackoff:       time.Millisecond,
+		QueueSize:          10,
+	}, splitStorageMock(), logging.NewLogger(nil))
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+		return
+	}
+	dispatcher.Start()
+	defer dispatcher.Stop(true)
+
+	dispatcher.SplitsChanged([]string{"split1", "split2", "split1"}, 10)
+
+	var d delivery
+	select {
+	case d = <-received:
+	case <-time.After(2 * time.Second):
+		t.Error("event should have been delivered")
+		return
+	}
+
+	if d.headers.Get(HeaderEvent) != EventSplits || d.headers.Get(HeaderDelivery) != d.event.ID {
+		t.Error("invalid headers: ", d.headers)
+	}
+
+	expected := "sha256=" + Sign([]byte("someSecret"), d.headers.Get(HeaderTimestamp), d.body)
+	if d.headers.Get(HeaderSignature) != expected {
+		t.Error("invalid signature: ", d.headers.Get(HeaderSignature))
+	}
+
+	if d.event.ChangeNumber != 10 || len(d.event.Splits) != 2 {
+		t.Error("invalid event: ", d.event)
+		return
+	}
+	if s := d.event.Splits[0]; s.Name != "split1" || s.Status != "ACTIVE" || s.ChangeNumber != 5 || s.Definition == nil || s.Definition.DefaultTreatment != "off" {
+		t.Error("invalid split1 change: ", s)
+	}
+	if s := d.event.Splits[1]; s.Name != "split2" || s.Status != "ARCHIVED" || s.ChangeNumber != 10 || s.Definition != nil {
+		t.Error("invalid split2 change: ", s)
+	}
+
+	time.Sleep(50 * time.Millisecond) // counters are updated after the response is received
+	if stats := dispatcher.Stats(); stats.Submitted != 1 || stats.Retried != 1 || stats.Delivered != 1 || stats.Failed != 0 {
+		t.Error("invalid stats: ", stats)
+	}
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	var calls int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	dispatcher, _ := NewDispatcher(Options{Endpoints: []string{ts.URL}, MaxRetries: 3, RetryBackoff: time.Millisecond, QueueSize: 1}, splitStorageMock(), logging.NewLogger(nil))
+	dispatcher.Start()
+	defer dispatcher.Stop(true)
+
+	dispatcher.SegmentChanged("segment1", 3, []string{"k1", "k2"})
+	time.Sleep(100 * time.Millisecond)
+	if c := atomic.LoadInt64(&calls); c != 1 {
+		t.Error("client errors should not be retried. Calls: ", c)
+	}
+	if stats := dispatcher.Stats(); stats.Failed != 1 || stats.Retried != 0 {
+		t.Error("invalid stats: ", stats)
+	}
+}
+
+func TestDispatcherValidation(t *testing.T) {
+	if _, err := NewDispatcher(Options{QueueSize: 1}, nil, logging.NewLogger(nil)); err != ErrNoEndpoints {
+		t.Error("ErrNoEndpoints expected. Got: ", err)
+	}
+	if _, err := NewDispatcher(Options{Endpoints: []string{"http://localhost"}}, nil, logging.NewLogger(nil)); err != ErrInvalidQueueSize {
+		t.Error("ErrInvalidQueueSize expected. Got: ", err)
+	}
+}
+
+type splitUpdaterMock struct {
+	result *split.UpdateResult
+	killed map[string]int64
+}
+
+func (m *splitUpdaterMock) SynchronizeSplits(till *int64) (*split.UpdateResult, error) {
+	return m.result, nil
+}
+func (m *splitUpdaterMock) LocalKill(splitName string, defaultTreatment string, changeNumber int64) {
+	if m.killed != nil && splitName == "split1" && changeNumber > m.killed[splitName] {
+		m.killed[splitName] = changeNumber
+	}
+}
+
+type segmentUpdaterMock struct {
+	results map[string]segment.UpdateResult
+}
+
+func (m *segmentUpdaterMock) SynchronizeSegment(name string, till *int64) (*segment.UpdateResult, error) {
+	result := m.results[name]
+	return &result, nil
+}
+func (m *segmentUpdaterMock) SynchronizeSegments() (map[string]segment.UpdateResult, error) {
+	return m.results, nil
+}
+func (m *segmentUpdaterMock) SegmentNames() []interface{}             { return nil }
+func (m *segmentUpdaterMock) IsSegmentCached(segmentName string) bool { return false }
+
+func TestUpdaters(t *testing.T) {
+	// dispatcher is not started so events remain in the queue
+	dispatcher, _ := NewDispatcher(Options{Endpoints: []string{"http://localhost"}, QueueSize: 10}, splitStorageMock(), logging.NewLogger(nil))
+
+	killed := make(map[string]int64)
+	splitStorage := &mocks.MockSplitStorage{
+		SplitCall: func(splitName string) *dtos.SplitDTO {
+			if changeNumber, ok := killed[splitName]; ok {
+				return &dtos.SplitDTO{Name: splitName, Killed: true, ChangeNumber: changeNumber}
+			}
+			return nil
+		},
+	}
+
+	splitUpdater := NewSplitUpdater(&splitUpdaterMock{result: &split.UpdateResult{NewChangeNumber: 2}}, splitStorage, dispatcher)
+	splitUpdater.SynchronizeSplits(nil)
+	if len(dispatcher.queue) != 0 {
+		t.Error("no event should be queued when no split is updated")
+	}
+
+	splitUpdater = NewSplitUpdater(&splitUpdaterMock{result: &split.UpdateResult{UpdatedSplits: []string{"split1"}, NewChangeNumber: 3}, killed: killed}, splitStorage, dispatcher)
+	splitUpdater.SynchronizeSplits(nil)
+	splitUpdater.LocalKill("split1", "off", 4)
+	splitUpdater.LocalKill("split1", "off", 4)       // already applied
+	splitUpdater.LocalKill("split1", "off", 3)       // stale
+	splitUpdater.LocalKill("nonexistent", "off", 10) // not applied by the wrapped updater
+
+	segmentUpdater := NewSegmentUpdater(&segmentUpdaterMock{results: map[string]segment.UpdateResult{
+		"segment1": {UpdatedKeys: []string{"k1"}, NewChangeNumber: 5},
+		"segment2": {NewChangeNumber: 6},
+	}}, dispatcher)
+	segmentUpdater.SynchronizeSegments()
+	segmentUpdater.SynchronizeSegment("segment2", nil)
+
+	if len(dispatcher.queue) != 3 {
+		t.Error("3 events should be queued. Got: ", len(dispatcher.queue))
+		return
+	}
+
+	if e := <-dispatcher.queue; e.Type != EventSplits || e.ChangeNumber != 3 {
+		t.Error("invalid sync event: ", e)
+	}
+	if e := <-dispatcher.queue; e.Type != EventSplits || e.ChangeNumber != 4 {
+		t.Error("invalid kill event: ", e)
+	}
+	if e := <-dispatcher.queue; e.Type != EventSegments || e.Segments[0].Name != "segment1" || e.Segments[0].UpdatedKeys != 1 || e.Segments[0].Keys != nil {
+		t.Error("invalid segment event: ", e)
+	}
+}
+
+func TestDispatcherTruncatesSegmentKeys(t *testing.T) {
+	dispatcher, _ := NewDispatcher(Options{
+		Endpoints:          []string{"http://localhost"},
+		QueueSize:          10,
+		IncludeDefinitions: true,
+		MaxSegmentKeys:     2,
+	}, splitStorageMock(), logging.NewLogger(nil))
+
+	dispatcher.SegmentChanged("segment1", 1, []string{"k1", "k2"})
+	dispatcher.SegmentChanged("segment1", 2, []string{"k1", "k2", "k3"})
+
+	if e := <-dispatcher.queue; len(e.Segments[0].Keys) != 2 || e.Segments[0].KeysTruncated {
+		t.Error("keys within the limit should be included as is: ", e.Segments[0])
+	}
+	if e := <-dispatcher.queue; len(e.Segments[0].Keys) != 2 || !e.Segments[0].KeysTruncated || e.Segments[0].UpdatedKeys != 3 {
+		t.Error("keys beyond the limit should be truncated: ", e.Segments[0])
+	}
+}
+
+func TestDispatcherDrainsOnStop(t *testing.T) {
+	var calls int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+	}))
+	defer ts.Close()
+
+	dispatcher, _ := NewDispatcher(Options{Endpoints: []string{ts.URL}, QueueSize: 10}, splitStorageMock(), logging.NewLogger(nil))
+	for idx := 0; idx < 5; idx++ {
+		dispatcher.SegmentChanged("segment1", int64(idx), []string{"k1"})
+	}
+
+	dispatcher.Start()
+	dispatcher.Stop(true)
+	if c := atomic.LoadInt64(&calls); c != 5 {
+		t.Error("queued events should be delivered when stopping. Got: ", c)
+	}
+	if stats := dispatcher.Stats(); stats.Delivered != 5 || stats.Dropped != 0 {
+		t.Error("invalid stats: ", stats)
+	}
+}
+
+func TestDispatcherDrainTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	dispatcher, _ := NewDispatcher(Options{
+		Endpoints:    []string{ts.URL},
+		QueueSize:    10,
+		DrainTimeout: 100 * time.Millisecond,
+	}, splitStorageMock(), logging.NewLogger(nil))
+	for idx := 0; idx < 5; idx++ {
+		dispatcher.SegmentChanged("segment1", int64(idx), []string{"k1"})
+	}
+
+	dispatcher.Start()
+	dispatcher.Stop(true)
+	stats := dispatcher.Stats()
+	if stats.Dropped < 3 || stats.Delivered+stats.Failed+stats.Dropped != 5 || len(dispatcher.queue) != 0 {
+		t.Error("events queued after the drain timeout should be dropped. Got: ", stats, len(dispatcher.queue))
+	}
+}
@@ -0,0 +1,85 @@
+package changehook
+
+import (
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/segment"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/split"
+)
+
+// Listener is notified of split & segment changes. Implemented by `Dispatcher`
+type Listener interface {
+	SplitsChanged(names []string, changeNumber int64)
+	SegmentChanged(name string, changeNumber int64, updatedKeys []string)
+}
+
+// SplitUpdater wraps a split updater and notifies the listener whenever splits are updated
+type SplitUpdater struct {
+	split.Updater
+	splitStorage storage.SplitStorageConsumer
+	dispatcher   Listener
+}
+
+// NewSplitUpdater constructs a new change-notifying split updater. The split storage is used to check whether
+// local kills were applied
+func NewSplitUpdater(wrapped split.Updater, splitStorage storage.SplitStorageConsumer, dispatcher Listener) *SplitUpdater {
+	return &SplitUpdater{Updater: wrapped, splitStorage: splitStorage, dispatcher: dispatcher}
+}
+
+// SynchronizeSplits forwards the call to the wrapped updater and notifies the updated splits
+func (u *SplitUpdater) SynchronizeSplits(till *int64) (*split.UpdateResult, error) {
+	result, err := u.Updater.SynchronizeSplits(till)
+	if result != nil && len(result.UpdatedSplits) > 0 {
+		u.dispatcher.SplitsChanged(result.UpdatedSplits, result.NewChangeNumber)
+	}
+	return result, err
+}
+
+// LocalKill forwards the call to the wrapped updater and notifies the killed split if the kill was applied
+func (u *SplitUpdater) LocalKill(splitName string, defaultTreatment string, changeNumber int64) {
+	alreadyKilled := killedAt(u.splitStorage.Split(splitName), changeNumber)
+	u.Updater.LocalKill(splitName, defaultTreatment, changeNumber)
+	if alreadyKilled || !killedAt(u.splitStorage.Split(splitName), changeNumber) {
+		return // stale kill, unknown split or a storage that doesn't support local kills
+	}
+	u.dispatcher.SplitsChanged([]string{splitName}, changeNumber)
+}
+
+func killedAt(split *dtos.SplitDTO, changeNumber int64) bool {
+	return split != nil && split.Killed && split.ChangeNumber == changeNumber
+}
+
+// SegmentUpdater wraps a segment updater and notifies the listener whenever segment keys are updated
+type SegmentUpdater struct {
+	segment.Updater
+	dispatcher Listener
+}
+
+// NewSegmentUpdater constructs a new change-notifying segment updater
+func NewSegmentUpdater(wrapped segment.Updater, dispatcher Listener) *SegmentUpdater {
+	return &SegmentUpdater{Updater: wrapped, dispatcher: dispatcher}
+}
+
+// SynchronizeSegment forwards the call to the wrapped updater and notifies the segment if it was updated
+func (u *SegmentUpdater) SynchronizeSegment(name string, till *int64) (*segment.UpdateResult, error) {
+	result, err := u.Updater.SynchronizeSegment(name, till)
+	if result != nil && len(result.UpdatedKeys) > 0 {
+		u.dispatcher.SegmentChanged(name, result.NewChangeNumber, result.UpdatedKeys)
+	}
+	return result, err
+}
+
+// SynchronizeSegments forwards the call to the wrapped updater and notifies every updated segment
+func (u *SegmentUpdater) SynchronizeSegments() (map[string]segment.UpdateResult, error) {
+	results, err := u.Updater.SynchronizeSegments()
+	for name, result := range results {
+		if len(result.UpdatedKeys) > 0 {
+			u.dispatcher.SegmentChanged(name, result.NewChangeNumber, result.UpdatedKeys)
+		}
+	}
+	return results, err
+}
+
+var _ Listener = (*Dispatcher)(nil)
+var _ split.Updater = (*SplitUpdater)(nil)
+var _ segment.Updater = (*SegmentUpdater)(nil)
@@ -0,0 +1,218 @@
+package changelog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Entry kinds
+const (
+	KindSplit   = "split"
+	KindSegment = "segment"
+)
+
+// Actions recorded in entries
+const (
+	ActionAdded    = "added"
+	ActionModified = "modified"
+	ActionArchived = "archived"
+	ActionKilled   = "killed"
+	ActionUpdated  = "updated"
+)
+
+const (
+	defaultQueryLimit = 100
+	minTrimInterval   = time.Minute
+)
+
+// FieldChange describes how a single field of a split changed
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Entry is a single record of the change history
+type Entry struct {
+	ID           string        `json:"id"`
+	Kind         string        `json:"kind"`
+	Name         string        `json:"name"`
+	Action       string        `json:"action"`
+	ChangeNumber int64         `json:"changeNumber"`
+	Timestamp    int64         `json:"timestamp"`
+	Changes      []FieldChange `json:"changes,omitempty"`
+	KeysAdded    int           `json:"keysAdded,omitempty"`
+	KeysRemoved  int           `json:"keysRemoved,omitempty"`
+}
+
+// Query filters entries. Empty/zero fields match everything. Since & Until are unix timestamps in milliseconds
+type Query struct {
+	Kind  string
+	Name  string
+	Since int64
+	Until int64
+	Limit int
+}
+
+// Matches returns true if the entry passes the kind, name & time filters
+func (q *Query) Matches(entry *Entry) bool {
+	return (q.Kind == "" || q.Kind == entry.Kind) &&
+		(q.Name == "" || q.Name == entry.Name) &&
+		(q.Since == 0 || entry.Timestamp >= q.Since) &&
+		(q.Until == 0 || entry.Timestamp <= q.Until)
+}
+
+// Storage defines the interface of an append-only store of change entries
+type Storage interface {
+	// Append stores the entries, assigning them an ID
+	Append(entries []Entry) error
+	// Query returns the entries matching the query, newest first
+	Query(query Query) ([]Entry, error)
+	// Trim removes entries older than the supplied timestamp (in ms) & the oldest ones in excess of maxEntries
+	Trim(olderThan int64, maxEntries int) (int, error)
+}
+
+// Recorder builds change entries from storage updates & keeps the underlying storage within the retention limits
+type Recorder struct {
+	storage    Storage
+	retention  time.Duration
+	maxEntries int
+	logger     logging.LoggerInterface
+	lastTrim   time.Time
+	mutex      sync.Mutex
+}
+
+// NewRecorder constructs a new change recorder. A retention or max entries <= 0 disables the respective limit
+func NewRecorder(storage Storage, retention time.Duration, maxEntries int, logger logging.LoggerInterface) *Recorder {
+	return &Recorder{storage: storage, retention: retention, maxEntries: maxEntries, logger: logger}
+}
+
+// RecordSplitChanges records the changes applied by a split storage update. `previous` must contain the
+// versions of the updated splits as they were before the update (missing or nil ones are considered new)
+func (r *Recorder) RecordSplitChanges(previous map[string]*dtos.SplitDTO, toAdd []dtos.SplitDTO, toRemove []dtos.SplitDTO, changeNumber int64) {
+	now := nowMs()
+	entries := make([]Entry, 0, len(toAdd)+len(toRemove))
+	for idx := range toAdd {
+		after := &toAdd[idx]
+		before := previous[after.Name]
+		entry := Entry{Kind: KindSplit, Name: after.Name, ChangeNumber: splitChangeNumber(after, changeNumber), Timestamp: now}
+		switch {
+		case before == nil:
+			entry.Action = ActionAdded
+			entry.Changes = DiffNewSplit(after)
+		case !before.Killed && after.Killed:
+			entry.Action = ActionKilled
+			entry.Changes = DiffSplits(before, after)
+		default:
+			entry.Action = ActionModified
+			entry.Changes = DiffSplits(before, after)
+			if len(entry.Changes) == 0 { // same definition received again
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	for idx := range toRemove {
+		removed := &toRemove[idx]
+		if previous[removed.Name] == nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Kind:         KindSplit,
+			Name:         removed.Name,
+			Action:       ActionArchived,
+			ChangeNumber: splitChangeNumber(removed, changeNumber),
+			Timestamp:    now,
+		})
+	}
+
+	r.append(entries)
+}
+
+// RecordKill records a split being killed through a streaming notification. Nothing is recorded if the kill
+// was not applied (ie: the split was unknown or the notification was older than the current definition)
+func (r *Recorder) RecordKill(before *dtos.SplitDTO, after *dtos.SplitDTO) {
+	if before == nil || after == nil || !after.Killed {
+		return
+	}
+
+	changes := DiffSplits(before, after)
+	if len(changes) == 0 {
+		return
+	}
+
+	r.append([]Entry{{
+		Kind:         KindSplit,
+		Name:         after.Name,
+		Action:       ActionKilled,
+		ChangeNumber: after.ChangeNumber,
+		Timestamp:    nowMs(),
+		Changes:      changes,
+	}})
+}
+
+// RecordSegmentChange records the keys added/removed to/from a segment
+func (r *Recorder) RecordSegmentChange(name string, added int, removed int, changeNumber int64) {
+	if added == 0 && removed == 0 {
+		return
+	}
+
+	r.append([]Entry{{
+		Kind:         KindSegment,
+		Name:         name,
+		Action:       ActionUpdated,
+		ChangeNumber: changeNumber,
+		Timestamp:    nowMs(),
+		KeysAdded:    added,
+		KeysRemoved:  removed,
+	}})
+}
+
+// Query returns the entries matching the query, newest first
+func (r *Recorder) Query(query Query) ([]Entry, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultQueryLimit
+	}
+	return r.storage.Query(query)
+}
+
+func (r *Recorder) append(entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := r.storage.Append(entries); err != nil {
+		r.logger.Error("error recording split/segment changes: ", err)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if time.Since(r.lastTrim) < minTrimInterval {
+		return
+	}
+	r.lastTrim = time.Now()
+
+	var olderThan int64
+	if r.retention > 0 {
+		olderThan = nowMs() - int64(r.retention/time.Millisecond)
+	}
+	if _, err := r.storage.Trim(olderThan, r.maxEntries); err != nil {
+		r.logger.Warning("error trimming change history: ", err)
+	}
+}
+
+func splitChangeNumber(split *dtos.SplitDTO, fallback int64) int64 {
+	if split.ChangeNumber > 0 {
+		return split.ChangeNumber
+	}
+	return fallback
+}
+
+func nowMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
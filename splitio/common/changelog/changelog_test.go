@@ -0,0 +1,152 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func makeSplit(name string, killed bool, defaultTreatment string, treatments ...string) dtos.SplitDTO {
+	partitions := make([]dtos.PartitionDTO, 0, len(treatments))
+	for _, treatment := range treatments {
+		partitions = append(partitions, dtos.PartitionDTO{Treatment: treatment, Size: 100 / len(treatments)})
+	}
+	return dtos.SplitDTO{
+		Name:             name,
+		Status:           "ACTIVE",
+		Killed:           killed,
+		DefaultTreatment: defaultTreatment,
+		TrafficTypeName:  "user",
+		Conditions:       []dtos.ConditionDTO{{ConditionType: "ROLLOUT", Partitions: partitions}},
+	}
+}
+
+func TestDiffSplits(t *testing.T) {
+	before := makeSplit("split1", false, "off", "on", "off")
+	after := makeSplit("split1", true, "off", "on", "off", "v2")
+	after.Configurations = map[string]string{"on": "{}"}
+
+	changes := DiffSplits(&before, &after)
+	byField := make(map[string]FieldChange)
+	for _, change := range changes {
+		byField[change.Field] = change
+	}
+
+	if len(changes) != 4 {
+		t.Error("4 changes expected. Got: ", changes)
+	}
+	if c := byField["killed"]; c.Before != "false" || c.After != "true" {
+		t.Error("invalid killed change: ", c)
+	}
+	if c := byField["treatments"]; c.Before != "off,on" || c.After != "off,on,v2" {
+		t.Error("invalid treatments change: ", c)
+	}
+	if c := byField["configurations.on"]; c.Before != "" || c.After != "{}" {
+		t.Error("invalid configurations change: ", c)
+	}
+	if _, ok := byField["conditions[0]"]; !ok {
+		t.Error("condition change should be reported")
+	}
+
+	if changes := DiffSplits(&before, &before); len(changes) != 0 {
+		t.Error("no changes expected. Got: ", changes)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	storage := NewInMemoryStorage()
+	recorder := NewRecorder(storage, time.Hour, 0, logging.NewLogger(nil))
+
+	split1 := makeSplit("split1", false, "off", "on", "off")
+	split2 := makeSplit("split2", false, "off", "on", "off")
+	recorder.RecordSplitChanges(nil, []dtos.SplitDTO{split1, split2}, nil, 1)
+
+	killed := split1
+	killed.Killed = true
+	archived := split2
+	archived.Status = "ARCHIVED"
+	recorder.RecordSplitChanges(
+		map[string]*dtos.SplitDTO{"split1": &split1, "split2": &split2, "split3": nil},
+		[]dtos.SplitDTO{killed},
+		[]dtos.SplitDTO{archived, makeSplit("split3", false, "off")},
+		2,
+	)
+
+	// same definition received again, nothing should be recorded
+	recorder.RecordSplitChanges(map[string]*dtos.SplitDTO{"split1": &killed}, []dtos.SplitDTO{killed}, nil, 3)
+
+	recorder.RecordSegmentChange("segment1", 10, 2, 4)
+	recorder.RecordSegmentChange("segment1", 0, 0, 5)
+
+	entries, _ := recorder.Query(Query{})
+	if len(entries) != 5 {
+		t.Error("5 entries expected. Got: ", entries)
+		return
+	}
+
+	expected := []struct {
+		name   string
+		action string
+		cn     int64
+	}{
+		{"segment1", ActionUpdated, 4},
+		{"split2", ActionArchived, 2},
+		{"split1", ActionKilled, 2},
+		{"split2", ActionAdded, 1},
+		{"split1", ActionAdded, 1},
+	}
+	for idx, e := range expected {
+		if entries[idx].Name != e.name || entries[idx].Action != e.action || entries[idx].ChangeNumber != e.cn {
+			t.Errorf("entry %d should be %+v. Got: %+v", idx, e, entries[idx])
+		}
+	}
+
+	if entries[0].KeysAdded != 10 || entries[0].KeysRemoved != 2 {
+		t.Error("invalid segment entry: ", entries[0])
+	}
+
+	entries, _ = recorder.Query(Query{Kind: KindSplit, Name: "split1"})
+	if len(entries) != 2 {
+		t.Error("2 entries expected for split1. Got: ", entries)
+	}
+}
+
+func TestRecordKill(t *testing.T) {
+	storage := NewInMemoryStorage()
+	recorder := NewRecorder(storage, 0, 0, logging.NewLogger(nil))
+
+	before := makeSplit("split1", false, "off", "on", "off")
+	after := before
+	after.Killed = true
+	after.DefaultTreatment = "on"
+	after.ChangeNumber = 10
+
+	recorder.RecordKill(nil, &after)
+	recorder.RecordKill(&before, &before) // kill not applied
+	recorder.RecordKill(&before, &after)
+
+	entries, _ := recorder.Query(Query{})
+	if len(entries) != 1 || entries[0].Action != ActionKilled || entries[0].ChangeNumber != 10 || len(entries[0].Changes) != 2 {
+		t.Error("a single kill entry expected. Got: ", entries)
+	}
+}
+
+func TestInMemoryTrim(t *testing.T) {
+	storage := NewInMemoryStorage()
+	storage.Append([]Entry{{Name: "a", Timestamp: 1}, {Name: "b", Timestamp: 2}, {Name: "c", Timestamp: 3}, {Name: "d", Timestamp: 4}})
+
+	if removed, _ := storage.Trim(2, 0); removed != 1 {
+		t.Error("1 entry should be removed by age. Got: ", removed)
+	}
+
+	if removed, _ := storage.Trim(0, 2); removed != 1 {
+		t.Error("1 entry should be removed by size. Got: ", removed)
+	}
+
+	entries, _ := storage.Query(Query{})
+	if len(entries) != 2 || entries[0].Name != "d" || entries[1].Name != "c" {
+		t.Error("invalid remaining entries: ", entries)
+	}
+}
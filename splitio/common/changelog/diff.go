@@ -0,0 +1,97 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+)
+
+// DiffSplits returns the field-level differences between two versions of a split.
+// Conditions are compared one by one, and treatments & configurations are compared as a whole
+func DiffSplits(before *dtos.SplitDTO, after *dtos.SplitDTO) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, b string, a string) {
+		if b != a {
+			changes = append(changes, FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+
+	add("status", before.Status, after.Status)
+	add("killed", strconv.FormatBool(before.Killed), strconv.FormatBool(after.Killed))
+	add("defaultTreatment", before.DefaultTreatment, after.DefaultTreatment)
+	add("trafficTypeName", before.TrafficTypeName, after.TrafficTypeName)
+	add("trafficAllocation", strconv.Itoa(before.TrafficAllocation), strconv.Itoa(after.TrafficAllocation))
+	add("trafficAllocationSeed", strconv.FormatInt(before.TrafficAllocationSeed, 10), strconv.FormatInt(after.TrafficAllocationSeed, 10))
+	add("seed", strconv.FormatInt(before.Seed, 10), strconv.FormatInt(after.Seed, 10))
+	add("algo", strconv.Itoa(before.Algo), strconv.Itoa(after.Algo))
+	add("treatments", strings.Join(treatments(before), ","), strings.Join(treatments(after), ","))
+
+	for _, treatment := range unionKeys(before.Configurations, after.Configurations) {
+		add("configurations."+treatment, before.Configurations[treatment], after.Configurations[treatment])
+	}
+
+	for idx := 0; idx < len(before.Conditions) || idx < len(after.Conditions); idx++ {
+		var b, a string
+		if idx < len(before.Conditions) {
+			b = serialize(&before.Conditions[idx])
+		}
+		if idx < len(after.Conditions) {
+			a = serialize(&after.Conditions[idx])
+		}
+		add(fmt.Sprintf("conditions[%d]", idx), b, a)
+	}
+
+	return changes
+}
+
+// DiffNewSplit summarizes a newly added split. Conditions are not included to keep entries small
+func DiffNewSplit(split *dtos.SplitDTO) []FieldChange {
+	return []FieldChange{
+		{Field: "trafficTypeName", After: split.TrafficTypeName},
+		{Field: "defaultTreatment", After: split.DefaultTreatment},
+		{Field: "treatments", After: strings.Join(treatments(split), ",")},
+		{Field: "killed", After: strconv.FormatBool(split.Killed)},
+	}
+}
+
+func treatments(split *dtos.SplitDTO) []string {
+	seen := make(map[string]struct{})
+	for _, condition := range split.Conditions {
+		for _, partition := range condition.Partitions {
+			seen[partition.Treatment] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for treatment := range seen {
+		result = append(result, treatment)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func unionKeys(m1 map[string]string, m2 map[string]string) []string {
+	keys := make([]string, 0, len(m1)+len(m2))
+	for key := range m1 {
+		keys = append(keys, key)
+	}
+	for key := range m2 {
+		if _, ok := m1[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func serialize(condition *dtos.ConditionDTO) string {
+	serialized, err := json.Marshal(condition)
+	if err != nil {
+		return ""
+	}
+	return string(serialized)
+}
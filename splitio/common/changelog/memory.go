@@ -0,0 +1,63 @@
+package changelog
+
+import (
+	"strconv"
+	"sync"
+)
+
+// InMemoryStorage keeps entries in memory. Entries are lost on restart
+type InMemoryStorage struct {
+	entries []Entry
+	nextID  uint64
+	mutex   sync.RWMutex
+}
+
+// NewInMemoryStorage constructs a new in-memory change storage
+func NewInMemoryStorage() *InMemoryStorage {
+	return &InMemoryStorage{}
+}
+
+// Append stores the entries, assigning them an ID
+func (s *InMemoryStorage) Append(entries []Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, entry := range entries {
+		s.nextID++
+		entry.ID = strconv.FormatUint(s.nextID, 10)
+		s.entries = append(s.entries, entry)
+	}
+	return nil
+}
+
+// Query returns the entries matching the query, newest first
+func (s *InMemoryStorage) Query(query Query) ([]Entry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []Entry
+	for idx := len(s.entries) - 1; idx >= 0 && (query.Limit <= 0 || len(result) < query.Limit); idx-- {
+		if query.Matches(&s.entries[idx]) {
+			result = append(result, s.entries[idx])
+		}
+	}
+	return result, nil
+}
+
+// Trim removes entries older than the supplied timestamp & the oldest ones in excess of maxEntries
+func (s *InMemoryStorage) Trim(olderThan int64, maxEntries int) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drop := 0
+	for drop < len(s.entries) && s.entries[drop].Timestamp < olderThan {
+		drop++
+	}
+	if maxEntries > 0 && len(s.entries)-drop > maxEntries {
+		drop = len(s.entries) - maxEntries
+	}
+
+	s.entries = append([]Entry(nil), s.entries[drop:]...)
+	return drop, nil
+}
+
+var _ Storage = (*InMemoryStorage)(nil)
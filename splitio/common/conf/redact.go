@@ -0,0 +1,66 @@
+package conf
+
+import "net/url"
+
+const redacted = "<redacted>"
+
+// Redacted returns a copy of the admin options with credentials masked, suitable for being displayed
+func (a Admin) Redacted() Admin {
+	a.Password = redactString(a.Password)
+	if len(a.Tokens) > 0 {
+		tokens := make([]string, 0, len(a.Tokens))
+		for _, token := range a.Tokens {
+			tokens = append(tokens, redactString(token))
+		}
+		a.Tokens = tokens
+	}
+	return a
+}
+
+// Redacted returns a copy of the integration options with credentials masked, suitable for being displayed
+func (i Integrations) Redacted() Integrations {
+	i.ChangeWebhook.Secret = redactString(i.ChangeWebhook.Secret)
+	i.Notifications.PagerDutyRoutingKey = redactString(i.Notifications.PagerDutyRoutingKey)
+	i.Notifications.SMTP.Password = redactString(i.Notifications.SMTP.Password)
+	i.Notifications.Webhooks = redactURLs(i.Notifications.Webhooks)
+	i.Slack.Webhook = redactURL(i.Slack.Webhook)
+	return i
+}
+
+// Redacted returns a copy of the health alert options with credentials masked, suitable for being displayed
+func (h HealthAlerts) Redacted() HealthAlerts {
+	h.Webhooks = redactURLs(h.Webhooks)
+	return h
+}
+
+// redactURL keeps the scheme & host of a webhook, masking the path & query where tokens are usually embedded
+func redactURL(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return redacted
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/" + redacted
+}
+
+func redactURLs(values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, redactURL(value))
+	}
+	return result
+}
+
+func redactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,29 @@
+package conf
+
+import "testing"
+
+func TestIntegrationsRedacted(t *testing.T) {
+	var integrations Integrations
+	integrations.Slack.Webhook = "https://hooks.slack.com/services/T000/B000/secret"
+	integrations.Notifications.Webhooks = []string{"https://example.com/hook?token=secret", "not a url"}
+	integrations.ChangeWebhook.Secret = "secret"
+
+	redacted := integrations.Redacted()
+	if redacted.Slack.Webhook != "https://hooks.slack.com/<redacted>" {
+		t.Error("slack webhook should be redacted. Got: ", redacted.Slack.Webhook)
+	}
+	if hooks := redacted.Notifications.Webhooks; len(hooks) != 2 || hooks[0] != "https://example.com/<redacted>" || hooks[1] != "<redacted>" {
+		t.Error("notification webhooks should be redacted. Got: ", hooks)
+	}
+	if redacted.ChangeWebhook.Secret != "<redacted>" {
+		t.Error("change webhook secret should be redacted")
+	}
+	if integrations.Notifications.Webhooks[0] != "https://example.com/hook?token=secret" {
+		t.Error("original options should not be modified")
+	}
+
+	alerts := HealthAlerts{Webhooks: []string{"https://example.com/alerts/secret"}}
+	if hooks := alerts.Redacted().Webhooks; len(hooks) != 1 || hooks[0] != "https://example.com/<redacted>" {
+		t.Error("health alert webhooks should be redacted. Got: ", hooks)
+	}
+}
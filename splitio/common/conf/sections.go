@@ -2,25 +2,36 @@ package conf
 
 // Logging configuration options
 type Logging struct {
-	Level             string `json:"level" s-cli:"log-level" s-def:"info" s-desc:"Log level (error|warning|info|debug|verbose)"`
-	Output            string `json:"output" s-cli:"log-output" s-def:"stdout" s-desc:"Where to output logs (defaults to stdout)"`
-	RotationMaxFiles  int64  `json:"rotationMaxFiles" s-cli:"log-rotation-max-files" s-def:"10" s-desc:"Max number of files to keep when rotating logs"`
-	RotationMaxSizeKb int64  `json:"rotationMaxSizeKb" s-cli:"log-rotation-max-size-kb" s-def:"1024" s-desc:"Maximum log file size in kbs"`
+	Level             string   `json:"level" s-cli:"log-level" s-def:"info" s-desc:"Log level (error|warning|info|debug|verbose)"`
+	Output            string   `json:"output" s-cli:"log-output" s-def:"stdout" s-desc:"Where to output logs (defaults to stdout)"`
+	RotationMaxFiles  int64    `json:"rotationMaxFiles" s-cli:"log-rotation-max-files" s-def:"10" s-desc:"Max number of files to keep when rotating logs"`
+	RotationMaxSizeKb int64    `json:"rotationMaxSizeKb" s-cli:"log-rotation-max-size-kb" s-def:"1024" s-desc:"Maximum log file size in kbs"`
+	Format            string   `json:"format" s-cli:"log-format" s-def:"text" s-desc:"Log line format (text|json)"`
+	Environment       string   `json:"environment" s-cli:"log-environment" s-def:"" s-desc:"Environment name added to json log lines"`
+	ComponentLevels   []string `json:"componentLevels" s-cli:"log-component-levels" s-def:"" s-desc:"Per-component log levels: <component>=<level>. Components: controllers|sync|pipelined|healthcheck|admin|notifier"`
 }
 
 // Admin configuration options
 type Admin struct {
-	Host     string `json:"host" s-cli:"admin-host" s-def:"0.0.0.0" s-desc:"Host where the admin server will listen"`
-	Port     int64  `json:"port" s-cli:"admin-port" s-def:"3010" s-desc:"Admin port where incoming connections will be accepted"`
-	Username string `json:"username" s-cli:"admin-username" s-def:"" s-desc:"HTTP basic auth username for admin endpoints"`
-	Password string `json:"password" s-cli:"admin-password" s-def:"" s-desc:"HTTP basic auth password for admin endpoints"`
-	SecureHC bool   `json:"secureChecks" s-cli:"admin-secure-hc" s-def:"false" s-desc:"Secure Healthcheck endpoints as well."`
+	Host            string   `json:"host" s-cli:"admin-host" s-def:"0.0.0.0" s-desc:"Host where the admin server will listen"`
+	Port            int64    `json:"port" s-cli:"admin-port" s-def:"3010" s-desc:"Admin port where incoming connections will be accepted"`
+	Username        string   `json:"username" s-cli:"admin-username" s-def:"" s-desc:"HTTP basic auth username for admin endpoints (granted the admin role)"`
+	Password        string   `json:"password" s-cli:"admin-password" s-def:"" s-desc:"HTTP basic auth password for admin endpoints"`
+	SecureHC        bool     `json:"secureChecks" s-cli:"admin-secure-hc" s-def:"false" s-desc:"Secure Healthcheck endpoints as well."`
+	Tokens          []string `json:"tokens" s-cli:"admin-tokens" s-def:"" s-desc:"Bearer tokens for admin endpoints in the form <name>:<role>:<token>. Roles: read-only, operator, admin"`
+	TokensFile      string   `json:"tokensFile" s-cli:"admin-tokens-file" s-def:"" s-desc:"File with admin tokens, one <name>:<role>:<token> per line. Reloaded on POST /admin/access/tokens/reload"`
+	AuditLogFile    string   `json:"auditLogFile" s-cli:"admin-audit-log-file" s-def:"" s-desc:"File to append admin actions to. Logged if empty"`
+	TLSCertFile     string   `json:"tlsCertFile" s-cli:"admin-tls-cert-file" s-def:"" s-desc:"Certificate used to serve the admin endpoints over TLS"`
+	TLSKeyFile      string   `json:"tlsKeyFile" s-cli:"admin-tls-key-file" s-def:"" s-desc:"Private key used to serve the admin endpoints over TLS"`
+	TLSClientCAFile string   `json:"tlsClientCAFile" s-cli:"admin-tls-client-ca-file" s-def:"" s-desc:"CA bundle used to verify client certificates. Enables mTLS when set"`
 }
 
 // Integrations configuration options
 type Integrations struct {
 	ImpressionListener ImpressionListener `json:"impressionListener" s-nested:"true"`
 	Slack              Slack              `json:"slack" s-nested:"true"`
+	Notifications      Notifications      `json:"notifications" s-nested:"true"`
+	ChangeWebhook      ChangeWebhook      `json:"changeWebhook" s-nested:"true"`
 }
 
 // ImpressionListener configuration options
@@ -29,8 +40,75 @@ type ImpressionListener struct {
 	QueueSize int64  `json:"queueSize" s-cli:"impression-listener-queue-size" s-def:"100" s-desc:"max number of impressions bulks to queue"`
 }
 
+// ChangeWebhook configuration options
+type ChangeWebhook struct {
+	Endpoints          []string `json:"endpoints" s-cli:"change-webhook-endpoints" s-def:"" s-desc:"URLs to POST split & segment changes to"`
+	Secret             string   `json:"secret" s-cli:"change-webhook-secret" s-def:"" s-desc:"Key used to sign payloads. The hex HMAC-SHA256 of '<X-Split-Timestamp>.<body>' is sent in X-Split-Signature"`
+	IncludeDefinitions bool     `json:"includeDefinitions" s-cli:"change-webhook-include-definitions" s-def:"false" s-desc:"Include full split definitions & updated segment keys in payloads"`
+	MaxSegmentKeys     int      `json:"maxSegmentKeys" s-cli:"change-webhook-max-segment-keys" s-def:"10000" s-desc:"Max number of updated segment keys included in a payload. The list is truncated beyond that"`
+	MaxRetries         int      `json:"maxRetries" s-cli:"change-webhook-max-retries" s-def:"3" s-desc:"Max number of retries for a delivery that failed with a network error, 429 or 5xx"`
+	RetryBackoffMs     int64    `json:"retryBackoffMs" s-cli:"change-webhook-retry-backoff-ms" s-def:"1000" s-desc:"Time to wait before the first retry, doubled on every subsequent one"`
+	TimeoutMs          int64    `json:"timeoutMs" s-cli:"change-webhook-timeout-ms" s-def:"5000" s-desc:"Timeout for each delivery attempt"`
+	QueueSize          int      `json:"queueSize" s-cli:"change-webhook-queue-size" s-def:"100" s-desc:"Max number of change events waiting to be delivered"`
+}
+
 // Slack configuration options
 type Slack struct {
 	Webhook string `json:"webhook" s-cli:"slack-webhook" s-def:"" s-desc:"slack webhook to post log messages"`
 	Channel string `json:"channel" s-cli:"slack-channel" s-def:"" s-desc:"slack channel to post log messages"`
 }
+
+// Notifications configuration options
+type Notifications struct {
+	Webhooks            []string `json:"webhooks" s-cli:"notifications-webhooks" s-def:"" s-desc:"URLs to POST notifications to as json"`
+	PagerDutyRoutingKey string   `json:"pagerDutyRoutingKey" s-cli:"notifications-pagerduty-routing-key" s-def:"" s-desc:"PagerDuty Events v2 integration key"`
+	PagerDutyURL        string   `json:"pagerDutyUrl" s-cli:"notifications-pagerduty-url" s-def:"" s-desc:"PagerDuty Events v2 compatible endpoint (defaults to PagerDuty's)"`
+	SMTP                SMTP     `json:"smtp" s-nested:"true"`
+	Rules               []string `json:"rules" s-cli:"notifications-rules" s-def:"" s-desc:"Routing rules: <sink>[:<filter>;...]. Sinks: slack|webhook|pagerduty|smtp. Filters: level=<level>, kind=<log|health|lifecycle>[|...], component=<name>[|...]"`
+	RateLimitPerMinute  int64    `json:"rateLimitPerMinute" s-cli:"notifications-rate-limit-per-minute" s-def:"0" s-desc:"Max notifications sent to each sink per minute (0 = unlimited)"`
+	DedupWindowSecs     int64    `json:"dedupWindowSecs" s-cli:"notifications-dedup-window-secs" s-def:"0" s-desc:"Identical notifications sent to a sink within this window (in seconds) are suppressed (0 = disabled)"`
+	QueueSize           int64    `json:"queueSize" s-cli:"notifications-queue-size" s-def:"500" s-desc:"Max number of notifications waiting to be sent"`
+}
+
+// SMTP configuration options
+type SMTP struct {
+	Host     string   `json:"host" s-cli:"notifications-smtp-host" s-def:"" s-desc:"SMTP server used to email notifications"`
+	Port     int64    `json:"port" s-cli:"notifications-smtp-port" s-def:"587" s-desc:"SMTP server port"`
+	Username string   `json:"username" s-cli:"notifications-smtp-username" s-def:"" s-desc:"SMTP username (plain auth)"`
+	Password string   `json:"password" s-cli:"notifications-smtp-password" s-def:"" s-desc:"SMTP password (plain auth)"`
+	From     string   `json:"from" s-cli:"notifications-smtp-from" s-def:"" s-desc:"Sender address of notification emails"`
+	To       []string `json:"to" s-cli:"notifications-smtp-to" s-def:"" s-desc:"Recipients of notification emails"`
+}
+
+// Probes configuration options
+type Probes struct {
+	LiveItems          []string `json:"liveItems" s-cli:"probe-live-items" s-def:"" s-desc:"Health items that must be healthy for /health/live to succeed"`
+	ReadyItems         []string `json:"readyItems" s-cli:"probe-ready-items" s-def:"Storage,Queues" s-desc:"Health items that must be healthy for /health/ready to succeed"`
+	StartupItems       []string `json:"startupItems" s-cli:"probe-startup-items" s-def:"" s-desc:"Health items that must be healthy for /health/startup to succeed"`
+	ReadyRequireSplits bool     `json:"readyRequireSplits" s-cli:"probe-ready-require-splits" s-def:"true" s-desc:"Only report ready once splits have been loaded"`
+	ReadyRequireSync   bool     `json:"readyRequireSync" s-cli:"probe-ready-require-sync" s-def:"true" s-desc:"Only report ready while the sync manager is running"`
+	ReadyMaxQueueUsage int64    `json:"readyMaxQueueUsage" s-cli:"probe-ready-max-queue-usage" s-def:"90" s-desc:"Percentage of an in-memory queue's capacity above which /health/ready fails (0 = disabled)"`
+	LiveTimeoutMs      int64    `json:"liveTimeoutMs" s-cli:"probe-live-timeout-ms" s-def:"5000" s-desc:"Max time to wait for the application monitor before failing the liveness probe"`
+}
+
+// HealthAlerts configuration options
+type HealthAlerts struct {
+	Webhooks    []string `json:"webhooks" s-cli:"health-alerts-webhooks" s-def:"" s-desc:"URLs to POST health transitions (healthy <-> unhealthy) to"`
+	Slack       bool     `json:"slack" s-cli:"health-alerts-slack" s-def:"false" s-desc:"Route health transitions to the slack webhook & channel set in integrations"`
+	CheckRateMs int64    `json:"checkRateMs" s-cli:"health-alerts-check-rate-ms" s-def:"10000" s-desc:"How often to look for health transitions"`
+}
+
+// ChangeLog configuration options
+type ChangeLog struct {
+	Enabled        bool  `json:"enabled" s-cli:"change-log-enabled" s-def:"false" s-desc:"Keep a history of split & segment changes"`
+	RetentionHours int64 `json:"retentionHours" s-cli:"change-log-retention-hours" s-def:"168" s-desc:"How long to keep split & segment change entries"`
+	MaxEntries     int   `json:"maxEntries" s-cli:"change-log-max-entries" s-def:"10000" s-desc:"Max number of split & segment change entries to keep"`
+}
+
+// Fleet configuration options
+type Fleet struct {
+	Enabled         bool     `json:"enabled" s-cli:"fleet-enabled" s-def:"true" s-desc:"Keep an inventory of the sdk instances sending data through this instance"`
+	MinVersions     []string `json:"minVersions" s-cli:"fleet-min-versions" s-def:"" s-desc:"Minimum sdk versions in the form <language>:<version>. Instances running older ones are flagged as outdated"`
+	MaxInstances    int      `json:"maxInstances" s-cli:"fleet-max-instances" s-def:"10000" s-desc:"Max number of sdk instances to keep track of. The least recently seen ones are dropped first"`
+	InstanceTTLSecs int64    `json:"instanceTtlSecs" s-cli:"fleet-instance-ttl-secs" s-def:"86400" s-desc:"Forget sdk instances not seen for this long"`
+}
@@ -0,0 +1,333 @@
+package fleet
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/telemetry"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+)
+
+// Kinds of activity tracked for each sdk instance
+const (
+	ActivitySDK         = "sdk"
+	ActivityImpressions = "impressions"
+	ActivityEvents      = "events"
+	ActivityTelemetry   = "telemetry"
+)
+
+const (
+	rateBuckets       = 5 // minutes taken into account when computing activity rates
+	rateBucketSeconds = 60
+)
+
+// Config options for the inventory
+type Config struct {
+	// Min version by (lowercase) language. Instances running older versions are flagged as outdated
+	MinVersions map[string]string
+
+	// Max number of instances to keep track of. The least recently seen ones are evicted first. Zero means no limit
+	MaxInstances int
+
+	// Instances not seen for this long are forgotten. Zero means never
+	InstanceTTL time.Duration
+}
+
+// ConfigFromOptions builds an inventory config from the user supplied options
+func ConfigFromOptions(opts *conf.Fleet) (Config, error) {
+	minVersions, err := ParseMinVersions(opts.MinVersions)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		MinVersions:  minVersions,
+		MaxInstances: opts.MaxInstances,
+		InstanceTTL:  time.Duration(opts.InstanceTTLSecs) * time.Second,
+	}, nil
+}
+
+// ConfigFlags are the sdk settings reported in telemetry config payloads
+type ConfigFlags struct {
+	OperationMode      string   `json:"operationMode"`
+	Storage            string   `json:"storage,omitempty"`
+	StreamingEnabled   bool     `json:"streamingEnabled"`
+	ImpressionsMode    string   `json:"impressionsMode"`
+	ImpressionListener bool     `json:"impressionListener"`
+	HTTPProxy          bool     `json:"httpProxy"`
+	ActiveFactories    int64    `json:"activeFactories"`
+	RedundantFactories int64    `json:"redundantFactories"`
+	Integrations       []string `json:"integrations,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+// Instance is a view of a single sdk instance
+type Instance struct {
+	SDKVersion      string           `json:"sdkVersion"`
+	Language        string           `json:"language"`
+	Version         string           `json:"version"`
+	MachineName     string           `json:"machineName"`
+	MachineIP       string           `json:"machineIp"`
+	FirstSeen       int64            `json:"firstSeen"`
+	LastSeen        int64            `json:"lastSeen"`
+	ImpressionsMode string           `json:"impressionsMode,omitempty"`
+	Config          *ConfigFlags     `json:"config,omitempty"`
+	Activity        map[string]int64 `json:"activity"`
+	RatePerMinute   float64          `json:"ratePerMinute"`
+	Outdated        bool             `json:"outdated"`
+}
+
+// VersionSummary aggregates the instances running the same sdk version
+type VersionSummary struct {
+	SDKVersion string `json:"sdkVersion"`
+	Language   string `json:"language"`
+	Version    string `json:"version"`
+	Instances  int    `json:"instances"`
+	Outdated   bool   `json:"outdated"`
+}
+
+// Summary is a view of the whole fleet
+type Summary struct {
+	Instances   []Instance        `json:"instances"`
+	Versions    []VersionSummary  `json:"versions"`
+	Outdated    int               `json:"outdated"`
+	MinVersions map[string]string `json:"minVersions"`
+}
+
+type instance struct {
+	metadata        dtos.Metadata
+	element         *list.Element
+	firstSeen       time.Time
+	lastSeen        time.Time
+	impressionsMode string
+	config          *ConfigFlags
+	activity        map[string]int64
+	rateCounts      [rateBuckets]int64
+	rateStarts      [rateBuckets]int64
+}
+
+func (i *instance) track(kind string, count int64, now time.Time) {
+	i.lastSeen = now
+	i.activity[kind] += count
+
+	bucket := now.Unix() / rateBucketSeconds
+	idx := bucket % rateBuckets
+	if i.rateStarts[idx] != bucket {
+		i.rateStarts[idx] = bucket
+		i.rateCounts[idx] = 0
+	}
+	i.rateCounts[idx] += count
+}
+
+func (i *instance) ratePerMinute(now time.Time) float64 {
+	current := now.Unix() / rateBucketSeconds
+	var total int64
+	for idx := range i.rateCounts {
+		if current-i.rateStarts[idx] < rateBuckets {
+			total += i.rateCounts[idx]
+		}
+	}
+	return float64(total) / rateBuckets
+}
+
+// Inventory keeps track of the sdk instances that send data through this synchronizer/proxy, as identified by their metadata
+type Inventory struct {
+	config    Config
+	instances map[dtos.Metadata]*instance
+	byRecency *list.List // most recently seen instances first
+	mutex     sync.Mutex
+	now       func() time.Time
+}
+
+// NewInventory constructs a new fleet inventory
+func NewInventory(config Config) *Inventory {
+	return &Inventory{
+		config:    config,
+		instances: make(map[dtos.Metadata]*instance),
+		byRecency: list.New(),
+		now:       time.Now,
+	}
+}
+
+// Track records `count` items (requests, impressions, events, etc) of a specific kind coming from an sdk instance
+func (i *Inventory) Track(metadata dtos.Metadata, kind string, count int64) {
+	if metadata.SDKVersion == "" {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.get(metadata).track(kind, count, i.now())
+}
+
+// TrackImpressionsMode records the impressions mode reported by an sdk instance
+func (i *Inventory) TrackImpressionsMode(metadata dtos.Metadata, mode string) {
+	if metadata.SDKVersion == "" || mode == "" {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.get(metadata).impressionsMode = mode
+}
+
+// TrackConfig records the settings reported by an sdk instance in a telemetry config payload
+func (i *Inventory) TrackConfig(metadata dtos.Metadata, config *dtos.Config) {
+	if metadata.SDKVersion == "" || config == nil {
+		return
+	}
+
+	flags := &ConfigFlags{
+		OperationMode:      operationModeName(config.OperationMode),
+		Storage:            config.Storage,
+		StreamingEnabled:   config.StreamingEnabled,
+		ImpressionsMode:    impressionsModeName(config.ImpressionsMode),
+		ImpressionListener: config.ImpressionsListenerEnabled,
+		HTTPProxy:          config.HTTPProxyDetected,
+		ActiveFactories:    config.ActiveFactories,
+		RedundantFactories: config.RedundantFactories,
+		Integrations:       config.Integrations,
+		Tags:               config.Tags,
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	inst := i.get(metadata)
+	inst.config = flags
+	inst.impressionsMode = flags.ImpressionsMode
+}
+
+// Summary returns a view of the instances currently tracked, most recently seen first, along with a per-version breakdown
+func (i *Inventory) Summary() Summary {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	now := i.now()
+	i.expire(now)
+
+	summary := Summary{
+		Instances:   make([]Instance, 0, len(i.instances)),
+		Versions:    make([]VersionSummary, 0),
+		MinVersions: i.config.MinVersions,
+	}
+
+	byVersion := make(map[string]*VersionSummary)
+	for metadata, inst := range i.instances {
+		language, version := ParseSDKVersion(metadata.SDKVersion)
+		outdated := i.isOutdated(language, version)
+		activity := make(map[string]int64, len(inst.activity))
+		for kind, count := range inst.activity {
+			activity[kind] = count
+		}
+
+		summary.Instances = append(summary.Instances, Instance{
+			SDKVersion:      metadata.SDKVersion,
+			Language:        language,
+			Version:         version,
+			MachineName:     metadata.MachineName,
+			MachineIP:       metadata.MachineIP,
+			FirstSeen:       inst.firstSeen.UnixNano() / int64(time.Millisecond),
+			LastSeen:        inst.lastSeen.UnixNano() / int64(time.Millisecond),
+			ImpressionsMode: inst.impressionsMode,
+			Config:          inst.config,
+			Activity:        activity,
+			RatePerMinute:   inst.ratePerMinute(now),
+			Outdated:        outdated,
+		})
+
+		if outdated {
+			summary.Outdated++
+		}
+
+		current, ok := byVersion[metadata.SDKVersion]
+		if !ok {
+			current = &VersionSummary{SDKVersion: metadata.SDKVersion, Language: language, Version: version, Outdated: outdated}
+			byVersion[metadata.SDKVersion] = current
+		}
+		current.Instances++
+	}
+
+	for _, version := range byVersion {
+		summary.Versions = append(summary.Versions, *version)
+	}
+
+	sort.Slice(summary.Instances, func(x, y int) bool { return summary.Instances[x].LastSeen > summary.Instances[y].LastSeen })
+	sort.Slice(summary.Versions, func(x, y int) bool {
+		if summary.Versions[x].Language != summary.Versions[y].Language {
+			return summary.Versions[x].Language < summary.Versions[y].Language
+		}
+		return compareVersions(summary.Versions[x].Version, summary.Versions[y].Version) > 0
+	})
+	return summary
+}
+
+func (i *Inventory) isOutdated(language string, version string) bool {
+	min, ok := i.config.MinVersions[language]
+	return ok && version != "" && compareVersions(version, min) < 0
+}
+
+// get returns the entry for an instance, creating it (and evicting others if needed) if it doesn't exist, and marks it as
+// the most recently seen one. Must be called with the lock held
+func (i *Inventory) get(metadata dtos.Metadata) *instance {
+	now := i.now()
+	if inst, ok := i.instances[metadata]; ok {
+		inst.lastSeen = now
+		i.byRecency.MoveToFront(inst.element)
+		return inst
+	}
+
+	if i.config.MaxInstances > 0 && len(i.instances) >= i.config.MaxInstances {
+		i.expire(now)
+		for len(i.instances) >= i.config.MaxInstances {
+			i.remove(i.byRecency.Back())
+		}
+	}
+
+	inst := &instance{metadata: metadata, firstSeen: now, lastSeen: now, activity: make(map[string]int64)}
+	inst.element = i.byRecency.PushFront(inst)
+	i.instances[metadata] = inst
+	return inst
+}
+
+// expire forgets the instances not seen within the configured TTL, starting from the least recently seen one
+func (i *Inventory) expire(now time.Time) {
+	if i.config.InstanceTTL <= 0 {
+		return
+	}
+	for oldest := i.byRecency.Back(); oldest != nil; oldest = i.byRecency.Back() {
+		if now.Sub(oldest.Value.(*instance).lastSeen) <= i.config.InstanceTTL {
+			return
+		}
+		i.remove(oldest)
+	}
+}
+
+func (i *Inventory) remove(element *list.Element) {
+	inst := i.byRecency.Remove(element).(*instance)
+	delete(i.instances, inst.metadata)
+}
+
+func operationModeName(mode int) string {
+	switch mode {
+	case telemetry.Standalone:
+		return "standalone"
+	case telemetry.Consumer:
+		return "consumer"
+	case telemetry.Producer:
+		return "producer"
+	}
+	return "unknown"
+}
+
+func impressionsModeName(mode int) string {
+	switch mode {
+	case telemetry.ImpressionsModeOptimized:
+		return "optimized"
+	case telemetry.ImpressionsModeDebug:
+		return "debug"
+	}
+	return "unknown"
+}
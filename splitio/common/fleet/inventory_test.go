@@ -0,0 +1,157 @@
+package fleet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/telemetry"
+)
+
+func TestParseSDKVersion(t *testing.T) {
+	cases := map[string][2]string{
+		"go-6.1.0":              {"go", "6.1.0"},
+		"javascript-10.20.0-rc": {"javascript", "10.20.0-rc"},
+		"Python-9.1.0":          {"python", "9.1.0"},
+		"react-native-0.1.2":    {"react-native", "0.1.2"},
+		"unversioned":           {"unversioned", ""},
+	}
+	for raw, expected := range cases {
+		if language, version := ParseSDKVersion(raw); language != expected[0] || version != expected[1] {
+			t.Error("wrong parsing for ", raw, ": ", language, version)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"6.1.0", "6.1.0", 0},
+		{"6.1", "6.1.0", 0},
+		{"6.10.0", "6.9.9", 1},
+		{"5.99.0", "6.0.0", -1},
+		{"6.0.0-rc1", "6.0.0", -1},
+		{"6.0.0-rc2", "6.0.0-rc1", 1},
+	}
+	for _, c := range cases {
+		if res := compareVersions(c.v1, c.v2); res != c.expected {
+			t.Error("wrong comparison for ", c.v1, c.v2, ": ", res)
+		}
+	}
+}
+
+func TestParseMinVersions(t *testing.T) {
+	parsed, err := ParseMinVersions([]string{"go:6.0.0", " Java:4.2 ", ""})
+	if err != nil || len(parsed) != 2 || parsed["go"] != "6.0.0" || parsed["java"] != "4.2" {
+		t.Error("wrong min versions parsed: ", parsed, err)
+	}
+
+	for _, invalid := range []string{"go", "go:", ":1.0.0", "go:latest"} {
+		if _, err := ParseMinVersions([]string{invalid}); err == nil {
+			t.Error("an error should have been returned for ", invalid)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	inventory := NewInventory(Config{MinVersions: map[string]string{"go": "6.0.0"}, MaxInstances: 3, InstanceTTL: time.Hour})
+	inventory.now = func() time.Time { return now }
+
+	go1 := dtos.Metadata{SDKVersion: "go-5.2.0", MachineName: "m1", MachineIP: "1.1.1.1"}
+	go2 := dtos.Metadata{SDKVersion: "go-6.1.0", MachineName: "m2", MachineIP: "2.2.2.2"}
+	java := dtos.Metadata{SDKVersion: "java-4.2.0", MachineName: "m3", MachineIP: "3.3.3.3"}
+
+	inventory.Track(go1, ActivityImpressions, 100)
+	inventory.Track(go1, ActivityEvents, 10)
+	inventory.TrackImpressionsMode(go1, "debug")
+	now = now.Add(time.Minute)
+	inventory.Track(go1, ActivityImpressions, 50)
+	inventory.Track(go2, ActivitySDK, 1)
+	inventory.TrackConfig(java, &dtos.Config{OperationMode: telemetry.Consumer, Storage: "redis", ImpressionsMode: telemetry.ImpressionsModeOptimized})
+	inventory.Track(dtos.Metadata{}, ActivitySDK, 1) // ignored
+
+	summary := inventory.Summary()
+	if len(summary.Instances) != 3 || summary.Outdated != 1 {
+		t.Error("wrong summary: ", summary)
+	}
+
+	for _, inst := range summary.Instances {
+		switch inst.SDKVersion {
+		case "go-5.2.0":
+			if !inst.Outdated || inst.Activity[ActivityImpressions] != 150 || inst.Activity[ActivityEvents] != 10 || inst.RatePerMinute != 32 {
+				t.Error("wrong go-5.2.0 instance: ", inst)
+			}
+			if inst.ImpressionsMode != "debug" || inst.Language != "go" || inst.MachineName != "m1" {
+				t.Error("wrong go-5.2.0 instance metadata: ", inst)
+			}
+		case "go-6.1.0":
+			if inst.Outdated || inst.Activity[ActivitySDK] != 1 {
+				t.Error("wrong go-6.1.0 instance: ", inst)
+			}
+		case "java-4.2.0":
+			if inst.Outdated || inst.Config == nil || inst.Config.OperationMode != "consumer" || inst.ImpressionsMode != "optimized" {
+				t.Error("wrong java instance: ", inst, inst.Config)
+			}
+		}
+	}
+
+	if len(summary.Versions) != 3 || summary.Versions[0].SDKVersion != "go-6.1.0" || summary.Versions[1].SDKVersion != "go-5.2.0" || !summary.Versions[1].Outdated {
+		t.Error("versions should be sorted by language & version, newest first: ", summary.Versions)
+	}
+
+	// rates only account for the last few minutes
+	now = now.Add(10 * time.Minute)
+	inventory.Track(go2, ActivitySDK, 1)
+	for _, inst := range inventory.Summary().Instances {
+		if inst.SDKVersion == "go-5.2.0" && inst.RatePerMinute != 0 {
+			t.Error("old activity should not be considered in rates: ", inst.RatePerMinute)
+		}
+	}
+
+	// the least recently seen instance is evicted when the limit is reached
+	inventory.Track(dtos.Metadata{SDKVersion: "python-9.0.0"}, ActivitySDK, 1)
+	for _, inst := range inventory.Summary().Instances {
+		if inst.SDKVersion == "go-5.2.0" {
+			t.Error("least recently seen instance should have been evicted")
+		}
+	}
+
+	// instances are forgotten after the ttl
+	now = now.Add(2 * time.Hour)
+	if summary := inventory.Summary(); len(summary.Instances) != 0 || len(summary.Versions) != 0 {
+		t.Error("expired instances should be removed: ", summary)
+	}
+}
+
+func TestInventoryRecencyOrder(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	inventory := NewInventory(Config{MaxInstances: 2, InstanceTTL: time.Hour})
+	inventory.now = func() time.Time { return now }
+
+	first := dtos.Metadata{SDKVersion: "go-6.0.0", MachineName: "m1"}
+	second := dtos.Metadata{SDKVersion: "go-6.0.0", MachineName: "m2"}
+	third := dtos.Metadata{SDKVersion: "go-6.0.0", MachineName: "m3"}
+
+	inventory.Track(first, ActivitySDK, 1)
+	now = now.Add(time.Minute)
+	inventory.Track(second, ActivitySDK, 1)
+	now = now.Add(time.Minute)
+	inventory.TrackImpressionsMode(first, "debug") // first becomes the most recently seen one
+	now = now.Add(time.Minute)
+	inventory.Track(third, ActivitySDK, 1)
+
+	summary := inventory.Summary()
+	if len(summary.Instances) != 2 || summary.Instances[0].MachineName != "m3" || summary.Instances[1].MachineName != "m1" {
+		t.Error("the least recently seen instance should have been evicted: ", summary.Instances)
+	}
+
+	// only the instances not seen within the ttl are forgotten
+	now = now.Add(time.Hour - 30*time.Second)
+	if summary := inventory.Summary(); len(summary.Instances) != 1 || summary.Instances[0].MachineName != "m3" {
+		t.Error("only the expired instance should be removed: ", summary.Instances)
+	}
+}
@@ -0,0 +1,90 @@
+package fleet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSDKVersion splits an sdk version string (ie: `go-6.1.0`, `javascript-10.20.0-rc1`) into language & version.
+// If no version is found, the whole string is returned as the language
+func ParseSDKVersion(sdkVersion string) (language string, version string) {
+	for idx := 0; idx < len(sdkVersion)-1; idx++ {
+		if sdkVersion[idx] == '-' && sdkVersion[idx+1] >= '0' && sdkVersion[idx+1] <= '9' {
+			return strings.ToLower(sdkVersion[:idx]), sdkVersion[idx+1:]
+		}
+	}
+	return strings.ToLower(sdkVersion), ""
+}
+
+// ParseMinVersions parses minimum version specs in the form `<language>:<version>` (ie: `go:6.0.0`)
+func ParseMinVersions(specs []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+
+		parts := strings.SplitN(spec, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || !isVersion(parts[1]) {
+			return nil, fmt.Errorf("invalid minimum sdk version '%s'. Expected <language>:<version>", spec)
+		}
+		parsed[strings.ToLower(parts[0])] = parts[1]
+	}
+	return parsed, nil
+}
+
+// compareVersions compares dot-separated numeric versions. Pre-release suffixes (ie: `-rc1`) sort before the release.
+// Non-numeric components are compared as 0
+func compareVersions(v1 string, v2 string) int {
+	base1, pre1 := splitPreRelease(v1)
+	base2, pre2 := splitPreRelease(v2)
+	parts1 := strings.Split(base1, ".")
+	parts2 := strings.Split(base2, ".")
+	for idx := 0; idx < len(parts1) || idx < len(parts2); idx++ {
+		n1, n2 := versionComponent(parts1, idx), versionComponent(parts2, idx)
+		if n1 != n2 {
+			if n1 < n2 {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case pre1 == pre2:
+		return 0
+	case pre1 == "":
+		return 1
+	case pre2 == "":
+		return -1
+	case pre1 < pre2:
+		return -1
+	}
+	return 1
+}
+
+func splitPreRelease(version string) (string, string) {
+	if idx := strings.IndexAny(version, "-+"); idx != -1 {
+		return version[:idx], version[idx+1:]
+	}
+	return version, ""
+}
+
+func versionComponent(parts []string, idx int) int64 {
+	if idx >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.ParseInt(parts[idx], 10, 64)
+	return n
+}
+
+func isVersion(version string) bool {
+	base, _ := splitPreRelease(version)
+	for _, part := range strings.Split(base, ".") {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/splitio/go-split-commons/v4/dtos"
 
@@ -39,12 +40,22 @@ type impressionListenerPostBody struct {
 	MachineName string                   `json:"machineName"`
 }
 
+// DeliveryStats contains the amount of impression bulks submitted to the listener & how many of them were not delivered
+type DeliveryStats struct {
+	Submitted int64
+	Dropped   int64
+	Failed    int64
+}
+
 // ImpressionBulkListenerImpl is an implementation of the ImpressionBulkListener interface
 type ImpressionBulkListenerImpl struct {
 	lifecycle  lifecycle.Manager
 	endpoint   string
 	httpClient *http.Client
 	queue      chan impressionListenerPostBody
+	submitted  int64
+	dropped    int64
+	failed     int64
 }
 
 // NewImpressionBulkListener constructs a new impression listner
@@ -69,6 +80,7 @@ func NewImpressionBulkListener(endpoint string, queueSize int, httpClient *http.
 // Submit attempts to push an impression bulk into the queue
 // Will fail if the queue is full
 func (l *ImpressionBulkListenerImpl) Submit(imps []ImpressionsForListener, metadata *dtos.Metadata) error {
+	atomic.AddInt64(&l.submitted, 1)
 	select {
 	case l.queue <- impressionListenerPostBody{
 		Impressions: imps,
@@ -78,10 +90,20 @@ func (l *ImpressionBulkListenerImpl) Submit(imps []ImpressionsForListener, metad
 	}:
 		return nil
 	default:
+		atomic.AddInt64(&l.dropped, 1)
 		return ErrQueueFull
 	}
 }
 
+// DeliveryStats returns the number of bulks submitted, dropped because of a full queue & failed to post since startup
+func (l *ImpressionBulkListenerImpl) DeliveryStats() DeliveryStats {
+	return DeliveryStats{
+		Submitted: atomic.LoadInt64(&l.submitted),
+		Dropped:   atomic.LoadInt64(&l.dropped),
+		Failed:    atomic.LoadInt64(&l.failed),
+	}
+}
+
 // Start the bg task that will take bulks from the queue and post them
 func (l *ImpressionBulkListenerImpl) Start() error {
 	if !l.lifecycle.BeginInitialization() {
@@ -99,7 +121,9 @@ func (l *ImpressionBulkListenerImpl) Start() error {
 			case <-l.lifecycle.ShutdownRequested():
 				return
 			case imps := <-l.queue:
-				l.post(imps)
+				if err := l.post(imps); err != nil {
+					atomic.AddInt64(&l.failed, 1)
+				}
 			}
 		}
 	}()
@@ -132,6 +156,9 @@ func (l *ImpressionBulkListenerImpl) post(imps impressionListenerPostBody) error
 		return err
 	}
 	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("impression listener returned status code %d", response.StatusCode)
+	}
 	return nil
 }
 
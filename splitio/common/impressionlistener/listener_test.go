@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/splitio/go-split-commons/v4/dtos"
 )
@@ -116,3 +117,28 @@ func TestImpressionListener(t *testing.T) {
 
 	<-reqsDone
 }
+
+func TestImpressionListenerDeliveryStats(t *testing.T) {
+	reqsDone := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		reqsDone <- struct{}{}
+	}))
+	defer ts.Close()
+
+	listener, _ := NewImpressionBulkListener(ts.URL, 1, nil)
+	metadata := &dtos.Metadata{SDKVersion: "go-1.1.1"}
+	listener.Submit([]ImpressionsForListener{}, metadata)
+	if err := listener.Submit([]ImpressionsForListener{}, metadata); err != ErrQueueFull {
+		t.Error("second submit should fail with a full queue. Got: ", err)
+	}
+
+	listener.Start()
+	defer listener.Stop(true)
+	<-reqsDone
+	time.Sleep(50 * time.Millisecond)
+
+	if stats := listener.DeliveryStats(); stats.Submitted != 2 || stats.Dropped != 1 || stats.Failed != 1 {
+		t.Error("invalid delivery stats: ", stats)
+	}
+}
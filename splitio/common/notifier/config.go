@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+)
+
+// BuildFromConfig constructs a notifier with the sinks set in the integrations section.
+// Returns nil if no sink is configured
+func BuildFromConfig(cfg *conf.Integrations, title string, logger logging.LoggerInterface) *Notifier {
+	sinks := make(map[string]Sink)
+	if _, err := url.ParseRequestURI(cfg.Slack.Webhook); err == nil && cfg.Slack.Channel != "" {
+		sinks[SinkSlack] = NewSlackSink(cfg.Slack.Webhook, cfg.Slack.Channel, nil)
+	}
+
+	ncfg := &cfg.Notifications
+	var webhooks []string
+	for _, webhook := range ncfg.Webhooks {
+		if webhook != "" {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) > 0 {
+		sinks[SinkWebhook] = NewWebhookSink(webhooks, nil)
+	}
+
+	if ncfg.PagerDutyRoutingKey != "" {
+		sinks[SinkPagerDuty] = NewPagerDutySink(ncfg.PagerDutyURL, ncfg.PagerDutyRoutingKey, nil)
+	}
+
+	var recipients []string
+	for _, to := range ncfg.SMTP.To {
+		if to != "" {
+			recipients = append(recipients, to)
+		}
+	}
+	if ncfg.SMTP.Host != "" && ncfg.SMTP.From != "" && len(recipients) > 0 {
+		sinks[SinkSMTP] = NewSMTPSink(SMTPOptions{
+			Host:     ncfg.SMTP.Host,
+			Port:     int(ncfg.SMTP.Port),
+			Username: ncfg.SMTP.Username,
+			Password: ncfg.SMTP.Password,
+			From:     ncfg.SMTP.From,
+			To:       recipients,
+		})
+	}
+
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	var rules []Rule
+	for _, raw := range ncfg.Rules {
+		if raw == "" {
+			continue
+		}
+		rule, err := ParseRule(raw)
+		if err != nil {
+			logger.Error("Ignoring notification rule: ", err)
+			continue
+		}
+		if _, ok := sinks[rule.Sink]; !ok {
+			logger.Warning("Notification rule '", raw, "' refers to a sink that is not configured. Ignoring.")
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		rules = defaultRules(sinks)
+	}
+
+	return New(title, sinks, rules, Options{
+		QueueSize:          int(ncfg.QueueSize),
+		RateLimitPerMinute: int(ncfg.RateLimitPerMinute),
+		DedupWindow:        time.Duration(ncfg.DedupWindowSecs) * time.Second,
+	}, logger)
+}
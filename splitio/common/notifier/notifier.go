@@ -0,0 +1,323 @@
+package notifier
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/log"
+)
+
+// Notification kinds
+const (
+	KindLog       = "log"
+	KindHealth    = "health"
+	KindLifecycle = "lifecycle"
+)
+
+const (
+	defaultQueueSize    = 500
+	defaultFlushTimeout = 10 * time.Second
+	defaultBatchPeriod  = 500 * time.Millisecond
+	maxDedupEntries     = 1000
+)
+
+// Notification is the unit of information routed to the sinks
+type Notification struct {
+	Kind      string            `json:"kind"`
+	Level     string            `json:"level"`
+	Title     string            `json:"title"`
+	Component string            `json:"component,omitempty"`
+	Message   string            `json:"message"`
+	Key       string            `json:"key,omitempty"`
+	Resolved  bool              `json:"resolved,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// Sink defines the interface of a notification destination
+type Sink interface {
+	Send(notification Notification) error
+}
+
+// BatchingSink defines the interface of a sink that buffers notifications until it's flushed.
+// Both methods are only called from the notifier's delivery goroutine
+type BatchingSink interface {
+	Sink
+	Flush() error
+}
+
+// Options for the notifier
+type Options struct {
+	QueueSize          int
+	RateLimitPerMinute int
+	DedupWindow        time.Duration
+	FlushTimeout       time.Duration
+	BatchPeriod        time.Duration
+}
+
+// Stats holds notifier counters
+type Stats struct {
+	Sent       int64 `json:"sent"`
+	Failed     int64 `json:"failed"`
+	Dropped    int64 `json:"dropped"`
+	Suppressed int64 `json:"suppressed"`
+}
+
+// Notifier routes notifications to sinks based on rules, applying deduplication & per-sink rate limits.
+// Notifications are queued & sent in the background. Stop flushes the queue
+type Notifier struct {
+	title        string
+	sinks        map[string]Sink
+	rules        []Rule
+	rulesMutex   sync.RWMutex
+	queue        chan Notification
+	closed       bool
+	queueMutex   sync.RWMutex
+	done         chan struct{}
+	limiters     map[string]*rateLimiter
+	dedupWindow  time.Duration
+	lastSent     map[string]time.Time
+	flushTimeout time.Duration
+	batchPeriod  time.Duration
+	logger       logging.LoggerInterface
+	stats        Stats
+}
+
+// New constructs a notifier & starts the goroutine that delivers notifications
+func New(title string, sinks map[string]Sink, rules []Rule, opts Options, logger logging.LoggerInterface) *Notifier {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	if opts.FlushTimeout <= 0 {
+		opts.FlushTimeout = defaultFlushTimeout
+	}
+	if opts.BatchPeriod <= 0 {
+		opts.BatchPeriod = defaultBatchPeriod
+	}
+
+	limiters := make(map[string]*rateLimiter, len(sinks))
+	for name := range sinks {
+		limiters[name] = &rateLimiter{limit: opts.RateLimitPerMinute}
+	}
+
+	n := &Notifier{
+		title:        title,
+		sinks:        sinks,
+		rules:        rules,
+		queue:        make(chan Notification, opts.QueueSize),
+		done:         make(chan struct{}),
+		limiters:     limiters,
+		dedupWindow:  opts.DedupWindow,
+		lastSent:     make(map[string]time.Time),
+		flushTimeout: opts.FlushTimeout,
+		batchPeriod:  opts.BatchPeriod,
+		logger:       logger,
+	}
+
+	go n.run()
+	return n
+}
+
+// AddRule appends a routing rule
+func (n *Notifier) AddRule(rule Rule) {
+	n.rulesMutex.Lock()
+	defer n.rulesMutex.Unlock()
+	n.rules = append(n.rules, rule)
+}
+
+// Notify queues a notification if at least one rule matches it. It never blocks:
+// if the queue is full or the notifier has been stopped the notification is dropped
+func (n *Notifier) Notify(notification Notification) {
+	if len(n.targets(&notification)) == 0 {
+		return
+	}
+
+	if notification.Title == "" {
+		notification.Title = n.title
+	}
+	if notification.Timestamp == 0 {
+		notification.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	n.queueMutex.RLock()
+	defer n.queueMutex.RUnlock()
+	if n.closed {
+		atomic.AddInt64(&n.stats.Dropped, 1)
+		return
+	}
+
+	select {
+	case n.queue <- notification:
+	default:
+		atomic.AddInt64(&n.stats.Dropped, 1)
+	}
+}
+
+// Fire implements log.Hook, turning log messages into notifications
+func (n *Notifier) Fire(component string, level int, message string) {
+	if component == log.ComponentNotifier { // avoid loops when a sink fails
+		return
+	}
+
+	n.Notify(Notification{Kind: KindLog, Level: log.LevelName(level), Component: component, Message: message})
+}
+
+// Stop stops accepting notifications & waits (up to the flush timeout) for queued ones to be delivered
+func (n *Notifier) Stop() {
+	n.queueMutex.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.queueMutex.Unlock()
+
+	select {
+	case <-n.done:
+	case <-time.After(n.flushTimeout):
+		n.logger.Warning("Timed out flushing notifications. Pending ones will be lost")
+	}
+}
+
+// Stats returns the notifier counters
+func (n *Notifier) Stats() Stats {
+	return Stats{
+		Sent:       atomic.LoadInt64(&n.stats.Sent),
+		Failed:     atomic.LoadInt64(&n.stats.Failed),
+		Dropped:    atomic.LoadInt64(&n.stats.Dropped),
+		Suppressed: atomic.LoadInt64(&n.stats.Suppressed),
+	}
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+	ticker := time.NewTicker(n.batchPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case notification, ok := <-n.queue:
+			if !ok {
+				n.flushBatches()
+				return
+			}
+			n.dispatch(notification)
+		case <-ticker.C:
+			n.flushBatches()
+		}
+	}
+}
+
+// flushBatches delivers the notifications buffered by batching sinks
+func (n *Notifier) flushBatches() {
+	for name, sink := range n.sinks {
+		batching, ok := sink.(BatchingSink)
+		if !ok {
+			continue
+		}
+		if err := batching.Flush(); err != nil {
+			atomic.AddInt64(&n.stats.Failed, 1)
+			n.logger.Error("error flushing notifications to ", name, ": ", err)
+		}
+	}
+}
+
+func (n *Notifier) dispatch(notification Notification) {
+	now := time.Now()
+	for _, name := range n.targets(&notification) {
+		sink, ok := n.sinks[name]
+		if !ok {
+			continue
+		}
+
+		if n.isDuplicate(name, &notification, now) || !n.limiters[name].allow(now) {
+			atomic.AddInt64(&n.stats.Suppressed, 1)
+			continue
+		}
+
+		if err := sink.Send(notification); err != nil {
+			atomic.AddInt64(&n.stats.Failed, 1)
+			n.logger.Error("error sending notification to ", name, ": ", err)
+			continue
+		}
+		atomic.AddInt64(&n.stats.Sent, 1)
+	}
+}
+
+// targets returns the names of the sinks with at least one rule matching the notification
+func (n *Notifier) targets(notification *Notification) []string {
+	n.rulesMutex.RLock()
+	defer n.rulesMutex.RUnlock()
+
+	var targets []string
+	for idx := range n.rules {
+		rule := &n.rules[idx]
+		if rule.matches(notification) && !contains(targets, rule.Sink) {
+			targets = append(targets, rule.Sink)
+		}
+	}
+	return targets
+}
+
+// isDuplicate returns true if the same notification has been sent to the sink within the dedup window.
+// Only accessed from the delivery goroutine
+func (n *Notifier) isDuplicate(sink string, notification *Notification, now time.Time) bool {
+	if n.dedupWindow <= 0 {
+		return false
+	}
+
+	key := sink + "|" + notification.Kind + "|" + notification.Component + "|" + notification.Key + "|" + notification.Message
+	if notification.Resolved {
+		key += "|resolved"
+	}
+
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < n.dedupWindow {
+		return true
+	}
+
+	if len(n.lastSent) >= maxDedupEntries {
+		for k, last := range n.lastSent {
+			if now.Sub(last) >= n.dedupWindow {
+				delete(n.lastSent, k)
+			}
+		}
+	}
+	n.lastSent[key] = now
+	return false
+}
+
+// rateLimiter allows up to `limit` notifications per minute. A limit <= 0 means no limit
+type rateLimiter struct {
+	limit       int
+	windowStart time.Time
+	count       int
+}
+
+func (r *rateLimiter) allow(now time.Time) bool {
+	if r.limit <= 0 {
+		return true
+	}
+
+	if now.Sub(r.windowStart) >= time.Minute {
+		r.windowStart = now
+		r.count = 0
+	}
+
+	if r.count >= r.limit {
+		return false
+	}
+	r.count++
+	return true
+}
+
+func contains(items []string, item string) bool {
+	for _, current := range items {
+		if current == item {
+			return true
+		}
+	}
+	return false
+}
+
+var _ log.Hook = (*Notifier)(nil)
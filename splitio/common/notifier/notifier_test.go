@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/log"
+)
+
+type sinkMock struct {
+	received []Notification
+	err      error
+	delay    time.Duration
+	mutex    sync.Mutex
+}
+
+func (s *sinkMock) Send(notification Notification) error {
+	time.Sleep(s.delay)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.received = append(s.received, notification)
+	return s.err
+}
+
+func (s *sinkMock) notifications() []Notification {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Notification(nil), s.received...)
+}
+
+type batchingSinkMock struct {
+	sinkMock
+	flushes int
+}
+
+func (s *batchingSinkMock) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.flushes++
+	return nil
+}
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("pagerduty:kind=health|log;level=warning;component=sync")
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if rule.Sink != SinkPagerDuty || len(rule.Kinds) != 2 || rule.MinLevel != logging.LevelWarning || len(rule.Components) != 1 || rule.Components[0] != "sync" {
+		t.Error("invalid rule: ", rule)
+	}
+
+	rule, err = ParseRule("Webhook:kind=Health|LIFECYCLE;component=Sync")
+	if err != nil || rule.Sink != SinkWebhook || len(rule.Kinds) != 2 || rule.Kinds[0] != KindHealth || rule.Kinds[1] != KindLifecycle || rule.Components[0] != "sync" {
+		t.Error("sinks, kinds & components should be case insensitive. Got: ", rule, err)
+	}
+
+	if rule, err := ParseRule("slack"); err != nil || rule.MinLevel != logging.LevelAll || len(rule.Kinds) != 0 {
+		t.Error("a rule without filters should match everything. Got: ", rule, err)
+	}
+
+	for _, invalid := range []string{"irc", "slack:level=loud", "slack:kind=metrics", "slack:color=red", "slack:level"} {
+		if _, err := ParseRule(invalid); err == nil {
+			t.Error("an error was expected for rule: ", invalid)
+		}
+	}
+}
+
+func TestRouting(t *testing.T) {
+	slack, pd := &sinkMock{}, &sinkMock{}
+	rules := []Rule{
+		{Sink: SinkSlack, Kinds: []string{KindLog}, MinLevel: logging.LevelWarning},
+		{Sink: SinkSlack, Kinds: []string{KindHealth}, MinLevel: logging.LevelAll},
+		{Sink: SinkPagerDuty, Components: []string{log.ComponentSync}, MinLevel: logging.LevelError},
+	}
+	n := New("test", map[string]Sink{SinkSlack: slack, SinkPagerDuty: pd}, rules, Options{}, logging.NewLogger(nil))
+
+	n.Fire("", logging.LevelInfo, "not routed")
+	n.Fire("", logging.LevelWarning, "some warning")
+	n.Fire(log.ComponentSync, logging.LevelError, "sync failed")
+	n.Fire(log.ComponentNotifier, logging.LevelError, "error sending notification")
+	n.Notify(Notification{Kind: KindHealth, Level: "info", Message: "recovered", Resolved: true})
+	n.Stop()
+
+	if received := slack.notifications(); len(received) != 3 {
+		t.Error("slack should have received 3 notifications. Got: ", received)
+	} else if received[0].Message != "some warning" || received[0].Title != "test" || received[0].Timestamp == 0 {
+		t.Error("invalid notification: ", received[0])
+	}
+
+	if received := pd.notifications(); len(received) != 1 || received[0].Component != log.ComponentSync {
+		t.Error("pagerduty should only receive the sync error. Got: ", received)
+	}
+
+	if stats := n.Stats(); stats.Sent != 4 {
+		t.Error("4 notifications should have been sent. Got: ", stats)
+	}
+
+	n.Fire("", logging.LevelError, "after stop")
+	if stats := n.Stats(); stats.Dropped != 1 {
+		t.Error("notifications after stop should be dropped. Got: ", stats)
+	}
+}
+
+func TestDedupAndRateLimit(t *testing.T) {
+	sink := &sinkMock{err: errors.New("some error")}
+	n := New("test", map[string]Sink{SinkWebhook: sink}, []Rule{{Sink: SinkWebhook, MinLevel: logging.LevelAll}}, Options{
+		RateLimitPerMinute: 3,
+		DedupWindow:        time.Minute,
+	}, logging.NewLogger(nil))
+
+	n.Notify(Notification{Kind: KindHealth, Key: "application/Splits", Message: "unhealthy"})
+	n.Notify(Notification{Kind: KindHealth, Key: "application/Splits", Message: "unhealthy"})
+	n.Notify(Notification{Kind: KindHealth, Key: "application/Splits", Message: "healthy", Resolved: true})
+	n.Notify(Notification{Kind: KindLog, Message: "a"})
+	n.Notify(Notification{Kind: KindLog, Message: "b"})
+	n.Stop()
+
+	if received := sink.notifications(); len(received) != 3 {
+		t.Error("3 notifications should have reached the sink. Got: ", received)
+	}
+
+	if stats := n.Stats(); stats.Suppressed != 2 || stats.Failed != 3 || stats.Sent != 0 {
+		t.Error("invalid stats: ", stats)
+	}
+}
+
+func TestStopFlushes(t *testing.T) {
+	sink := &sinkMock{delay: 10 * time.Millisecond}
+	n := New("test", map[string]Sink{SinkSlack: sink}, []Rule{{Sink: SinkSlack, MinLevel: logging.LevelAll}}, Options{}, logging.NewLogger(nil))
+	for idx := 0; idx < 10; idx++ {
+		n.Notify(Notification{Kind: KindLifecycle, Message: string(rune('a' + idx))})
+	}
+
+	n.Stop()
+	if received := sink.notifications(); len(received) != 10 {
+		t.Error("all queued notifications should be sent before stop returns. Got: ", len(received))
+	}
+	n.Stop() // should not panic
+}
+
+func TestBatchingSinksFlushed(t *testing.T) {
+	sink := &batchingSinkMock{}
+	n := New("test", map[string]Sink{SinkSlack: sink}, []Rule{{Sink: SinkSlack, MinLevel: logging.LevelAll}}, Options{BatchPeriod: 10 * time.Millisecond}, logging.NewLogger(nil))
+	time.Sleep(50 * time.Millisecond)
+
+	sink.mutex.Lock()
+	periodic := sink.flushes
+	sink.mutex.Unlock()
+	if periodic == 0 {
+		t.Error("batching sinks should be flushed periodically")
+	}
+
+	n.Stop()
+	if sink.flushes <= periodic {
+		t.Error("batching sinks should be flushed on stop")
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	sink := &sinkMock{delay: 50 * time.Millisecond}
+	n := New("test", map[string]Sink{SinkSlack: sink}, []Rule{{Sink: SinkSlack, MinLevel: logging.LevelAll}}, Options{QueueSize: 1}, logging.NewLogger(nil))
+	for idx := 0; idx < 5; idx++ {
+		n.Notify(Notification{Kind: KindLifecycle, Message: string(rune('a' + idx))})
+	}
+	n.Stop()
+
+	if stats := n.Stats(); stats.Dropped == 0 || stats.Sent+stats.Dropped != 5 {
+		t.Error("notifications should be dropped when the queue is full. Got: ", stats)
+	}
+}
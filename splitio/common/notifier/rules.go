@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/log"
+)
+
+// Sink names used in rules
+const (
+	SinkSlack     = "slack"
+	SinkWebhook   = "webhook"
+	SinkPagerDuty = "pagerduty"
+	SinkSMTP      = "smtp"
+)
+
+// Rule routes notifications matching all of its filters to a sink. Empty filters match everything
+type Rule struct {
+	Sink       string
+	Kinds      []string
+	Components []string
+	MinLevel   int // least severe level accepted (go-toolkit levels)
+}
+
+func (r *Rule) matches(notification *Notification) bool {
+	if len(r.Kinds) > 0 && !contains(r.Kinds, notification.Kind) {
+		return false
+	}
+
+	if len(r.Components) > 0 && !contains(r.Components, notification.Component) {
+		return false
+	}
+
+	level, err := log.ParseLevel(notification.Level)
+	if err != nil {
+		level = logging.LevelInfo
+	}
+	return level <= r.MinLevel
+}
+
+// ParseRule parses a rule with the format `<sink>[:<filter>[;<filter>...]]`, where each filter is one of
+// `level=<level>`, `kind=<kind>[|<kind>...]` or `component=<component>[|<component>...]`
+func ParseRule(raw string) (Rule, error) {
+	parts := strings.SplitN(strings.TrimSpace(raw), ":", 2)
+	rule := Rule{Sink: strings.ToLower(parts[0]), MinLevel: logging.LevelAll}
+	switch rule.Sink {
+	case SinkSlack, SinkWebhook, SinkPagerDuty, SinkSMTP:
+	default:
+		return Rule{}, fmt.Errorf("unknown sink '%s' in rule '%s'", parts[0], raw)
+	}
+
+	if len(parts) == 1 {
+		return rule, nil
+	}
+
+	for _, filter := range strings.Split(parts[1], ";") {
+		if strings.TrimSpace(filter) == "" {
+			continue
+		}
+
+		kv := strings.SplitN(filter, "=", 2)
+		if len(kv) != 2 {
+			return Rule{}, fmt.Errorf("invalid filter '%s' in rule '%s'", filter, raw)
+		}
+
+		value := strings.TrimSpace(kv[1])
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "level":
+			level, err := log.ParseLevel(value)
+			if err != nil {
+				return Rule{}, fmt.Errorf("invalid level in rule '%s': %w", raw, err)
+			}
+			rule.MinLevel = level
+		case "kind":
+			for _, kind := range strings.Split(strings.ToLower(value), "|") {
+				kind = strings.TrimSpace(kind)
+				switch kind {
+				case KindLog, KindHealth, KindLifecycle:
+					rule.Kinds = append(rule.Kinds, kind)
+				default:
+					return Rule{}, fmt.Errorf("unknown kind '%s' in rule '%s'", kind, raw)
+				}
+			}
+		case "component":
+			for _, component := range strings.Split(strings.ToLower(value), "|") {
+				rule.Components = append(rule.Components, strings.TrimSpace(component))
+			}
+		default:
+			return Rule{}, fmt.Errorf("unknown filter '%s' in rule '%s'", kv[0], raw)
+		}
+	}
+	return rule, nil
+}
+
+// defaultRules keeps slack mirroring log messages as it did before the notifier existed,
+// and sends health transitions & lifecycle events to the rest of the sinks
+func defaultRules(sinks map[string]Sink) []Rule {
+	var rules []Rule
+	for name := range sinks {
+		switch name {
+		case SinkSlack:
+			rules = append(rules, Rule{Sink: name, Kinds: []string{KindLog, KindLifecycle}, MinLevel: logging.LevelInfo})
+		case SinkWebhook:
+			rules = append(rules, Rule{Sink: name, Kinds: []string{KindHealth, KindLifecycle}, MinLevel: logging.LevelAll})
+		default:
+			rules = append(rules, Rule{Sink: name, Kinds: []string{KindHealth}, MinLevel: logging.LevelAll})
+		}
+	}
+	return rules
+}
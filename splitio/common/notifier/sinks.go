@@ -0,0 +1,412 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout   = 10 * time.Second
+	defaultSMTPTimeout   = 30 * time.Second
+	defaultPagerDutyURL  = "https://events.pagerduty.com/v2/enqueue"
+	pagerDutyTrigger     = "trigger"
+	pagerDutyResolve     = "resolve"
+	slackUsername        = "Split-Sync"
+	slackIcon            = ":robot_face:"
+	maxSlackBatchSize    = 50
+	maxErrorBodyReadSize = 1024
+)
+
+// WebhookSink posts notifications as json to a set of http endpoints
+type WebhookSink struct {
+	urls       []string
+	httpClient *http.Client
+}
+
+// NewWebhookSink constructs a new webhook sink
+func NewWebhookSink(urls []string, httpClient *http.Client) *WebhookSink {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return &WebhookSink{urls: urls, httpClient: httpClient}
+}
+
+// Send posts the notification to every endpoint. The last error (if any) is returned
+func (s *WebhookSink) Send(notification Notification) error {
+	serialized, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("error serializing notification: %w", err)
+	}
+
+	var lastErr error
+	for _, url := range s.urls {
+		if err := postJSON(s.httpClient, url, serialized); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// PagerDutySink sends notifications as PagerDuty Events API v2 events. Notifications with a key are
+// deduplicated by PagerDuty, and resolved notifications close the matching incident
+type PagerDutySink struct {
+	url        string
+	routingKey string
+	source     string
+	httpClient *http.Client
+}
+
+// NewPagerDutySink constructs a new PagerDuty sink. An empty url defaults to the public events endpoint
+func NewPagerDutySink(url string, routingKey string, httpClient *http.Client) *PagerDutySink {
+	if url == "" {
+		url = defaultPagerDutyURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	source, err := os.Hostname()
+	if err != nil {
+		source = "split-synchronizer"
+	}
+	return &PagerDutySink{url: url, routingKey: routingKey, source: source, httpClient: httpClient}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// Send posts the notification to the PagerDuty events endpoint
+func (s *PagerDutySink) Send(notification Notification) error {
+	event := pagerDutyEvent{RoutingKey: s.routingKey, EventAction: pagerDutyTrigger, DedupKey: notification.Key}
+	if notification.Resolved && notification.Key != "" {
+		event.EventAction = pagerDutyResolve
+	} else {
+		event.Payload = &pagerDutyPayload{
+			Summary:       fmt.Sprintf("[%s] %s", notification.Title, notification.Message),
+			Source:        s.source,
+			Severity:      pagerDutySeverity(&notification),
+			Timestamp:     time.Unix(0, notification.Timestamp*int64(time.Millisecond)).UTC().Format(time.RFC3339),
+			Component:     notification.Component,
+			Group:         notification.Title,
+			Class:         notification.Kind,
+			CustomDetails: notification.Fields,
+		}
+	}
+
+	serialized, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error serializing pagerduty event: %w", err)
+	}
+	return postJSON(s.httpClient, s.url, serialized)
+}
+
+func pagerDutySeverity(notification *Notification) string {
+	switch notification.Level {
+	case "error":
+		if notification.Kind == KindHealth {
+			return "critical"
+		}
+		return "error"
+	case "warning":
+		return "warning"
+	}
+	return "info"
+}
+
+// SMTPOptions holds the parameters used to send emails
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPSink sends notifications as plain text emails
+type SMTPSink struct {
+	addr     string
+	host     string
+	from     string
+	to       []string
+	auth     smtp.Auth
+	timeout  time.Duration
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSink constructs a new SMTP sink. Authentication is only used if a username is set
+func NewSMTPSink(opts SMTPOptions) *SMTPSink {
+	var auth smtp.Auth
+	if opts.Username != "" {
+		auth = smtp.PlainAuth("", opts.Username, opts.Password, opts.Host)
+	}
+	sink := &SMTPSink{
+		addr:    fmt.Sprintf("%s:%d", opts.Host, opts.Port),
+		host:    opts.Host,
+		from:    opts.From,
+		to:      opts.To,
+		auth:    auth,
+		timeout: defaultSMTPTimeout,
+	}
+	sink.sendMail = sink.sendMailWithDeadline
+	return sink
+}
+
+// sendMailWithDeadline behaves like smtp.SendMail, but bounds the whole exchange (including dialing) with the sink
+// timeout, so that an unresponsive server cannot stall notification delivery
+func (s *SMTPSink) sendMailWithDeadline(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", addr, s.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server doesn't support AUTH")
+		}
+		if err := client.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Send emails the notification to every recipient
+func (s *SMTPSink) Send(notification Notification) error {
+	subject := fmt.Sprintf("[%s] %s %s", notification.Title, strings.ToUpper(notification.Level), firstLine(notification.Message))
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", s.from)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	fmt.Fprintf(&body, "%s\r\n", notification.Message)
+	if notification.Component != "" {
+		fmt.Fprintf(&body, "\r\nComponent: %s\r\n", notification.Component)
+	}
+	for _, key := range sortedKeys(notification.Fields) {
+		fmt.Fprintf(&body, "%s: %s\r\n", key, notification.Fields[key])
+	}
+
+	if err := s.sendMail(s.addr, s.auth, s.from, s.to, body.Bytes()); err != nil {
+		return fmt.Errorf("error sending notification email: %w", err)
+	}
+	return nil
+}
+
+// SlackSink posts notifications to a slack channel through an incoming webhook. Log notifications are buffered
+// & posted together as a single message when the sink is flushed, to avoid flooding the channel
+type SlackSink struct {
+	webhookURL string
+	channel    string
+	httpClient *http.Client
+	pending    []string
+	omitted    int
+}
+
+// NewSlackSink constructs a new slack sink
+func NewSlackSink(webhookURL string, channel string, httpClient *http.Client) *SlackSink {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return &SlackSink{webhookURL: webhookURL, channel: channel, httpClient: httpClient}
+}
+
+type slackPayload struct {
+	Channel     string            `json:"channel"`
+	Username    string            `json:"username"`
+	Text        string            `json:"text"`
+	IconEmoji   string            `json:"icon_emoji"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Fallback string       `json:"fallback"`
+	Color    string       `json:"color"` // Can either be one of 'good', 'warning', 'danger', or any hex color code
+	Fields   []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Send posts the notification to slack
+func (s *SlackSink) Send(notification Notification) error {
+	source := notification.Title
+	if notification.Component != "" {
+		source += "/" + notification.Component
+	}
+
+	payload := slackPayload{
+		Channel:   s.channel,
+		Username:  slackUsername,
+		Text:      fmt.Sprintf("*[%s]* %s", source, notification.Message),
+		IconEmoji: slackIcon,
+	}
+
+	if notification.Kind == KindLog {
+		s.buffer(fmt.Sprintf("*[%s]* %s: %s", source, strings.ToUpper(notification.Level), notification.Message))
+		return nil
+	}
+
+	if len(notification.Fields) > 0 {
+		fields := make([]slackField, 0, len(notification.Fields))
+		for _, key := range sortedKeys(notification.Fields) {
+			fields = append(fields, slackField{Title: key, Value: notification.Fields[key], Short: len(notification.Fields[key]) < 40})
+		}
+		payload.Attachments = []slackAttachment{{Fallback: payload.Text, Color: slackColor(&notification), Fields: fields}}
+	}
+
+	return s.post(&payload)
+}
+
+// Flush posts all the buffered log notifications as a single message
+func (s *SlackSink) Flush() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	text := strings.Join(s.pending, "\n")
+	if s.omitted > 0 {
+		text += fmt.Sprintf("\n_%d more log messages omitted_", s.omitted)
+	}
+	s.pending = s.pending[:0]
+	s.omitted = 0
+
+	return s.post(&slackPayload{Channel: s.channel, Username: slackUsername, Text: text, IconEmoji: slackIcon})
+}
+
+func (s *SlackSink) buffer(line string) {
+	if len(s.pending) >= maxSlackBatchSize {
+		s.omitted++
+		return
+	}
+	s.pending = append(s.pending, line)
+}
+
+func (s *SlackSink) post(payload *slackPayload) error {
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error serializing slack message: %w", err)
+	}
+	return postJSON(s.httpClient, s.webhookURL, serialized)
+}
+
+func slackColor(notification *Notification) string {
+	if notification.Resolved {
+		return "good"
+	}
+	switch notification.Level {
+	case "error":
+		return "danger"
+	case "warning":
+		return "warning"
+	}
+	return "good"
+}
+
+func postJSON(client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting notification to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyReadSize))
+		return fmt.Errorf("%s returned status code %d: %s", url, resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func firstLine(message string) string {
+	if idx := strings.IndexByte(message, '\n'); idx >= 0 {
+		return message[:idx]
+	}
+	return message
+}
+
+func sortedKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ Sink = (*WebhookSink)(nil)
+var _ Sink = (*PagerDutySink)(nil)
+var _ Sink = (*SMTPSink)(nil)
+var _ BatchingSink = (*SlackSink)(nil)
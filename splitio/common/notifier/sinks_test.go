@@ -0,0 +1,238 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+)
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan Notification, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification Notification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- notification
+	}))
+	defer ts.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	sink := NewWebhookSink([]string{failing.URL, ts.URL}, nil)
+	if err := sink.Send(Notification{Kind: KindLog, Level: "error", Message: "something"}); err == nil {
+		t.Error("the error of the failing endpoint should be returned")
+	}
+	if got := <-received; got.Message != "something" || got.Level != "error" {
+		t.Error("invalid notification: ", got)
+	}
+}
+
+func TestPagerDutySink(t *testing.T) {
+	received := make(chan pagerDutyEvent, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	sink := NewPagerDutySink(ts.URL, "someKey", nil)
+	notification := Notification{Kind: KindHealth, Level: "error", Title: "Split Proxy", Message: "item is now unhealthy", Key: "application/Splits", Timestamp: 1}
+	if err := sink.Send(notification); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	event := <-received
+	if event.RoutingKey != "someKey" || event.EventAction != pagerDutyTrigger || event.DedupKey != "application/Splits" {
+		t.Error("invalid event: ", event)
+	}
+	if event.Payload == nil || event.Payload.Severity != "critical" || event.Payload.Summary != "[Split Proxy] item is now unhealthy" {
+		t.Error("invalid payload: ", event.Payload)
+	}
+
+	notification.Resolved = true
+	if err := sink.Send(notification); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if event := <-received; event.EventAction != pagerDutyResolve || event.DedupKey != "application/Splits" || event.Payload != nil {
+		t.Error("invalid resolve event: ", event)
+	}
+}
+
+func TestSMTPSink(t *testing.T) {
+	sink := NewSMTPSink(SMTPOptions{Host: "smtp.example.com", Port: 587, From: "sync@example.com", To: []string{"a@example.com", "b@example.com"}})
+	var sentTo []string
+	var sentBody string
+	sink.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:587" || from != "sync@example.com" || a != nil {
+			t.Error("invalid parameters: ", addr, from, a)
+		}
+		sentTo, sentBody = to, string(msg)
+		return nil
+	}
+
+	err := sink.Send(Notification{Kind: KindHealth, Level: "warning", Title: "Split Sync", Message: "queues are full", Fields: map[string]string{"Severity": "low"}})
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if len(sentTo) != 2 {
+		t.Error("both recipients should receive the email. Got: ", sentTo)
+	}
+	if !strings.Contains(sentBody, "Subject: [Split Sync] WARNING queues are full\r\n") || !strings.Contains(sentBody, "Severity: low") {
+		t.Error("invalid email: ", sentBody)
+	}
+}
+
+func TestSMTPSinkTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("error listening: ", err)
+	}
+	defer listener.Close()
+	go func() {
+		// accept connections without ever sending the smtp greeting
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				for _, conn := range conns {
+					conn.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	sink := NewSMTPSink(SMTPOptions{Host: "127.0.0.1", Port: port, From: "sync@example.com", To: []string{"a@example.com"}})
+	sink.timeout = 100 * time.Millisecond
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.Send(Notification{Kind: KindHealth, Level: "error", Title: "Split Sync", Message: "down"})
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("an error should be returned when the server doesn't respond")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("sending should give up once the timeout expires")
+	}
+}
+
+func TestSlackSink(t *testing.T) {
+	received := make(chan slackPayload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- payload
+	}))
+	defer ts.Close()
+
+	sink := NewSlackSink(ts.URL, "#alerts", nil)
+	err := sink.Send(Notification{Kind: KindHealth, Level: "error", Title: "Split Proxy", Component: "healthcheck", Message: "item is now unhealthy", Fields: map[string]string{"Severity": "critical"}})
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	payload := <-received
+	if payload.Channel != "#alerts" || payload.Text != "*[Split Proxy/healthcheck]* item is now unhealthy" {
+		t.Error("invalid payload: ", payload)
+	}
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Color != "danger" || payload.Attachments[0].Fields[0].Value != "critical" {
+		t.Error("invalid attachments: ", payload.Attachments)
+	}
+}
+
+func TestSlackSinkBatchesLogs(t *testing.T) {
+	received := make(chan slackPayload, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- payload
+	}))
+	defer ts.Close()
+
+	sink := NewSlackSink(ts.URL, "#alerts", nil)
+	for idx := 0; idx < maxSlackBatchSize+2; idx++ {
+		if err := sink.Send(Notification{Kind: KindLog, Level: "warning", Title: "Split Sync", Component: "sync", Message: "retrying"}); err != nil {
+			t.Error("no error expected. Got: ", err)
+		}
+	}
+
+	select {
+	case payload := <-received:
+		t.Error("log notifications should not be posted until the sink is flushed. Got: ", payload)
+	default:
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	payload := <-received
+	lines := strings.Split(payload.Text, "\n")
+	if len(lines) != maxSlackBatchSize+1 || lines[0] != "*[Split Sync/sync]* WARNING: retrying" || lines[maxSlackBatchSize] != "_2 more log messages omitted_" {
+		t.Error("buffered logs should be posted as a single message. Got: ", payload.Text)
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	select {
+	case payload := <-received:
+		t.Error("nothing should be posted when no logs are buffered. Got: ", payload)
+	default:
+	}
+}
+
+func TestBuildFromConfig(t *testing.T) {
+	logger := logging.NewLogger(nil)
+	if n := BuildFromConfig(&conf.Integrations{Notifications: conf.Notifications{Webhooks: []string{""}}}, "test", logger); n != nil {
+		t.Error("no notifier should be built without sinks")
+	}
+
+	n := BuildFromConfig(&conf.Integrations{
+		Slack: conf.Slack{Webhook: "https://hooks.slack.com/something", Channel: "#alerts"},
+		Notifications: conf.Notifications{
+			PagerDutyRoutingKey: "someKey",
+			Rules:               []string{"", "pagerduty:kind=health", "smtp:level=error", "webhook:kind=log|metrics"},
+		},
+	}, "test", logger)
+	if n == nil {
+		t.Error("a notifier should be built")
+		return
+	}
+	defer n.Stop()
+
+	if len(n.sinks) != 2 || len(n.rules) != 1 || n.rules[0].Sink != SinkPagerDuty {
+		t.Error("only the pagerduty rule should be used. Got: ", n.rules)
+	}
+
+	n = BuildFromConfig(&conf.Integrations{Slack: conf.Slack{Webhook: "https://hooks.slack.com/something", Channel: "#alerts"}}, "test", logger)
+	defer n.Stop()
+	if len(n.rules) != 1 || n.rules[0].MinLevel != logging.LevelInfo {
+		t.Error("slack should mirror info logs by default. Got: ", n.rules)
+	}
+}
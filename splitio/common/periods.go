@@ -0,0 +1,10 @@
+package common
+
+// PeriodSecs converts a period set in milliseconds into the seconds expected by async tasks.
+// Periods shorter than a second are rounded up to 1, since a 0 period would make tasks spin (or panic)
+func PeriodSecs(ms int64) int {
+	if secs := int(ms / 1000); secs > 0 {
+		return secs
+	}
+	return 1
+}
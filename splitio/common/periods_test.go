@@ -0,0 +1,12 @@
+package common
+
+import "testing"
+
+func TestPeriodSecs(t *testing.T) {
+	cases := map[int64]int{-1: 1, 0: 1, 1: 1, 999: 1, 1000: 1, 1999: 1, 60000: 60}
+	for ms, expected := range cases {
+		if secs := PeriodSecs(ms); secs != expected {
+			t.Error("wrong period for ", ms, "ms: ", secs)
+		}
+	}
+}
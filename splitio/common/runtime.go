@@ -12,6 +12,7 @@ import (
 	"github.com/splitio/go-toolkit/v5/sync"
 
 	"github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
 	"github.com/splitio/split-synchronizer/v5/splitio/log"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
@@ -25,6 +26,7 @@ type Runtime interface {
 	Uptime() time.Duration
 	Shutdown()
 	Kill()
+	RegisterShutdownHook(hook func())
 }
 
 // RuntimeImpl provides an implementation for the Runtime interface
@@ -34,13 +36,14 @@ type RuntimeImpl struct {
 	shutdownRegistered *sync.AtomicBool
 	logger             logging.LoggerInterface
 	dashboardTitle     string
-	slackWriter        *log.SlackWriter
+	notifier           *notifier.Notifier
 	syncManager        synchronizer.Manager
 	impListener        impressionlistener.ImpressionBulkListener
 	blocker            chan struct{}
 	osSignals          chan os.Signal
 	appMonitor         application.MonitorIterface
 	servicesMonitor    services.MonitorIterface
+	shutdownHooks      []func()
 }
 
 // NewRuntime constructs a RuntimeImpl object
@@ -50,7 +53,7 @@ func NewRuntime(
 	logger logging.LoggerInterface,
 	dashboardTitle string,
 	listener impressionlistener.ImpressionBulkListener,
-	slackWriter *log.SlackWriter,
+	notifier *notifier.Notifier,
 	appMonitor application.MonitorIterface,
 	servicesMonitor services.MonitorIterface,
 ) *RuntimeImpl {
@@ -59,7 +62,7 @@ func NewRuntime(
 		startup:            time.Now(),
 		logger:             logger,
 		dashboardTitle:     dashboardTitle,
-		slackWriter:        slackWriter,
+		notifier:           notifier,
 		syncManager:        syncManager,
 		impListener:        listener,
 		blocker:            make(chan struct{}),
@@ -93,22 +96,38 @@ func (r *RuntimeImpl) Uptime() time.Duration {
 	return time.Now().Sub(r.startup)
 }
 
+// RegisterShutdownHook adds a function to be called during a graceful shutdown, once synchronization has stopped.
+// Must be called before the shutdown handler is registered
+func (r *RuntimeImpl) RegisterShutdownHook(hook func()) {
+	r.shutdownHooks = append(r.shutdownHooks, hook)
+}
+
 // Shutdown stops sends a SIGTERM to the current process
 func (r *RuntimeImpl) Shutdown() {
 	r.logger.Info("\n\n * Starting graceful shutdown")
 	r.logger.Info(" * Waiting goroutines stop")
-	if r.slackWriter != nil {
-		message, attachments := buildSlackShutdownMessage(r.dashboardTitle, false)
-		r.slackWriter.PostNow(message, attachments)
+	// lifecycle events are notified with info level, as long as the configured log level allows it
+	if levels := log.LevelsOf(r.logger); r.notifier != nil && (levels == nil || levels.Enabled("", logging.LevelInfo)) {
+		r.notifier.Notify(notifier.Notification{
+			Kind:    notifier.KindLifecycle,
+			Level:   log.LevelName(logging.LevelInfo),
+			Message: "Shutting down - see you soon!",
+		})
 	}
 	r.syncManager.Stop()
 	if r.impListener != nil {
 		r.impListener.Stop(true)
 	}
+	for _, hook := range r.shutdownHooks {
+		hook()
+	}
 	r.appMonitor.Stop()
 	r.servicesMonitor.Stop()
 
 	r.logger.Info(" * Shutdown complete - see you soon!")
+	if r.notifier != nil {
+		r.notifier.Stop() // flush pending notifications, including the messages logged during shutdown
+	}
 	r.blocker <- struct{}{}
 }
 
@@ -121,32 +140,3 @@ func (r *RuntimeImpl) Block() {
 func (r *RuntimeImpl) Kill() {
 	r.osSignals <- syscall.SIGKILL
 }
-
-func buildSlackShutdownMessage(title string, kill bool) ([]byte, []log.SlackMessageAttachment) {
-	var color string
-	var message string
-	if kill {
-		color = "danger"
-		message = "*[KILL]* Force shutdown signal sent - see you soon!"
-	} else {
-		color = "good"
-		message = "*[Important]* Shutting down split-sync - see you soon!"
-	}
-
-	var attach []log.SlackMessageAttachment
-	if title != "" {
-		fields := make([]log.SlackMessageAttachmentFields, 0)
-		fields = append(fields)
-		attach = []log.SlackMessageAttachment{log.SlackMessageAttachment{
-			Fallback: "Shutting Split-Sync down",
-			Color:    color,
-			Fields: []log.SlackMessageAttachmentFields{{
-				Title: title,
-				Value: "Shutting it down, see you soon!",
-				Short: false,
-			}},
-		}}
-	}
-
-	return []byte(message), attach
-}
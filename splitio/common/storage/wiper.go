@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/segment"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/split"
+)
+
+// DataWiper interface to be implemented by components capable of removing all the synchronized splits & segments
+type DataWiper interface {
+	WipeAll() error
+}
+
+// SyncGuard keeps guarded split & segment updaters from synchronizing while data is being wiped, so that a sync that
+// was already running doesn't write stale data (or change numbers) back once the storage has been cleared
+type SyncGuard struct {
+	mutex sync.RWMutex // held for writing while wiping & for reading while synchronizing
+}
+
+// Exclusive waits for ongoing synchronizations & runs the supplied function before allowing new ones
+func (g *SyncGuard) Exclusive(fn func() error) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return fn()
+}
+
+// GuardSplitUpdater wraps a split updater so that it doesn't synchronize while data is being wiped
+func (g *SyncGuard) GuardSplitUpdater(wrapped split.Updater) split.Updater {
+	return &guardedSplitUpdater{Updater: wrapped, mutex: &g.mutex}
+}
+
+// GuardSegmentUpdater wraps a segment updater so that it doesn't synchronize while data is being wiped
+func (g *SyncGuard) GuardSegmentUpdater(wrapped segment.Updater) segment.Updater {
+	return &guardedSegmentUpdater{Updater: wrapped, mutex: &g.mutex}
+}
+
+type guardedSplitUpdater struct {
+	split.Updater
+	mutex *sync.RWMutex
+}
+
+func (u *guardedSplitUpdater) SynchronizeSplits(till *int64) (*split.UpdateResult, error) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.Updater.SynchronizeSplits(till)
+}
+
+func (u *guardedSplitUpdater) LocalKill(splitName string, defaultTreatment string, changeNumber int64) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	u.Updater.LocalKill(splitName, defaultTreatment, changeNumber)
+}
+
+type guardedSegmentUpdater struct {
+	segment.Updater
+	mutex *sync.RWMutex
+}
+
+func (u *guardedSegmentUpdater) SynchronizeSegment(name string, till *int64) (*segment.UpdateResult, error) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.Updater.SynchronizeSegment(name, till)
+}
+
+func (u *guardedSegmentUpdater) SynchronizeSegments() (map[string]segment.UpdateResult, error) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.Updater.SynchronizeSegments()
+}
@@ -80,8 +80,10 @@ type HistoricLogger interface {
 
 // NewHistoricLoggerWrapper constructs a new historic logger
 func NewHistoricLoggerWrapper(l logging.LoggerInterface, enabled [logLevelCount]bool, size int) *HistoricLoggerWrapper {
+	emitter, _ := l.(componentEmitter)
 	return &HistoricLoggerWrapper{
 		LoggerInterface: l,
+		emitter:         emitter,
 		buffers: [logLevelCount]historicBuffer{
 			*newHistoricBuffer(enabled[logging.LevelError-logging.LevelError], size),
 			*newHistoricBuffer(enabled[logging.LevelWarning-logging.LevelError], size),
@@ -95,6 +97,7 @@ func NewHistoricLoggerWrapper(l logging.LoggerInterface, enabled [logLevelCount]
 // HistoricLoggerWrapper is an implementation of the HistoricLogger interface
 type HistoricLoggerWrapper struct {
 	logging.LoggerInterface
+	emitter componentEmitter
 	buffers [logLevelCount]historicBuffer
 }
 
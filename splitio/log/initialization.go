@@ -5,7 +5,6 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
-	"net/url"
 	"os"
 	"strings"
 
@@ -22,8 +21,8 @@ func meansStdout(s string) bool {
 	}
 }
 
-// BuildFromConfig creates a logger from a config
-func BuildFromConfig(cfg *conf.Logging, prefix string, slackCfg *conf.Slack) *HistoricLoggerWrapper {
+// BuildFromConfig creates a logger from a config. Use AttachHook to forward messages to other destinations
+func BuildFromConfig(cfg *conf.Logging, prefix string) *HistoricLoggerWrapper {
 	var err error
 	var mainWriter io.Writer = os.Stdout
 
@@ -41,39 +40,49 @@ func BuildFromConfig(cfg *conf.Logging, prefix string, slackCfg *conf.Slack) *Hi
 		}
 	}
 
-	nonDebugWriter := mainWriter
-	_, err = url.ParseRequestURI(slackCfg.Webhook)
-	if err == nil && slackCfg.Channel != "" {
-		nonDebugWriter = io.MultiWriter(mainWriter, NewSlackWriter(slackCfg.Webhook, slackCfg.Channel))
-	}
+	return newLogger(cfg, prefix, mainWriter)
+}
 
-	var level int
-	switch strings.ToUpper(cfg.Level) {
-	case "VERBOSE":
-		level = logging.LevelVerbose
-	case "DEBUG":
-		level = logging.LevelDebug
-	case "INFO":
-		level = logging.LevelInfo
-	case "WARNING", "WARN":
+func newLogger(cfg *conf.Logging, prefix string, writer io.Writer) *HistoricLoggerWrapper {
+	level, levelErr := ParseLevel(cfg.Level)
+	if levelErr != nil {
 		level = logging.LevelError
-	case "ERROR":
-		level = logging.LevelWarning
-	case "NONE":
-		level = logging.LevelNone
+	}
+
+	levels := NewLevelRegistry(level)
+	componentsErr := levels.SetComponentLevels(cfg.ComponentLevels)
+
+	backend := &leveledLogger{levels: levels}
+	if strings.ToLower(cfg.Format) == FormatJSON {
+		backend.json = &jsonEncoder{
+			app:         prefix,
+			environment: cfg.Environment,
+			writer:      writer,
+		}
+	} else {
+		// filtering is done by the level registry, so that levels can be changed at runtime
+		backend.text = logging.NewLogger(&logging.LoggerOptions{
+			StandardLoggerFlags: log.Ldate | log.Ltime | log.Lshortfile,
+			Prefix:              prefix,
+			VerboseWriter:       writer,
+			DebugWriter:         writer,
+			InfoWriter:          writer,
+			WarningWriter:       writer,
+			ErrorWriter:         writer,
+			LogLevel:            logging.LevelAll,
+			ExtraFramesToSkip:   emitFramesToSkip,
+		})
 	}
 
 	// buffer error, warning & info. don't buffer debug and verbose
 	buffered := [5]bool{true, true, true, false, false}
-	return NewHistoricLoggerWrapper(logging.NewLogger(&logging.LoggerOptions{
-		StandardLoggerFlags: log.Ldate | log.Ltime | log.Lshortfile,
-		Prefix:              prefix,
-		VerboseWriter:       mainWriter,
-		DebugWriter:         mainWriter,
-		InfoWriter:          nonDebugWriter,
-		WarningWriter:       nonDebugWriter,
-		ErrorWriter:         nonDebugWriter,
-		LogLevel:            level,
-		ExtraFramesToSkip:   1,
-	}), buffered, 5)
+	logger := NewHistoricLoggerWrapper(backend, buffered, 5)
+
+	if levelErr != nil {
+		logger.Error(fmt.Sprintf("%s. Defaulting to error.", levelErr))
+	}
+	if componentsErr != nil {
+		logger.Error(fmt.Sprintf("Error parsing component log levels: %s. Ignoring.", componentsErr))
+	}
+	return logger
 }
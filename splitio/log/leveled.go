@@ -0,0 +1,235 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Supported log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// stack frames between the caller of a logging function & leveledLogger.emit
+const emitFramesToSkip = 3
+
+// componentEmitter is implemented by loggers capable of tagging messages with a component
+type componentEmitter interface {
+	emit(component string, level int, msg ...interface{})
+}
+
+// Hook receives every message that passes the level filter, already formatted
+type Hook interface {
+	Fire(component string, level int, message string)
+}
+
+// leveledLogger filters messages using a level registry (that can be updated at runtime) and writes them
+// either as plain text (through a go-toolkit logger) or as json lines
+type leveledLogger struct {
+	levels *LevelRegistry
+	text   logging.LoggerInterface
+	json   *jsonEncoder
+	hook   atomic.Value // Hook
+}
+
+// Error writes a log message with Error level
+func (l *leveledLogger) Error(msg ...interface{}) {
+	l.emit("", logging.LevelError, msg...)
+}
+
+// Warning writes a log message with Warning level
+func (l *leveledLogger) Warning(msg ...interface{}) {
+	l.emit("", logging.LevelWarning, msg...)
+}
+
+// Info writes a log message with Info level
+func (l *leveledLogger) Info(msg ...interface{}) {
+	l.emit("", logging.LevelInfo, msg...)
+}
+
+// Debug writes a log message with Debug level
+func (l *leveledLogger) Debug(msg ...interface{}) {
+	l.emit("", logging.LevelDebug, msg...)
+}
+
+// Verbose writes a log message with Verbose level
+func (l *leveledLogger) Verbose(msg ...interface{}) {
+	l.emit("", logging.LevelVerbose, msg...)
+}
+
+func (l *leveledLogger) emit(component string, level int, msg ...interface{}) {
+	if !l.levels.Enabled(component, level) {
+		return
+	}
+
+	if hook, ok := l.hook.Load().(Hook); ok {
+		hook.Fire(component, level, formatMessage(msg...))
+	}
+
+	if l.json != nil {
+		l.json.write(component, level, msg...)
+		return
+	}
+
+	if component != "" {
+		msg = append([]interface{}{"[" + component + "]"}, msg...)
+	}
+
+	switch level {
+	case logging.LevelError:
+		l.text.Error(msg...)
+	case logging.LevelWarning:
+		l.text.Warning(msg...)
+	case logging.LevelInfo:
+		l.text.Info(msg...)
+	case logging.LevelDebug:
+		l.text.Debug(msg...)
+	case logging.LevelVerbose:
+		l.text.Verbose(msg...)
+	}
+}
+
+type jsonRecord struct {
+	Timestamp   string `json:"timestamp"`
+	Level       string `json:"level"`
+	App         string `json:"app,omitempty"`
+	Component   string `json:"component,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Caller      string `json:"caller,omitempty"`
+	Message     string `json:"message"`
+	Error       string `json:"error,omitempty"`
+}
+
+// jsonEncoder writes one json object per line, so that log lines can be reliably parsed by log pipelines
+type jsonEncoder struct {
+	app         string
+	environment string
+	writer      io.Writer
+	mutex       sync.Mutex
+}
+
+func (e *jsonEncoder) write(component string, level int, msg ...interface{}) {
+	record := jsonRecord{
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Level:       LevelName(level),
+		App:         e.app,
+		Component:   component,
+		Environment: e.environment,
+		Message:     formatMessage(msg...),
+	}
+
+	// +1 to account for this function
+	if _, file, line, ok := runtime.Caller(emitFramesToSkip + 1); ok {
+		record.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	for idx := len(msg) - 1; idx >= 0; idx-- {
+		if err, ok := msg[idx].(error); ok {
+			record.Error = err.Error()
+			break
+		}
+	}
+
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.writer.Write(append(serialized, '\n'))
+}
+
+func formatMessage(msg ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(msg...), "\n")
+}
+
+// componentLogger tags all messages with a component & is filtered using that component's level.
+// Recent messages are still recorded in the parent's history
+type componentLogger struct {
+	parent    *HistoricLoggerWrapper
+	component string
+}
+
+// Error writes a log message with Error level
+func (c *componentLogger) Error(msg ...interface{}) {
+	c.log(logging.LevelError, msg...)
+}
+
+// Warning writes a log message with Warning level
+func (c *componentLogger) Warning(msg ...interface{}) {
+	c.log(logging.LevelWarning, msg...)
+}
+
+// Info writes a log message with Info level
+func (c *componentLogger) Info(msg ...interface{}) {
+	c.log(logging.LevelInfo, msg...)
+}
+
+// Debug writes a log message with Debug level
+func (c *componentLogger) Debug(msg ...interface{}) {
+	c.log(logging.LevelDebug, msg...)
+}
+
+// Verbose writes a log message with Verbose level
+func (c *componentLogger) Verbose(msg ...interface{}) {
+	c.log(logging.LevelVerbose, msg...)
+}
+
+// Messages returns the buffered messages for a specific level
+func (c *componentLogger) Messages(level int) []string {
+	return c.parent.Messages(level)
+}
+
+// TotalCount returns the total number of messages logged for a specific level
+func (c *componentLogger) TotalCount(level int) int64 {
+	return c.parent.TotalCount(level)
+}
+
+func (c *componentLogger) log(level int, msg ...interface{}) {
+	c.parent.toHistory(level, msg...)
+	c.parent.emitter.emit(c.component, level, msg...)
+}
+
+// Component returns a logger tagged with the specified component, if the logger supports it.
+// Otherwise the same logger is returned
+func Component(logger logging.LoggerInterface, component string) logging.LoggerInterface {
+	if historic, ok := logger.(*HistoricLoggerWrapper); ok && historic.emitter != nil {
+		return &componentLogger{parent: historic, component: component}
+	}
+	return logger
+}
+
+// LevelsOf returns the level registry of a logger built with BuildFromConfig, or nil if the logger doesn't support runtime levels
+func LevelsOf(logger logging.LoggerInterface) *LevelRegistry {
+	if historic, ok := logger.(*HistoricLoggerWrapper); ok {
+		if leveled, ok := historic.LoggerInterface.(*leveledLogger); ok {
+			return leveled.levels
+		}
+	}
+	return nil
+}
+
+// AttachHook makes a logger built with BuildFromConfig forward every emitted message to the hook.
+// Returns false if the logger doesn't support hooks
+func AttachHook(logger logging.LoggerInterface, hook Hook) bool {
+	if historic, ok := logger.(*HistoricLoggerWrapper); ok {
+		if leveled, ok := historic.LoggerInterface.(*leveledLogger); ok {
+			leveled.hook.Store(hook)
+			return true
+		}
+	}
+	return false
+}
+
+var _ HistoricLogger = (*componentLogger)(nil)
@@ -0,0 +1,161 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+)
+
+func readLines(buffer *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buffer.String()), "\n")
+}
+
+func TestJSONLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newLogger(&conf.Logging{
+		Level:           "info",
+		Format:          FormatJSON,
+		Environment:     "staging",
+		ComponentLevels: []string{"pipelined=debug", ""},
+	}, "Split-Sync", &buffer)
+
+	logger.Info("hello", "world")
+	logger.Debug("filtered out")
+	Component(logger, ComponentPipelined).Debug("fetched items")
+	Component(logger, ComponentSync).Error("sync failed: ", errors.New("some error"))
+
+	lines := readLines(&buffer)
+	if len(lines) != 3 {
+		t.Error("3 lines should have been written. Got: ", lines)
+		return
+	}
+
+	var records [3]jsonRecord
+	for idx := range lines {
+		if err := json.Unmarshal([]byte(lines[idx]), &records[idx]); err != nil {
+			t.Error("invalid json line: ", lines[idx], err)
+		}
+	}
+
+	if r := records[0]; r.Level != "info" || r.Message != "hello world" || r.App != "Split-Sync" || r.Environment != "staging" ||
+		r.Component != "" || r.Timestamp == "" || !strings.HasPrefix(r.Caller, "leveled_test.go:") {
+		t.Error("invalid record: ", r)
+	}
+
+	if r := records[1]; r.Level != "debug" || r.Component != ComponentPipelined || !strings.HasPrefix(r.Caller, "leveled_test.go:") {
+		t.Error("invalid record: ", r)
+	}
+
+	if r := records[2]; r.Level != "error" || r.Component != ComponentSync || r.Error != "some error" {
+		t.Error("invalid record: ", r)
+	}
+
+	if messages := logger.Messages(logging.LevelError); len(messages) != 1 || messages[0] != "sync failed: some error" {
+		t.Error("component messages should be recorded in the history. Got: ", messages)
+	}
+}
+
+func TestTextLoggerRuntimeLevels(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newLogger(&conf.Logging{Level: "error"}, "Split-Proxy", &buffer)
+	levels := LevelsOf(logger)
+	if levels == nil {
+		t.Error("level registry should be available")
+		return
+	}
+
+	admin := Component(logger, ComponentAdmin)
+	admin.Info("not logged")
+	if err := levels.SetLevel(ComponentAdmin, "info"); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	admin.Info("logged")
+	logger.Info("not logged either")
+
+	lines := readLines(&buffer)
+	if len(lines) != 1 || !strings.Contains(lines[0], "Split-Proxy - INFO - ") || !strings.Contains(lines[0], "leveled_test.go:") ||
+		!strings.HasSuffix(lines[0], "[admin] logged") {
+		t.Error("invalid log lines: ", lines)
+	}
+
+	if err := levels.SetLevel("unknown", "info"); err == nil {
+		t.Error("unknown components should fail")
+	}
+
+	if err := levels.SetLevel(ComponentGlobal, "verbose"); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if l := levels.Levels(); l[ComponentGlobal] != "verbose" || l[ComponentAdmin] != "info" || l[ComponentSync] != "verbose" {
+		t.Error("invalid levels: ", l)
+	}
+}
+
+func TestComponentFallback(t *testing.T) {
+	plain := logging.NewLogger(nil)
+	if Component(plain, ComponentSync) != plain || LevelsOf(plain) != nil {
+		t.Error("plain loggers should be returned as-is")
+	}
+}
+
+type hookMock struct{ fired []string }
+
+func (h *hookMock) Fire(component string, level int, message string) {
+	h.fired = append(h.fired, component+"|"+LevelName(level)+"|"+message)
+}
+
+func TestAttachHook(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newLogger(&conf.Logging{Level: "info"}, "Split-Sync", &buffer)
+	LevelsOf(logger).SetLevel(ComponentGlobal, "warning")
+	hook := &hookMock{}
+	if !AttachHook(logger, hook) {
+		t.Error("the hook should be attached")
+	}
+
+	logger.Info("filtered out")
+	logger.Error("some error", 123)
+	Component(logger, ComponentSync).Warning("some warning")
+
+	if len(hook.fired) != 2 || hook.fired[0] != "|error|some error 123" || hook.fired[1] != "sync|warning|some warning" {
+		t.Error("invalid messages received by the hook: ", hook.fired)
+	}
+
+	if AttachHook(logging.NewLogger(nil), hook) {
+		t.Error("plain loggers don't support hooks")
+	}
+}
+
+func TestConfiguredLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newLogger(&conf.Logging{Level: "loud", ComponentLevels: []string{"sync"}}, "Split-Sync", &buffer)
+	lines := readLines(&buffer)
+	if len(lines) != 2 || !strings.Contains(lines[0], "unknown log level 'loud'. Defaulting to error.") ||
+		!strings.Contains(lines[1], "Error parsing component log levels") {
+		t.Error("configuration errors should be logged. Got: ", lines)
+	}
+	if levels := LevelsOf(logger).Levels(); levels[ComponentGlobal] != "error" {
+		t.Error("unknown levels should default to error. Got: ", levels)
+	}
+
+	buffer.Reset()
+	logger = newLogger(&conf.Logging{Level: "warning"}, "Split-Sync", &buffer)
+	logger.Info("filtered out")
+	logger.Warning("some warning")
+	if lines := readLines(&buffer); len(lines) != 1 || !strings.Contains(lines[0], "some warning") {
+		t.Error("the warning level should log warnings & errors only. Got: ", lines)
+	}
+
+	buffer.Reset()
+	logger = newLogger(&conf.Logging{Level: "error"}, "Split-Sync", &buffer)
+	logger.Warning("filtered out")
+	logger.Error("some error")
+	if lines := readLines(&buffer); len(lines) != 1 || !strings.Contains(lines[0], "some error") {
+		t.Error("the error level should log errors only. Got: ", lines)
+	}
+}
@@ -0,0 +1,160 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Components whose log level can be set individually
+const (
+	ComponentControllers = "controllers"
+	ComponentSync        = "sync"
+	ComponentPipelined   = "pipelined"
+	ComponentHealthcheck = "healthcheck"
+	ComponentAdmin       = "admin"
+	ComponentNotifier    = "notifier"
+)
+
+// ComponentGlobal is used to refer to the default level, used by messages without a component & components without an override
+const ComponentGlobal = "global"
+
+var knownComponents = []string{ComponentControllers, ComponentSync, ComponentPipelined, ComponentHealthcheck, ComponentAdmin, ComponentNotifier}
+
+// ParseLevel converts a level name into a go-toolkit log level
+func ParseLevel(level string) (int, error) {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "VERBOSE":
+		return logging.LevelVerbose, nil
+	case "DEBUG":
+		return logging.LevelDebug, nil
+	case "INFO":
+		return logging.LevelInfo, nil
+	case "WARNING", "WARN":
+		return logging.LevelWarning, nil
+	case "ERROR":
+		return logging.LevelError, nil
+	case "NONE":
+		return logging.LevelNone, nil
+	}
+	return 0, fmt.Errorf("unknown log level '%s'", level)
+}
+
+// LevelName returns the name of a go-toolkit log level
+func LevelName(level int) string {
+	switch level {
+	case logging.LevelVerbose:
+		return "verbose"
+	case logging.LevelDebug:
+		return "debug"
+	case logging.LevelInfo:
+		return "info"
+	case logging.LevelWarning:
+		return "warning"
+	case logging.LevelError:
+		return "error"
+	case logging.LevelNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// LevelRegistry keeps track of the global log level & per-component overrides. Levels can be updated at runtime
+type LevelRegistry struct {
+	global     int
+	components map[string]int
+	mutex      sync.RWMutex
+}
+
+// NewLevelRegistry constructs a new level registry with a global level & no overrides
+func NewLevelRegistry(global int) *LevelRegistry {
+	return &LevelRegistry{global: global, components: make(map[string]int)}
+}
+
+// Enabled returns whether a message with the specified level should be logged for a component
+func (r *LevelRegistry) Enabled(component string, level int) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	threshold, ok := r.components[component]
+	if !ok {
+		threshold = r.global
+	}
+	return threshold >= level
+}
+
+// SetLevel updates the level of a component. Use ComponentGlobal to update the default one
+func (r *LevelRegistry) SetLevel(component string, level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if component == ComponentGlobal {
+		r.global = parsed
+		return nil
+	}
+
+	if !isKnownComponent(component) {
+		return fmt.Errorf("unknown component '%s'. Valid ones are: %s", component, strings.Join(knownComponents, ", "))
+	}
+
+	r.components[component] = parsed
+	return nil
+}
+
+// ResetLevel removes a component override, making it use the global level
+func (r *LevelRegistry) ResetLevel(component string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.components, component)
+}
+
+// Levels returns the name of the effective level of each component, including the global one
+func (r *LevelRegistry) Levels() map[string]string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	levels := map[string]string{ComponentGlobal: LevelName(r.global)}
+	for _, component := range knownComponents {
+		level, ok := r.components[component]
+		if !ok {
+			level = r.global
+		}
+		levels[component] = LevelName(level)
+	}
+	return levels
+}
+
+// SetComponentLevels applies a list of overrides with the format `<component>=<level>`. Empty strings are ignored
+func (r *LevelRegistry) SetComponentLevels(overrides []string) error {
+	for _, override := range overrides {
+		if strings.TrimSpace(override) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid component log level '%s'. Expected <component>=<level>", override)
+		}
+
+		if err := r.SetLevel(strings.TrimSpace(parts[0]), parts[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isKnownComponent(component string) bool {
+	for _, known := range knownComponents {
+		if known == component {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+package backpressure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/asynctask"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+)
+
+const (
+	// ActionNone only reports the condition (health & flag key)
+	ActionNone = "none"
+	// ActionTrim drops the oldest elements of a queue until its length is back to the low watermark
+	ActionTrim = "trim"
+	// ActionSample drops a percentage of the oldest elements in excess of the low watermark on every check
+	ActionSample = "sample"
+)
+
+const (
+	resourceImpressions = "impressions"
+	resourceEvents      = "events"
+)
+
+// Queue defines the interface of a redis list that can be measured & trimmed from its oldest end
+type Queue interface {
+	Count() int64
+	Drop(size int64) error
+}
+
+// MemoryReader defines the interface of a component capable of reporting redis memory usage in bytes
+type MemoryReader interface {
+	UsedMemory() (int64, error)
+}
+
+// FlagWriter defines the interface of a storage where the backpressure flag is written
+type FlagWriter interface {
+	Set(key string, value interface{}, expiration time.Duration) error
+	Del(keys ...string) (int64, error)
+}
+
+// Watermarks define the value above which protection is activated (High) and the value below which it's deactivated (Low).
+// A High value of 0 disables the check. A Low value of 0 (or greater than High) defaults to 80% of High
+type Watermarks struct {
+	High int64
+	Low  int64
+}
+
+func (w Watermarks) normalized() Watermarks {
+	if w.Low <= 0 || w.Low > w.High {
+		w.Low = w.High * 8 / 10
+	}
+	return w
+}
+
+// Config bundles the guard dependencies & options
+type Config struct {
+	Logger                logging.LoggerInterface
+	Impressions           Queue
+	ImpressionsWatermarks Watermarks
+	Events                Queue
+	EventsWatermarks      Watermarks
+	Memory                MemoryReader
+	MemoryWatermarks      Watermarks
+	Action                string
+	SamplePercentage      int64
+	FlagWriter            FlagWriter
+	FlagKey               string
+	HealthCounter         counter.StatusCounterInterface
+	CheckPeriodSecs       int
+}
+
+// Flag is the payload written to the flag key while any of the watermarks is exceeded
+type Flag struct {
+	Impressions bool  `json:"impressions"`
+	Events      bool  `json:"events"`
+	Memory      bool  `json:"memory"`
+	Since       int64 `json:"since"`
+}
+
+func (f Flag) active() bool {
+	return f.Impressions || f.Events || f.Memory
+}
+
+// Guard periodically checks the size of the impressions & events queues and redis memory usage,
+// reporting (and optionally mitigating) the condition when the configured watermarks are exceeded
+type Guard struct {
+	logger           logging.LoggerInterface
+	queues           map[string]Queue
+	queueWatermarks  map[string]Watermarks
+	memory           MemoryReader
+	memoryWatermarks Watermarks
+	action           string
+	samplePercentage int64
+	flagWriter       FlagWriter
+	flagKey          string
+	flagTTL          time.Duration
+	healthCounter    counter.StatusCounterInterface
+	task             *asynctask.AsyncTask
+	status           Flag
+	mutex            sync.Mutex
+}
+
+// New constructs a new guard
+func New(cfg *Config) (*Guard, error) {
+	switch cfg.Action {
+	case "":
+		cfg.Action = ActionNone
+	case ActionNone, ActionTrim, ActionSample:
+	default:
+		return nil, fmt.Errorf("invalid queue protection action '%s'", cfg.Action)
+	}
+
+	if cfg.Action == ActionSample && (cfg.SamplePercentage <= 0 || cfg.SamplePercentage > 100) {
+		return nil, errors.New("sample percentage must be between 1 and 100")
+	}
+
+	if cfg.CheckPeriodSecs <= 0 {
+		cfg.CheckPeriodSecs = 1
+	}
+
+	g := &Guard{
+		logger:           cfg.Logger,
+		queues:           make(map[string]Queue),
+		queueWatermarks:  make(map[string]Watermarks),
+		memory:           cfg.Memory,
+		memoryWatermarks: cfg.MemoryWatermarks.normalized(),
+		action:           cfg.Action,
+		samplePercentage: cfg.SamplePercentage,
+		flagWriter:       cfg.FlagWriter,
+		flagKey:          cfg.FlagKey,
+		flagTTL:          time.Duration(3*cfg.CheckPeriodSecs) * time.Second,
+		healthCounter:    cfg.HealthCounter,
+	}
+
+	if cfg.Impressions != nil {
+		g.queues[resourceImpressions] = cfg.Impressions
+		g.queueWatermarks[resourceImpressions] = cfg.ImpressionsWatermarks.normalized()
+	}
+
+	if cfg.Events != nil {
+		g.queues[resourceEvents] = cfg.Events
+		g.queueWatermarks[resourceEvents] = cfg.EventsWatermarks.normalized()
+	}
+
+	g.task = asynctask.NewAsyncTask("queue-protection", func(l logging.LoggerInterface) error {
+		g.check()
+		return nil
+	}, cfg.CheckPeriodSecs, nil, nil, cfg.Logger)
+	return g, nil
+}
+
+// Start begins periodic checks
+func (g *Guard) Start() {
+	g.task.Start()
+}
+
+// Stop ends periodic checks, waiting for an ongoing one to finish
+func (g *Guard) Stop() {
+	g.task.Stop(true)
+}
+
+// Status returns the current state of the queues & memory
+func (g *Guard) Status() Flag {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.status
+}
+
+func (g *Guard) check() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	next := Flag{Since: g.status.Since}
+	if g.memory != nil && g.memoryWatermarks.High > 0 {
+		used, err := g.memory.UsedMemory()
+		if err != nil {
+			g.logger.Error("error reading redis memory usage: ", err)
+			next.Memory = g.status.Memory
+		} else {
+			next.Memory = exceeds(g.status.Memory, used, g.memoryWatermarks)
+		}
+	}
+
+	for name, queue := range g.queues {
+		watermarks := g.queueWatermarks[name]
+		if watermarks.High <= 0 && !next.Memory {
+			continue
+		}
+
+		size := queue.Count()
+		exceeded := watermarks.High > 0 && exceeds(g.wasExceeded(name), size, watermarks)
+		g.setExceeded(&next, name, exceeded)
+
+		// when memory is over the limit, queues are trimmed down to their low watermark regardless of their own state.
+		// Queues without watermarks have a low watermark of 0, so the action applies to all of their elements
+		if exceeded || (next.Memory && size > watermarks.Low) {
+			g.mitigate(name, queue, size, watermarks.Low)
+		}
+	}
+
+	g.transition(next)
+}
+
+// exceeds implements the hysteresis: once the high watermark is crossed, the condition holds until the value goes below the low one
+func exceeds(previously bool, value int64, watermarks Watermarks) bool {
+	if previously {
+		return value > watermarks.Low
+	}
+	return value >= watermarks.High
+}
+
+func (g *Guard) wasExceeded(name string) bool {
+	switch name {
+	case resourceImpressions:
+		return g.status.Impressions
+	case resourceEvents:
+		return g.status.Events
+	}
+	return false
+}
+
+func (g *Guard) setExceeded(flag *Flag, name string, exceeded bool) {
+	switch name {
+	case resourceImpressions:
+		flag.Impressions = exceeded
+	case resourceEvents:
+		flag.Events = exceeded
+	}
+}
+
+func (g *Guard) mitigate(name string, queue Queue, size int64, low int64) {
+	var toDrop int64
+	switch g.action {
+	case ActionTrim:
+		toDrop = size - low
+	case ActionSample:
+		toDrop = (size - low) * g.samplePercentage / 100
+	}
+
+	if toDrop <= 0 {
+		return
+	}
+
+	g.logger.Warning(fmt.Sprintf("Queue protection: dropping %d oldest %s (current size: %d)", toDrop, name, size))
+	if err := queue.Drop(toDrop); err != nil {
+		g.logger.Error(fmt.Sprintf("error dropping %s: ", name), err)
+	}
+}
+
+func (g *Guard) transition(next Flag) {
+	wasActive := g.status.active()
+	isActive := next.active()
+
+	if isActive && !wasActive {
+		next.Since = time.Now().UnixNano() / int64(time.Millisecond)
+		g.logger.Warning(fmt.Sprintf(
+			"Queue protection activated. Impressions: %t, Events: %t, Memory: %t",
+			next.Impressions, next.Events, next.Memory,
+		))
+	} else if !isActive {
+		next.Since = 0
+		if wasActive {
+			g.logger.Info("Queue protection deactivated. All queues are below their low watermarks")
+		}
+	}
+
+	g.status = next
+	if g.healthCounter != nil {
+		g.healthCounter.SetHealthy(!isActive)
+	}
+
+	g.writeFlag(isActive, wasActive)
+}
+
+func (g *Guard) writeFlag(isActive bool, wasActive bool) {
+	if g.flagWriter == nil || g.flagKey == "" {
+		return
+	}
+
+	if !isActive {
+		if wasActive {
+			if _, err := g.flagWriter.Del(g.flagKey); err != nil {
+				g.logger.Error("error removing backpressure flag: ", err)
+			}
+		}
+		return
+	}
+
+	// The flag is rewritten on every check with a ttl, so that it doesn't outlive a stopped synchronizer
+	serialized, err := json.Marshal(g.status)
+	if err != nil {
+		g.logger.Error("error serializing backpressure flag: ", err)
+		return
+	}
+
+	if err := g.flagWriter.Set(g.flagKey, string(serialized), g.flagTTL); err != nil {
+		g.logger.Error("error writing backpressure flag: ", err)
+	}
+}
@@ -0,0 +1,224 @@
+package backpressure
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+)
+
+type queueMock struct {
+	size    int64
+	dropped []int64
+}
+
+func (q *queueMock) Count() int64 { return q.size }
+func (q *queueMock) Drop(size int64) error {
+	q.dropped = append(q.dropped, size)
+	q.size -= size
+	return nil
+}
+
+type memoryMock struct{ used int64 }
+
+func (m *memoryMock) UsedMemory() (int64, error) { return m.used, nil }
+
+type flagWriterMock struct {
+	values map[string]string
+	ttl    time.Duration
+}
+
+func (f *flagWriterMock) Set(key string, value interface{}, expiration time.Duration) error {
+	f.values[key] = value.(string)
+	f.ttl = expiration
+	return nil
+}
+
+func (f *flagWriterMock) Del(keys ...string) (int64, error) {
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+	return int64(len(keys)), nil
+}
+
+func TestGuardHysteresisAndFlag(t *testing.T) {
+	impressions := &queueMock{}
+	events := &queueMock{}
+	flags := &flagWriterMock{values: make(map[string]string)}
+	health := counter.NewStatusCounter(counter.StatusConfig{Name: "Queues", Severity: counter.Low}, logging.NewLogger(nil))
+
+	guard, err := New(&Config{
+		Logger:                logging.NewLogger(nil),
+		Impressions:           impressions,
+		ImpressionsWatermarks: Watermarks{High: 100, Low: 50},
+		Events:                events,
+		EventsWatermarks:      Watermarks{High: 100},
+		FlagWriter:            flags,
+		FlagKey:               "SPLITIO.backpressure",
+		HealthCounter:         health,
+		CheckPeriodSecs:       5,
+	})
+	if err != nil {
+		t.Error("there should be no error. Got: ", err)
+	}
+
+	impressions.size = 99
+	guard.check()
+	if guard.Status().Impressions || !health.IsHealthy().Healthy || len(flags.values) != 0 {
+		t.Error("protection should not be active below the high watermark")
+	}
+
+	impressions.size = 100
+	guard.check()
+	if status := guard.Status(); !status.Impressions || status.Events || status.Since == 0 {
+		t.Error("impressions protection should be active. Got: ", status)
+	}
+	if health.IsHealthy().Healthy {
+		t.Error("health item should be degraded")
+	}
+
+	var flag Flag
+	if err := json.Unmarshal([]byte(flags.values["SPLITIO.backpressure"]), &flag); err != nil || !flag.Impressions {
+		t.Error("flag should be written. Got: ", flags.values, err)
+	}
+	if flags.ttl != 15*time.Second {
+		t.Error("flag ttl should be 3 times the check period. Got: ", flags.ttl)
+	}
+
+	impressions.size = 60
+	guard.check()
+	if !guard.Status().Impressions {
+		t.Error("protection should remain active until the low watermark is reached")
+	}
+
+	impressions.size = 50
+	guard.check()
+	if guard.Status().active() || !health.IsHealthy().Healthy || len(flags.values) != 0 {
+		t.Error("protection should be deactivated & flag removed")
+	}
+
+	if len(impressions.dropped) != 0 {
+		t.Error("nothing should be dropped with the default action")
+	}
+
+	events.size = 80
+	guard.check()
+	if guard.Status().Events {
+		t.Error("events should not be flagged")
+	}
+}
+
+func TestGuardTrimAndSample(t *testing.T) {
+	impressions := &queueMock{}
+	guard, _ := New(&Config{
+		Logger:                logging.NewLogger(nil),
+		Impressions:           impressions,
+		ImpressionsWatermarks: Watermarks{High: 100, Low: 50},
+		Action:                ActionTrim,
+	})
+
+	impressions.size = 150
+	guard.check()
+	if len(impressions.dropped) != 1 || impressions.dropped[0] != 100 {
+		t.Error("100 oldest impressions should have been dropped. Got: ", impressions.dropped)
+	}
+
+	impressions = &queueMock{}
+	guard, _ = New(&Config{
+		Logger:                logging.NewLogger(nil),
+		Impressions:           impressions,
+		ImpressionsWatermarks: Watermarks{High: 100, Low: 50},
+		Action:                ActionSample,
+		SamplePercentage:      50,
+	})
+
+	impressions.size = 150
+	guard.check()
+	guard.check()
+	if len(impressions.dropped) != 2 || impressions.dropped[0] != 50 || impressions.dropped[1] != 25 {
+		t.Error("50% of the excess should be dropped on each check. Got: ", impressions.dropped)
+	}
+
+	if _, err := New(&Config{Action: ActionSample}); err == nil {
+		t.Error("sampling without a percentage should fail")
+	}
+
+	if _, err := New(&Config{Action: "something"}); err == nil {
+		t.Error("invalid actions should fail")
+	}
+}
+
+func TestGuardMemory(t *testing.T) {
+	impressions := &queueMock{}
+	memory := &memoryMock{}
+	guard, _ := New(&Config{
+		Logger:                logging.NewLogger(nil),
+		Impressions:           impressions,
+		ImpressionsWatermarks: Watermarks{High: 1000, Low: 100},
+		Memory:                memory,
+		MemoryWatermarks:      Watermarks{High: 1000},
+		Action:                ActionTrim,
+	})
+
+	impressions.size = 500
+	memory.used = 999
+	guard.check()
+	if guard.Status().active() {
+		t.Error("protection should not be active")
+	}
+
+	memory.used = 1000
+	guard.check()
+	if status := guard.Status(); !status.Memory || status.Impressions {
+		t.Error("only memory protection should be active. Got: ", status)
+	}
+	if len(impressions.dropped) != 1 || impressions.dropped[0] != 400 {
+		t.Error("impressions should be trimmed down to the low watermark when memory is exceeded. Got: ", impressions.dropped)
+	}
+
+	memory.used = 801
+	guard.check()
+	if !guard.Status().Memory {
+		t.Error("memory protection should remain active above 80% of the high watermark")
+	}
+
+	memory.used = 800
+	guard.check()
+	if guard.Status().Memory {
+		t.Error("memory protection should be deactivated")
+	}
+}
+
+func TestGuardMemoryWithoutQueueWatermarks(t *testing.T) {
+	impressions := &queueMock{}
+	events := &queueMock{}
+	memory := &memoryMock{}
+	guard, _ := New(&Config{
+		Logger:           logging.NewLogger(nil),
+		Impressions:      impressions,
+		Events:           events,
+		Memory:           memory,
+		MemoryWatermarks: Watermarks{High: 1000},
+		Action:           ActionSample,
+		SamplePercentage: 50,
+	})
+
+	impressions.size, events.size = 500, 100
+	memory.used = 999
+	guard.check()
+	if len(impressions.dropped) != 0 || len(events.dropped) != 0 {
+		t.Error("nothing should be dropped below the memory watermark")
+	}
+
+	memory.used = 1000
+	guard.check()
+	if status := guard.Status(); !status.Memory || status.Impressions || status.Events {
+		t.Error("only memory protection should be active. Got: ", status)
+	}
+	if len(impressions.dropped) != 1 || impressions.dropped[0] != 250 || len(events.dropped) != 1 || events.dropped[0] != 50 {
+		t.Error("queues without watermarks should be sampled when memory is exceeded. Got: ", impressions.dropped, events.dropped)
+	}
+}
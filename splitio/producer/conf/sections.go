@@ -15,7 +15,10 @@ type Main struct {
 	Admin            conf.Admin        `json:"admin" s-nested:"true"`
 	Integrations     conf.Integrations `json:"integrations" s-nested:"true"`
 	Logging          conf.Logging      `json:"logging" s-nested:"true"`
+	ChangeLog        conf.ChangeLog    `json:"changeLog" s-nested:"true"`
+	Fleet            conf.Fleet        `json:"fleet" s-nested:"true"`
 	Healthcheck      Healthcheck       `json:"healthcheck" s-nested:"true"`
+	QueueProtection  QueueProtection   `json:"queueProtection" s-nested:"true"`
 }
 
 // BuildAdvancedConfig generates a commons-compatible advancedconfig with default + overriden parameters
@@ -47,6 +50,7 @@ type Sync struct {
 	SplitRefreshRateMs   int64        `json:"splitRefreshRateMs" s-cli:"split-refresh-rate-ms" s-def:"60000" s-desc:"How often to refresh splits"`
 	SegmentRefreshRateMs int64        `json:"segmentRefreshRateMs" s-cli:"segment-refresh-rate-ms" s-def:"60000" s-desc:"How often to refresh segments"`
 	ImpressionsMode      string       `json:"impressionsMode" s-cli:"impressions-mode" s-def:"optimized" s-desc:"whether to send all impressions for debugging"`
+	SamplingRules        []string     `json:"samplingRules" s-cli:"sampling-rules" s-def:"" s-desc:"Impression/event sampling rules: <split|trafficType|eventType>:<name>=<rate:0..1|limit:perSecond|drop>"`
 	Advanced             AdvancedSync `json:"advanced" s-nested:"true"`
 }
 
@@ -99,10 +103,30 @@ type Redis struct {
 
 // Healthcheck configuration options
 type Healthcheck struct {
-	App HealthcheckApp `json:"app" s-nested:"true"`
+	App    HealthcheckApp    `json:"app" s-nested:"true"`
+	Probes conf.Probes       `json:"probes" s-nested:"true"`
+	Alerts conf.HealthAlerts `json:"alerts" s-nested:"true"`
 }
 
 // HealthcheckApp configuration options
 type HealthcheckApp struct {
-	StorageCheckRateMs int64 `json:"storageCheckRateMs" s-cli:"storage-check-rate-ms" s-def:"3600000" s-desc:"How often to check storage health"`
+	StorageCheckRateMs           int64 `json:"storageCheckRateMs" s-cli:"storage-check-rate-ms" s-def:"3600000" s-desc:"How often to check storage health"`
+	RedisLatencyThresholdMs      int64 `json:"redisLatencyThresholdMs" s-cli:"redis-latency-threshold-ms" s-def:"0" s-desc:"Redis round-trip time above which storage is reported as degraded (0 = disabled)"`
+	PipelineSaturationThreshold  int64 `json:"pipelineSaturationThreshold" s-cli:"pipeline-saturation-threshold" s-def:"0" s-desc:"Percentage of impressions/events fetches deferred due to a full processing buffer above which pipelines are reported as degraded (0 = disabled)"`
+	ListenerFailureRateThreshold int64 `json:"listenerFailureRateThreshold" s-cli:"listener-failure-rate-threshold" s-def:"0" s-desc:"Percentage of undelivered impression listener bulks above which it's reported as degraded (0 = disabled)"`
+}
+
+// QueueProtection configuration options
+type QueueProtection struct {
+	Enabled                  bool   `json:"enabled" s-cli:"queue-protection-enabled" s-def:"false" s-desc:"Monitor impressions/events queues & redis memory against configurable watermarks"`
+	CheckRateMs              int64  `json:"checkRateMs" s-cli:"queue-protection-check-rate-ms" s-def:"5000" s-desc:"How often to check queue sizes & redis memory"`
+	ImpressionsHighWatermark int64  `json:"impressionsHighWatermark" s-cli:"queue-protection-impressions-high-watermark" s-def:"0" s-desc:"Impressions queue length that activates protection (0 = disabled)"`
+	ImpressionsLowWatermark  int64  `json:"impressionsLowWatermark" s-cli:"queue-protection-impressions-low-watermark" s-def:"0" s-desc:"Impressions queue length that deactivates protection (0 = 80% of high)"`
+	EventsHighWatermark      int64  `json:"eventsHighWatermark" s-cli:"queue-protection-events-high-watermark" s-def:"0" s-desc:"Events queue length that activates protection (0 = disabled)"`
+	EventsLowWatermark       int64  `json:"eventsLowWatermark" s-cli:"queue-protection-events-low-watermark" s-def:"0" s-desc:"Events queue length that deactivates protection (0 = 80% of high)"`
+	MemoryHighWatermarkMb    int64  `json:"memoryHighWatermarkMb" s-cli:"queue-protection-memory-high-watermark-mb" s-def:"0" s-desc:"Redis used memory (MB) that activates protection (0 = disabled). Queues without watermarks are fully subject to the action while it is exceeded"`
+	MemoryLowWatermarkMb     int64  `json:"memoryLowWatermarkMb" s-cli:"queue-protection-memory-low-watermark-mb" s-def:"0" s-desc:"Redis used memory (MB) that deactivates protection (0 = 80% of high)"`
+	Action                   string `json:"action" s-cli:"queue-protection-action" s-def:"none" s-desc:"What to do with queues over their watermark: none|trim|sample"`
+	SamplePercentage         int64  `json:"samplePercentage" s-cli:"queue-protection-sample-percentage" s-def:"50" s-desc:"Percentage of the oldest elements in excess of the low watermark to drop on each check when action=sample"`
+	FlagKey                  string `json:"flagKey" s-cli:"queue-protection-flag-key" s-def:"SPLITIO.backpressure" s-desc:"Redis key (prefixed) set while protection is active, for sdks/operators to throttle"`
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -25,23 +26,37 @@ import (
 	"github.com/splitio/split-synchronizer/v5/splitio/admin"
 	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
 	"github.com/splitio/split-synchronizer/v5/splitio/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changehook"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
 	ssync "github.com/splitio/split-synchronizer/v5/splitio/common/sync"
+	splitlog "github.com/splitio/split-synchronizer/v5/splitio/log"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/backpressure"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/conf"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/sampling"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/task"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/worker"
+	hcAlerts "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/alerts"
 	hcApplication "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
 	hcAppCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
 	hcServices "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
 	hcServicesCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services/counter"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/observability"
 	"github.com/splitio/split-synchronizer/v5/splitio/util"
 )
 
-// Start initialize the producer mode
-func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
+// Start initialize the producer mode. The notifier is optional
+func Start(logger logging.LoggerInterface, cfg *conf.Main, notif *notifier.Notifier) error {
+	syncLogger := splitlog.Component(logger, splitlog.ComponentSync)
+	pipelinedLogger := splitlog.Component(logger, splitlog.ComponentPipelined)
+	hcLogger := splitlog.Component(logger, splitlog.ComponentHealthcheck)
+	adminLogger := splitlog.Component(logger, splitlog.ComponentAdmin)
+
 	// Getting initial config data
 	advanced := cfg.BuildAdvancedConfig()
 	metadata := util.GetMetadata(false, cfg.IPAddressEnabled)
@@ -92,6 +107,14 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 	if err != nil {
 		return fmt.Errorf("error instantiating observable segment storage: %w", err)
 	}
+	var changeRecorder *changelog.Recorder
+	if cfg.ChangeLog.Enabled {
+		changeStorage := storage.NewRedisChangeLogStorage(redisClient, cfg.ChangeLog.MaxEntries, syncLogger)
+		changeRecorder = changelog.NewRecorder(changeStorage, time.Duration(cfg.ChangeLog.RetentionHours)*time.Hour, cfg.ChangeLog.MaxEntries, syncLogger)
+		splitStorage.SetChangeRecorder(changeRecorder)
+		segmentStorage.SetChangeRecorder(changeRecorder)
+	}
+
 	storages := adminCommon.Storages{
 		SplitStorage:          splitStorage,
 		SegmentStorage:        segmentStorage,
@@ -105,23 +128,46 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 
 	// Healcheck Monitor
 	splitsConfig, segmentsConfig, storageConfig := getAppCounterConfigs(storages.SplitStorage)
-	appMonitor := hcApplication.NewMonitorImp(splitsConfig, segmentsConfig, &storageConfig, logger)
-	servicesMonitor := hcServices.NewMonitorImp(getServicesCountersConfig(advanced), logger)
+	appMonitor := hcApplication.NewMonitorImp(splitsConfig, segmentsConfig, &storageConfig, hcLogger)
+	servicesMonitor := hcServices.NewMonitorImp(getServicesCountersConfig(advanced), hcLogger)
+
+	var queueGuard *backpressure.Guard
+	if cfg.QueueProtection.Enabled {
+		queuesCounter := hcAppCounter.NewStatusCounter(hcAppCounter.StatusConfig{Name: "Queues", Severity: hcAppCounter.Low}, hcLogger)
+		appMonitor.RegisterCounter(queuesCounter)
+		queueGuard, err = newQueueGuard(&cfg.QueueProtection, redisClient, redisClient, storages, queuesCounter, pipelinedLogger)
+		if err != nil {
+			return common.NewInitError(fmt.Errorf("error instantiating queue protection: %w", err), common.ExitTaskInitialization)
+		}
+	}
+
+	changeHook, err := changehook.BuildFromConfig(&cfg.Integrations.ChangeWebhook, storages.SplitStorage, syncLogger)
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error instantiating change webhook: %w", err), common.ExitTaskInitialization)
+	}
 
 	workers := synchronizer.Workers{
-		SplitFetcher: split.NewSplitFetcher(storages.SplitStorage, splitAPI.SplitFetcher, logger, syncTelemetryStorage, appMonitor),
+		SplitFetcher: split.NewSplitFetcher(storages.SplitStorage, splitAPI.SplitFetcher, syncLogger, syncTelemetryStorage, appMonitor),
 		SegmentFetcher: segment.NewSegmentFetcher(storages.SplitStorage, storages.SegmentStorage, splitAPI.SegmentFetcher,
-			logger, syncTelemetryStorage, appMonitor),
+			syncLogger, syncTelemetryStorage, appMonitor),
 		// local telemetry
 		TelemetryRecorder: telemetry.NewTelemetrySynchronizer(syncTelemetryStorage, splitAPI.TelemetryRecorder,
-			storages.SplitStorage, storages.SegmentStorage, logger, metadata, syncTelemetryStorage),
+			storages.SplitStorage, storages.SegmentStorage, syncLogger, metadata, syncTelemetryStorage),
 	}
+	if changeHook != nil {
+		workers.SplitFetcher = changehook.NewSplitUpdater(workers.SplitFetcher, storages.SplitStorage, changeHook)
+		workers.SegmentFetcher = changehook.NewSegmentUpdater(workers.SegmentFetcher, changeHook)
+		changeHook.Start()
+	}
+	dataWiper := newRedisDataWiper(miscStorage, cfg.Apikey)
+	workers.SplitFetcher = dataWiper.GuardSplitUpdater(workers.SplitFetcher)
+	workers.SegmentFetcher = dataWiper.GuardSegmentUpdater(workers.SegmentFetcher)
 	splitTasks := synchronizer.SplitTasks{
-		SplitSyncTask: tasks.NewFetchSplitsTask(workers.SplitFetcher, int(cfg.Sync.SplitRefreshRateMs)/1000, logger),
-		SegmentSyncTask: tasks.NewFetchSegmentsTask(workers.SegmentFetcher, int(cfg.Sync.SegmentRefreshRateMs)/1000,
-			advanced.SegmentWorkers, advanced.SegmentQueueSize, logger),
+		SplitSyncTask: tasks.NewFetchSplitsTask(workers.SplitFetcher, common.PeriodSecs(cfg.Sync.SplitRefreshRateMs), syncLogger),
+		SegmentSyncTask: tasks.NewFetchSegmentsTask(workers.SegmentFetcher, common.PeriodSecs(cfg.Sync.SegmentRefreshRateMs),
+			advanced.SegmentWorkers, advanced.SegmentQueueSize, syncLogger),
 		// local telemetry
-		TelemetrySyncTask: tasks.NewRecordTelemetryTask(workers.TelemetryRecorder, int(cfg.Sync.Advanced.InternalMetricsRateMs)/1000, logger),
+		TelemetrySyncTask: tasks.NewRecordTelemetryTask(workers.TelemetryRecorder, common.PeriodSecs(cfg.Sync.Advanced.InternalMetricsRateMs), syncLogger),
 	}
 
 	impressionEvictionMonitor := evcalc.New(1)
@@ -138,16 +184,38 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 		impListener.Start()
 	}
 
+	samplingRules, err := sampling.ParseRules(cfg.Sync.SamplingRules)
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error parsing sampling rules: %w", err), common.ExitInvalidConfiguration)
+	}
+
+	var sampler *sampling.Sampler
+	if len(samplingRules) > 0 {
+		sampler = sampling.NewSampler(samplingRules, sampling.NewTrafficTypeCache(storages.SplitStorage, time.Minute, sampling.DefaultMaxCachedSplits).Resolve)
+	}
+
+	// Impressions are also counted in debug mode when sampling is enabled, so that sampled out impressions are not lost
 	var impCounter *provisional.ImpressionsCounter
-	if cfg.Sync.ImpressionsMode == cconf.ImpressionsModeOptimized {
+	if cfg.Sync.ImpressionsMode == cconf.ImpressionsModeOptimized || sampler != nil {
 		impCounter = provisional.NewImpressionsCounter()
-		workers.ImpressionsCountRecorder = impressionscount.NewRecorderSingle(impCounter, splitAPI.ImpressionRecorder, metadata, logger, syncTelemetryStorage)
-		splitTasks.ImpressionsCountSyncTask = tasks.NewRecordImpressionsCountTask(workers.ImpressionsCountRecorder, logger)
+		workers.ImpressionsCountRecorder = impressionscount.NewRecorderSingle(impCounter, splitAPI.ImpressionRecorder, metadata, syncLogger, syncTelemetryStorage)
+		splitTasks.ImpressionsCountSyncTask = tasks.NewRecordImpressionsCountTask(workers.ImpressionsCountRecorder, syncLogger)
+	}
+
+	var fleetInventory *fleet.Inventory
+	var adminFleet adminCommon.FleetInventory
+	if cfg.Fleet.Enabled {
+		fleetConfig, err := fleet.ConfigFromOptions(&cfg.Fleet)
+		if err != nil {
+			return common.NewInitError(fmt.Errorf("error parsing fleet options: %w", err), common.ExitInvalidConfiguration)
+		}
+		fleetInventory = fleet.NewInventory(fleetConfig)
+		adminFleet = fleetInventory
 	}
 
 	// Impression & events pipelined tasks @{
 	impWorker, err := task.NewImpressionWorker(&task.ImpressionWorkerConfig{
-		Logger:              logger,
+		Logger:              pipelinedLogger,
 		Storage:             storages.ImpressionStorage,
 		EvictionMonitor:     impressionEvictionMonitor,
 		URL:                 advanced.EventsURL,
@@ -155,6 +223,8 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 		ImpressionsMode:     cfg.Sync.ImpressionsMode,
 		ImpressionsListener: impListener,
 		ImpressionCounter:   impCounter,
+		Sampler:             sampler,
+		Fleet:               fleetInventory,
 		FetchSize:           int(cfg.Sync.Advanced.ImpressionsFetchSize),
 	})
 	if err != nil {
@@ -163,7 +233,7 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 
 	impTask, err := task.NewPipelinedTask(&task.Config{
 		Name:               "impressions",
-		Logger:             logger,
+		Logger:             pipelinedLogger,
 		Worker:             impWorker,
 		ProcessConcurrency: cfg.Sync.Advanced.ImpressionsProcessConcurrency,
 		ProcessBatchSize:   cfg.Sync.Advanced.ImpressionsProcessBatchSize,
@@ -176,10 +246,12 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 	}
 
 	evWorker, err := task.NewEventsWorker(&task.EventWorkerConfig{
-		Logger:          logger,
+		Logger:          pipelinedLogger,
 		Storage:         storages.EventStorage,
 		URL:             advanced.EventsURL,
 		EvictionMonitor: eventEvictionMonitor,
+		Sampler:         sampler,
+		Fleet:           fleetInventory,
 		Apikey:          cfg.Apikey,
 		FetchSize:       int(cfg.Sync.Advanced.EventsFetchSize),
 	})
@@ -189,7 +261,7 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 
 	evTask, err := task.NewPipelinedTask(&task.Config{
 		Name:               "events",
-		Logger:             logger,
+		Logger:             pipelinedLogger,
 		Worker:             evWorker,
 		ProcessConcurrency: cfg.Sync.Advanced.ImpressionsProcessConcurrency,
 		ProcessBatchSize:   cfg.Sync.Advanced.ImpressionsProcessBatchSize,
@@ -205,13 +277,24 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 	splitTasks.EventSyncTask = evTask
 	// @}
 
-	sdkTelemetryWorker := worker.NewTelemetryMultiWorker(logger, sdkTelemetryStorage, splitAPI.TelemetryRecorder)
-	sdkTelemetryTask := task.NewTelemetrySyncTask(sdkTelemetryWorker, logger, int(cfg.Sync.Advanced.TelemetryPushRateMs/1000))
-	syncImpl := ssync.NewSynchronizer(*advanced, splitTasks, workers, logger, nil, []tasks.Task{sdkTelemetryTask}, appMonitor)
+	for _, customCounter := range getCustomCounters(&cfg.Healthcheck.App, storages.SplitStorage, impTask, evTask, impListener, hcLogger) {
+		appMonitor.RegisterCounter(customCounter)
+	}
+	healthAlerts := hcAlerts.NewWatcher(
+		appMonitor,
+		servicesMonitor,
+		hcAlerts.BuildSinks(&cfg.Healthcheck.Alerts, notif),
+		common.PeriodSecs(cfg.Healthcheck.Alerts.CheckRateMs),
+		hcLogger,
+	)
+
+	sdkTelemetryWorker := worker.NewTelemetryMultiWorker(syncLogger, sdkTelemetryStorage, splitAPI.TelemetryRecorder, fleetInventory)
+	sdkTelemetryTask := task.NewTelemetrySyncTask(sdkTelemetryWorker, syncLogger, common.PeriodSecs(cfg.Sync.Advanced.TelemetryPushRateMs))
+	syncImpl := ssync.NewSynchronizer(*advanced, splitTasks, workers, syncLogger, nil, []tasks.Task{sdkTelemetryTask}, appMonitor)
 	managerStatus := make(chan int, 1)
 	syncManager, err := synchronizer.NewSynchronizerManager(
 		syncImpl,
-		logger,
+		syncLogger,
 		*advanced,
 		splitAPI.AuthClient,
 		storages.SplitStorage,
@@ -226,11 +309,37 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 		return common.NewInitError(fmt.Errorf("error instantiating sync manager: %w", err), common.ExitTaskInitialization)
 	}
 
-	rtm := common.NewRuntime(false, syncManager, logger, "Split Synchronizer", nil, nil, appMonitor, servicesMonitor)
+	rtm := common.NewRuntime(false, syncManager, logger, "Split Synchronizer", nil, notif, appMonitor, servicesMonitor)
+	if changeHook != nil {
+		rtm.RegisterShutdownHook(func() { changeHook.Stop(true) })
+	}
+	if queueGuard != nil {
+		rtm.RegisterShutdownHook(queueGuard.Stop)
+	}
+	probeEvaluator := probes.NewEvaluator(
+		probes.ConfigFromOptions(&cfg.Healthcheck.Probes),
+		appMonitor,
+		servicesMonitor,
+		syncManager,
+		storages.SplitStorage,
+	)
 
 	// --------------------------- ADMIN DASHBOARD ------------------------------
+	var logLevels adminCommon.LogLevels
+	if levels := splitlog.LevelsOf(logger); levels != nil {
+		logLevels = levels
+	}
+
+	var changeHistory adminCommon.ChangeHistory
+	if changeRecorder != nil {
+		changeHistory = changeRecorder
+	}
+
 	cfgForAdmin := *cfg
 	cfgForAdmin.Apikey = logging.ObfuscateAPIKey(cfgForAdmin.Apikey)
+	cfgForAdmin.Admin = cfgForAdmin.Admin.Redacted()
+	cfgForAdmin.Integrations = cfgForAdmin.Integrations.Redacted()
+	cfgForAdmin.Healthcheck.Alerts = cfgForAdmin.Healthcheck.Alerts.Redacted()
 	adminServer, err := admin.NewServer(&admin.Options{
 		Host:              cfg.Admin.Host,
 		Port:              int(cfg.Admin.Port),
@@ -238,19 +347,39 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 		Proxy:             false,
 		Username:          cfg.Admin.Username,
 		Password:          cfg.Admin.Password,
-		Logger:            logger,
+		Tokens:            cfg.Admin.Tokens,
+		TokensFile:        cfg.Admin.TokensFile,
+		AuditLogFile:      cfg.Admin.AuditLogFile,
+		TLSCertFile:       cfg.Admin.TLSCertFile,
+		TLSKeyFile:        cfg.Admin.TLSKeyFile,
+		TLSClientCAFile:   cfg.Admin.TLSClientCAFile,
+		SecureHC:          cfg.Admin.SecureHC,
+		Logger:            adminLogger,
 		Storages:          storages,
 		ImpressionsEvCalc: impressionEvictionMonitor,
 		EventsEvCalc:      eventEvictionMonitor,
 		Runtime:           rtm,
+		Synchronizer:      syncImpl,
+		DataWiper:         dataWiper,
+		ImpressionsTask:   impTask,
+		EventsTask:        evTask,
+		Sampler:           sampler,
+		LogLevels:         logLevels,
+		ChangeHistory:     changeHistory,
+		Fleet:             adminFleet,
 		HcAppMonitor:      appMonitor,
 		HcServicesMonitor: servicesMonitor,
+		Probes:            probeEvaluator,
 		FullConfig:        cfgForAdmin,
 	})
 	if err != nil {
-		panic(err.Error())
+		return common.NewInitError(fmt.Errorf("error starting admin server: %w", err), common.ExitAdminError)
 	}
-	go adminServer.ListenAndServe()
+	go func() {
+		if err := admin.ListenAndServe(adminServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error running admin server: ", err)
+		}
+	}()
 
 	// Run Sync Manager
 	before := time.Now()
@@ -260,15 +389,20 @@ func Start(logger logging.LoggerInterface, cfg *conf.Main) error {
 		switch status {
 		case synchronizer.Ready:
 			logger.Info("Synchronizer tasks started")
+			probeEvaluator.MarkStarted()
 			appMonitor.Start()
 			servicesMonitor.Start()
+			healthAlerts.Start()
+			if queueGuard != nil {
+				queueGuard.Start()
+			}
 			workers.TelemetryRecorder.SynchronizeConfig(
 				telemetry.InitConfig{
 					AdvancedConfig: *advanced,
 					TaskPeriods: cconf.TaskPeriods{
-						SplitSync:     int(cfg.Sync.SplitRefreshRateMs / 1000),
-						SegmentSync:   int(cfg.Sync.SegmentRefreshRateMs / 1000),
-						TelemetrySync: int(cfg.Sync.Advanced.InternalMetricsRateMs / 1000),
+						SplitSync:     common.PeriodSecs(cfg.Sync.SplitRefreshRateMs),
+						SegmentSync:   common.PeriodSecs(cfg.Sync.SegmentRefreshRateMs),
+						TelemetrySync: common.PeriodSecs(cfg.Sync.Advanced.InternalMetricsRateMs),
 					},
 					ManagerConfig: cconf.ManagerConfig{
 						ImpressionsMode: cfg.Sync.ImpressionsMode,
@@ -311,6 +445,47 @@ func getAppCounterConfigs(storage storageCommon.SplitStorage) (hcAppCounter.Thre
 	return splitsConfig, segmentsConfig, storageConfig
 }
 
+func getCustomCounters(
+	cfg *conf.HealthcheckApp,
+	splitStorage storageCommon.SplitStorageConsumer,
+	impTask *task.PipelinedSyncTask,
+	evTask *task.PipelinedSyncTask,
+	listener impressionlistener.ImpressionBulkListener,
+	logger logging.LoggerInterface,
+) []hcAppCounter.CustomCounterInterface {
+	var counters []hcAppCounter.CustomCounterInterface
+	newCounter := func(name string, check func() error) {
+		counters = append(counters, hcAppCounter.NewCheckCounter(hcAppCounter.CheckConfig{
+			Name:     name,
+			Severity: hcAppCounter.Low,
+			Period:   10,
+			Check:    check,
+		}, logger))
+	}
+
+	if cfg.RedisLatencyThresholdMs > 0 {
+		newCounter("RedisLatency", hcAppCounter.NewThresholdCheck(func() (int64, error) {
+			before := time.Now()
+			_, err := splitStorage.ChangeNumber()
+			return time.Since(before).Milliseconds(), err
+		}, cfg.RedisLatencyThresholdMs, "ms"))
+	}
+
+	if cfg.PipelineSaturationThreshold > 0 {
+		newCounter("ImpressionsPipeline", hcAppCounter.NewRateCheck(impTask.SaturationStats, cfg.PipelineSaturationThreshold))
+		newCounter("EventsPipeline", hcAppCounter.NewRateCheck(evTask.SaturationStats, cfg.PipelineSaturationThreshold))
+	}
+
+	if withStats, ok := listener.(*impressionlistener.ImpressionBulkListenerImpl); ok && cfg.ListenerFailureRateThreshold > 0 {
+		newCounter("ImpressionListener", hcAppCounter.NewRateCheck(func() (int64, int64) {
+			stats := withStats.DeliveryStats()
+			return stats.Submitted, stats.Dropped + stats.Failed
+		}, cfg.ListenerFailureRateThreshold))
+	}
+
+	return counters
+}
+
 func getServicesCountersConfig(advanced *cconf.AdvancedConfig) []hcServicesCounter.Config {
 	var cfgs []hcServicesCounter.Config
 
@@ -332,3 +507,39 @@ func getServicesCountersConfig(advanced *cconf.AdvancedConfig) []hcServicesCount
 
 	return append(cfgs, telemetryConfig, authConfig, apiConfig, eventsConfig, streamingConfig)
 }
+
+func newQueueGuard(
+	cfg *conf.QueueProtection,
+	flagWriter backpressure.FlagWriter,
+	memoryClient memoryInfoClient,
+	storages adminCommon.Storages,
+	healthCounter hcAppCounter.StatusCounterInterface,
+	logger logging.LoggerInterface,
+) (*backpressure.Guard, error) {
+	guardCfg := &backpressure.Config{
+		Logger:                logger,
+		ImpressionsWatermarks: backpressure.Watermarks{High: cfg.ImpressionsHighWatermark, Low: cfg.ImpressionsLowWatermark},
+		EventsWatermarks:      backpressure.Watermarks{High: cfg.EventsHighWatermark, Low: cfg.EventsLowWatermark},
+		MemoryWatermarks:      backpressure.Watermarks{High: cfg.MemoryHighWatermarkMb << 20, Low: cfg.MemoryLowWatermarkMb << 20},
+		Action:                cfg.Action,
+		SamplePercentage:      cfg.SamplePercentage,
+		FlagWriter:            flagWriter,
+		FlagKey:               cfg.FlagKey,
+		HealthCounter:         healthCounter,
+		CheckPeriodSecs:       common.PeriodSecs(cfg.CheckRateMs),
+	}
+
+	if queue, ok := storages.ImpressionStorage.(backpressure.Queue); ok {
+		guardCfg.Impressions = queue
+	}
+
+	if queue, ok := storages.EventStorage.(backpressure.Queue); ok {
+		guardCfg.Events = queue
+	}
+
+	if cfg.MemoryHighWatermarkMb > 0 {
+		guardCfg.Memory = newRedisMemoryReader(memoryClient)
+	}
+
+	return backpressure.New(guardCfg)
+}
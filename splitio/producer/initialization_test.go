@@ -203,3 +203,58 @@ func getDefaultConf() *conf.Main {
 	cconf.PopulateDefaults(&c)
 	return &c
 }
+
+func TestParseUsedMemory(t *testing.T) {
+	used, err := parseUsedMemory("# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n")
+	if err != nil || used != 1048576 {
+		t.Error("used memory should be 1048576. Got: ", used, err)
+	}
+
+	if _, err := parseUsedMemory("# Memory\r\nused_memory_human:1.00M\r\n"); err == nil {
+		t.Error("there should be an error if used_memory is missing")
+	}
+}
+
+type memoryInfoClientMock struct {
+	prefix string
+	keys   []string
+	values map[string]string
+	err    error
+}
+
+func (m *memoryInfoClientMock) Prefix() string { return m.prefix }
+
+func (m *memoryInfoClientMock) Eval(script string, keys []string, args ...interface{}) error {
+	m.keys = keys
+	if m.err != nil {
+		return m.err
+	}
+	m.values[keys[0]] = "# Memory\r\nused_memory:2048\r\n"
+	return nil
+}
+
+func (m *memoryInfoClientMock) Get(key string) (string, error) {
+	value, ok := m.values[m.prefix+"."+key]
+	if !ok {
+		return "", errors.New("nil")
+	}
+	return value, nil
+}
+
+func TestRedisMemoryReader(t *testing.T) {
+	client := &memoryInfoClientMock{prefix: "someprefix", values: make(map[string]string)}
+	reader := newRedisMemoryReader(client)
+
+	used, err := reader.UsedMemory()
+	if err != nil || used != 2048 {
+		t.Error("used memory should be 2048. Got: ", used, err)
+	}
+	if len(client.keys) != 1 || client.keys[0] != "someprefix."+KeyMemoryInfo {
+		t.Error("script keys should be prefixed. Got: ", client.keys)
+	}
+
+	client.err = errors.New("NOSCRIPT")
+	if _, err := reader.UsedMemory(); err == nil {
+		t.Error("script errors should be returned")
+	}
+}
@@ -0,0 +1,87 @@
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage"
+)
+
+// DefaultMaxCachedSplits is the number of traffic types kept by default. Impressions can reference arbitrary split names,
+// so the cache is bounded
+const DefaultMaxCachedSplits = 10000
+
+type cachedTrafficType struct {
+	trafficType string
+	expiration  time.Time
+}
+
+// TrafficTypeCache resolves split traffic types from a split storage, caching results to avoid hitting redis on every impression
+type TrafficTypeCache struct {
+	splitStorage storage.SplitStorageConsumer
+	ttl          time.Duration
+	maxEntries   int
+	cache        map[string]cachedTrafficType
+	mutex        sync.RWMutex
+}
+
+// NewTrafficTypeCache constructs a new traffic type cache holding up to maxEntries splits (DefaultMaxCachedSplits if <= 0)
+func NewTrafficTypeCache(splitStorage storage.SplitStorageConsumer, ttl time.Duration, maxEntries int) *TrafficTypeCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxCachedSplits
+	}
+	return &TrafficTypeCache{
+		splitStorage: splitStorage,
+		ttl:          ttl,
+		maxEntries:   maxEntries,
+		cache:        make(map[string]cachedTrafficType),
+	}
+}
+
+// Resolve returns the traffic type of a split, or an empty string if the split is not found
+func (c *TrafficTypeCache) Resolve(splitName string) string {
+	now := time.Now()
+	c.mutex.RLock()
+	cached, ok := c.cache[splitName]
+	c.mutex.RUnlock()
+	if ok && now.Before(cached.expiration) {
+		return cached.trafficType
+	}
+
+	var trafficType string
+	if split := c.splitStorage.Split(splitName); split != nil {
+		trafficType = split.TrafficTypeName
+	}
+
+	c.mutex.Lock()
+	if _, ok := c.cache[splitName]; !ok && len(c.cache) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.cache[splitName] = cachedTrafficType{trafficType: trafficType, expiration: now.Add(c.ttl)}
+	c.mutex.Unlock()
+	return trafficType
+}
+
+// Size returns the number of cached splits
+func (c *TrafficTypeCache) Size() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.cache)
+}
+
+// evict removes expired entries. If none has expired, an arbitrary one is removed to make room for a new one.
+// Must be called with the lock held
+func (c *TrafficTypeCache) evict(now time.Time) {
+	for name, cached := range c.cache {
+		if !now.Before(cached.expiration) {
+			delete(c.cache, name)
+		}
+	}
+
+	for name := range c.cache {
+		if len(c.cache) < c.maxEntries {
+			return
+		}
+		delete(c.cache, name)
+	}
+}
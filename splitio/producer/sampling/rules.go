@@ -0,0 +1,110 @@
+package sampling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule targets
+const (
+	TargetSplit       = "split"
+	TargetTrafficType = "trafficType"
+	TargetEventType   = "eventType"
+)
+
+// Rule policies
+const (
+	PolicyRate  = "rate"
+	PolicyLimit = "limit"
+	PolicyDrop  = "drop"
+)
+
+// Rule defines how data matching a split name, traffic type or event type should be sampled
+type Rule struct {
+	Target    string
+	Name      string
+	Policy    string
+	Rate      float64
+	PerSecond int64
+}
+
+// String returns the rule in the same format it's parsed from
+func (r Rule) String() string {
+	switch r.Policy {
+	case PolicyRate:
+		return fmt.Sprintf("%s:%s=%s:%s", r.Target, r.Name, r.Policy, strconv.FormatFloat(r.Rate, 'f', -1, 64))
+	case PolicyLimit:
+		return fmt.Sprintf("%s:%s=%s:%d", r.Target, r.Name, r.Policy, r.PerSecond)
+	}
+	return fmt.Sprintf("%s:%s=%s", r.Target, r.Name, r.Policy)
+}
+
+// ParseRules parses a list of rules with the format `<target>:<name>=<policy>[:<value>]` where:
+// - target is one of `split`, `trafficType` or `eventType`
+// - policy is one of `rate:<0..1>` (fraction of items to keep), `limit:<n>` (max items per second) or `drop`.
+// Empty strings are ignored.
+func ParseRules(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for _, raw := range rules {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		rule, err := parseRule(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling rule '%s': %w", raw, err)
+		}
+		parsed = append(parsed, *rule)
+	}
+	return parsed, nil
+}
+
+func parseRule(raw string) (*Rule, error) {
+	selector, policy := splitPair(raw, "=")
+	target, name := splitPair(selector, ":")
+	switch target {
+	case TargetSplit, TargetTrafficType, TargetEventType:
+	default:
+		return nil, fmt.Errorf("unknown target '%s'", target)
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("missing %s name", target)
+	}
+
+	rule := &Rule{Target: target, Name: name}
+	policyName, value := splitPair(policy, ":")
+	rule.Policy = policyName
+	switch policyName {
+	case PolicyDrop:
+		if value != "" {
+			return nil, fmt.Errorf("policy '%s' takes no value", PolicyDrop)
+		}
+	case PolicyRate:
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("rate must be a number between 0 and 1")
+		}
+		rule.Rate = rate
+	case PolicyLimit:
+		perSecond, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || perSecond < 0 {
+			return nil, fmt.Errorf("limit must be a non-negative integer")
+		}
+		rule.PerSecond = perSecond
+	default:
+		return nil, fmt.Errorf("unknown policy '%s'", policyName)
+	}
+
+	return rule, nil
+}
+
+func splitPair(str string, sep string) (string, string) {
+	idx := strings.Index(str, sep)
+	if idx == -1 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
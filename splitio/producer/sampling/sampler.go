@@ -0,0 +1,147 @@
+package sampling
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TrafficTypeResolver returns the traffic type of a split, or an empty string if unknown
+type TrafficTypeResolver func(splitName string) string
+
+// Stats contains the amount of items sampled out since startup
+type Stats struct {
+	Impressions int64            `json:"impressions"`
+	Events      int64            `json:"events"`
+	ByRule      map[string]int64 `json:"byRule"`
+}
+
+// ruleState holds the runtime state associated to a rule
+type ruleState struct {
+	rule       Rule
+	sampledOut int64
+
+	// per-second limiter
+	mutex       sync.Mutex
+	window      int64
+	windowCount int64
+}
+
+func (s *ruleState) keep(now time.Time, random func() float64) bool {
+	var keep bool
+	switch s.rule.Policy {
+	case PolicyRate:
+		keep = random() < s.rule.Rate
+	case PolicyLimit:
+		s.mutex.Lock()
+		if second := now.Unix(); second != s.window {
+			s.window = second
+			s.windowCount = 0
+		}
+		keep = s.windowCount < s.rule.PerSecond
+		if keep {
+			s.windowCount++
+		}
+		s.mutex.Unlock()
+	}
+
+	if !keep {
+		atomic.AddInt64(&s.sampledOut, 1)
+	}
+	return keep
+}
+
+// Sampler decides whether impressions & events should be forwarded based on the configured rules.
+// Split & event type rules take precedence over traffic type ones.
+type Sampler struct {
+	splits       map[string]*ruleState
+	trafficTypes map[string]*ruleState
+	eventTypes   map[string]*ruleState
+	resolver     TrafficTypeResolver
+	random       func() float64
+	impressions  int64
+	events       int64
+}
+
+// NewSampler constructs a sampler. The resolver is only used (and required) if traffic type rules are present
+func NewSampler(rules []Rule, resolver TrafficTypeResolver) *Sampler {
+	s := &Sampler{
+		splits:       make(map[string]*ruleState),
+		trafficTypes: make(map[string]*ruleState),
+		eventTypes:   make(map[string]*ruleState),
+		resolver:     resolver,
+		random:       rand.Float64,
+	}
+
+	for _, rule := range rules {
+		state := &ruleState{rule: rule}
+		switch rule.Target {
+		case TargetSplit:
+			s.splits[rule.Name] = state
+		case TargetTrafficType:
+			s.trafficTypes[rule.Name] = state
+		case TargetEventType:
+			s.eventTypes[rule.Name] = state
+		}
+	}
+
+	return s
+}
+
+// KeepImpression returns whether an impression of the provided split should be forwarded
+func (s *Sampler) KeepImpression(splitName string) bool {
+	state, ok := s.splits[splitName]
+	if !ok && len(s.trafficTypes) > 0 && s.resolver != nil {
+		state, ok = s.trafficTypes[s.resolver(splitName)]
+	}
+
+	if !ok || state.keep(time.Now(), s.random) {
+		return true
+	}
+
+	atomic.AddInt64(&s.impressions, 1)
+	return false
+}
+
+// KeepEvent returns whether an event with the provided traffic & event type should be forwarded
+func (s *Sampler) KeepEvent(trafficType string, eventType string) bool {
+	state, ok := s.eventTypes[eventType]
+	if !ok {
+		state, ok = s.trafficTypes[trafficType]
+	}
+
+	if !ok || state.keep(time.Now(), s.random) {
+		return true
+	}
+
+	atomic.AddInt64(&s.events, 1)
+	return false
+}
+
+// Stats returns the amount of items sampled out, in total and per rule. It's safe to call on a nil sampler
+func (s *Sampler) Stats() Stats {
+	if s == nil {
+		return Stats{ByRule: map[string]int64{}}
+	}
+
+	stats := Stats{
+		Impressions: atomic.LoadInt64(&s.impressions),
+		Events:      atomic.LoadInt64(&s.events),
+		ByRule:      make(map[string]int64),
+	}
+
+	for _, states := range []map[string]*ruleState{s.splits, s.trafficTypes, s.eventTypes} {
+		for _, state := range states {
+			stats.ByRule[state.rule.String()] = atomic.LoadInt64(&state.sampledOut)
+		}
+	}
+	return stats
+}
+
+// SampledOut returns the amount of impressions & events sampled out, along with the per rule breakdown.
+// It's safe to call on a nil sampler
+func (s *Sampler) SampledOut() (int64, int64, map[string]int64) {
+	stats := s.Stats()
+	return stats.Impressions, stats.Events, stats.ByRule
+}
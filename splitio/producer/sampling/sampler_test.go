@@ -0,0 +1,156 @@
+package sampling
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"", "split:page_load=rate:0.25", "trafficType:user=limit:100", "eventType:click=drop"})
+	if err != nil {
+		t.Error("there should be no error. Got: ", err)
+	}
+
+	expected := []Rule{
+		{Target: TargetSplit, Name: "page_load", Policy: PolicyRate, Rate: 0.25},
+		{Target: TargetTrafficType, Name: "user", Policy: PolicyLimit, PerSecond: 100},
+		{Target: TargetEventType, Name: "click", Policy: PolicyDrop},
+	}
+	if len(rules) != len(expected) {
+		t.Error("wrong rules: ", rules)
+	}
+	for idx := range expected {
+		if rules[idx] != expected[idx] {
+			t.Error("wrong rule: ", rules[idx])
+		}
+	}
+
+	if rules[0].String() != "split:page_load=rate:0.25" || rules[1].String() != "trafficType:user=limit:100" || rules[2].String() != "eventType:click=drop" {
+		t.Error("rules should be serialized in the same format they're parsed from")
+	}
+
+	for _, invalid := range []string{"key:a=drop", "split:=drop", "split:a", "split:a=rate:2", "split:a=limit:x", "split:a=drop:1", "split:a=other"} {
+		if _, err := ParseRules([]string{invalid}); err == nil {
+			t.Error("there should be an error for rule: ", invalid)
+		}
+	}
+}
+
+func TestSamplerImpressions(t *testing.T) {
+	rules, _ := ParseRules([]string{"split:s1=drop", "split:s2=rate:0.5", "trafficType:account=drop"})
+	sampler := NewSampler(rules, func(splitName string) string {
+		if splitName == "s3" {
+			return "account"
+		}
+		return "user"
+	})
+
+	values := []float64{0.1, 0.9}
+	sampler.random = func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	if sampler.KeepImpression("s1") {
+		t.Error("s1 should be dropped")
+	}
+	if !sampler.KeepImpression("s2") || sampler.KeepImpression("s2") {
+		t.Error("s2 should be kept only when the random value is below the rate")
+	}
+	if sampler.KeepImpression("s3") {
+		t.Error("s3 should be dropped because of its traffic type")
+	}
+	if !sampler.KeepImpression("s4") {
+		t.Error("s4 should be kept")
+	}
+
+	stats := sampler.Stats()
+	if stats.Impressions != 3 || stats.Events != 0 {
+		t.Error("wrong stats: ", stats)
+	}
+	if stats.ByRule["split:s1=drop"] != 1 || stats.ByRule["split:s2=rate:0.5"] != 1 || stats.ByRule["trafficType:account=drop"] != 1 {
+		t.Error("wrong stats by rule: ", stats.ByRule)
+	}
+}
+
+func TestSamplerEvents(t *testing.T) {
+	rules, _ := ParseRules([]string{"eventType:page_view=limit:2", "trafficType:anonymous=drop", "eventType:purchase=rate:1"})
+	sampler := NewSampler(rules, nil)
+
+	kept := 0
+	for i := 0; i < 10; i++ {
+		if sampler.KeepEvent("user", "page_view") {
+			kept++
+		}
+	}
+	if kept < 2 || kept > 4 { // a second boundary may be crossed during the loop
+		t.Error("only 2 page views per second should be kept. Got: ", kept)
+	}
+
+	if !sampler.KeepEvent("anonymous", "purchase") {
+		t.Error("event type rules should take precedence over traffic type ones")
+	}
+	if sampler.KeepEvent("anonymous", "click") {
+		t.Error("anonymous events should be dropped")
+	}
+
+	if stats := sampler.Stats(); stats.Events != int64(10-kept+1) || stats.Impressions != 0 {
+		t.Error("wrong stats: ", stats)
+	}
+}
+
+func TestTrafficTypeCache(t *testing.T) {
+	calls := 0
+	cache := NewTrafficTypeCache(&mocks.MockSplitStorage{
+		SplitCall: func(name string) *dtos.SplitDTO {
+			calls++
+			if name == "s1" {
+				return &dtos.SplitDTO{Name: "s1", TrafficTypeName: "user"}
+			}
+			return nil
+		},
+	}, time.Minute, 3)
+
+	if cache.Resolve("s1") != "user" || cache.Resolve("s1") != "user" || calls != 1 {
+		t.Error("traffic type should be fetched once and cached")
+	}
+	if cache.Resolve("missing") != "" {
+		t.Error("unknown splits should resolve to an empty traffic type")
+	}
+
+	// the cache is bounded no matter how many different split names are resolved
+	for idx := 0; idx < 100; idx++ {
+		cache.Resolve(fmt.Sprintf("unknown%d", idx))
+	}
+	if size := cache.Size(); size != 3 {
+		t.Error("cache should be capped at 3 entries. Got: ", size)
+	}
+
+	// expired entries are evicted first
+	expiring := NewTrafficTypeCache(&mocks.MockSplitStorage{SplitCall: func(string) *dtos.SplitDTO { return nil }}, -time.Second, 2)
+	expiring.Resolve("a")
+	expiring.Resolve("b")
+	expiring.Resolve("c")
+	if size := expiring.Size(); size != 1 {
+		t.Error("expired entries should have been removed. Got: ", size)
+	}
+}
+
+func TestSamplerSampledOut(t *testing.T) {
+	var sampler *Sampler
+	if impressions, events, byRule := sampler.SampledOut(); impressions != 0 || events != 0 || byRule == nil {
+		t.Error("a nil sampler should report nothing. Got: ", impressions, events, byRule)
+	}
+
+	rules, _ := ParseRules([]string{"split:s1=drop"})
+	sampler = NewSampler(rules, nil)
+	sampler.KeepImpression("s1")
+	if impressions, events, byRule := sampler.SampledOut(); impressions != 1 || events != 0 || len(byRule) != 1 {
+		t.Error("wrong sampled out counts: ", impressions, events, byRule)
+	}
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+const (
+	// KeyChangeLog is the list holding the split/segment change history, oldest first
+	KeyChangeLog = "SPLITIO.changes"
+
+	// KeyChangeLogSeq is the counter used to assign IDs to change log entries
+	KeyChangeLogSeq = "SPLITIO.changes.seq"
+
+	changeLogPageSize = 500
+)
+
+// ListClient defines the subset of redis operations used by the change log. Keys are prefixed by the client
+type ListClient interface {
+	Incr(key string) (int64, error)
+	RPush(key string, values ...interface{}) (int64, error)
+	LRange(key string, start, stop int64) ([]string, error)
+	LTrim(key string, start, stop int64) error
+	LLen(key string) (int64, error)
+}
+
+// RedisChangeLogStorage keeps the split/segment change history in a redis list. Only list commands are used, so that it
+// works on any redis version
+type RedisChangeLogStorage struct {
+	client     ListClient
+	maxEntries int64
+	logger     logging.LoggerInterface
+}
+
+// NewRedisChangeLogStorage constructs a new change log storage on top of a redis list.
+// maxEntries is used to cap the list on every insertion
+func NewRedisChangeLogStorage(client ListClient, maxEntries int, logger logging.LoggerInterface) *RedisChangeLogStorage {
+	return &RedisChangeLogStorage{client: client, maxEntries: int64(maxEntries), logger: logger}
+}
+
+// Append adds the entries to the list
+func (r *RedisChangeLogStorage) Append(entries []changelog.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	serialized := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		seq, err := r.client.Incr(KeyChangeLogSeq)
+		if err != nil {
+			return fmt.Errorf("error generating change log entry id: %w", err)
+		}
+		entry.ID = strconv.FormatInt(seq, 10)
+
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("error serializing change log entry: %w", err)
+		}
+		serialized = append(serialized, string(raw))
+	}
+
+	length, err := r.client.RPush(KeyChangeLog, serialized...)
+	if err != nil {
+		return fmt.Errorf("error appending to change log: %w", err)
+	}
+
+	if r.maxEntries > 0 && length > r.maxEntries {
+		if err := r.client.LTrim(KeyChangeLog, -r.maxEntries, -1); err != nil {
+			return fmt.Errorf("error capping change log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Query returns the entries matching the query, newest first
+func (r *RedisChangeLogStorage) Query(query changelog.Query) ([]changelog.Entry, error) {
+	length, err := r.client.LLen(KeyChangeLog)
+	if err != nil {
+		return nil, fmt.Errorf("error reading change log size: %w", err)
+	}
+
+	// positive indexes are used so that concurrent appends don't shift the pages being read
+	var result []changelog.Entry
+	for end := length - 1; end >= 0; end -= changeLogPageSize {
+		start := end - changeLogPageSize + 1
+		if start < 0 {
+			start = 0
+		}
+
+		raws, err := r.client.LRange(KeyChangeLog, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("error reading change log: %w", err)
+		}
+
+		for idx := len(raws) - 1; idx >= 0; idx-- {
+			var entry changelog.Entry
+			if err := json.Unmarshal([]byte(raws[idx]), &entry); err != nil {
+				r.logger.Warning("skipping unparseable change log entry: ", err)
+				continue
+			}
+
+			if query.Since > 0 && entry.Timestamp < query.Since {
+				return result, nil // entries are sorted, no need to keep reading
+			}
+
+			if query.Matches(&entry) {
+				result = append(result, entry)
+				if query.Limit > 0 && len(result) >= query.Limit {
+					return result, nil
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// Trim removes entries older than the supplied timestamp & the oldest ones in excess of maxEntries
+func (r *RedisChangeLogStorage) Trim(olderThan int64, maxEntries int) (int, error) {
+	var removed int64
+	if olderThan > 0 {
+		expired, err := r.countOlderThan(olderThan)
+		if err != nil {
+			return 0, err
+		}
+
+		if expired > 0 {
+			if err := r.client.LTrim(KeyChangeLog, expired, -1); err != nil {
+				return 0, fmt.Errorf("error trimming change log by age: %w", err)
+			}
+			removed += expired
+		}
+	}
+
+	if maxEntries > 0 {
+		length, err := r.client.LLen(KeyChangeLog)
+		if err != nil {
+			return int(removed), fmt.Errorf("error reading change log size: %w", err)
+		}
+
+		if excess := length - int64(maxEntries); excess > 0 {
+			if err := r.client.LTrim(KeyChangeLog, excess, -1); err != nil {
+				return int(removed), fmt.Errorf("error trimming change log by size: %w", err)
+			}
+			removed += excess
+		}
+	}
+	return int(removed), nil
+}
+
+// countOlderThan returns the number of entries at the head of the list with a timestamp lower than the supplied one
+func (r *RedisChangeLogStorage) countOlderThan(timestamp int64) (int64, error) {
+	var count int64
+	for {
+		raws, err := r.client.LRange(KeyChangeLog, count, count+changeLogPageSize-1)
+		if err != nil {
+			return 0, fmt.Errorf("error reading change log: %w", err)
+		}
+
+		for _, raw := range raws {
+			var entry changelog.Entry
+			if err := json.Unmarshal([]byte(raw), &entry); err == nil && entry.Timestamp >= timestamp {
+				return count, nil
+			}
+			count++ // unparseable entries are removed along with the expired ones
+		}
+
+		if len(raws) < changeLogPageSize {
+			return count, nil
+		}
+	}
+}
+
+var _ changelog.Storage = (*RedisChangeLogStorage)(nil)
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+// listClientMock implements the list commands used by the change log on top of an in-memory slice
+type listClientMock struct {
+	keys  map[string]bool
+	seq   int64
+	items []string
+}
+
+func (m *listClientMock) Incr(key string) (int64, error) {
+	m.keys[key] = true
+	m.seq++
+	return m.seq, nil
+}
+
+func (m *listClientMock) RPush(key string, values ...interface{}) (int64, error) {
+	m.keys[key] = true
+	for _, value := range values {
+		m.items = append(m.items, value.(string))
+	}
+	return int64(len(m.items)), nil
+}
+
+func (m *listClientMock) index(idx int64) int64 {
+	if idx < 0 {
+		idx += int64(len(m.items))
+	}
+	if idx < 0 {
+		return 0
+	}
+	return idx
+}
+
+func (m *listClientMock) LRange(key string, start, stop int64) ([]string, error) {
+	start, stop = m.index(start), m.index(stop)
+	if stop >= int64(len(m.items)) {
+		stop = int64(len(m.items)) - 1
+	}
+	if start > stop {
+		return nil, nil
+	}
+	return append([]string(nil), m.items[start:stop+1]...), nil
+}
+
+func (m *listClientMock) LTrim(key string, start, stop int64) error {
+	items, _ := m.LRange(key, start, stop)
+	m.items = items
+	return nil
+}
+
+func (m *listClientMock) LLen(key string) (int64, error) {
+	return int64(len(m.items)), nil
+}
+
+func TestRedisChangeLogStorage(t *testing.T) {
+	client := &listClientMock{keys: make(map[string]bool)}
+	st := NewRedisChangeLogStorage(client, 3, logging.NewLogger(nil))
+
+	err := st.Append([]changelog.Entry{
+		{Kind: changelog.KindSplit, Name: "split1", Action: changelog.ActionAdded, Timestamp: 1},
+		{Kind: changelog.KindSegment, Name: "segment1", Action: changelog.ActionUpdated, Timestamp: 2, KeysRemoved: 4},
+	})
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	if !client.keys[KeyChangeLog] || !client.keys[KeyChangeLogSeq] {
+		t.Error("invalid keys: ", client.keys)
+	}
+
+	entries, err := st.Query(changelog.Query{})
+	if err != nil || len(entries) != 2 || entries[0].ID != "2" || entries[0].KeysRemoved != 4 || entries[1].Name != "split1" {
+		t.Error("invalid entries: ", entries, err)
+	}
+
+	entries, _ = st.Query(changelog.Query{Kind: changelog.KindSplit})
+	if len(entries) != 1 || entries[0].Name != "split1" {
+		t.Error("only the split entry should be returned. Got: ", entries)
+	}
+
+	st.Append([]changelog.Entry{
+		{Kind: changelog.KindSplit, Name: "split2", Action: changelog.ActionAdded, Timestamp: 3},
+		{Kind: changelog.KindSplit, Name: "split3", Action: changelog.ActionAdded, Timestamp: 4},
+	})
+	if len(client.items) != 3 {
+		t.Error("list should be capped on insertion. Got: ", len(client.items))
+	}
+
+	entries, _ = st.Query(changelog.Query{Since: 3})
+	if len(entries) != 2 || entries[0].Name != "split3" || entries[1].Name != "split2" {
+		t.Error("only entries newer than 3 should be returned. Got: ", entries)
+	}
+
+	if removed, err := st.Trim(3, 0); err != nil || removed != 1 {
+		t.Error("1 expired entry should be trimmed. Got: ", removed, err)
+	}
+
+	if removed, err := st.Trim(0, 1); err != nil || removed != 1 {
+		t.Error("1 entry should be trimmed. Got: ", removed, err)
+	}
+
+	entries, _ = st.Query(changelog.Query{})
+	if len(entries) != 1 || entries[0].Name != "split3" || entries[0].ID != "4" {
+		t.Error("only the newest entry should remain. Got: ", entries)
+	}
+}
+
+func TestRedisChangeLogStoragePaging(t *testing.T) {
+	client := &listClientMock{keys: make(map[string]bool)}
+	st := NewRedisChangeLogStorage(client, 0, logging.NewLogger(nil))
+
+	entries := make([]changelog.Entry, 0, 2*changeLogPageSize+10)
+	for idx := 0; idx < 2*changeLogPageSize+10; idx++ {
+		entries = append(entries, changelog.Entry{Kind: changelog.KindSplit, Name: "split", Timestamp: int64(idx)})
+	}
+	st.Append(entries)
+
+	result, _ := st.Query(changelog.Query{})
+	if len(result) != len(entries) || result[0].Timestamp != int64(len(entries)-1) || result[len(result)-1].Timestamp != 0 {
+		t.Error("all entries should be returned newest first. Got: ", len(result))
+	}
+
+	if removed, _ := st.Trim(changeLogPageSize+5, 0); removed != changeLogPageSize+5 {
+		t.Error("expired entries spanning several pages should be trimmed. Got: ", removed)
+	}
+}
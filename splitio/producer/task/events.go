@@ -11,7 +11,9 @@ import (
 	"github.com/splitio/go-split-commons/v4/dtos"
 	"github.com/splitio/go-split-commons/v4/storage"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/sampling"
 )
 
 const (
@@ -24,6 +26,8 @@ type EventWorkerConfig struct {
 	Logger          logging.LoggerInterface
 	Storage         storage.EventMultiSdkConsumer
 	EvictionMonitor evcalc.Monitor
+	Sampler         *sampling.Sampler
+	Fleet           *fleet.Inventory
 	URL             string
 	Apikey          string
 	FetchSize       int
@@ -40,6 +44,8 @@ type EventsPipelineWorker struct {
 	logger          logging.LoggerInterface
 	storage         storage.EventMultiSdkConsumer
 	evictionMonitor evcalc.Monitor
+	sampler         *sampling.Sampler
+	fleet           *fleet.Inventory
 
 	url       string
 	apikey    string
@@ -53,6 +59,8 @@ func NewEventsWorker(cfg *EventWorkerConfig) (*EventsPipelineWorker, error) {
 	return &EventsPipelineWorker{
 		logger:          cfg.Logger,
 		evictionMonitor: cfg.EvictionMonitor,
+		sampler:         cfg.Sampler,
+		fleet:           cfg.Fleet,
 		storage:         cfg.Storage,
 		url:             cfg.URL + "/events/bulk",
 		apikey:          cfg.Apikey,
@@ -67,7 +75,12 @@ func NewEventsWorker(cfg *EventWorkerConfig) (*EventsPipelineWorker, error) {
 // We should eventually revisit the redis client interface and see how feasible it is
 // to return bytes directly.
 func (i *EventsPipelineWorker) Fetch() ([]string, error) {
-	raw, sizeAfterPop, err := i.storage.PopNRaw(i.fetchSize)
+	return i.FetchN(i.fetchSize)
+}
+
+// FetchN fetches up to n raw events
+func (i *EventsPipelineWorker) FetchN(n int64) ([]string, error) {
+	raw, sizeAfterPop, err := i.storage.PopNRaw(n)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching raw events: %w", err)
 	}
@@ -82,6 +95,11 @@ func (i *EventsPipelineWorker) Process(raws [][]byte, sink chan<- interface{}) e
 	// which will be released after imrpessions have been successfully posted
 	defer batches.recycleContainer()
 
+	var bySDK map[dtos.Metadata]int64
+	if i.fleet != nil {
+		bySDK = make(map[dtos.Metadata]int64)
+	}
+
 	for _, raw := range raws {
 		var queueObj dtos.QueueStoredEventDTO
 		err := json.Unmarshal(raw, &queueObj)
@@ -89,9 +107,21 @@ func (i *EventsPipelineWorker) Process(raws [][]byte, sink chan<- interface{}) e
 			i.logger.Error("error deserializing fetched events: ", err.Error())
 			continue
 		}
+
+		if bySDK != nil {
+			bySDK[queueObj.Metadata]++
+		}
+
+		if i.sampler != nil && !i.sampler.KeepEvent(queueObj.Event.TrafficTypeName, queueObj.Event.EventTypeID) {
+			continue
+		}
 		batches.add(&queueObj)
 	}
 
+	for metadata, count := range bySDK {
+		i.fleet.Track(metadata, fleet.ActivityEvents, count)
+	}
+
 	for retIndex := range batches.groups {
 		sink <- batches.groups[retIndex]
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/splitio/go-split-commons/v4/storage/mocks"
 	"github.com/splitio/go-toolkit/v5/logging"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/sampling"
 )
 
 type eventTrackingAllocator struct {
@@ -201,3 +202,46 @@ func TestEventsIntegration(t *testing.T) {
 		t.Error("machine2 should have 500 events. Has ", r)
 	}
 }
+
+func TestEventsSampling(t *testing.T) {
+	rules, _ := sampling.ParseRules([]string{"eventType:page_view=drop"})
+	w, err := NewEventsWorker(&EventWorkerConfig{
+		EvictionMonitor: evcalc.New(1),
+		Logger:          logging.NewLogger(nil),
+		Sampler:         sampling.NewSampler(rules, nil),
+		Storage:         mocks.MockEventStorage{},
+		URL:             "http://test",
+		Apikey:          "someApikey",
+		FetchSize:       100,
+	})
+	if err != nil {
+		t.Error("there should be no error. Got: ", err)
+	}
+
+	raws := make([][]byte, 0, 10)
+	for idx := 0; idx < 10; idx++ {
+		eventType := "click"
+		if idx%2 == 0 {
+			eventType = "page_view"
+		}
+		raw, _ := json.Marshal(&dtos.QueueStoredEventDTO{Event: dtos.EventDTO{Key: "key", EventTypeID: eventType, TrafficTypeName: "user"}})
+		raws = append(raws, raw)
+	}
+
+	sinker := make(chan interface{}, 100)
+	w.Process(raws, sinker)
+	if len(sinker) != 1 {
+		t.Error("there should be 1 bulk ready for submission")
+	}
+
+	bulk := (<-sinker).(eventsWithMetadata)
+	if len(bulk.events) != 5 {
+		t.Error("only 5 events should be forwarded. Got: ", len(bulk.events))
+	}
+	for _, event := range bulk.events {
+		if event.EventTypeID != "click" {
+			t.Error("page views should have been dropped")
+		}
+	}
+	bulk.recycle()
+}
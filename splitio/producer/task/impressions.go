@@ -13,8 +13,10 @@ import (
 	"github.com/splitio/go-split-commons/v4/provisional"
 	"github.com/splitio/go-split-commons/v4/storage"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/sampling"
 )
 
 const (
@@ -33,6 +35,8 @@ type ImpressionWorkerConfig struct {
 	ImpressionsListener impressionlistener.ImpressionBulkListener
 	Telemetry           storage.TelemetryRuntimeProducer
 	EvictionMonitor     evcalc.Monitor
+	Sampler             *sampling.Sampler
+	Fleet               *fleet.Inventory
 	URL                 string
 	Apikey              string
 	FetchSize           int
@@ -52,6 +56,10 @@ type ImpressionsPipelineWorker struct {
 	impManager      provisional.ImpressionManager
 	impListener     impressionlistener.ImpressionBulkListener
 	evictionMonitor evcalc.Monitor
+	sampler         *sampling.Sampler
+	impCounter      *provisional.ImpressionsCounter
+	countSampled    bool
+	fleet           *fleet.Inventory
 
 	url       string
 	apikey    string
@@ -75,6 +83,10 @@ func NewImpressionWorker(cfg *ImpressionWorkerConfig) (*ImpressionsPipelineWorke
 		return nil, fmt.Errorf("failed to instantiate impressions manager: %w", err)
 	}
 
+	// in optimized mode the impression manager already counts every impression, including the ones sampled out.
+	// otherwise, sampled out impressions are counted separately so that they're not lost
+	countSampled := cfg.ImpressionCounter != nil && cfg.ImpressionsMode != conf.ImpressionsModeOptimized
+
 	return &ImpressionsPipelineWorker{
 		logger:          cfg.Logger,
 		storage:         cfg.Storage,
@@ -84,6 +96,10 @@ func NewImpressionWorker(cfg *ImpressionWorkerConfig) (*ImpressionsPipelineWorke
 		apikey:          cfg.Apikey,
 		fetchSize:       int64(cfg.FetchSize),
 		evictionMonitor: cfg.EvictionMonitor,
+		sampler:         cfg.Sampler,
+		impCounter:      cfg.ImpressionCounter,
+		countSampled:    countSampled,
+		fleet:           cfg.Fleet,
 		pool:            newImpWorkerMemoryPool(cfg.FetchSize, defaultMetasPerBulk, defaultFeatureCount, defaultImpsPerFeature),
 	}, nil
 }
@@ -94,7 +110,12 @@ func NewImpressionWorker(cfg *ImpressionWorkerConfig) (*ImpressionsPipelineWorke
 // We should eventually revisit the redis client interface and see how feasible it is
 // to return bytes directly.
 func (i *ImpressionsPipelineWorker) Fetch() ([]string, error) {
-	raw, sizeAfterPop, err := i.storage.PopNRaw(i.fetchSize)
+	return i.FetchN(i.fetchSize)
+}
+
+// FetchN fetches up to n raw impressions
+func (i *ImpressionsPipelineWorker) FetchN(n int64) ([]string, error) {
+	raw, sizeAfterPop, err := i.storage.PopNRaw(n)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching raw impressions: %w", err)
 	}
@@ -109,7 +130,13 @@ func (i *ImpressionsPipelineWorker) Process(raws [][]byte, sink chan<- interface
 	// which will be released after imrpessions have been successfully posted
 	defer batches.recycleContainer()
 
+	var bySDK map[dtos.Metadata]int64
+	if i.fleet != nil {
+		bySDK = make(map[dtos.Metadata]int64)
+	}
+
 	deduped := 0
+	sampled := 0
 	for _, raw := range raws {
 		var queueObj dtos.ImpressionQueueObject
 		err := json.Unmarshal(raw, &queueObj)
@@ -118,16 +145,32 @@ func (i *ImpressionsPipelineWorker) Process(raws [][]byte, sink chan<- interface
 			continue
 		}
 
+		if bySDK != nil {
+			bySDK[queueObj.Metadata]++
+		}
+
 		toLog, _ := i.impManager.ProcessSingle(&queueObj.Impression)
 		if !toLog {
 			deduped++
 			continue
 		}
 
+		if i.sampler != nil && !i.sampler.KeepImpression(queueObj.Impression.FeatureName) {
+			sampled++
+			if i.countSampled {
+				i.impCounter.Inc(queueObj.Impression.FeatureName, queueObj.Impression.Time*int64(time.Millisecond), 1)
+			}
+			continue
+		}
+
 		batches.add(&queueObj)
 	}
 
-	i.logger.Debug(fmt.Sprintf("[pipelined imp worker] total impressions Processed: %d, deduped %d", len(raws), deduped))
+	i.logger.Debug(fmt.Sprintf("[pipelined imp worker] total impressions Processed: %d, deduped %d, sampled out: %d", len(raws), deduped, sampled))
+
+	for metadata, count := range bySDK {
+		i.fleet.Track(metadata, fleet.ActivityImpressions, count)
+	}
 
 	if i.impListener != nil {
 		i.sendImpressionsToListener(batches)
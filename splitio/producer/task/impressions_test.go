@@ -14,10 +14,14 @@ import (
 
 	"github.com/splitio/go-split-commons/v4/conf"
 	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/provisional"
 	"github.com/splitio/go-split-commons/v4/storage/inmemory"
 	"github.com/splitio/go-split-commons/v4/storage/mocks"
+	"github.com/splitio/go-split-commons/v4/util"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/evcalc"
+	"github.com/splitio/split-synchronizer/v5/splitio/producer/sampling"
 )
 
 type trackingAllocator struct {
@@ -251,3 +255,95 @@ func TestImpressionsIntegration(t *testing.T) {
 		t.Errorf("machine0 should have %d impressions. Has %d", expectedImpressionsPerMeta, r)
 	}
 }
+
+func TestImpressionsSampling(t *testing.T) {
+	rules, _ := sampling.ParseRules([]string{"split:feat_0=drop"})
+	counter := provisional.NewImpressionsCounter()
+	w, err := NewImpressionWorker(&ImpressionWorkerConfig{
+		EvictionMonitor:   evcalc.New(1),
+		Logger:            logging.NewLogger(nil),
+		ImpressionsMode:   conf.ImpressionsModeDebug,
+		ImpressionCounter: counter,
+		Sampler:           sampling.NewSampler(rules, nil),
+		Telemetry:         &inmemory.TelemetryStorage{},
+		Storage:           mocks.MockImpressionStorage{},
+		URL:               "http://test",
+		Apikey:            "someApikey",
+		FetchSize:         100,
+	})
+	if err != nil {
+		t.Error("there should be no error. Got: ", err)
+	}
+
+	impressionTime := time.Date(2020, 9, 2, 10, 10, 12, 0, time.UTC)
+	older, _ := json.Marshal(&dtos.ImpressionQueueObject{
+		Metadata:   dtos.Metadata{SDKVersion: "go-1.1.1", MachineName: "machine_0"},
+		Impression: dtos.Impression{FeatureName: "feat_0", KeyName: "key_older", Time: impressionTime.UnixNano() / int64(time.Millisecond)},
+	})
+
+	sinker := make(chan interface{}, 100)
+	w.Process(append(makeSerializedImpressions(1, 2, 10), older), sinker)
+	if len(sinker) != 1 {
+		t.Error("there should be 1 bulk ready for submission")
+	}
+
+	bulk := (<-sinker).(impsWithMetadata)
+	if len(bulk.imps) != 1 || bulk.imps[0].TestName != "feat_1" || len(bulk.imps[0].KeyImpressions) != 10 {
+		t.Error("only feat_1 impressions should be forwarded. Got: ", bulk.imps)
+	}
+	bulk.recycle()
+
+	counts := counter.PopAll()
+	for key := range counts {
+		if key.FeatureName != "feat_0" {
+			t.Error("only sampled out impressions should be counted in debug mode. Got: ", key)
+		}
+	}
+	// impressions are counted in the hourly bucket of the time they were generated at
+	if count := counts[provisional.Key{FeatureName: "feat_0", TimeFrame: 0}]; count != 10 {
+		t.Error("10 impressions should have been counted in the first bucket. Got: ", counts)
+	}
+	olderBucket := util.TruncateTimeFrame(impressionTime.UnixNano())
+	if count := counts[provisional.Key{FeatureName: "feat_0", TimeFrame: olderBucket}]; count != 1 || len(counts) != 2 {
+		t.Error("1 impression should have been counted in the 10am bucket. Got: ", counts)
+	}
+
+	if stats := w.sampler.Stats(); stats.Impressions != 11 {
+		t.Error("11 impressions should have been sampled out. Got: ", stats.Impressions)
+	}
+}
+
+func TestImpressionsFleetTracking(t *testing.T) {
+	inventory := fleet.NewInventory(fleet.Config{})
+	w, err := NewImpressionWorker(&ImpressionWorkerConfig{
+		EvictionMonitor: evcalc.New(1),
+		Logger:          logging.NewLogger(nil),
+		ImpressionsMode: conf.ImpressionsModeDebug,
+		Telemetry:       &inmemory.TelemetryStorage{},
+		Storage:         mocks.MockImpressionStorage{},
+		Fleet:           inventory,
+		URL:             "http://test",
+		Apikey:          "someApikey",
+		FetchSize:       100,
+	})
+	if err != nil {
+		t.Error("there should be no error. Got: ", err)
+	}
+
+	sinker := make(chan interface{}, 100)
+	w.Process(makeSerializedImpressions(2, 2, 10), sinker)
+	for len(sinker) > 0 {
+		bulk := (<-sinker).(impsWithMetadata)
+		bulk.recycle()
+	}
+
+	instances := inventory.Summary().Instances
+	if len(instances) != 2 {
+		t.Error("there should be 2 instances in the fleet inventory. Got: ", instances)
+	}
+	for _, instance := range instances {
+		if instance.SDKVersion != "go-1.1.1" || instance.Activity[fleet.ActivityImpressions] != 20 {
+			t.Error("wrong fleet instance: ", instance)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	tsync "github.com/splitio/go-toolkit/v5/sync"
@@ -42,6 +43,11 @@ type Worker interface {
 	BuildRequest(data interface{}) (*http.Request, func(), error)
 }
 
+// boundedFetcher is implemented by workers able to fetch an arbitrary number of items on demand
+type boundedFetcher interface {
+	FetchN(n int64) ([]string, error)
+}
+
 func (c *Config) normalize() {
 	if c.InputBufferSize == 0 {
 		c.InputBufferSize = defaultInputBufferSize
@@ -87,10 +93,16 @@ type PipelinedSyncTask struct {
 
 	// synchronization elements
 	inputBuffer     chan []string
+	inputMutex      sync.RWMutex // keeps on-demand flushes from pushing into the input buffer once it's closed
 	preSubmitBuffer chan interface{}
 	waiter          sync.WaitGroup
 	running         *tsync.AtomicBool
+	paused          *tsync.AtomicBool
 	shutdown        chan struct{}
+
+	// stats
+	fetches int64
+	stalls  int64
 }
 
 // NewPipelinedTask constructs a pipelined task
@@ -111,6 +123,7 @@ func NewPipelinedTask(config *Config) (*PipelinedSyncTask, error) {
 		processConcurrency: config.ProcessConcurrency,
 		maxAccumWait:       config.MaxAccumWait,
 		running:            tsync.NewAtomicBool(true),
+		paused:             tsync.NewAtomicBool(false),
 		inputBuffer:        make(chan []string, config.InputBufferSize),
 		preSubmitBuffer:    make(chan interface{}, config.PostConcurrency*4),
 		shutdown:           make(chan struct{}, 1),
@@ -158,19 +171,113 @@ func (p *PipelinedSyncTask) IsRunning() bool {
 	return p.running.IsSet()
 }
 
+// Pause stops fetching new data from the queue. Data already fetched will still be processed & posted
+func (p *PipelinedSyncTask) Pause() {
+	if p.paused.TestAndSet() {
+		p.logger.Info(fmt.Sprintf("[pipelined/%s] - paused", p.name))
+	}
+}
+
+// Resume restarts fetching data from the queue after a pause
+func (p *PipelinedSyncTask) Resume() {
+	if p.paused.TestAndClear() {
+		p.logger.Info(fmt.Sprintf("[pipelined/%s] - resumed", p.name))
+	}
+}
+
+// IsPaused returns whether the task is paused or not
+func (p *PipelinedSyncTask) IsPaused() bool {
+	return p.paused.IsSet()
+}
+
+// Flush fetches up to `n` items from the queue and pushes them through the pipeline, regardless of whether the task is paused.
+// Nothing is fetched if the processing buffer is full. It returns the number of items fetched
+func (p *PipelinedSyncTask) Flush(n int64) (int, error) {
+	fetcher, ok := p.worker.(boundedFetcher)
+	if !ok {
+		return 0, errFlushNotSupported
+	}
+
+	p.inputMutex.RLock()
+	defer p.inputMutex.RUnlock()
+	if !p.running.IsSet() {
+		return 0, errTaskNotRunning
+	}
+
+	atomic.AddInt64(&p.fetches, 1)
+	if p.bufferFull() {
+		atomic.AddInt64(&p.stalls, 1)
+		return 0, errBufferFull
+	}
+
+	raw, err := fetcher.FetchN(n)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching items to flush: %w", err)
+	}
+
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	// items are already popped from the queue, so we wait for room in the buffer rather than dropping them.
+	// processors keep consuming until the buffer is closed, which cannot happen while we hold the read lock
+	p.inputBuffer <- raw
+	p.logger.Debug(fmt.Sprintf("[pipelined/%s] Pushed %d items into the processing buffer on demand", p.name, len(raw)))
+	return len(raw), nil
+}
+
+// SaturationStats returns the number of fetches attempted & how many of them were deferred because the processing buffer was full
+func (p *PipelinedSyncTask) SaturationStats() (fetches int64, stalls int64) {
+	return atomic.LoadInt64(&p.fetches), atomic.LoadInt64(&p.stalls)
+}
+
+func (p *PipelinedSyncTask) bufferFull() bool {
+	return len(p.inputBuffer) >= cap(p.inputBuffer)
+}
+
+func (p *PipelinedSyncTask) closeInput() {
+	p.inputMutex.Lock()
+	close(p.inputBuffer)
+	p.inputMutex.Unlock()
+}
+
 func (p *PipelinedSyncTask) filler() {
 	p.logger.Debug(fmt.Sprintf("[pipelined/%s] - starting filling task", p.name))
 	defer p.waiter.Done()
 	timer := time.NewTimer(1 * time.Second)
 	for p.running.IsSet() {
 		timer.Reset(1 * time.Second)
+		if p.paused.IsSet() {
+			select {
+			case <-timer.C:
+				continue
+			case <-p.shutdown:
+				p.closeInput()
+				return
+			}
+		}
+
+		// don't pop anything from the queue if there's no room to hold it. Items are left in the queue until processors catch up
+		atomic.AddInt64(&p.fetches, 1)
+		if p.bufferFull() {
+			atomic.AddInt64(&p.stalls, 1)
+			p.logger.Debug(fmt.Sprintf("[pipelined/%s] - processing buffer is full, deferring fetch", p.name))
+			select {
+			case <-timer.C:
+				continue
+			case <-p.shutdown:
+				p.closeInput()
+				return
+			}
+		}
+
 		raw, err := p.worker.Fetch()
 		if len(raw) == 0 {
 			select {
 			case <-timer.C:
 				continue
 			case <-p.shutdown:
-				close(p.inputBuffer)
+				p.closeInput()
 				return
 			}
 		}
@@ -179,15 +286,9 @@ func (p *PipelinedSyncTask) filler() {
 			continue
 		}
 
-		howMany := len(raw)
-		select {
-		case p.inputBuffer <- raw:
-			p.logger.Debug(fmt.Sprintf("[pipelined/%s] Pushed %d items into the processing buffer", p.name, howMany))
-		default:
-			p.logger.Warning(fmt.Sprintf(
-				"[pipelined/%s] - dropping bulk of %d fetched items because processing buffer is full", p.name, len(raw),
-			))
-		}
+		// an on-demand flush may have taken the last free slot. Wait for processors to make room rather than dropping popped items
+		p.inputBuffer <- raw
+		p.logger.Debug(fmt.Sprintf("[pipelined/%s] Pushed %d items into the processing buffer", p.name, len(raw)))
 	}
 }
 
@@ -310,3 +411,6 @@ func (t *taskMemoryPoolImpl) releaseRawBuffer(b rawBuffer) {
 
 var errHTTP = errors.New("http")
 var errTaskRunning = errors.New("task already running")
+var errTaskNotRunning = errors.New("task not running")
+var errFlushNotSupported = errors.New("worker does not support on-demand flushing")
+var errBufferFull = errors.New("processing buffer is full")
@@ -167,3 +167,138 @@ func TestPipelineTask(t *testing.T) {
 
 	poolWrapper.validate(t)
 }
+
+type mockBoundedWorker struct {
+	mockWorker
+	fetchNCall func(n int64) ([]string, error)
+}
+
+func (m *mockBoundedWorker) FetchN(n int64) ([]string, error) {
+	return m.fetchNCall(n)
+}
+
+func TestPipelineTaskPauseAndFlush(t *testing.T) {
+	var fetchCalls int64
+	var fetchNCalls int64
+	var processedItems int64
+	w := &mockBoundedWorker{
+		mockWorker: mockWorker{
+			fetchCall: func() ([]string, error) {
+				atomic.AddInt64(&fetchCalls, 1)
+				return nil, nil
+			},
+			processCall: func(rawData [][]byte, sink chan<- interface{}) error {
+				atomic.AddInt64(&processedItems, int64(len(rawData)))
+				return nil
+			},
+			buildRequestCall: func(data interface{}) (*http.Request, func(), error) {
+				t.Error("no requests should be built")
+				return nil, nil, nil
+			},
+		},
+		fetchNCall: func(n int64) ([]string, error) {
+			atomic.AddInt64(&fetchNCalls, 1)
+			if n != 3 {
+				t.Error("n should be 3. Got: ", n)
+			}
+			return []string{"a", "b", "c"}, nil
+		},
+	}
+
+	task, err := NewPipelinedTask(&Config{Worker: w, Logger: logging.NewLogger(nil), ProcessConcurrency: 1, MaxAccumWait: 100 * time.Millisecond})
+	if err != nil {
+		t.Error("task init: ", err)
+	}
+
+	task.Pause()
+	if !task.IsPaused() {
+		t.Error("task should be paused")
+	}
+
+	task.Start()
+	time.Sleep(1500 * time.Millisecond)
+	if c := atomic.LoadInt64(&fetchCalls); c != 0 {
+		t.Error("no fetches should be made while paused. Got: ", c)
+	}
+
+	flushed, err := task.Flush(3)
+	if err != nil || flushed != 3 {
+		t.Error("3 items should have been flushed. Got: ", flushed, err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if c := atomic.LoadInt64(&processedItems); c != 3 {
+		t.Error("flushed items should be processed even if paused. Got: ", c)
+	}
+	if fetches, stalls := task.SaturationStats(); fetches != 1 || stalls != 0 {
+		t.Error("1 fetch should be made & none deferred. Got: ", fetches, stalls)
+	}
+
+	task.Resume()
+	if task.IsPaused() {
+		t.Error("task should not be paused")
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if c := atomic.LoadInt64(&fetchCalls); c == 0 {
+		t.Error("fetches should be resumed")
+	}
+	task.Stop(true)
+
+	if _, err := task.Flush(3); err != errTaskNotRunning {
+		t.Error("flush should fail when the task is not running. Got: ", err)
+	}
+	if c := atomic.LoadInt64(&fetchNCalls); c != 1 {
+		t.Error("FetchN should have been called once. Got: ", c)
+	}
+}
+
+func TestPipelineTaskFullBuffer(t *testing.T) {
+	var fetchCalls int64
+	var fetchNCalls int64
+	release := make(chan struct{})
+	w := &mockBoundedWorker{
+		mockWorker: mockWorker{
+			fetchCall: func() ([]string, error) {
+				atomic.AddInt64(&fetchCalls, 1)
+				return []string{"a"}, nil
+			},
+			processCall: func(rawData [][]byte, sink chan<- interface{}) error {
+				<-release // block processing so that the input buffer fills up
+				return nil
+			},
+			buildRequestCall: func(data interface{}) (*http.Request, func(), error) { return nil, nil, nil },
+		},
+		fetchNCall: func(n int64) ([]string, error) {
+			atomic.AddInt64(&fetchNCalls, 1)
+			return []string{"a"}, nil
+		},
+	}
+
+	task, err := NewPipelinedTask(&Config{
+		Worker:             w,
+		Logger:             logging.NewLogger(nil),
+		InputBufferSize:    2,
+		ProcessConcurrency: 1,
+		ProcessBatchSize:   1,
+		MaxAccumWait:       10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Error("task init: ", err)
+	}
+
+	task.Start()
+	time.Sleep(4500 * time.Millisecond)
+
+	// one bulk is being processed & 2 are sitting in the buffer. No more items should be popped from the queue
+	if c := atomic.LoadInt64(&fetchCalls); c != 3 {
+		t.Error("no fetches should be made while the buffer is full. Got: ", c)
+	}
+	if _, err := task.Flush(1); err != errBufferFull || atomic.LoadInt64(&fetchNCalls) != 0 {
+		t.Error("nothing should be fetched on demand while the buffer is full. Got: ", err)
+	}
+	if fetches, stalls := task.SaturationStats(); stalls == 0 || fetches <= stalls {
+		t.Error("deferred fetches should be reported. Got: ", fetches, stalls)
+	}
+
+	close(release)
+	task.Stop(true)
+}
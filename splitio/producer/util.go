@@ -14,6 +14,7 @@ import (
 	"github.com/splitio/go-split-commons/v4/service"
 	"github.com/splitio/go-split-commons/v4/storage/redis"
 	"github.com/splitio/go-toolkit/v5/logging"
+	cstorage "github.com/splitio/split-synchronizer/v5/splitio/common/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/conf"
 	"github.com/splitio/split-synchronizer/v5/splitio/util"
 )
@@ -130,3 +131,93 @@ func sanitizeRedis(cfg *conf.Main, miscStorage *redis.MiscStorage, logger loggin
 	}
 	return nil
 }
+
+// redisWipeableStorage defines the subset of misc storage operations used to wipe redis
+type redisWipeableStorage interface {
+	ClearAll() error
+	SetApikeyHash(newApikeyHash string) error
+}
+
+// redisDataWiper removes all the split-related data from redis, restoring the apikey hash afterwards.
+// Updaters guarded by the wiper don't run while data is being wiped
+type redisDataWiper struct {
+	cstorage.SyncGuard
+	miscStorage redisWipeableStorage
+	apikeyHash  string
+}
+
+func newRedisDataWiper(miscStorage redisWipeableStorage, apikey string) *redisDataWiper {
+	return &redisDataWiper{miscStorage: miscStorage, apikeyHash: strconv.Itoa(int(util.HashAPIKey(apikey)))}
+}
+
+// WipeAll waits for ongoing synchronizations & removes all the data in redis under the configured prefix
+func (w *redisDataWiper) WipeAll() error {
+	return w.Exclusive(func() error {
+		if err := w.miscStorage.ClearAll(); err != nil {
+			return fmt.Errorf("error wiping redis: %w", err)
+		}
+
+		if err := w.miscStorage.SetApikeyHash(w.apikeyHash); err != nil {
+			return fmt.Errorf("error restoring apikey hash after wiping redis: %w", err)
+		}
+		return nil
+	})
+}
+
+// KeyMemoryInfo holds a short-lived copy of the redis INFO memory section, used by the memory reader
+const KeyMemoryInfo = "SPLITIO.memoryInfo"
+
+const memoryInfoTTLSecs = 10
+
+// memoryInfoScript copies the INFO memory section into a key, since the storage client can neither issue INFO
+// commands nor return script results. Effects replication is requested where available so that the write is allowed
+// after a non deterministic command
+const memoryInfoScript = `
+pcall(redis.replicate_commands)
+local info = redis.call('INFO', 'memory')
+redis.call('SET', KEYS[1], info, 'EX', ARGV[1])
+`
+
+// memoryInfoClient defines the subset of storage client operations used to read redis memory usage
+type memoryInfoClient interface {
+	Prefix() string
+	Eval(script string, keys []string, args ...interface{}) error
+	Get(key string) (string, error)
+}
+
+// redisMemoryReader reports redis memory usage using the existing storage client.
+// In cluster mode, the node holding the info key is the one measured
+type redisMemoryReader struct {
+	client memoryInfoClient
+}
+
+func newRedisMemoryReader(client memoryInfoClient) *redisMemoryReader {
+	return &redisMemoryReader{client: client}
+}
+
+// UsedMemory returns the memory used by redis in bytes
+func (r *redisMemoryReader) UsedMemory() (int64, error) {
+	key := KeyMemoryInfo
+	if prefix := r.client.Prefix(); prefix != "" { // keys passed to scripts are not prefixed by the client
+		key = prefix + "." + key
+	}
+
+	if err := r.client.Eval(memoryInfoScript, []string{key}, memoryInfoTTLSecs); err != nil {
+		return 0, fmt.Errorf("error fetching redis memory info: %w", err)
+	}
+
+	info, err := r.client.Get(KeyMemoryInfo)
+	if err != nil {
+		return 0, fmt.Errorf("error reading redis memory info: %w", err)
+	}
+	return parseUsedMemory(info)
+}
+
+func parseUsedMemory(info string) (int64, error) {
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, "used_memory:") {
+			return strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "used_memory:")), 10, 64)
+		}
+	}
+	return 0, errors.New("used_memory not present in redis info")
+}
@@ -0,0 +1,105 @@
+package producer
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/segment"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/split"
+	"github.com/splitio/split-synchronizer/v5/splitio/util"
+)
+
+type wipeableStorageMock struct {
+	clears     int32
+	apikeyHash string
+	clearErr   error
+}
+
+func (m *wipeableStorageMock) ClearAll() error {
+	atomic.AddInt32(&m.clears, 1)
+	return m.clearErr
+}
+
+func (m *wipeableStorageMock) SetApikeyHash(newApikeyHash string) error {
+	m.apikeyHash = newApikeyHash
+	return nil
+}
+
+type blockingSplitUpdater struct {
+	split.Updater
+	started chan struct{}
+	release chan struct{}
+}
+
+func (u *blockingSplitUpdater) SynchronizeSplits(till *int64) (*split.UpdateResult, error) {
+	close(u.started)
+	<-u.release
+	return &split.UpdateResult{}, nil
+}
+
+type segmentUpdaterMock struct {
+	segment.Updater
+	calls int32
+}
+
+func (u *segmentUpdaterMock) SynchronizeSegments() (map[string]segment.UpdateResult, error) {
+	atomic.AddInt32(&u.calls, 1)
+	return nil, nil
+}
+
+func TestRedisDataWiper(t *testing.T) {
+	storage := &wipeableStorageMock{}
+	wiper := newRedisDataWiper(storage, "someApikey")
+
+	splitUpdater := &blockingSplitUpdater{started: make(chan struct{}), release: make(chan struct{})}
+	guarded := wiper.GuardSplitUpdater(splitUpdater)
+	go guarded.SynchronizeSplits(nil)
+	<-splitUpdater.started
+
+	wiped := make(chan error, 1)
+	go func() { wiped <- wiper.WipeAll() }()
+
+	select {
+	case <-wiped:
+		t.Error("wiping should wait for the ongoing synchronization")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if atomic.LoadInt32(&storage.clears) != 0 {
+		t.Error("redis should not be cleared while synchronizing")
+	}
+
+	close(splitUpdater.release)
+	select {
+	case err := <-wiped:
+		if err != nil {
+			t.Error("no error expected. Got: ", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wiping should complete once the synchronization finishes")
+	}
+
+	if atomic.LoadInt32(&storage.clears) != 1 {
+		t.Error("redis should have been cleared")
+	}
+	if expected := strconv.Itoa(int(util.HashAPIKey("someApikey"))); storage.apikeyHash != expected {
+		t.Error("the apikey hash should have been restored. Got: ", storage.apikeyHash)
+	}
+
+	segmentUpdater := &segmentUpdaterMock{}
+	if _, err := wiper.GuardSegmentUpdater(segmentUpdater).SynchronizeSegments(); err != nil || segmentUpdater.calls != 1 {
+		t.Error("calls should be forwarded to the wrapped updater. Got: ", segmentUpdater.calls, err)
+	}
+
+	storage.clearErr = errors.New("someError")
+	storage.apikeyHash = ""
+	if err := wiper.WipeAll(); err == nil {
+		t.Error("an error should be returned when clearing redis fails")
+	}
+	if storage.apikeyHash != "" {
+		t.Error("the apikey hash should not be restored when clearing fails")
+	}
+}
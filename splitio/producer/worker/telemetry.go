@@ -6,6 +6,7 @@ import (
 	"github.com/splitio/go-split-commons/v4/dtos"
 	"github.com/splitio/go-split-commons/v4/service"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/storage"
 )
 
@@ -39,14 +40,21 @@ type TelemetryMultiWorkerImpl struct {
 	logger  logging.LoggerInterface
 	storage storage.RedisTelemetryConsumerMulti
 	sync    service.TelemetryRecorder
+	fleet   *fleet.Inventory
 }
 
-// NewTelemetryMultiWorker instantes a new telemetry worker
-func NewTelemetryMultiWorker(logger logging.LoggerInterface, store storage.RedisTelemetryConsumerMulti, sync service.TelemetryRecorder) *TelemetryMultiWorkerImpl {
+// NewTelemetryMultiWorker instantes a new telemetry worker. The fleet inventory is optional
+func NewTelemetryMultiWorker(
+	logger logging.LoggerInterface,
+	store storage.RedisTelemetryConsumerMulti,
+	sync service.TelemetryRecorder,
+	inventory *fleet.Inventory,
+) *TelemetryMultiWorkerImpl {
 	return &TelemetryMultiWorkerImpl{
 		logger:  logger,
 		storage: store,
 		sync:    sync,
+		fleet:   inventory,
 	}
 }
 
@@ -85,6 +93,9 @@ func newConsumerStats() dtos.Stats {
 func (w *TelemetryMultiWorkerImpl) SynchronizeStats() error {
 	errors := make(map[dtos.Metadata]error)
 	for metadata, stats := range w.buildStats() {
+		if w.fleet != nil {
+			w.fleet.Track(metadata, fleet.ActivityTelemetry, 1)
+		}
 		err := w.sync.RecordStats(stats, metadata)
 		if err != nil {
 			errors[metadata] = err
@@ -102,6 +113,10 @@ func (w *TelemetryMultiWorkerImpl) SynchronizeStats() error {
 func (w *TelemetryMultiWorkerImpl) SyncrhonizeConfigs() error {
 	errors := make(map[dtos.Metadata]error)
 	for metadata, config := range w.storage.PopConfigs() {
+		if w.fleet != nil {
+			configCopy := config
+			w.fleet.TrackConfig(metadata, &configCopy)
+		}
 		err := w.sync.RecordConfig(config, metadata)
 		if err != nil {
 			errors[metadata] = err
@@ -8,6 +8,7 @@ import (
 	serviceMocks "github.com/splitio/go-split-commons/v4/service/mocks"
 	"github.com/splitio/go-split-commons/v4/telemetry"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/producer/storage"
 	storageMocks "github.com/splitio/split-synchronizer/v5/splitio/producer/storage/mocks"
 )
@@ -81,7 +82,8 @@ func TestTelemetryMultiWorker(t *testing.T) {
 		},
 	}
 
-	worker := NewTelemetryMultiWorker(logger, &store, &sync)
+	inventory := fleet.NewInventory(fleet.Config{})
+	worker := NewTelemetryMultiWorker(logger, &store, &sync, inventory)
 	err := worker.SynchronizeStats()
 	if err != nil {
 		t.Error("no errors should have been returned.")
@@ -95,4 +97,14 @@ func TestTelemetryMultiWorker(t *testing.T) {
 	if configCalls != 2 || statsCalls != 2 {
 		t.Error("invalid number of calls: ", configCalls, statsCalls)
 	}
+
+	instances := inventory.Summary().Instances
+	if len(instances) != 2 {
+		t.Error("both sdk instances should be in the fleet inventory: ", instances)
+	}
+	for _, instance := range instances {
+		if instance.Activity[fleet.ActivityTelemetry] != 1 || instance.Config == nil || instance.Config.OperationMode != "consumer" && instance.Config.OperationMode != "producer" {
+			t.Error("wrong fleet instance: ", instance)
+		}
+	}
 }
@@ -0,0 +1,171 @@
+package alerts
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/asynctask"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	appCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
+	servicesCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services/counter"
+)
+
+// Transition sources
+const (
+	SourceApplication  = "application"
+	SourceDependencies = "dependencies"
+)
+
+// Severities reported in transitions
+const (
+	SeverityCritical = "critical"
+	SeverityDegraded = "degraded"
+	SeverityLow      = "low"
+)
+
+// Transition is emitted every time a health item changes from healthy to unhealthy or vice versa
+type Transition struct {
+	Source    string `json:"source"`
+	Item      string `json:"item"`
+	Healthy   bool   `json:"healthy"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Sink defines the interface of a destination for health transitions
+type Sink interface {
+	Notify(transition Transition) error
+}
+
+// Watcher periodically polls the application & dependencies monitors and forwards health transitions to the configured sinks
+type Watcher struct {
+	logger          logging.LoggerInterface
+	appMonitor      application.MonitorIterface
+	servicesMonitor services.MonitorIterface
+	sinks           []Sink
+	task            *asynctask.AsyncTask
+	lastState       map[string]bool
+	mutex           sync.Mutex
+}
+
+// NewWatcher constructs a new health transitions watcher. The services monitor is optional
+func NewWatcher(
+	appMonitor application.MonitorIterface,
+	servicesMonitor services.MonitorIterface,
+	sinks []Sink,
+	periodSecs int,
+	logger logging.LoggerInterface,
+) *Watcher {
+	if periodSecs <= 0 {
+		periodSecs = 1
+	}
+
+	w := &Watcher{
+		logger:          logger,
+		appMonitor:      appMonitor,
+		servicesMonitor: servicesMonitor,
+		sinks:           sinks,
+	}
+
+	w.task = asynctask.NewAsyncTask("health-alerts", func(l logging.LoggerInterface) error {
+		w.check()
+		return nil
+	}, periodSecs, nil, nil, logger)
+	return w
+}
+
+// Start polling the monitors
+func (w *Watcher) Start() {
+	w.task.Start()
+}
+
+// Stop polling the monitors
+func (w *Watcher) Stop() {
+	w.task.Stop(false)
+}
+
+func (w *Watcher) check() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	current := w.snapshot()
+	if w.lastState == nil { // the first poll only sets the baseline
+		w.lastState = make(map[string]bool, len(current))
+		for key, item := range current {
+			w.lastState[key] = item.Healthy
+		}
+		return
+	}
+
+	for key, item := range current {
+		previouslyHealthy, seen := w.lastState[key]
+		w.lastState[key] = item.Healthy
+		if !seen || previouslyHealthy == item.Healthy {
+			continue
+		}
+
+		item.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+		w.dispatch(item)
+	}
+}
+
+func (w *Watcher) snapshot() map[string]Transition {
+	current := make(map[string]Transition)
+	for _, item := range w.appMonitor.GetHealthStatus().Items {
+		current[SourceApplication+"/"+item.Name] = Transition{
+			Source:   SourceApplication,
+			Item:     item.Name,
+			Healthy:  item.Healthy,
+			Severity: appSeverity(item.Severity),
+			Message:  item.Message,
+		}
+	}
+
+	if w.servicesMonitor != nil {
+		for _, item := range w.servicesMonitor.GetHealthStatus().Items {
+			name := item.Name
+			if name == "" {
+				name = item.Service
+			}
+			current[SourceDependencies+"/"+name] = Transition{
+				Source:   SourceDependencies,
+				Item:     name,
+				Healthy:  item.Healthy,
+				Severity: servicesSeverity(item.Severity),
+				Message:  item.Message,
+			}
+		}
+	}
+	return current
+}
+
+func (w *Watcher) dispatch(transition Transition) {
+	w.logger.Info(fmt.Sprintf("Health transition: %s/%s healthy=%t (%s)", transition.Source, transition.Item, transition.Healthy, transition.Severity))
+	for _, sink := range w.sinks {
+		if err := sink.Notify(transition); err != nil {
+			w.logger.Error("error notifying health transition: ", err)
+		}
+	}
+}
+
+func appSeverity(severity int) string {
+	if severity == appCounter.Critical {
+		return SeverityCritical
+	}
+	return SeverityDegraded
+}
+
+func servicesSeverity(severity int) string {
+	switch severity {
+	case servicesCounter.Critical:
+		return SeverityCritical
+	case servicesCounter.Degraded:
+		return SeverityDegraded
+	}
+	return SeverityLow
+}
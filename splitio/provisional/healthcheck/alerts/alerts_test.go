@@ -0,0 +1,120 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	appCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
+)
+
+type appMonitorMock struct{ status application.HealthDto }
+
+func (m *appMonitorMock) GetHealthStatus() application.HealthDto { return m.status }
+func (m *appMonitorMock) NotifyEvent(counterType int)            {}
+func (m *appMonitorMock) Reset(counterType int, value int)       {}
+func (m *appMonitorMock) Start()                                 {}
+func (m *appMonitorMock) Stop()                                  {}
+
+type servicesMonitorMock struct{ status services.HealthDto }
+
+func (m *servicesMonitorMock) Start()                              {}
+func (m *servicesMonitorMock) Stop()                               {}
+func (m *servicesMonitorMock) GetHealthStatus() services.HealthDto { return m.status }
+
+type sinkMock struct{ transitions []Transition }
+
+func (s *sinkMock) Notify(transition Transition) error {
+	s.transitions = append(s.transitions, transition)
+	return nil
+}
+
+func TestWatcherTransitions(t *testing.T) {
+	app := &appMonitorMock{status: application.HealthDto{Items: []application.ItemDto{
+		{Name: "Splits", Healthy: true, Severity: appCounter.Critical},
+		{Name: "Queues", Healthy: true, Severity: appCounter.Low},
+	}}}
+	deps := &servicesMonitorMock{status: services.HealthDto{Items: []services.ItemDto{{Name: "API", Healthy: true}}}}
+	sink := &sinkMock{}
+	watcher := NewWatcher(app, deps, []Sink{sink}, 1, logging.NewLogger(nil))
+
+	app.status.Items[1].Healthy = false
+	watcher.check()
+	if len(sink.transitions) != 0 {
+		t.Error("the first check should only set the baseline. Got: ", sink.transitions)
+	}
+
+	watcher.check()
+	if len(sink.transitions) != 0 {
+		t.Error("no transitions should be notified without changes")
+	}
+
+	app.status.Items[1].Healthy = true
+	app.status.Items[0].Healthy = false
+	app.status.Items[0].Message = "no updates"
+	deps.status.Items[0].Healthy = false
+	watcher.check()
+	if len(sink.transitions) != 3 {
+		t.Error("3 transitions should have been notified. Got: ", sink.transitions)
+	}
+
+	byItem := make(map[string]Transition)
+	for _, transition := range sink.transitions {
+		byItem[transition.Source+"/"+transition.Item] = transition
+	}
+
+	if tr := byItem["application/Splits"]; tr.Healthy || tr.Severity != SeverityCritical || tr.Message != "no updates" || tr.Timestamp == 0 {
+		t.Error("invalid splits transition: ", tr)
+	}
+	if tr := byItem["application/Queues"]; !tr.Healthy || tr.Severity != SeverityDegraded {
+		t.Error("invalid queues transition: ", tr)
+	}
+	if tr := byItem["dependencies/API"]; tr.Healthy || tr.Severity != SeverityCritical {
+		t.Error("invalid api transition: ", tr)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	received := make(chan Transition, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var transition Transition
+		if err := json.NewDecoder(r.Body).Decode(&transition); err != nil {
+			t.Error("invalid body: ", err)
+		}
+		received <- transition
+	}))
+	defer ts.Close()
+
+	sinks := BuildSinks(&conf.HealthAlerts{Webhooks: []string{"", ts.URL}, Slack: true}, nil)
+	if len(sinks) != 1 {
+		t.Error("only the webhook sink should be built. Got: ", sinks)
+	}
+
+	transition := Transition{Source: SourceApplication, Item: "Splits", Severity: SeverityCritical}
+	if err := sinks[0].Notify(transition); err != nil {
+		t.Error("notify should not fail. Got: ", err)
+	}
+	if got := <-received; got.Item != "Splits" || got.Severity != SeverityCritical {
+		t.Error("invalid transition received: ", got)
+	}
+
+	notification := ToNotification(transition)
+	if notification.Kind != notifier.KindHealth || notification.Level != "error" || notification.Resolved || notification.Key != "application/Splits" {
+		t.Error("invalid notification: ", notification)
+	}
+	if notification.Message != "application item `Splits` is now unhealthy" || notification.Fields["Severity"] != SeverityCritical {
+		t.Error("invalid notification message: ", notification)
+	}
+
+	transition.Healthy = true
+	if notification := ToNotification(transition); notification.Level != "info" || !notification.Resolved {
+		t.Error("recoveries should be reported as resolved. Got: ", notification)
+	}
+}
@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
+	"github.com/splitio/split-synchronizer/v5/splitio/log"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookSink posts transitions as json to an http endpoint
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink constructs a new webhook sink
+func NewWebhookSink(url string, httpClient *http.Client) *WebhookSink {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	return &WebhookSink{url: url, httpClient: httpClient}
+}
+
+// Notify posts the transition to the webhook
+func (s *WebhookSink) Notify(transition Transition) error {
+	serialized, err := json.Marshal(transition)
+	if err != nil {
+		return fmt.Errorf("error serializing health transition: %w", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, s.url, bytes.NewBuffer(serialized))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting health transition to %s: %w", s.url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status code %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// NotifierSink forwards transitions to the notifier, which routes them according to its rules
+type NotifierSink struct {
+	notifier *notifier.Notifier
+}
+
+// NewNotifierSink constructs a new notifier sink
+func NewNotifierSink(n *notifier.Notifier) *NotifierSink {
+	return &NotifierSink{notifier: n}
+}
+
+// Notify queues the transition in the notifier
+func (s *NotifierSink) Notify(transition Transition) error {
+	s.notifier.Notify(ToNotification(transition))
+	return nil
+}
+
+// ToNotification converts a health transition into a notification. Unhealthy critical items are reported as errors,
+// the rest of unhealthy items as warnings & recoveries as info
+func ToNotification(transition Transition) notifier.Notification {
+	status, level := "healthy", "info"
+	if !transition.Healthy {
+		status, level = "unhealthy", "warning"
+		if transition.Severity == SeverityCritical {
+			level = "error"
+		}
+	}
+
+	fields := map[string]string{"Severity": transition.Severity}
+	if transition.Message != "" {
+		fields["Message"] = transition.Message
+	}
+
+	return notifier.Notification{
+		Kind:      notifier.KindHealth,
+		Level:     level,
+		Component: log.ComponentHealthcheck,
+		Message:   fmt.Sprintf("%s item `%s` is now %s", transition.Source, transition.Item, status),
+		Key:       transition.Source + "/" + transition.Item,
+		Resolved:  transition.Healthy,
+		Fields:    fields,
+		Timestamp: transition.Timestamp,
+	}
+}
+
+var _ Sink = (*WebhookSink)(nil)
+var _ Sink = (*NotifierSink)(nil)
+
+// BuildSinks constructs the sinks enabled in the config. Transitions are always forwarded to the notifier (if any),
+// and routed to slack if enabled in the config
+func BuildSinks(cfg *conf.HealthAlerts, n *notifier.Notifier) []Sink {
+	var sinks []Sink
+	for _, url := range cfg.Webhooks {
+		if url != "" {
+			sinks = append(sinks, NewWebhookSink(url, nil))
+		}
+	}
+
+	if n != nil {
+		if cfg.Slack {
+			n.AddRule(notifier.Rule{Sink: notifier.SinkSlack, Kinds: []string{notifier.KindHealth}, MinLevel: logging.LevelAll})
+		}
+		sinks = append(sinks, NewNotifierSink(n))
+	}
+	return sinks
+}
@@ -31,6 +31,7 @@ type HealthyResult struct {
 	Healthy    bool
 	LastHit    *time.Time
 	ErrorCount int
+	Message    string
 }
 
 type applicationCounterImp struct {
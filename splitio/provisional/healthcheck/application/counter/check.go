@@ -0,0 +1,130 @@
+package counter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/splitio/go-toolkit/v5/asynctask"
+	"github.com/splitio/go-toolkit/v5/logging"
+	toolkitsync "github.com/splitio/go-toolkit/v5/sync"
+)
+
+// CustomCounterInterface is the minimum set of methods a counter must implement to be registered in the application monitor.
+// Counters that also implement Start() & Stop() will be started & stopped along with the monitor
+type CustomCounterInterface interface {
+	IsHealthy() HealthyResult
+}
+
+// CheckConfig config struct
+type CheckConfig struct {
+	Name     string
+	Severity int
+	Period   int
+	Check    func() error
+}
+
+// CheckImp counter whose health is computed by periodically running a check function.
+// The counter is unhealthy while the function returns an error
+type CheckImp struct {
+	applicationCounterImp
+	check       func() error
+	lastMessage string
+	task        *asynctask.AsyncTask
+}
+
+func (c *CheckImp) run() {
+	err := c.check()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.updateLastHit()
+	c.healthy = err == nil
+	c.lastMessage = ""
+	if err != nil {
+		c.lastMessage = err.Error()
+		c.logger.Debug(c.name, " check failed: ", err)
+	}
+}
+
+// IsHealthy return the counter health
+func (c *CheckImp) IsHealthy() HealthyResult {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return HealthyResult{
+		Name:     c.name,
+		Healthy:  c.healthy,
+		Severity: c.severity,
+		LastHit:  c.lastHit,
+		Message:  c.lastMessage,
+	}
+}
+
+// Start running the check periodically
+func (c *CheckImp) Start() {
+	if c.running.TestAndSet() {
+		c.task.Start()
+	}
+}
+
+// Stop running the check
+func (c *CheckImp) Stop() {
+	if c.running.TestAndClear() {
+		c.task.Stop(false)
+	}
+}
+
+// NewCheckCounter create a new check counter
+func NewCheckCounter(config CheckConfig, logger logging.LoggerInterface) *CheckImp {
+	counter := &CheckImp{
+		applicationCounterImp: applicationCounterImp{
+			name:     config.Name,
+			lock:     sync.RWMutex{},
+			logger:   logger,
+			healthy:  true,
+			running:  *toolkitsync.NewAtomicBool(false),
+			period:   config.Period,
+			severity: config.Severity,
+		},
+		check: config.Check,
+	}
+
+	// the check is also executed on startup, so that the counter doesn't report a stale status for a whole period
+	runCheck := func(l logging.LoggerInterface) error {
+		counter.run()
+		return nil
+	}
+	counter.task = asynctask.NewAsyncTask(config.Name, runCheck, counter.period, runCheck, nil, logger)
+
+	return counter
+}
+
+// NewRateCheck builds a check function that fails when the percentage of failed items since the previous
+// execution is greater than maxPercentage. The reader must return monotonically increasing totals
+func NewRateCheck(reader func() (total int64, failed int64), maxPercentage int64) func() error {
+	var lastTotal, lastFailed int64
+	return func() error {
+		total, failed := reader()
+		deltaTotal, deltaFailed := total-lastTotal, failed-lastFailed
+		lastTotal, lastFailed = total, failed
+		if deltaTotal <= 0 || deltaFailed*100 <= deltaTotal*maxPercentage {
+			return nil
+		}
+		return fmt.Errorf("%d out of %d items failed since the last check", deltaFailed, deltaTotal)
+	}
+}
+
+// NewThresholdCheck builds a check function that fails when the value returned by the reader exceeds the threshold
+func NewThresholdCheck(reader func() (int64, error), threshold int64, unit string) func() error {
+	return func() error {
+		value, err := reader()
+		if err != nil {
+			return err
+		}
+		if value > threshold {
+			return fmt.Errorf("current value %d%s exceeds the threshold of %d%s", value, unit, threshold, unit)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package counter
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// waitFor polls a condition until it holds or the deadline is reached
+func waitFor(deadline time.Duration, condition func() bool) bool {
+	for until := time.Now().Add(deadline); time.Now().Before(until); time.Sleep(10 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return condition()
+}
+
+func TestCheckCounter(t *testing.T) {
+	var checkErr error
+	var mutex sync.Mutex
+	setErr := func(err error) {
+		mutex.Lock()
+		checkErr = err
+		mutex.Unlock()
+	}
+
+	counter := NewCheckCounter(CheckConfig{
+		Name:     "Custom",
+		Severity: Low,
+		Period:   1,
+		Check: func() error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return checkErr
+		},
+	}, logging.NewLogger(nil))
+
+	res := counter.IsHealthy()
+	if !res.Healthy || res.Name != "Custom" || res.Severity != Low || res.LastHit != nil {
+		t.Error("counter should start healthy. Got: ", res)
+	}
+
+	setErr(errors.New("something went wrong"))
+	counter.Start()
+	unhealthy := waitFor(time.Second, func() bool {
+		res = counter.IsHealthy()
+		return !res.Healthy && res.Message == "something went wrong" && res.LastHit != nil
+	})
+	if !unhealthy {
+		t.Error("check should run on startup & make the counter unhealthy. Got: ", res)
+	}
+
+	setErr(nil)
+	if !waitFor(3*time.Second, func() bool { res = counter.IsHealthy(); return res.Healthy && res.Message == "" }) {
+		t.Error("counter should be healthy after a successful check. Got: ", res)
+	}
+	counter.Stop()
+}
+
+func TestRateAndThresholdChecks(t *testing.T) {
+	var total, failed int64
+	check := NewRateCheck(func() (int64, int64) { return total, failed }, 10)
+	if check() != nil {
+		t.Error("no items should not fail")
+	}
+
+	total, failed = 100, 10
+	if check() != nil {
+		t.Error("10% should not fail")
+	}
+
+	total, failed = 200, 21
+	if check() == nil {
+		t.Error("11% since the last check should fail")
+	}
+
+	total, failed = 300, 21
+	if check() != nil {
+		t.Error("only the delta since the last check should be considered")
+	}
+
+	var value int64
+	thresholdCheck := NewThresholdCheck(func() (int64, error) { return value, nil }, 100, "ms")
+	if thresholdCheck() != nil {
+		t.Error("value below the threshold should not fail")
+	}
+
+	value = 101
+	if err := thresholdCheck(); err == nil || err.Error() != "current value 101ms exceeds the threshold of 100ms" {
+		t.Error("value above the threshold should fail. Got: ", err)
+	}
+}
@@ -0,0 +1,66 @@
+package counter
+
+import (
+	"sync"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+	toolkitsync "github.com/splitio/go-toolkit/v5/sync"
+)
+
+// StatusCounterInterface application counter interface for components that report their health explicitly
+type StatusCounterInterface interface {
+	IsHealthy() HealthyResult
+	SetHealthy(healthy bool)
+}
+
+// StatusConfig config struct
+type StatusConfig struct {
+	Name     string
+	Severity int
+}
+
+// StatusImp counter whose health is set explicitly by the component being monitored
+type StatusImp struct {
+	applicationCounterImp
+}
+
+// SetHealthy updates the counter health. LastHit is updated only when the status changes
+func (c *StatusImp) SetHealthy(healthy bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.healthy == healthy {
+		return
+	}
+
+	c.healthy = healthy
+	c.updateLastHit()
+	c.logger.Debug("Status counter updated. Healthy: ", healthy)
+}
+
+// IsHealthy return the counter health
+func (c *StatusImp) IsHealthy() HealthyResult {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return HealthyResult{
+		Name:     c.name,
+		Healthy:  c.healthy,
+		Severity: c.severity,
+		LastHit:  c.lastHit,
+	}
+}
+
+// NewStatusCounter create a new status counter
+func NewStatusCounter(config StatusConfig, logger logging.LoggerInterface) *StatusImp {
+	return &StatusImp{
+		applicationCounterImp: applicationCounterImp{
+			name:     config.Name,
+			lock:     sync.RWMutex{},
+			logger:   logger,
+			healthy:  true,
+			running:  *toolkitsync.NewAtomicBool(true),
+			severity: config.Severity,
+		},
+	}
+}
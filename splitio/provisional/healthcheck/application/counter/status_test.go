@@ -0,0 +1,33 @@
+package counter
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestStatusCounter(t *testing.T) {
+	counter := NewStatusCounter(StatusConfig{Name: "Test", Severity: Low}, logging.NewLogger(nil))
+
+	res := counter.IsHealthy()
+	if !res.Healthy || res.LastHit != nil || res.Name != "Test" || res.Severity != Low {
+		t.Error("counter should start healthy. Got: ", res)
+	}
+
+	counter.SetHealthy(false)
+	res = counter.IsHealthy()
+	if res.Healthy || res.LastHit == nil {
+		t.Error("counter should be unhealthy. Got: ", res)
+	}
+
+	lastHit := *res.LastHit
+	counter.SetHealthy(false)
+	if res = counter.IsHealthy(); !res.LastHit.Equal(lastHit) {
+		t.Error("last hit should only be updated when the status changes")
+	}
+
+	counter.SetHealthy(true)
+	if res = counter.IsHealthy(); !res.Healthy {
+		t.Error("counter should be healthy")
+	}
+}
@@ -19,11 +19,18 @@ type MonitorIterface interface {
 	Stop()
 }
 
+type lifecycle interface {
+	Start()
+	Stop()
+}
+
 // MonitorImp description
 type MonitorImp struct {
 	splitsCounter   counter.ThresholdCounterInterface
 	segmentsCounter counter.ThresholdCounterInterface
 	storageCounter  counter.PeriodicCounterInterface
+	customCounters  []counter.CustomCounterInterface
+	started         bool
 	producerMode    toolkitsync.AtomicBool
 	healthySince    *time.Time
 	lock            sync.RWMutex
@@ -43,6 +50,7 @@ type ItemDto struct {
 	Healthy    bool       `json:"healthy"`
 	LastHit    *time.Time `json:"lastHit,omitempty"`
 	ErrorCount int        `json:"errorCount,omitempty"`
+	Message    string     `json:"message,omitempty"`
 	Severity   int        `json:"-"`
 }
 
@@ -77,12 +85,17 @@ func (m *MonitorImp) GetHealthStatus() HealthDto {
 		results = append(results, m.storageCounter.IsHealthy())
 	}
 
+	for _, customCounter := range m.customCounters {
+		results = append(results, customCounter.IsHealthy())
+	}
+
 	for _, res := range results {
 		items = append(items, ItemDto{
 			Name:       res.Name,
 			Healthy:    res.Healthy,
 			LastHit:    res.LastHit,
 			ErrorCount: res.ErrorCount,
+			Message:    res.Message,
 			Severity:   res.Severity,
 		})
 	}
@@ -127,6 +140,17 @@ func (m *MonitorImp) Reset(counterType int, value int) {
 	}
 }
 
+// RegisterCounter adds a custom counter to the monitor. Counters registered after the monitor has started are started immediately
+func (m *MonitorImp) RegisterCounter(customCounter counter.CustomCounterInterface) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.customCounters = append(m.customCounters, customCounter)
+	if withLifecycle, ok := customCounter.(lifecycle); ok && m.started {
+		withLifecycle.Start()
+	}
+}
+
 // Start counters
 func (m *MonitorImp) Start() {
 	m.lock.Lock()
@@ -138,6 +162,13 @@ func (m *MonitorImp) Start() {
 		m.storageCounter.Start()
 	}
 
+	for _, customCounter := range m.customCounters {
+		if withLifecycle, ok := customCounter.(lifecycle); ok {
+			withLifecycle.Start()
+		}
+	}
+
+	m.started = true
 	m.logger.Debug("Application Monitor started.")
 }
 
@@ -152,6 +183,13 @@ func (m *MonitorImp) Stop() {
 	if m.producerMode.IsSet() {
 		m.storageCounter.Stop()
 	}
+
+	for _, customCounter := range m.customCounters {
+		if withLifecycle, ok := customCounter.(lifecycle); ok {
+			withLifecycle.Stop()
+		}
+	}
+	m.started = false
 }
 
 // NewMonitorImp create a new application monitor
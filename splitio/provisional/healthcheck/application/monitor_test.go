@@ -1,6 +1,7 @@
 package application
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -80,3 +81,54 @@ func TestMonitor(t *testing.T) {
 	assertItemsHealthy(t, res.Items, false, true, false)
 	monitor.Stop()
 }
+
+func TestMonitorWithStatusCounter(t *testing.T) {
+	monitor := NewMonitorImp(counter.DefaultThresholdConfig("Splits"), counter.DefaultThresholdConfig("Segments"), nil, logging.NewLogger(nil))
+	queues := counter.NewStatusCounter(counter.StatusConfig{Name: "Queues", Severity: counter.Low}, logging.NewLogger(nil))
+	monitor.RegisterCounter(queues)
+
+	queues.SetHealthy(false)
+	res := monitor.GetHealthStatus()
+	if !res.Healthy {
+		t.Error("low severity counters should not make the application unhealthy")
+	}
+
+	found := false
+	for _, item := range res.Items {
+		if item.Name == "Queues" {
+			found = true
+			if item.Healthy {
+				t.Error("queues item should be unhealthy")
+			}
+		}
+	}
+	if !found {
+		t.Error("queues item should be present")
+	}
+}
+
+func TestMonitorCustomCounterLifecycle(t *testing.T) {
+	monitor := NewMonitorImp(counter.DefaultThresholdConfig("Splits"), counter.DefaultThresholdConfig("Segments"), nil, logging.NewLogger(nil))
+	monitor.Start()
+	defer monitor.Stop()
+
+	custom := counter.NewCheckCounter(counter.CheckConfig{
+		Name:     "Custom",
+		Severity: counter.Low,
+		Period:   10,
+		Check:    func() error { return errors.New("some error") },
+	}, logging.NewLogger(nil))
+	monitor.RegisterCounter(custom)
+	time.Sleep(100 * time.Millisecond)
+
+	res := monitor.GetHealthStatus()
+	if !res.Healthy {
+		t.Error("low severity counters should not make the application unhealthy")
+	}
+
+	for _, item := range res.Items {
+		if item.Name == "Custom" && (item.Healthy || item.Message != "some error") {
+			t.Error("counters registered after start should be started immediately. Got: ", item)
+		}
+	}
+}
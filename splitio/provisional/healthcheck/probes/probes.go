@@ -0,0 +1,251 @@
+package probes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage"
+	"github.com/splitio/go-split-commons/v4/synchronizer"
+	toolkitsync "github.com/splitio/go-toolkit/v5/sync"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
+)
+
+const defaultLiveTimeout = 5 * time.Second
+
+var errMonitorTimeout = errors.New("timed out waiting for the application monitor")
+
+// Config bundles the rules used to evaluate each probe. Items are matched by name against
+// both application & dependencies health items. Items not present are ignored
+type Config struct {
+	LiveItems          []string
+	ReadyItems         []string
+	StartupItems       []string
+	ReadyRequireSplits bool
+	ReadyRequireSync   bool
+	ReadyMaxQueueUsage int64 // percentage of a registered queue's capacity above which the app is not ready. 0 means no limit
+	LiveTimeout        time.Duration
+}
+
+// QueueUsageFn returns the current number of items & the capacity of an in-memory queue
+type QueueUsageFn func() (size int, capacity int)
+
+type statusRequest struct {
+	done   chan struct{}
+	status application.HealthDto
+}
+
+// CheckResult is the outcome of a single check within a probe
+type CheckResult struct {
+	Name    string `json:"name"`
+	Ok      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// Result is the outcome of a probe
+type Result struct {
+	Ok     bool          `json:"ok"`
+	Checks []CheckResult `json:"checks"`
+}
+
+func (r *Result) add(name string, ok bool, message string) {
+	r.Checks = append(r.Checks, CheckResult{Name: name, Ok: ok, Message: message})
+	r.Ok = r.Ok && ok
+}
+
+// Evaluator computes the liveness, readiness & startup status of the application
+type Evaluator struct {
+	config          Config
+	appMonitor      application.MonitorIterface
+	servicesMonitor services.MonitorIterface
+	syncManager     synchronizer.Manager
+	splitStorage    storage.SplitStorageConsumer
+	started         *toolkitsync.AtomicBool
+	queues          map[string]QueueUsageFn
+	mutex           sync.Mutex
+	pendingStatus   *statusRequest
+}
+
+// NewEvaluator constructs a new probe evaluator. The sync manager & split storage are optional
+func NewEvaluator(
+	config Config,
+	appMonitor application.MonitorIterface,
+	servicesMonitor services.MonitorIterface,
+	syncManager synchronizer.Manager,
+	splitStorage storage.SplitStorageConsumer,
+) *Evaluator {
+	if config.LiveTimeout <= 0 {
+		config.LiveTimeout = defaultLiveTimeout
+	}
+
+	return &Evaluator{
+		config:          config,
+		appMonitor:      appMonitor,
+		servicesMonitor: servicesMonitor,
+		syncManager:     syncManager,
+		splitStorage:    splitStorage,
+		started:         toolkitsync.NewAtomicBool(false),
+		queues:          make(map[string]QueueUsageFn),
+	}
+}
+
+// RegisterQueue adds an in-memory queue whose saturation is taken into account by the readiness probe
+func (e *Evaluator) RegisterQueue(name string, usage QueueUsageFn) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.queues[name] = usage
+}
+
+// MarkStarted should be called once the initial synchronization has finished (or data was loaded from a snapshot)
+func (e *Evaluator) MarkStarted() {
+	e.started.Set()
+}
+
+// Live checks that the application is responsive. The application monitor is queried with a timeout
+// in order to detect a wedged process
+func (e *Evaluator) Live() Result {
+	result := Result{Ok: true}
+	appStatus, err := e.appStatusWithTimeout()
+	if err != nil {
+		result.add("responsive", false, err.Error())
+		return result
+	}
+
+	result.add("responsive", true, "")
+	e.checkItems(&result, e.config.LiveItems, &appStatus)
+	return result
+}
+
+// Startup checks that the initial synchronization has finished
+func (e *Evaluator) Startup() Result {
+	result := Result{Ok: true}
+	result.add("started", e.started.IsSet(), "")
+	e.checkItems(&result, e.config.StartupItems, nil)
+	return result
+}
+
+// Ready checks that the application can serve traffic
+func (e *Evaluator) Ready() Result {
+	result := Result{Ok: true}
+	result.add("started", e.started.IsSet(), "")
+
+	if e.config.ReadyRequireSync && e.syncManager != nil {
+		result.add("sync", e.syncManager.IsRunning(), "")
+	}
+
+	if e.config.ReadyRequireSplits && e.splitStorage != nil {
+		cn, err := e.splitStorage.ChangeNumber()
+		switch {
+		case err != nil:
+			result.add("splits", false, fmt.Sprintf("error reading split storage: %s", err))
+		case cn == -1:
+			result.add("splits", false, "no splits loaded")
+		default:
+			result.add("splits", true, "")
+		}
+	}
+
+	e.checkQueues(&result)
+	e.checkItems(&result, e.config.ReadyItems, nil)
+	return result
+}
+
+func (e *Evaluator) checkQueues(result *Result) {
+	if e.config.ReadyMaxQueueUsage <= 0 {
+		return
+	}
+
+	e.mutex.Lock()
+	names := make([]string, 0, len(e.queues))
+	for name := range e.queues {
+		names = append(names, name)
+	}
+	queues := e.queues
+	e.mutex.Unlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		size, capacity := queues[name]()
+		if capacity <= 0 {
+			continue
+		}
+		if int64(size)*100 >= int64(capacity)*e.config.ReadyMaxQueueUsage {
+			result.add("queue:"+name, false, fmt.Sprintf("%d/%d items queued", size, capacity))
+			continue
+		}
+		result.add("queue:"+name, true, "")
+	}
+}
+
+// appStatusWithTimeout queries the application monitor, giving up after the configured timeout. Only one query is in
+// flight at any time, so that a wedged monitor doesn't pile up a goroutine per probe
+func (e *Evaluator) appStatusWithTimeout() (application.HealthDto, error) {
+	e.mutex.Lock()
+	request := e.pendingStatus
+	if request == nil {
+		request = &statusRequest{done: make(chan struct{})}
+		e.pendingStatus = request
+		go func() {
+			request.status = e.appMonitor.GetHealthStatus()
+			close(request.done)
+			e.mutex.Lock()
+			e.pendingStatus = nil
+			e.mutex.Unlock()
+		}()
+	}
+	e.mutex.Unlock()
+
+	timer := time.NewTimer(e.config.LiveTimeout)
+	defer timer.Stop()
+	select {
+	case <-request.done:
+		return request.status, nil
+	case <-timer.C:
+		return application.HealthDto{}, errMonitorTimeout
+	}
+}
+
+func (e *Evaluator) checkItems(result *Result, names []string, appStatus *application.HealthDto) {
+	if len(names) == 0 {
+		return
+	}
+
+	health := make(map[string]bool)
+	if appStatus == nil {
+		status := e.appMonitor.GetHealthStatus()
+		appStatus = &status
+	}
+	for _, item := range appStatus.Items {
+		health[item.Name] = item.Healthy
+	}
+
+	if e.servicesMonitor != nil {
+		for _, item := range e.servicesMonitor.GetHealthStatus().Items {
+			health[item.Name] = item.Healthy
+		}
+	}
+
+	for _, name := range names {
+		if healthy, ok := health[name]; ok {
+			result.add(name, healthy, "")
+		}
+	}
+}
+
+// ConfigFromOptions builds a probes config from the user-facing options
+func ConfigFromOptions(opts *conf.Probes) Config {
+	return Config{
+		LiveItems:          opts.LiveItems,
+		ReadyItems:         opts.ReadyItems,
+		StartupItems:       opts.StartupItems,
+		ReadyRequireSplits: opts.ReadyRequireSplits,
+		ReadyRequireSync:   opts.ReadyRequireSync,
+		ReadyMaxQueueUsage: opts.ReadyMaxQueueUsage,
+		LiveTimeout:        time.Duration(opts.LiveTimeoutMs) * time.Millisecond,
+	}
+}
@@ -0,0 +1,142 @@
+package probes
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
+)
+
+type appMonitorMock struct {
+	status application.HealthDto
+	delay  int64 // nanoseconds, accessed atomically
+	calls  int64
+}
+
+func (m *appMonitorMock) GetHealthStatus() application.HealthDto {
+	atomic.AddInt64(&m.calls, 1)
+	time.Sleep(time.Duration(atomic.LoadInt64(&m.delay)))
+	return m.status
+}
+func (m *appMonitorMock) NotifyEvent(counterType int)      {}
+func (m *appMonitorMock) Reset(counterType int, value int) {}
+func (m *appMonitorMock) Start()                           {}
+func (m *appMonitorMock) Stop()                            {}
+
+type servicesMonitorMock struct{ status services.HealthDto }
+
+func (m *servicesMonitorMock) Start()                              {}
+func (m *servicesMonitorMock) Stop()                               {}
+func (m *servicesMonitorMock) GetHealthStatus() services.HealthDto { return m.status }
+
+type managerMock struct{ running bool }
+
+func (m *managerMock) Start()          {}
+func (m *managerMock) Stop()           {}
+func (m *managerMock) IsRunning() bool { return m.running }
+
+func TestStartupAndReady(t *testing.T) {
+	app := &appMonitorMock{status: application.HealthDto{Items: []application.ItemDto{
+		{Name: "Splits", Healthy: true},
+		{Name: "Queues", Healthy: false},
+	}}}
+	deps := &servicesMonitorMock{status: services.HealthDto{Items: []services.ItemDto{{Name: "Storage", Healthy: true}}}}
+	manager := &managerMock{}
+	cn := int64(-1)
+	splits := &mocks.MockSplitStorage{ChangeNumberCall: func() (int64, error) { return cn, nil }}
+
+	evaluator := NewEvaluator(Config{
+		ReadyItems:         []string{"Storage", "Unknown"},
+		ReadyRequireSplits: true,
+		ReadyRequireSync:   true,
+	}, app, deps, manager, splits)
+
+	if evaluator.Startup().Ok || evaluator.Ready().Ok {
+		t.Error("probes should fail before the app is started")
+	}
+
+	evaluator.MarkStarted()
+	if !evaluator.Startup().Ok {
+		t.Error("startup probe should succeed")
+	}
+
+	if res := evaluator.Ready(); res.Ok {
+		t.Error("ready probe should fail with no splits & sync stopped. Got: ", res)
+	}
+
+	cn = 123
+	manager.running = true
+	res := evaluator.Ready()
+	if !res.Ok {
+		t.Error("ready probe should succeed. Got: ", res)
+	}
+	if len(res.Checks) != 4 {
+		t.Error("unknown items should be ignored. Got: ", res.Checks)
+	}
+
+	deps.status.Items[0].Healthy = false
+	if evaluator.Ready().Ok {
+		t.Error("ready probe should fail with an unhealthy item")
+	}
+}
+
+func TestLive(t *testing.T) {
+	app := &appMonitorMock{status: application.HealthDto{Items: []application.ItemDto{{Name: "Queues", Healthy: false}}}}
+	evaluator := NewEvaluator(Config{LiveTimeout: 50 * time.Millisecond}, app, nil, nil, nil)
+	if !evaluator.Live().Ok {
+		t.Error("live probe should succeed")
+	}
+
+	evaluator = NewEvaluator(Config{LiveItems: []string{"Queues"}}, app, nil, nil, nil)
+	if evaluator.Live().Ok {
+		t.Error("live probe should fail with an unhealthy item")
+	}
+
+	atomic.StoreInt64(&app.delay, int64(100*time.Millisecond))
+	evaluator = NewEvaluator(Config{LiveTimeout: 10 * time.Millisecond}, app, nil, nil, nil)
+	if res := evaluator.Live(); res.Ok || res.Checks[0].Message == "" {
+		t.Error("live probe should fail when the monitor doesn't respond in time. Got: ", res)
+	}
+
+	// a wedged monitor is only queried once, regardless of how many probes time out
+	before := atomic.LoadInt64(&app.calls)
+	evaluator.Live()
+	evaluator.Live()
+	if calls := atomic.LoadInt64(&app.calls); calls != before {
+		t.Error("no new queries should be made while one is in flight. Got: ", calls-before)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	atomic.StoreInt64(&app.delay, 0)
+	if !evaluator.Live().Ok {
+		t.Error("live probe should succeed once the monitor responds")
+	}
+}
+
+func TestReadyQueues(t *testing.T) {
+	size := 0
+	evaluator := NewEvaluator(Config{ReadyMaxQueueUsage: 90}, &appMonitorMock{}, nil, nil, nil)
+	evaluator.RegisterQueue("impressions", func() (int, int) { return size, 100 })
+	evaluator.RegisterQueue("events", func() (int, int) { return 0, 0 })
+	evaluator.MarkStarted()
+
+	if res := evaluator.Ready(); !res.Ok || len(res.Checks) != 2 {
+		t.Error("ready probe should succeed with queues below the threshold. Got: ", res)
+	}
+
+	size = 90
+	if res := evaluator.Ready(); res.Ok || res.Checks[1].Name != "queue:impressions" || res.Checks[1].Message != "90/100 items queued" {
+		t.Error("ready probe should fail with a saturated queue. Got: ", res)
+	}
+
+	evaluator = NewEvaluator(Config{}, &appMonitorMock{}, nil, nil, nil)
+	evaluator.RegisterQueue("impressions", func() (int, int) { return 100, 100 })
+	evaluator.MarkStarted()
+	if !evaluator.Ready().Ok {
+		t.Error("queues should be ignored when no limit is set")
+	}
+}
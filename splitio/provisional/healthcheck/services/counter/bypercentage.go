@@ -50,6 +50,7 @@ type ServicesCounterInterface interface {
 
 // HealthyResult result
 type HealthyResult struct {
+	Name         string
 	URL          string
 	Severity     int
 	Healthy      bool
@@ -130,6 +131,7 @@ func (c *ByPercentageImp) IsHealthy() HealthyResult {
 	defer c.lock.RUnlock()
 
 	return HealthyResult{
+		Name:         c.name,
 		URL:          c.url,
 		Severity:     c.severity,
 		Healthy:      c.healthy,
@@ -22,11 +22,13 @@ type HealthDto struct {
 
 // ItemDto description
 type ItemDto struct {
+	Name         string     `json:"name,omitempty"`
 	Service      string     `json:"service"`
 	Healthy      bool       `json:"healthy"`
 	Message      string     `json:"message,omitempty"`
 	HealthySince *time.Time `json:"healthySince,omitempty"`
 	LastHit      *time.Time `json:"lastHit,omitempty"`
+	Severity     int        `json:"-"`
 }
 
 // MonitorIterface monitor interface
@@ -86,11 +88,13 @@ func (m *MonitorImp) GetHealthStatus() HealthDto {
 		}
 
 		items = append(items, ItemDto{
+			Name:         res.Name,
 			Service:      res.URL,
 			Healthy:      res.Healthy,
 			Message:      res.LastMessage,
 			HealthySince: res.HealthySince,
 			LastHit:      res.LastHit,
+			Severity:     res.Severity,
 		})
 	}
 
@@ -8,6 +8,8 @@ import (
 	"github.com/splitio/go-split-commons/v4/storage"
 	"github.com/splitio/go-toolkit/v5/datastructures/set"
 	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
 )
 
 // ErrIncompatibleSegmentStorage is returned when the supplied storage that not have the required methods
@@ -24,6 +26,7 @@ type ObservableSegmentStorageImpl struct {
 	extendedSegmentStorage
 	counter *ActiveSegmentTracker
 	logger  logging.LoggerInterface
+	changes *changelog.Recorder
 }
 
 // NewObservableSegmentStorage constructs and observable segment storage
@@ -71,9 +74,17 @@ func (s *ObservableSegmentStorageImpl) Update(name string, toAdd *set.ThreadUnsa
 		s.logger.Error(fmt.Sprintf("something went wrong when updating segment '%s': %s", name, err.Error()))
 	}
 	s.counter.Update(name, added, removed)
+	if s.changes != nil {
+		s.changes.RecordSegmentChange(name, added, removed, changeNumber)
+	}
 	return nil
 }
 
+// SetChangeRecorder sets the recorder used to keep track of segment changes
+func (s *ObservableSegmentStorageImpl) SetChangeRecorder(recorder *changelog.Recorder) {
+	s.changes = recorder
+}
+
 // NamesAndCount returns a map of segment names with the number of keys
 func (s *ObservableSegmentStorageImpl) NamesAndCount() map[string]int {
 	return s.counter.NamesAndCount()
@@ -105,6 +116,13 @@ func (t *ActiveSegmentTracker) Update(name string, added int, removed int) {
 	t.activeSegmentMap[name] = current
 }
 
+// Reset removes all the tracked segments
+func (t *ActiveSegmentTracker) Reset() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.activeSegmentMap = make(map[string]int, len(t.activeSegmentMap)+1)
+}
+
 // NamesAndCount returns a map of segment names to key count
 func (t *ActiveSegmentTracker) NamesAndCount() map[string]int {
 	t.mtx.RLock()
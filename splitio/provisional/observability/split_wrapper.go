@@ -8,6 +8,8 @@ import (
 	"github.com/splitio/go-split-commons/v4/storage"
 	"github.com/splitio/go-split-commons/v4/storage/redis"
 	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
 )
 
 // ErrIncompatibleSplitStorage is returned when the supplied storage that not have the required methods
@@ -23,7 +25,8 @@ type ObservableSplitStorage interface {
 // caches and caches splitnames in-memory (in case the underlying one is non-local, ie: redis)
 type ObservableSplitStorageImpl struct {
 	extendedSplitStorage
-	active *activeSplitTracker
+	active  *activeSplitTracker
+	changes *changelog.Recorder
 }
 
 // NewObservableSplitStorage constructs a NewObservableSplitStorage
@@ -46,6 +49,11 @@ func NewObservableSplitStorage(toWrap storage.SplitStorage, logger logging.Logge
 
 // Update is an override that wraps the original Update method and calls update on the local cache as well
 func (s *ObservableSplitStorageImpl) Update(toAdd []dtos.SplitDTO, toRemove []dtos.SplitDTO, changeNumber int64) {
+	var previous map[string]*dtos.SplitDTO
+	if s.changes != nil {
+		previous = s.FetchMany(append(splitNames(toAdd), splitNames(toRemove)...))
+	}
+
 	if err := s.UpdateWithErrors(toAdd, toRemove, changeNumber); err != nil {
 		switch parsedErr := err.(type) {
 		case nil:
@@ -60,6 +68,26 @@ func (s *ObservableSplitStorageImpl) Update(toAdd []dtos.SplitDTO, toRemove []dt
 		}
 	}
 	s.active.update(splitNames(toAdd), splitNames(toRemove))
+	if s.changes != nil {
+		s.changes.RecordSplitChanges(previous, toAdd, toRemove, changeNumber)
+	}
+}
+
+// KillLocally is an override that records the kill after forwarding the call to the underlying storage
+func (s *ObservableSplitStorageImpl) KillLocally(splitName string, defaultTreatment string, changeNumber int64) {
+	if s.changes == nil {
+		s.extendedSplitStorage.KillLocally(splitName, defaultTreatment, changeNumber)
+		return
+	}
+
+	previous := s.Split(splitName)
+	s.extendedSplitStorage.KillLocally(splitName, defaultTreatment, changeNumber)
+	s.changes.RecordKill(previous, s.Split(splitName))
+}
+
+// SetChangeRecorder sets the recorder used to keep track of split changes
+func (s *ObservableSplitStorageImpl) SetChangeRecorder(recorder *changelog.Recorder) {
+	s.changes = recorder
 }
 
 // Count returns the number of active splits
@@ -0,0 +1,77 @@
+package apikeys
+
+import (
+	"errors"
+	"time"
+
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+// AdminView exposes a key manager to the admin endpoints
+type AdminView struct {
+	manager *Manager
+}
+
+// NewAdminView constructs an admin view of the supplied manager
+func NewAdminView(manager *Manager) *AdminView {
+	return &AdminView{manager: manager}
+}
+
+// List returns the status of every key
+func (v *AdminView) List() []adminCommon.ClientAPIKeyStatus {
+	statuses := v.manager.List()
+	result := make([]adminCommon.ClientAPIKeyStatus, 0, len(statuses))
+	for idx := range statuses {
+		result = append(result, toAdminStatus(&statuses[idx]))
+	}
+	return result
+}
+
+// Add creates a new key (generating one if apikey is empty)
+func (v *AdminView) Add(apikey string, label string, expiresAt time.Time) (string, *adminCommon.ClientAPIKeyStatus, error) {
+	created, status, err := v.manager.Add(apikey, label, expiresAt)
+	if err != nil {
+		return "", nil, toAdminError(err)
+	}
+	asAdmin := toAdminStatus(status)
+	return created, &asAdmin, nil
+}
+
+// Revoke revokes the key with the supplied id
+func (v *AdminView) Revoke(id string) (*adminCommon.ClientAPIKeyStatus, error) {
+	status, err := v.manager.Revoke(id)
+	if err != nil {
+		return nil, toAdminError(err)
+	}
+	asAdmin := toAdminStatus(status)
+	return &asAdmin, nil
+}
+
+func toAdminStatus(status *Status) adminCommon.ClientAPIKeyStatus {
+	return adminCommon.ClientAPIKeyStatus{
+		ID:        status.ID,
+		Hint:      status.Hint,
+		Label:     status.Label,
+		Source:    status.Source,
+		CreatedAt: status.CreatedAt,
+		ExpiresAt: status.ExpiresAt,
+		RevokedAt: status.RevokedAt,
+		Active:    status.Active,
+		Usage:     status.Usage,
+		LastUsed:  status.LastUsed,
+	}
+}
+
+func toAdminError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return adminCommon.ErrClientAPIKeyNotFound
+	case errors.Is(err, ErrDuplicate):
+		return adminCommon.ErrClientAPIKeyDuplicate
+	case errors.Is(err, ErrExpired):
+		return adminCommon.ErrClientAPIKeyExpired
+	}
+	return err
+}
+
+var _ adminCommon.ClientAPIKeys = (*AdminView)(nil)
@@ -0,0 +1,339 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Key sources
+const (
+	SourceConfig  = "config"
+	SourceRuntime = "runtime"
+)
+
+// Errors returned when managing keys
+var (
+	ErrNotFound  = errors.New("apikey not found")
+	ErrDuplicate = errors.New("apikey already exists")
+	ErrExpired   = errors.New("expiration date is in the past")
+)
+
+// Key is a client apikey created at runtime, or a revoked one from the configuration. Only a hash of the key is kept,
+// along with the last characters to help identifying it
+type Key struct {
+	ID        string `json:"id"`
+	Source    string `json:"source,omitempty"`
+	Hash      string `json:"hash"`
+	Hint      string `json:"hint"`
+	Label     string `json:"label"`
+	CreatedAt int64  `json:"createdAt"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	RevokedAt int64  `json:"revokedAt,omitempty"`
+}
+
+// Store persists keys created at runtime & revocations of the ones set in the configuration
+type Store interface {
+	Save(key *Key) error
+	FetchAll() ([]Key, error)
+}
+
+// Status is the view of a key exposed to admins, including usage since startup
+type Status struct {
+	ID        string `json:"id"`
+	Hint      string `json:"hint"`
+	Label     string `json:"label"`
+	Source    string `json:"source"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	RevokedAt int64  `json:"revokedAt,omitempty"`
+	Active    bool   `json:"active"`
+	Usage     int64  `json:"usage"`
+	LastUsed  int64  `json:"lastUsed,omitempty"`
+}
+
+type usage struct {
+	count    int64
+	lastUsed int64
+}
+
+// Manager validates client apikeys, both the ones set in the configuration and the ones created at runtime
+type Manager struct {
+	static  map[string]*Key   // raw key -> key
+	dynamic map[string]*Key   // hash -> key
+	usage   map[string]*usage // id -> usage
+	store   Store
+	logger  logging.LoggerInterface
+	now     func() time.Time
+	mutex   sync.RWMutex
+}
+
+// NewManager constructs a key manager. If store is nil, keys created at runtime are not persisted
+func NewManager(static []string, store Store, logger logging.LoggerInterface) *Manager {
+	manager := &Manager{
+		static:  make(map[string]*Key, len(static)),
+		dynamic: make(map[string]*Key),
+		usage:   make(map[string]*usage),
+		store:   store,
+		logger:  logger,
+		now:     time.Now,
+	}
+
+	for _, apikey := range static {
+		hashed := hash(apikey)
+		key := &Key{ID: idFor(hashed), Source: SourceConfig, Hash: hashed, Hint: hint(apikey)}
+		manager.static[apikey] = key
+		manager.usage[key.ID] = &usage{}
+	}
+	return manager
+}
+
+// Load reads the keys previously created at runtime & the revocations of configured keys from the store
+func (m *Manager) Load() error {
+	if m.store == nil {
+		return nil
+	}
+
+	keys, err := m.store.FetchAll()
+	if err != nil {
+		return fmt.Errorf("error reading client apikeys: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for idx := range keys {
+		key := keys[idx]
+		if key.Source == SourceConfig {
+			// revocations are kept even if the key is no longer configured, so that it doesn't come back if re-added
+			if static := m.staticByHash(key.Hash); static != nil {
+				static.RevokedAt = key.RevokedAt
+			}
+			continue
+		}
+		m.dynamic[key.Hash] = &key
+		if _, ok := m.usage[key.ID]; !ok {
+			m.usage[key.ID] = &usage{}
+		}
+	}
+	return nil
+}
+
+// IsValid checks if an apikey is valid and records its usage
+func (m *Manager) IsValid(apikey string) bool {
+	id, ok := m.validate(apikey)
+	if !ok {
+		return false
+	}
+
+	m.mutex.RLock()
+	current := m.usage[id]
+	m.mutex.RUnlock()
+	if current != nil {
+		atomic.AddInt64(&current.count, 1)
+		atomic.StoreInt64(&current.lastUsed, toMillis(m.now()))
+	}
+	return true
+}
+
+// Exists checks if an apikey is valid without recording its usage
+func (m *Manager) Exists(apikey string) bool {
+	_, ok := m.validate(apikey)
+	return ok
+}
+
+func (m *Manager) validate(apikey string) (string, bool) {
+	m.mutex.RLock()
+	key, ok := m.static[apikey]
+	if !ok {
+		key, ok = m.dynamic[hash(apikey)]
+	}
+	m.mutex.RUnlock()
+	if !ok || !isActive(key, m.now()) {
+		return "", false
+	}
+	return key.ID, true
+}
+
+// Add creates a new key. If apikey is empty, a random one is generated. An expiresAt of 0 means no expiration.
+// The raw key is returned, and cannot be retrieved afterwards
+func (m *Manager) Add(apikey string, label string, expiresAt time.Time) (string, *Status, error) {
+	now := m.now()
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return "", nil, ErrExpired
+	}
+
+	if apikey == "" {
+		var err error
+		if apikey, err = generate(); err != nil {
+			return "", nil, fmt.Errorf("error generating apikey: %w", err)
+		}
+	}
+
+	hashed := hash(apikey)
+	key := &Key{
+		ID:        idFor(hashed),
+		Source:    SourceRuntime,
+		Hash:      hashed,
+		Hint:      hint(apikey),
+		Label:     label,
+		CreatedAt: toMillis(now),
+	}
+	if !expiresAt.IsZero() {
+		key.ExpiresAt = toMillis(expiresAt)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.static[apikey]; ok {
+		return "", nil, ErrDuplicate
+	}
+	if existing, ok := m.dynamic[hashed]; ok && existing.RevokedAt == 0 {
+		return "", nil, ErrDuplicate
+	}
+
+	if err := m.save(key); err != nil {
+		return "", nil, err
+	}
+	m.dynamic[hashed] = key
+	m.usage[key.ID] = &usage{}
+	status := m.statusFor(key, SourceRuntime, now)
+	return apikey, &status, nil
+}
+
+// Revoke invalidates a key. Revocations of keys set in the configuration are persisted as well, so that a leaked key
+// can be disabled without a redeploy
+func (m *Manager) Revoke(id string) (*Status, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, key := range m.static {
+		if key.ID == id {
+			return m.revoke(key, SourceConfig)
+		}
+	}
+
+	for _, key := range m.dynamic {
+		if key.ID == id {
+			return m.revoke(key, SourceRuntime)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// must be called with the lock acquired
+func (m *Manager) revoke(key *Key, source string) (*Status, error) {
+	if key.RevokedAt == 0 {
+		updated := *key
+		updated.RevokedAt = toMillis(m.now())
+		if err := m.save(&updated); err != nil {
+			return nil, err
+		}
+		*key = updated
+	}
+	status := m.statusFor(key, source, m.now())
+	return &status, nil
+}
+
+// must be called with the lock acquired
+func (m *Manager) staticByHash(hashed string) *Key {
+	for _, key := range m.static {
+		if key.Hash == hashed {
+			return key
+		}
+	}
+	return nil
+}
+
+// List returns all keys with their usage, ones from the configuration first
+func (m *Manager) List() []Status {
+	now := m.now()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := make([]Status, 0, len(m.static)+len(m.dynamic))
+	for _, key := range m.static {
+		result = append(result, m.statusFor(key, SourceConfig, now))
+	}
+	for _, key := range m.dynamic {
+		result = append(result, m.statusFor(key, SourceRuntime, now))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Source != result[j].Source {
+			return result[i].Source == SourceConfig
+		}
+		if result[i].CreatedAt != result[j].CreatedAt {
+			return result[i].CreatedAt < result[j].CreatedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// must be called with the lock acquired
+func (m *Manager) statusFor(key *Key, source string, now time.Time) Status {
+	status := Status{
+		ID:        key.ID,
+		Hint:      key.Hint,
+		Label:     key.Label,
+		Source:    source,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		Active:    isActive(key, now),
+	}
+	if current, ok := m.usage[key.ID]; ok {
+		status.Usage = atomic.LoadInt64(&current.count)
+		status.LastUsed = atomic.LoadInt64(&current.lastUsed)
+	}
+	return status
+}
+
+func (m *Manager) save(key *Key) error {
+	if m.store == nil {
+		return nil
+	}
+	if err := m.store.Save(key); err != nil {
+		return fmt.Errorf("error persisting client apikey: %w", err)
+	}
+	return nil
+}
+
+func isActive(key *Key, now time.Time) bool {
+	return key.RevokedAt == 0 && (key.ExpiresAt == 0 || toMillis(now) < key.ExpiresAt)
+}
+
+func hash(apikey string) string {
+	sum := sha256.Sum256([]byte(apikey))
+	return hex.EncodeToString(sum[:])
+}
+
+func idFor(hashed string) string {
+	return hashed[:16]
+}
+
+func hint(apikey string) string {
+	if len(apikey) <= 4 {
+		return "****"
+	}
+	return "****" + apikey[len(apikey)-4:]
+}
+
+func generate() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func toMillis(t time.Time) int64 {
+	return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+}
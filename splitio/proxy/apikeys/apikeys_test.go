@@ -0,0 +1,137 @@
+package apikeys
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+type storeMock struct {
+	keys map[string]Key
+	err  error
+}
+
+func (s *storeMock) Save(key *Key) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.keys[key.ID] = *key
+	return nil
+}
+
+func (s *storeMock) FetchAll() ([]Key, error) {
+	result := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func TestManagerStaticKeys(t *testing.T) {
+	store := &storeMock{keys: make(map[string]Key)}
+	manager := NewManager([]string{"staticKey1"}, store, logging.NewLogger(nil))
+	if !manager.IsValid("staticKey1") || manager.IsValid("other") {
+		t.Error("only configured keys should be valid")
+	}
+
+	keys := manager.List()
+	if len(keys) != 1 || keys[0].Source != SourceConfig || keys[0].Hint != "****Key1" || keys[0].Usage != 1 || !keys[0].Active {
+		t.Error("wrong key status: ", keys)
+	}
+
+	if !manager.Exists("staticKey1") || manager.List()[0].Usage != 1 {
+		t.Error("Exists should not record usage")
+	}
+
+	if _, _, err := manager.Add("staticKey1", "dup", time.Time{}); !errors.Is(err, ErrDuplicate) {
+		t.Error("static keys should not be added again. Got: ", err)
+	}
+
+	status, err := manager.Revoke(keys[0].ID)
+	if err != nil || status.Active || status.RevokedAt == 0 || status.Source != SourceConfig || manager.IsValid("staticKey1") {
+		t.Error("static keys should be revocable at runtime. Got: ", status, err)
+	}
+
+	// the revocation survives a restart, even with the key still in the configuration
+	restarted := NewManager([]string{"staticKey1", "staticKey2"}, store, logging.NewLogger(nil))
+	if err := restarted.Load(); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if restarted.IsValid("staticKey1") || !restarted.IsValid("staticKey2") {
+		t.Error("only the non-revoked static key should be valid after a restart")
+	}
+	if listed := restarted.List(); len(listed) != 2 {
+		t.Error("revocation tombstones should not be listed as runtime keys. Got: ", listed)
+	}
+}
+
+func TestManagerRuntimeKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := &storeMock{keys: make(map[string]Key)}
+	manager := NewManager(nil, store, logging.NewLogger(nil))
+	manager.now = func() time.Time { return now }
+
+	generated, status, err := manager.Add("", "mobile", time.Time{})
+	now = now.Add(time.Second)
+	if err != nil || len(generated) != 48 || status.Label != "mobile" || !status.Active {
+		t.Error("a random key should have been generated. Got: ", generated, status, err)
+	}
+
+	expiring, _, err := manager.Add("expiringKey", "web", now.Add(time.Hour))
+	if err != nil || expiring != "expiringKey" {
+		t.Error("the supplied key should be used. Got: ", expiring, err)
+	}
+
+	if _, _, err := manager.Add("pastKey", "web", now.Add(-time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Error("expired keys should be rejected. Got: ", err)
+	}
+
+	if !manager.IsValid(generated) || !manager.IsValid(expiring) {
+		t.Error("added keys should be valid")
+	}
+
+	for _, key := range store.keys {
+		if key.Hash == generated || key.Hint == "" {
+			t.Error("raw keys should not be persisted: ", key)
+		}
+	}
+
+	// a new manager picks up persisted keys
+	reloaded := NewManager(nil, store, logging.NewLogger(nil))
+	reloaded.now = manager.now
+	if err := reloaded.Load(); err != nil || !reloaded.IsValid(generated) {
+		t.Error("persisted keys should be loaded. Got: ", err)
+	}
+
+	revoked, err := manager.Revoke(status.ID)
+	if err != nil || revoked.Active || revoked.RevokedAt == 0 {
+		t.Error("key should have been revoked. Got: ", revoked, err)
+	}
+	if manager.IsValid(generated) {
+		t.Error("revoked keys should not be valid")
+	}
+	if store.keys[status.ID].RevokedAt == 0 {
+		t.Error("revocation should be persisted")
+	}
+
+	if _, err := manager.Revoke("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Error("unknown keys should not be found. Got: ", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if manager.IsValid(expiring) {
+		t.Error("expired keys should not be valid")
+	}
+
+	keys := manager.List()
+	if len(keys) != 2 || keys[0].Label != "mobile" || keys[0].Usage != 1 || keys[1].Active {
+		t.Error("wrong key listing: ", keys)
+	}
+
+	store.err = errors.New("some error")
+	if _, _, err := manager.Add("another", "", time.Time{}); err == nil || manager.IsValid("another") {
+		t.Error("keys failing to persist should not be added")
+	}
+}
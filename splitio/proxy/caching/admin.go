@@ -0,0 +1,38 @@
+package caching
+
+import (
+	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
+)
+
+// AdminView exposes a response cache to the admin endpoints
+type AdminView struct {
+	cache *HTTPCache
+}
+
+// NewAdminView constructs an admin view of the supplied cache
+func NewAdminView(cache *HTTPCache) *AdminView {
+	return &AdminView{cache: cache}
+}
+
+// Stats returns the current size, limits & per-family counters of the cache
+func (v *AdminView) Stats() adminCommon.HTTPCacheStats {
+	stats := v.cache.Stats()
+	families := make(map[string]adminCommon.HTTPCacheFamilyStats, len(stats.Families))
+	for name, family := range stats.Families {
+		families[name] = adminCommon.HTTPCacheFamilyStats(family)
+	}
+	return adminCommon.HTTPCacheStats{
+		Entries:    stats.Entries,
+		Bytes:      stats.Bytes,
+		MaxEntries: stats.MaxEntries,
+		MaxBytes:   stats.MaxBytes,
+		Families:   families,
+	}
+}
+
+// Purge removes the entries associated to a surrogate, or every entry if the surrogate is empty
+func (v *AdminView) Purge(surrogate string) int {
+	return v.cache.Purge(surrogate)
+}
+
+var _ adminCommon.HTTPCache = (*AdminView)(nil)
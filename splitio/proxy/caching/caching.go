@@ -1,10 +1,11 @@
 package caching
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/splitio/gincache"
 	"github.com/splitio/go-split-commons/v4/dtos"
 )
 
@@ -14,7 +15,14 @@ const (
 
 	// StickyContextKey should be set to (boolean) true whenever we want an entry to be kept in cache when making room
 	// for new entries
-	StickyContextKey = gincache.StickyEntry
+	StickyContextKey = "c_sticky"
+
+	// MaxEntryBytesContextKey can be set to an int64 to lower the amount of bytes buffered (and thus the max entry size)
+	// for a single response. Larger responses are passed through & not cached
+	MaxEntryBytesContextKey = "c_max_bytes"
+
+	// StreamedEntryMaxBytes is the max entry size used for responses streamed from storage, which can be arbitrarily large
+	StreamedEntryMaxBytes int64 = 1 << 20
 
 	// SplitSurrogate key (we only need one, since all splitChanges should be expired when an update is processed)
 	SplitSurrogate = "sp"
@@ -25,8 +33,6 @@ const (
 	segmentPrefix = "se::"
 )
 
-const cacheSize = 1000000
-
 // MakeSurrogateForSegmentChanges creates a surrogate key for the segment being queried
 func MakeSurrogateForSegmentChanges(segmentName string) string {
 	return segmentPrefix + segmentName
@@ -50,16 +56,23 @@ func MakeSurrogateForMySegments(mysegments []dtos.MySegmentDTO) []string {
 	return nil
 }
 
+// MakeETag builds a strong entity tag out of the values that uniquely identify a response (ie: change numbers)
+func MakeETag(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
 // MakeMySegmentsEntry create a cache entry key for mysegments
 func MakeMySegmentsEntry(key string) string {
 	return "/api/mySegments/" + key
 }
 
-// MakeProxyCache creates and configures a split-proxy-ready cache
-func MakeProxyCache() *gincache.Middleware {
-	return gincache.New(&gincache.Options{
+// MakeProxyCache creates and configures a split-proxy-ready cache, bounded by number of entries & estimated memory usage
+func MakeProxyCache(maxEntries int, maxBytes int64) *HTTPCache {
+	return NewHTTPCache(&HTTPCacheOptions{
 		SuccessfulOnly: true, // we're not interested in caching non-200 responses
-		Size:           cacheSize,
+		MaxEntries:     maxEntries,
+		MaxBytes:       maxBytes,
 		KeyFactory: func(ctx *gin.Context) string {
 			if strings.HasPrefix(ctx.Request.URL.Path, "/api/auth") || strings.HasPrefix(ctx.Request.URL.Path, "/api/v2/auth") {
 				// For auth requests, since we don't support streaming yet, we only need a single entry in the table,
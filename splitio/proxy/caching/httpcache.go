@@ -0,0 +1,438 @@
+package caching
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/gincache"
+)
+
+// Entry families used to break down cache statistics
+const (
+	FamilySplits     = "splits"
+	FamilySegments   = "segments"
+	FamilyMySegments = "mySegments"
+	FamilyAuth       = "auth"
+	FamilyOther      = "other"
+)
+
+// entryOverhead is a rough estimate of the memory used by an entry on top of its key, body & headers
+// (list element, map bucket, slices & string headers)
+const entryOverhead = 256
+
+// headers that depend on the request & are set by other middlewares, hence must not be replayed from cache
+var headersToIgnore = map[string]struct{}{
+	"Access-Control-Allow-Credentials": {},
+	"Access-Control-Expose-Headers":    {},
+	"Access-Control-Allow-Origin":      {},
+	"Vary":                             {},
+}
+
+// KeyFactoryFn builds the cache key for a request
+type KeyFactoryFn func(ctx *gin.Context) string
+
+// SurrogateFactoryFn builds the list of surrogates for a response
+type SurrogateFactoryFn func(ctx *gin.Context) []string
+
+// HTTPCacheOptions wraps the parameters used to configure an HTTPCache
+type HTTPCacheOptions struct {
+	MaxEntries       int
+	MaxBytes         int64
+	KeyFactory       KeyFactoryFn
+	SurrogateFactory SurrogateFactoryFn
+	SuccessfulOnly   bool
+}
+
+// FamilyStats contains the usage counters of a family of cache entries
+type FamilyStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Purged    int64 `json:"purged"`
+	Entries   int64 `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// HTTPCacheStats contains the current size & limits of the cache along with per-family counters
+type HTTPCacheStats struct {
+	Entries    int64                  `json:"entries"`
+	Bytes      int64                  `json:"bytes"`
+	MaxEntries int64                  `json:"maxEntries"`
+	MaxBytes   int64                  `json:"maxBytes"`
+	Families   map[string]FamilyStats `json:"families"`
+}
+
+type httpCacheEntry struct {
+	key        string
+	family     string
+	status     int
+	body       []byte
+	headers    http.Header
+	surrogates []string
+	sticky     bool
+	size       int64
+}
+
+// HTTPCache is a gin middleware that caches responses, bounded by both number of entries & estimated memory usage.
+// When full, the least recently used entries are evicted first. Sticky entries are only evicted when no other entry is left
+type HTTPCache struct {
+	keyFactory        KeyFactoryFn
+	surrogatesFactory SurrogateFactoryFn
+	successOnly       bool
+	maxEntries        int
+	maxBytes          int64
+	mutex             sync.Mutex
+	entries           map[string]*list.Element
+	regular           *list.List
+	sticky            *list.List
+	surrogates        map[string]map[string]struct{}
+	bytes             int64
+	families          map[string]*FamilyStats
+}
+
+// NewHTTPCache constructs a new cache. A limit <= 0 means no limit
+func NewHTTPCache(options *HTTPCacheOptions) *HTTPCache {
+	return &HTTPCache{
+		keyFactory:        options.KeyFactory,
+		surrogatesFactory: options.SurrogateFactory,
+		successOnly:       options.SuccessfulOnly,
+		maxEntries:        options.MaxEntries,
+		maxBytes:          options.MaxBytes,
+		entries:           make(map[string]*list.Element),
+		regular:           list.New(),
+		sticky:            list.New(),
+		surrogates:        make(map[string]map[string]struct{}),
+		families:          make(map[string]*FamilyStats),
+	}
+}
+
+// Handle is the function that should be passed to the router's `.Use()` method
+func (c *HTTPCache) Handle(ctx *gin.Context) {
+	if ctx.Request.Method == http.MethodOptions {
+		return
+	}
+
+	key := c.keyFactory(ctx)
+	if status, body, headers, ok := c.get(key); ok {
+		for name, values := range headers {
+			if _, ignore := headersToIgnore[name]; ignore {
+				continue
+			}
+			for _, value := range values {
+				ctx.Writer.Header().Add(name, value)
+			}
+		}
+		ctx.Writer.WriteHeader(status)
+		ctx.Writer.Write(body)
+		ctx.Abort()
+		return
+	}
+
+	// intercept the response written by the handlers down the chain. Responses that wouldn't fit in the cache
+	// are passed through as soon as they exceed the limit, instead of being held in memory until complete
+	writer := &cacheWriter{ResponseWriter: ctx.Writer, ctx: ctx, limit: c.maxBytes}
+	ctx.Writer = writer
+	ctx.Next()
+
+	if writer.passthrough || len(ctx.Errors) > 0 { // too large to be cached or incomplete
+		writer.flush()
+		return
+	}
+
+	body := make([]byte, writer.body.Len())
+	copy(body, writer.body.Bytes())
+	writer.flush()
+
+	status := writer.statusCode
+	if status == 0 {
+		status = http.StatusOK // the handler wrote a body without setting a status
+	}
+	if c.successOnly && status != http.StatusOK {
+		return
+	}
+
+	var surrogates []string
+	if c.surrogatesFactory != nil {
+		surrogates = c.surrogatesFactory(ctx)
+	}
+
+	c.set(key, surrogates, status, body, writer.Header().Clone(), ctx.GetBool(StickyContextKey))
+}
+
+// EvictAll removes every entry from the cache
+func (c *HTTPCache) EvictAll() {
+	c.Purge("")
+}
+
+// Evict removes a single entry
+func (c *HTTPCache) Evict(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.remove(key, false)
+}
+
+// EvictBySurrogate removes all the entries referenced by a surrogate
+func (c *HTTPCache) EvictBySurrogate(surrogate string) {
+	c.Purge(surrogate)
+}
+
+// Purge removes all the entries referenced by a surrogate, or every entry if the surrogate is empty.
+// Returns the number of entries removed
+func (c *HTTPCache) Purge(surrogate string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var keys []string
+	if surrogate == "" {
+		keys = make([]string, 0, len(c.entries))
+		for key := range c.entries {
+			keys = append(keys, key)
+		}
+	} else {
+		keys = make([]string, 0, len(c.surrogates[surrogate]))
+		for key := range c.surrogates[surrogate] {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		c.remove(key, false)
+	}
+	return len(keys)
+}
+
+// Stats returns the current cache size & usage counters
+func (c *HTTPCache) Stats() HTTPCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats := HTTPCacheStats{
+		Entries:    int64(len(c.entries)),
+		Bytes:      c.bytes,
+		MaxEntries: int64(c.maxEntries),
+		MaxBytes:   c.maxBytes,
+		Families:   make(map[string]FamilyStats, len(c.families)),
+	}
+	for name, family := range c.families {
+		stats.Families[name] = *family
+	}
+	return stats
+}
+
+func (c *HTTPCache) get(key string) (int, []byte, http.Header, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.family(familyFor(key)).Misses++
+		return 0, nil, nil, false
+	}
+
+	entry := element.Value.(*httpCacheEntry)
+	c.listFor(entry.sticky).MoveToFront(element)
+	c.family(entry.family).Hits++
+	return entry.status, entry.body, entry.headers, true
+}
+
+func (c *HTTPCache) set(key string, surrogates []string, status int, body []byte, headers http.Header, sticky bool) {
+	for name := range headers {
+		if _, ignore := headersToIgnore[name]; ignore {
+			delete(headers, name)
+		}
+	}
+
+	entry := &httpCacheEntry{
+		key:        key,
+		family:     familyFor(key),
+		status:     status,
+		body:       body,
+		headers:    headers,
+		surrogates: surrogates,
+		sticky:     sticky,
+	}
+	entry.size = estimateSize(entry)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.entries[key]; exists {
+		return // another request populated it in the meantime
+	}
+
+	if c.maxBytes > 0 && entry.size > c.maxBytes {
+		return // would evict everything & still not fit
+	}
+
+	for c.full(entry.size) {
+		if !c.evictOne() {
+			break
+		}
+	}
+
+	c.entries[key] = c.listFor(sticky).PushFront(entry)
+	c.bytes += entry.size
+	family := c.family(entry.family)
+	family.Entries++
+	family.Bytes += entry.size
+	for _, surrogate := range surrogates {
+		referenced, ok := c.surrogates[surrogate]
+		if !ok {
+			referenced = make(map[string]struct{})
+			c.surrogates[surrogate] = referenced
+		}
+		referenced[key] = struct{}{}
+	}
+}
+
+// full returns whether an entry of the supplied size needs room to be made
+func (c *HTTPCache) full(size int64) bool {
+	return (c.maxEntries > 0 && len(c.entries) >= c.maxEntries) || (c.maxBytes > 0 && c.bytes+size > c.maxBytes)
+}
+
+// evictOne removes the least recently used entry, preferring non-sticky ones. Returns false if the cache is empty
+func (c *HTTPCache) evictOne() bool {
+	oldest := c.regular.Back()
+	if oldest == nil {
+		oldest = c.sticky.Back()
+	}
+	if oldest == nil {
+		return false
+	}
+	c.remove(oldest.Value.(*httpCacheEntry).key, true)
+	return true
+}
+
+// remove must be called with the mutex held
+func (c *HTTPCache) remove(key string, evicted bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	entry := element.Value.(*httpCacheEntry)
+	c.listFor(entry.sticky).Remove(element)
+	delete(c.entries, key)
+	c.bytes -= entry.size
+
+	family := c.family(entry.family)
+	family.Entries--
+	family.Bytes -= entry.size
+	if evicted {
+		family.Evictions++
+	} else {
+		family.Purged++
+	}
+
+	// drop references from surrogates so that a new entry with the same key isn't purged by a stale association
+	for _, surrogate := range entry.surrogates {
+		if referenced, ok := c.surrogates[surrogate]; ok {
+			delete(referenced, key)
+			if len(referenced) == 0 {
+				delete(c.surrogates, surrogate)
+			}
+		}
+	}
+}
+
+func (c *HTTPCache) listFor(sticky bool) *list.List {
+	if sticky {
+		return c.sticky
+	}
+	return c.regular
+}
+
+func (c *HTTPCache) family(name string) *FamilyStats {
+	family, ok := c.families[name]
+	if !ok {
+		family = &FamilyStats{}
+		c.families[name] = family
+	}
+	return family
+}
+
+func familyFor(key string) string {
+	switch {
+	case strings.HasPrefix(key, "/api/splitChanges"):
+		return FamilySplits
+	case strings.HasPrefix(key, "/api/segmentChanges"):
+		return FamilySegments
+	case strings.HasPrefix(key, "/api/mySegments"):
+		return FamilyMySegments
+	case strings.HasPrefix(key, "/api/auth"), strings.HasPrefix(key, "/api/v2/auth"):
+		return FamilyAuth
+	default:
+		return FamilyOther
+	}
+}
+
+func estimateSize(entry *httpCacheEntry) int64 {
+	size := int64(entryOverhead + len(entry.key) + len(entry.body))
+	for name, values := range entry.headers {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	for _, surrogate := range entry.surrogates {
+		size += int64(len(surrogate))
+	}
+	return size
+}
+
+// cacheWriter accumulates the response body so that it can be stored once the handlers are done
+type cacheWriter struct {
+	gin.ResponseWriter
+	ctx           *gin.Context
+	statusCode    int
+	body          bytes.Buffer
+	limit         int64
+	limitResolved bool
+	passthrough   bool
+}
+
+func (w *cacheWriter) flush() {
+	w.ResponseWriter.Write(w.body.Bytes())
+	w.body.Reset()
+}
+
+func (w *cacheWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheWriter) Write(data []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+
+	if !w.limitResolved { // handlers set the per-response limit before writing the body
+		w.limitResolved = true
+		if perResponse := w.ctx.GetInt64(MaxEntryBytesContextKey); perResponse > 0 && (w.limit <= 0 || perResponse < w.limit) {
+			w.limit = perResponse
+		}
+	}
+
+	n, err := w.body.Write(data)
+	if w.limit > 0 && int64(w.body.Len()) > w.limit {
+		w.passthrough = true
+		w.flush()
+	}
+	return n, err
+}
+
+func (w *cacheWriter) WriteString(data string) (int, error) {
+	return w.Write([]byte(data))
+}
+
+func (w *cacheWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+var _ gincache.CacheFlusher = (*HTTPCache)(nil)
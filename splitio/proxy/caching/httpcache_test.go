@@ -0,0 +1,239 @@
+package caching
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHTTPCacheMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := MakeProxyCache(10, 0)
+
+	calls := 0
+	router := gin.New()
+	router.Use(cache.Handle)
+	router.GET("/api/splitChanges", func(ctx *gin.Context) {
+		calls++
+		ctx.Set(SurrogateContextKey, []string{SplitSurrogate})
+		ctx.Header("ETag", `"abc"`)
+		ctx.Header("Vary", "Accept-Encoding")
+		ctx.String(200, "splits")
+	})
+	router.GET("/api/segmentChanges/:name", func(ctx *gin.Context) {
+		calls++
+		ctx.String(404, "not found")
+	})
+
+	for i := 0; i < 3; i++ {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/splitChanges?since=-1", nil)
+		router.ServeHTTP(resp, req)
+		if resp.Code != 200 || resp.Body.String() != "splits" || resp.Header().Get("ETag") != `"abc"` {
+			t.Error("unexpected response: ", resp.Code, resp.Body.String(), resp.Header())
+		}
+		if i > 0 && resp.Header().Get("Vary") != "" {
+			t.Error("ignored headers should not be replayed from cache")
+		}
+	}
+	if calls != 1 {
+		t.Error("handler should have been called once. Got: ", calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/segmentChanges/s1?since=-1", nil)
+		router.ServeHTTP(resp, req)
+		if resp.Code != 404 {
+			t.Error("status should be 404. Got: ", resp.Code)
+		}
+	}
+	if calls != 3 {
+		t.Error("non-200 responses should not be cached. Calls: ", calls)
+	}
+
+	stats := cache.Stats()
+	if splits := stats.Families[FamilySplits]; splits.Hits != 2 || splits.Misses != 1 || splits.Entries != 1 || splits.Bytes <= 0 {
+		t.Error("invalid splits stats: ", splits)
+	}
+	if segments := stats.Families[FamilySegments]; segments.Misses != 2 || segments.Entries != 0 {
+		t.Error("invalid segments stats: ", segments)
+	}
+
+	adminView := NewAdminView(cache)
+	if viewed := adminView.Stats(); viewed.Entries != stats.Entries || viewed.MaxEntries != 10 || viewed.Families[FamilySplits].Hits != 2 {
+		t.Error("the admin view should expose the cache stats. Got: ", viewed)
+	}
+
+	if purged := adminView.Purge(SplitSurrogate); purged != 1 {
+		t.Error("1 entry should have been purged. Got: ", purged)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 || stats.Families[FamilySplits].Purged != 1 {
+		t.Error("cache should be empty: ", stats)
+	}
+}
+
+func TestHTTPCacheBounds(t *testing.T) {
+	cache := NewHTTPCache(&HTTPCacheOptions{MaxEntries: 3})
+	cache.set("/api/mySegments/k1", nil, 200, []byte("1"), nil, false)
+	cache.set("/api/mySegments/k2", nil, 200, []byte("2"), nil, false)
+	cache.set("/api/splitChanges-1", []string{SplitSurrogate}, 200, []byte("3"), nil, true)
+	cache.get("/api/mySegments/k1") // k2 becomes the least recently used one
+	cache.set("/api/mySegments/k3", nil, 200, []byte("4"), nil, false)
+
+	if _, _, _, ok := cache.get("/api/mySegments/k2"); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	for _, key := range []string{"/api/mySegments/k1", "/api/mySegments/k3", "/api/splitChanges-1"} {
+		if _, _, _, ok := cache.get(key); !ok {
+			t.Error("entry should be cached: ", key)
+		}
+	}
+	if evictions := cache.Stats().Families[FamilyMySegments].Evictions; evictions != 1 {
+		t.Error("1 eviction should have been recorded. Got: ", evictions)
+	}
+
+	// sticky entries are only evicted when nothing else is left
+	cache.Evict("/api/mySegments/k1")
+	cache.Evict("/api/mySegments/k3")
+	cache.set("/api/mySegments/k4", nil, 200, []byte("5"), nil, false)
+	cache.set("/api/mySegments/k5", nil, 200, []byte("6"), nil, false)
+	cache.set("/api/mySegments/k6", nil, 200, []byte("7"), nil, false)
+	if _, _, _, ok := cache.get("/api/splitChanges-1"); !ok {
+		t.Error("sticky entry should be kept")
+	}
+
+	body := []byte(strings.Repeat("x", 1000))
+	bySize := NewHTTPCache(&HTTPCacheOptions{MaxBytes: 3000})
+	for _, key := range []string{"/api/mySegments/a", "/api/mySegments/b", "/api/mySegments/c"} {
+		bySize.set(key, nil, 200, body, nil, false)
+	}
+	if stats := bySize.Stats(); stats.Entries != 2 || stats.Bytes > 3000 || stats.Families[FamilyMySegments].Evictions != 1 {
+		t.Error("cache should be bounded by size: ", stats)
+	}
+
+	bySize.set("/api/mySegments/huge", nil, 200, make([]byte, 4000), nil, false)
+	if _, _, _, ok := bySize.get("/api/mySegments/huge"); ok {
+		t.Error("entries larger than the limit should not be cached")
+	}
+	if stats := bySize.Stats(); stats.Entries != 2 {
+		t.Error("oversized entries should not evict others: ", stats)
+	}
+}
+
+func TestHTTPCacheSurrogates(t *testing.T) {
+	cache := NewHTTPCache(&HTTPCacheOptions{})
+	cache.set("/api/segmentChanges/s1", []string{MakeSurrogateForSegmentChanges("s1")}, 200, []byte("1"), nil, false)
+	cache.set("/api/segmentChanges/s2", []string{MakeSurrogateForSegmentChanges("s2")}, 200, []byte("2"), nil, false)
+
+	// re-adding an evicted key must not keep the old surrogate association
+	cache.Evict("/api/segmentChanges/s1")
+	cache.set("/api/segmentChanges/s1", nil, 200, []byte("1"), nil, false)
+	cache.EvictBySurrogate(MakeSurrogateForSegmentChanges("s1"))
+	if _, _, _, ok := cache.get("/api/segmentChanges/s1"); !ok {
+		t.Error("entry should not be purged by a stale surrogate")
+	}
+
+	cache.EvictBySurrogate(MakeSurrogateForSegmentChanges("s2"))
+	if _, _, _, ok := cache.get("/api/segmentChanges/s2"); ok {
+		t.Error("entry should have been purged")
+	}
+
+	if purged := cache.Purge(""); purged != 1 {
+		t.Error("remaining entry should have been purged. Got: ", purged)
+	}
+}
+
+func TestHTTPCacheOversizedAndFailedResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := MakeProxyCache(10, 4096)
+
+	calls := 0
+	router := gin.New()
+	router.Use(cache.Handle)
+	router.GET("/api/segmentChanges/:name", func(ctx *gin.Context) {
+		calls++
+		ctx.Status(200)
+		for i := 0; i < 100; i++ {
+			ctx.Writer.WriteString(strings.Repeat("x", 100))
+		}
+	})
+	router.GET("/api/splitChanges", func(ctx *gin.Context) {
+		calls++
+		ctx.String(200, "partial")
+		ctx.Error(errors.New("something went wrong while writing"))
+	})
+
+	for i := 0; i < 2; i++ {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/segmentChanges/s1?since=-1", nil)
+		router.ServeHTTP(resp, req)
+		if resp.Code != 200 || resp.Body.Len() != 10000 {
+			t.Error("the whole body should have been passed through: ", resp.Code, resp.Body.Len())
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/splitChanges?since=-1", nil)
+		router.ServeHTTP(resp, req)
+		if resp.Body.String() != "partial" {
+			t.Error("wrong body: ", resp.Body.String())
+		}
+	}
+
+	if calls != 4 {
+		t.Error("oversized & failed responses should not be cached. Calls: ", calls)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Error("cache should be empty: ", stats)
+	}
+}
+
+func TestHTTPCachePerResponseLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := MakeProxyCache(10, 268435456)
+
+	var peak int
+	router := gin.New()
+	router.Use(cache.Handle)
+	router.GET("/api/segmentChanges/:name", func(ctx *gin.Context) {
+		ctx.Set(MaxEntryBytesContextKey, int64(4096))
+		ctx.Status(200)
+		chunks := 1000
+		if ctx.Param("name") == "small" {
+			chunks = 2
+		}
+		for i := 0; i < chunks; i++ {
+			ctx.Writer.WriteString(strings.Repeat("x", 1000))
+			if buffered := ctx.Writer.(*cacheWriter).body.Cap(); buffered > peak {
+				peak = buffered
+			}
+		}
+	})
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/segmentChanges/large?since=-1", nil)
+	router.ServeHTTP(resp, req)
+	if resp.Code != 200 || resp.Body.Len() != 1000000 {
+		t.Error("the whole body should have been passed through: ", resp.Code, resp.Body.Len())
+	}
+	if peak > 16384 {
+		t.Error("the buffered body should be bounded by the per-response limit. Peak: ", peak)
+	}
+
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/segmentChanges/small?since=-1", nil)
+	router.ServeHTTP(resp, req)
+	if resp.Code != 200 || resp.Body.Len() != 2000 {
+		t.Error("wrong response: ", resp.Code, resp.Body.Len())
+	}
+
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Error("only the response within the per-response limit should be cached: ", stats)
+	}
+}
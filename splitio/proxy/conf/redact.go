@@ -0,0 +1,9 @@
+package conf
+
+// Redacted returns a copy of the publishing options with credentials masked, suitable for being displayed
+func (p Publishing) Redacted() Publishing {
+	if p.S3.SecretAccessKey != "" {
+		p.S3.SecretAccessKey = "<redacted>"
+	}
+	return p
+}
@@ -16,8 +16,12 @@ type Main struct {
 	Sync             Sync              `json:"sync" s-nested:"true"`
 	Integrations     conf.Integrations `json:"integrations" s-nested:"true"`
 	Logging          conf.Logging      `json:"logging" s-nested:"true"`
+	ChangeLog        conf.ChangeLog    `json:"changeLog" s-nested:"true"`
+	Fleet            conf.Fleet        `json:"fleet" s-nested:"true"`
 	Healthcheck      Healthcheck       `json:"healthcheck" s-nested:"true"`
 	Observability    Observability     `json:"observability" s-nested:"true"`
+	Offline          Offline           `json:"offline" s-nested:"true"`
+	Publishing       Publishing        `json:"publishing" s-nested:"true"`
 }
 
 // BuildAdvancedConfig generates a commons-compatible advancedconfig with default + overriden parameters
@@ -41,10 +45,53 @@ type Initialization struct {
 
 // Server configuration options
 type Server struct {
-	ClientApikeys []string `json:"apikeys" s-cli:"client-apikeys" s-def:"SDK_API_KEY" s-desc:"Apikeys that clients connecting to this proxy will use."`
-	Host          string   `json:"host" s-cli:"server-host" s-def:"0.0.0.0" s-desc:"Host/IP to start the proxy server on"`
-	Port          int64    `json:"port" s-cli:"server-port" s-def:"3000" s-desc:"Port to listten for incoming requests from SDKs"`
-	CacheSize     int64    `json:"httpCacheSize" s-cli:"http-cache-size" s-def:"1000000" s-desc:"How many responses to cache"`
+	ClientApikeys       []string     `json:"apikeys" s-cli:"client-apikeys" s-def:"SDK_API_KEY" s-desc:"Apikeys that clients connecting to this proxy will use."`
+	Host                string       `json:"host" s-cli:"server-host" s-def:"0.0.0.0" s-desc:"Host/IP to start the proxy server on. Use unix:<path> to listen on a unix socket"`
+	Port                int64        `json:"port" s-cli:"server-port" s-def:"3000" s-desc:"Port to listten for incoming requests from SDKs"`
+	CacheSize           int64        `json:"httpCacheSize" s-cli:"http-cache-size" s-def:"1000000" s-desc:"How many responses to cache. 0 means no limit"`
+	CacheMaxBytes       int64        `json:"httpCacheMaxBytes" s-cli:"http-cache-max-bytes" s-def:"268435456" s-desc:"Max estimated memory used by cached responses. 0 means no limit"`
+	TLS                 TLS          `json:"tls" s-nested:"true"`
+	HTTP2               bool         `json:"http2" s-cli:"server-http2" s-def:"true" s-desc:"Negotiate HTTP/2 with clients connecting over TLS"`
+	ReadTimeoutMs       int64        `json:"readTimeoutMs" s-cli:"server-read-timeout-ms" s-def:"0" s-desc:"Max time to read a whole request, including the body. 0 means no limit"`
+	ReadHeaderTimeoutMs int64        `json:"readHeaderTimeoutMs" s-cli:"server-read-header-timeout-ms" s-def:"10000" s-desc:"Max time to read request headers. 0 means no limit"`
+	WriteTimeoutMs      int64        `json:"writeTimeoutMs" s-cli:"server-write-timeout-ms" s-def:"0" s-desc:"Max time to write a response. 0 means no limit"`
+	IdleTimeoutMs       int64        `json:"idleTimeoutMs" s-cli:"server-idle-timeout-ms" s-def:"120000" s-desc:"Max time to keep an idle keep-alive connection open. 0 means no limit"`
+	MaxHeaderBytes      int64        `json:"maxHeaderBytes" s-cli:"server-max-header-bytes" s-def:"1048576" s-desc:"Max size of request headers"`
+	RateLimit           RateLimit    `json:"rateLimit" s-nested:"true"`
+	CacheControl        CacheControl `json:"cacheControl" s-nested:"true"`
+	Ingestion           Ingestion    `json:"ingestion" s-nested:"true"`
+}
+
+// Ingestion configuration options for impressions, events & telemetry endpoints
+type Ingestion struct {
+	MaxImpressionsBodyBytes int64 `json:"maxImpressionsBodyBytes" s-cli:"ingestion-max-impressions-body-bytes" s-def:"10485760" s-desc:"Max (decompressed) size of impressions & impression count request bodies. 0 means no limit"`
+	MaxEventsBodyBytes      int64 `json:"maxEventsBodyBytes" s-cli:"ingestion-max-events-body-bytes" s-def:"10485760" s-desc:"Max (decompressed) size of events request bodies. 0 means no limit"`
+	MaxTelemetryBodyBytes   int64 `json:"maxTelemetryBodyBytes" s-cli:"ingestion-max-telemetry-body-bytes" s-def:"1048576" s-desc:"Max (decompressed) size of telemetry request bodies. 0 means no limit"`
+	ValidatePayloads        bool  `json:"validatePayloads" s-cli:"ingestion-validate-payloads" s-def:"false" s-desc:"Reject malformed impressions, events & telemetry payloads with a 400 instead of forwarding them"`
+}
+
+// CacheControl configuration options. Shared caches (ie: CDNs) will only store responses to authenticated requests
+// if these include `public` or `s-maxage`, in which case the Authorization header must be part of their cache key
+type CacheControl struct {
+	SplitChanges   string `json:"splitChanges" s-cli:"cache-control-split-changes" s-def:"no-cache" s-desc:"Cache-Control header sent with splitChanges responses"`
+	SegmentChanges string `json:"segmentChanges" s-cli:"cache-control-segment-changes" s-def:"no-cache" s-desc:"Cache-Control header sent with segmentChanges responses"`
+	MySegments     string `json:"mySegments" s-cli:"cache-control-my-segments" s-def:"private, no-cache" s-desc:"Cache-Control header sent with mySegments responses"`
+}
+
+// RateLimit configuration options
+type RateLimit struct {
+	Rules             []string `json:"rules" s-cli:"rate-limit-rules" s-def:"" s-desc:"Token-bucket limits: <group>:<scope>:<requests per second>[:<burst>]. Groups: sdk|events|telemetry|*. Scopes: apikey|apikey=<key>|ip|global"`
+	MaxTrackedClients int64    `json:"maxTrackedClients" s-cli:"rate-limit-max-tracked-clients" s-def:"100000" s-desc:"Max number of apikeys/ips tracked by each rule"`
+	TrustForwardedFor bool     `json:"trustForwardedFor" s-cli:"rate-limit-trust-forwarded-for" s-def:"false" s-desc:"Identify clients by X-Forwarded-For/X-Real-Ip instead of the connection address (only when behind a trusted load balancer)"`
+}
+
+// TLS configuration options for the sdk-facing server
+type TLS struct {
+	CertFile              string `json:"certFile" s-cli:"server-tls-cert-file" s-def:"" s-desc:"Certificate used to serve SDKs over TLS. Reloaded when the file changes"`
+	KeyFile               string `json:"keyFile" s-cli:"server-tls-key-file" s-def:"" s-desc:"Private key used to serve SDKs over TLS. Reloaded when the file changes"`
+	ClientCAFile          string `json:"clientCAFile" s-cli:"server-tls-client-ca-file" s-def:"" s-desc:"CA bundle used to verify client certificates"`
+	RequireClientCert     bool   `json:"requireClientCert" s-cli:"server-tls-require-client-cert" s-def:"false" s-desc:"Reject connections without a valid client certificate"`
+	ReloadCheckIntervalMs int64  `json:"reloadCheckIntervalMs" s-cli:"server-tls-reload-check-interval-ms" s-def:"60000" s-desc:"How often to check the certificate & key files for changes. 0 disables reloading"`
 }
 
 // Storage configuration options
@@ -55,11 +102,12 @@ type Storage struct {
 
 // Volatile storage configuration options
 type Volatile struct {
+	MySegmentsMaxKeysInMemory int64 `json:"mySegmentsMaxKeysInMemory" s-cli:"mysegments-max-keys-in-memory" s-def:"0" s-desc:"Max number of keys kept in memory by the mySegments index. Keys beyond this limit are stored on disk. 0 means no limit"`
 }
 
 // Persistent storage configuration options
 type Persistent struct {
-	Filename string `json:"filename" s-cli:"persistent-storage-fn" s-def:"" s-desc:"Where to store flags & user-generated data. (Default: temporary file)"`
+	Filename string `json:"filename" s-cli:"persistent-storage-fn" s-def:"" s-desc:"Where to store user-generated data that must survive restarts (client apikeys & observability time slices). (Default: temporary file)"`
 }
 
 // Sync configuration options
@@ -85,6 +133,15 @@ type AdvancedSync struct {
 // Healthcheck configuration options
 type Healthcheck struct {
 	Dependecies HealthcheckDependecines `json:"dependencies" s-nested:"true"`
+	App         HealthcheckApp          `json:"app" s-nested:"true"`
+	Probes      conf.Probes             `json:"probes" s-nested:"true"`
+	Alerts      conf.HealthAlerts       `json:"alerts" s-nested:"true"`
+}
+
+// HealthcheckApp configuration options
+type HealthcheckApp struct {
+	BoltDBSizeThresholdMb        int64 `json:"boltDBSizeThresholdMb" s-cli:"boltdb-size-threshold-mb" s-def:"0" s-desc:"Database size (MB) above which storage is reported as degraded (0 = disabled)"`
+	ListenerFailureRateThreshold int64 `json:"listenerFailureRateThreshold" s-cli:"listener-failure-rate-threshold" s-def:"0" s-desc:"Percentage of undelivered impression listener bulks above which it's reported as degraded (0 = disabled)"`
 }
 
 // HealthcheckDependecines configuration options
@@ -96,4 +153,35 @@ type HealthcheckDependecines struct {
 type Observability struct {
 	TimeSliceWidthSecs int64 `json:"timeSliceWidthSecs" s-cli:"observability-time-slice-width-secs" s-def:"300" s-desc:"time slice size in seconds"`
 	MaxTimeSliceCount  int64 `json:"maxTimeSliceCount" s-cli:"observability-time-slice-max-count" s-def:"100" s-desc:"max time slices to keep in memory before rotating"`
+	PersistTimeSlices  bool  `json:"persistTimeSlices" s-cli:"observability-persist-time-slices" s-def:"true" s-desc:"persist time slices to the local db so that they survive restarts (requires persistent-storage-fn)"`
+	PersistRateSecs    int64 `json:"persistRateSecs" s-cli:"observability-persist-rate-secs" s-def:"60" s-desc:"how often (in seconds) time slices are flushed to the local db"`
+	RetentionHours     int64 `json:"retentionHours" s-cli:"observability-retention-hours" s-def:"168" s-desc:"how long (in hours) persisted time slices are kept"`
+}
+
+// Offline configuration options
+type Offline struct {
+	Enabled           bool   `json:"enabled" s-cli:"offline-mode" s-def:"false" s-desc:"Run without connecting to split servers. Data is read from a snapshot and/or local files"`
+	DataDir           string `json:"dataDir" s-cli:"offline-data-dir" s-def:"" s-desc:"Directory to watch for splitChanges.json & segmentChanges/<segment>.json files"`
+	RefreshRateMs     int64  `json:"refreshRateMs" s-cli:"offline-refresh-rate-ms" s-def:"10000" s-desc:"How often to check the data directory for changes"`
+	OutputDir         string `json:"outputDir" s-cli:"offline-output-dir" s-def:"." s-desc:"Directory where impressions, events & telemetry posted by sdks are written"`
+	RotationMaxFiles  int64  `json:"rotationMaxFiles" s-cli:"offline-rotation-max-files" s-def:"10" s-desc:"Max number of files to keep per data type when rotating"`
+	RotationMaxSizeKb int64  `json:"rotationMaxSizeKb" s-cli:"offline-rotation-max-size-kb" s-def:"10240" s-desc:"Maximum output file size in kbs"`
+}
+
+// Publishing configuration options
+type Publishing struct {
+	Dir            string       `json:"dir" s-cli:"publishing-dir" s-def:"" s-desc:"Directory where versioned splitChanges/segmentChanges payloads & a manifest are written on every change"`
+	Prefix         string       `json:"prefix" s-cli:"publishing-prefix" s-def:"" s-desc:"Path prefix prepended to every published file"`
+	DebounceMs     int64        `json:"debounceMs" s-cli:"publishing-debounce-ms" s-def:"1000" s-desc:"How long to wait for further changes before publishing"`
+	RetainVersions int64        `json:"retainVersions" s-cli:"publishing-retain-versions" s-def:"10" s-desc:"How many versions of each payload to keep. 0 keeps them all"`
+	S3             PublishingS3 `json:"s3" s-nested:"true"`
+}
+
+// PublishingS3 configuration options
+type PublishingS3 struct {
+	Endpoint        string `json:"endpoint" s-cli:"publishing-s3-endpoint" s-def:"" s-desc:"S3-compatible endpoint. Defaults to AWS's for the region"`
+	Bucket          string `json:"bucket" s-cli:"publishing-s3-bucket" s-def:"" s-desc:"Bucket where payloads are published"`
+	Region          string `json:"region" s-cli:"publishing-s3-region" s-def:"us-east-1" s-desc:"Bucket region"`
+	AccessKeyID     string `json:"accessKeyId" s-cli:"publishing-s3-access-key-id" s-def:"" s-desc:"Access key used to sign requests"`
+	SecretAccessKey string `json:"secretAccessKey" s-cli:"publishing-s3-secret-access-key" s-def:"" s-desc:"Secret key used to sign requests"`
 }
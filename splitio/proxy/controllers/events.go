@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +21,7 @@ type EventsServerController struct {
 	eventsSink          tasks.DeferredRecordingTask
 	listener            impressionlistener.ImpressionBulkListener
 	apikeyValidator     func(string) bool
+	payloads            *payloadReader
 }
 
 // NewEventsServerController returns a new events server controller
@@ -32,6 +32,7 @@ func NewEventsServerController(
 	eventsSink tasks.DeferredRecordingTask,
 	listener impressionlistener.ImpressionBulkListener,
 	apikeyValidator func(string) bool,
+	ingestion *IngestionOptions,
 ) *EventsServerController {
 	return &EventsServerController{
 		logger:              logger,
@@ -40,6 +41,7 @@ func NewEventsServerController(
 		eventsSink:          eventsSink,
 		listener:            listener,
 		apikeyValidator:     apikeyValidator,
+		payloads:            newPayloadReader(logger, ingestion),
 	}
 }
 
@@ -49,13 +51,6 @@ func (c *EventsServerController) Register(regular gin.IRouter, beacon gin.IRoute
 	regular.POST("/testImpressions/count", c.TestImpressionsCount)
 	regular.POST("/events/bulk", c.EventsBulk)
 
-	// dummy endpoints that just return 200
-	regular.POST("/metrics/times", c.DummyAlwaysOk)
-	regular.POST("/metrics/counters", c.DummyAlwaysOk)
-	regular.POST("/metrics/gauge", c.DummyAlwaysOk)
-	regular.POST("/metrics/time", c.DummyAlwaysOk)
-	regular.POST("/metrics/counter", c.DummyAlwaysOk)
-
 	// beacon endpoints
 	beacon.POST("/testImpressions/count/beacon", c.TestImpressionsCountBeacon)
 	beacon.POST("/testImpressions/beacon", c.TestImpressionsBeacon)
@@ -66,10 +61,9 @@ func (c *EventsServerController) Register(regular gin.IRouter, beacon gin.IRoute
 func (c *EventsServerController) TestImpressionsBulk(ctx *gin.Context) {
 	metadata := metadataFromHeaders(ctx)
 	impressionsMode := parseImpressionsMode(ctx.Request.Header.Get("SplitSDKImpressionsMode"))
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxImpressionsBodyBytes, validateImpressions)
 	if err != nil {
-		c.logger.Error(err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "testImpressions/bulk", metadata.SDKVersion, err)
 		return
 	}
 	if c.listener != nil {
@@ -99,10 +93,9 @@ func (c *EventsServerController) TestImpressionsBeacon(ctx *gin.Context) {
 		return
 	}
 
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxImpressionsBodyBytes, nil)
 	if err != nil {
-		c.logger.Error("Error reading testImpressions/beacon request body: ", err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "testImpressions/beacon", "", err)
 		return
 	}
 
@@ -119,6 +112,11 @@ func (c *EventsServerController) TestImpressionsBeacon(ctx *gin.Context) {
 		return
 	}
 
+	if err := c.payloads.validate(body.Entries, validateImpressions); err != nil {
+		c.payloads.handleError(ctx, "testImpressions/beacon", body.Sdk, err)
+		return
+	}
+
 	err = c.impressionsSink.Stage(internal.NewRawImpressions(dtos.Metadata{SDKVersion: body.Sdk, MachineIP: "NA", MachineName: "NA"}, "", body.Entries))
 	if err != nil {
 		if err == tasks.ErrQueueFull {
@@ -135,10 +133,9 @@ func (c *EventsServerController) TestImpressionsBeacon(ctx *gin.Context) {
 func (c *EventsServerController) TestImpressionsCount(ctx *gin.Context) {
 
 	metadata := metadataFromHeaders(ctx)
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxImpressionsBodyBytes, validateImpressionCounts)
 	if err != nil {
-		c.logger.Error("Error reading request body in testImpressions/count endpoint: ", err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "testImpressions/count", metadata.SDKVersion, err)
 		return
 	}
 
@@ -162,10 +159,9 @@ func (c *EventsServerController) TestImpressionsCountBeacon(ctx *gin.Context) {
 		return
 	}
 
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxImpressionsBodyBytes, nil)
 	if err != nil {
-		c.logger.Error(err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "testImpressions/count/beacon", "", err)
 		return
 	}
 
@@ -181,6 +177,11 @@ func (c *EventsServerController) TestImpressionsCountBeacon(ctx *gin.Context) {
 		return
 	}
 
+	if err := c.payloads.validate(body.Entries, validateImpressionCounts); err != nil {
+		c.payloads.handleError(ctx, "testImpressions/count/beacon", body.Sdk, err)
+		return
+	}
+
 	code := http.StatusNoContent
 
 	err = c.impressionCountSink.Stage(internal.NewRawImpressionCounts(dtos.Metadata{SDKVersion: body.Sdk, MachineIP: "NA", MachineName: "NA"}, body.Entries))
@@ -199,10 +200,9 @@ func (c *EventsServerController) TestImpressionsCountBeacon(ctx *gin.Context) {
 // EventsBulk accepts incoming event bulks
 func (c *EventsServerController) EventsBulk(ctx *gin.Context) {
 	metadata := metadataFromHeaders(ctx)
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxEventsBodyBytes, validateEvents)
 	if err != nil {
-		c.logger.Error("Error reading request body when accepting an event bulk: ", err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "events/bulk", metadata.SDKVersion, err)
 		return
 	}
 
@@ -225,10 +225,9 @@ func (c *EventsServerController) EventsBulkBeacon(ctx *gin.Context) {
 		return
 	}
 
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxEventsBodyBytes, nil)
 	if err != nil {
-		c.logger.Error(err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "events/beacon", "", err)
 		return
 	}
 
@@ -244,6 +243,11 @@ func (c *EventsServerController) EventsBulkBeacon(ctx *gin.Context) {
 		return
 	}
 
+	if err := c.payloads.validate(body.Entries, validateEvents); err != nil {
+		c.payloads.handleError(ctx, "events/beacon", body.Sdk, err)
+		return
+	}
+
 	err = c.eventsSink.Stage(internal.NewRawEvents(dtos.Metadata{SDKVersion: body.Sdk, MachineIP: "NA", MachineName: "NA"}, body.Entries))
 	if err != nil {
 		if err == tasks.ErrQueueFull {
@@ -256,10 +260,6 @@ func (c *EventsServerController) EventsBulkBeacon(ctx *gin.Context) {
 	ctx.JSON(http.StatusNoContent, nil)
 }
 
-// DummyAlwaysOk accepts anything and returns 200 without even reading the body
-// This is meant to be used with legacy telemetry endpoints
-func (c *EventsServerController) DummyAlwaysOk(ctx *gin.Context) {}
-
 func (c *EventsServerController) submitImpressionsToListener(raw []byte, metadata *dtos.Metadata) {
 	var parsed []dtos.ImpressionsDTO
 	err := json.Unmarshal(raw, &parsed)
@@ -80,6 +80,7 @@ func TestPostImpressionsbulk(t *testing.T) {
 			},
 		},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
@@ -154,6 +155,7 @@ func TestPostEventsBulk(t *testing.T) {
 		}, // events
 		&ilMock.ImpressionBulkListenerMock{},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
@@ -213,6 +215,7 @@ func TestPostImpressionsCounts(t *testing.T) {
 		&mocks.MockDeferredRecordingTask{}, // events
 		&ilMock.ImpressionBulkListenerMock{},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
@@ -239,76 +242,6 @@ func TestPostImpressionsCounts(t *testing.T) {
 	}
 }
 
-func TestPostLegacyMetrics(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	resp := httptest.NewRecorder()
-	ctx, router := gin.CreateTestContext(resp)
-
-	logger := logging.NewLogger(nil)
-	apikeyValidator := mw.NewAPIKeyValidator([]string{"someApiKey"})
-
-	group := router.Group("/api")
-	controller := NewEventsServerController(
-		logger,
-		&mocks.MockDeferredRecordingTask{}, // impssions
-		&mocks.MockDeferredRecordingTask{}, // imp counts
-		&mocks.MockDeferredRecordingTask{}, // events
-		&ilMock.ImpressionBulkListenerMock{},
-		apikeyValidator.IsValid,
-	)
-	controller.Register(group, group)
-
-	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/counter", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
-	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
-	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
-	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
-	router.ServeHTTP(resp, ctx.Request)
-	if resp.Code != 200 {
-		t.Error("Status code should be 200 and is ", resp.Code)
-	}
-
-	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/counters", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
-	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
-	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
-	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
-	router.ServeHTTP(resp, ctx.Request)
-	if resp.Code != 200 {
-		t.Error("Status code should be 200 and is ", resp.Code)
-	}
-
-	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/time", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
-	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
-	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
-	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
-	router.ServeHTTP(resp, ctx.Request)
-	if resp.Code != 200 {
-		t.Error("Status code should be 200 and is ", resp.Code)
-	}
-
-	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/times", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
-	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
-	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
-	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
-	router.ServeHTTP(resp, ctx.Request)
-	if resp.Code != 200 {
-		t.Error("Status code should be 200 and is ", resp.Code)
-	}
-
-	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/gauge", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
-	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
-	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
-	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
-	router.ServeHTTP(resp, ctx.Request)
-	if resp.Code != 200 {
-		t.Error("Status code should be 200 and is ", resp.Code)
-	}
-}
-
 func TestPostBeaconImpressionsbulk(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	resp := httptest.NewRecorder()
@@ -368,6 +301,7 @@ func TestPostBeaconImpressionsbulk(t *testing.T) {
 			},
 		},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
@@ -443,6 +377,7 @@ func TestPostBeaconEventsBulk(t *testing.T) {
 		}, // events
 		&ilMock.ImpressionBulkListenerMock{},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
@@ -503,6 +438,7 @@ func TestPostBeaconImpressionsCounts(t *testing.T) {
 		&mocks.MockDeferredRecordingTask{}, // events
 		&ilMock.ImpressionBulkListenerMock{},
 		apikeyValidator.IsValid,
+		nil,
 	)
 	controller.Register(group, group)
 
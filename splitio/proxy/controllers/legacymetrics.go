@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/telemetry"
+)
+
+// Payloads posted by sdks predating the telemetry api to /metrics/time(s), /metrics/counter(s) & /metrics/gauge
+type legacyLatencies struct {
+	Name      string  `json:"name"`
+	Latencies []int64 `json:"latencies"`
+}
+
+type legacyCounter struct {
+	Name  string `json:"name"`
+	Delta int64  `json:"delta"`
+}
+
+type legacyGauge struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// legacyMetricsTranslator parses a legacy payload & maps its metrics onto a telemetry stats object.
+// Metrics without a counterpart in the telemetry model are skipped
+type legacyMetricsTranslator func(data []byte) (*dtos.Stats, error)
+
+// Backend resources as referred to by the telemetry model
+const (
+	legacyResourceSplits = iota + 1
+	legacyResourceSegments
+	legacyResourceImpressions
+	legacyResourceImpressionsCount
+	legacyResourceEvents
+)
+
+// decodeLegacyMetrics parses a payload containing either a single metric or a list of them
+func decodeLegacyMetrics(data []byte, single interface{}, list interface{}) (bool, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return false, json.Unmarshal(trimmed, list)
+	}
+	return true, json.Unmarshal(data, single)
+}
+
+func translateLegacyLatencies(data []byte) (*dtos.Stats, error) {
+	var single legacyLatencies
+	var list []legacyLatencies
+	isSingle, err := decodeLegacyMetrics(data, &single, &list)
+	if err != nil {
+		return nil, err
+	}
+	if isSingle {
+		list = []legacyLatencies{single}
+	}
+
+	var methods dtos.MethodLatencies
+	var requests dtos.HTTPLatencies
+	var hasMethods, hasRequests bool
+	for _, metric := range list {
+		if target := methodLatencies(&methods, metric.Name); target != nil {
+			*target = addLatencies(*target, metric.Latencies)
+			hasMethods = true
+		} else if target := httpLatencies(&requests, legacyResource(strings.TrimSuffix(metric.Name, ".time"))); target != nil {
+			*target = addLatencies(*target, metric.Latencies)
+			hasRequests = true
+		}
+	}
+
+	stats := &dtos.Stats{}
+	if hasMethods {
+		stats.MethodLatencies = &methods
+	}
+	if hasRequests {
+		stats.HTTPLatencies = &requests
+	}
+	return stats, nil
+}
+
+func translateLegacyCounters(data []byte) (*dtos.Stats, error) {
+	var single legacyCounter
+	var list []legacyCounter
+	isSingle, err := decodeLegacyMetrics(data, &single, &list)
+	if err != nil {
+		return nil, err
+	}
+	if isSingle {
+		list = []legacyCounter{single}
+	}
+
+	var exceptions dtos.MethodExceptions
+	var failures dtos.HTTPErrors
+	var hasExceptions, hasErrors bool
+	for _, metric := range list {
+		if target := methodExceptions(&exceptions, metric.Name); target != nil {
+			*target += metric.Delta
+			hasExceptions = true
+			continue
+		}
+
+		resource, code, ok := parseLegacyStatusCounter(metric.Name)
+		if !ok || (code >= 200 && code < 300) { // successful requests are not tracked by the telemetry model
+			continue
+		}
+		if target := httpErrors(&failures, resource); target != nil {
+			if *target == nil {
+				*target = make(map[int]int64)
+			}
+			(*target)[code] += metric.Delta
+			hasErrors = true
+		}
+	}
+
+	stats := &dtos.Stats{}
+	if hasExceptions {
+		stats.MethodExceptions = &exceptions
+	}
+	if hasErrors {
+		stats.HTTPErrors = &failures
+	}
+	return stats, nil
+}
+
+// translateLegacyGauge only checks that the payload is well formed, since gauges have no counterpart in the telemetry model
+func translateLegacyGauge(data []byte) (*dtos.Stats, error) {
+	var gauge legacyGauge
+	if err := json.Unmarshal(data, &gauge); err != nil {
+		return nil, err
+	}
+	return &dtos.Stats{}, nil
+}
+
+func isEmptyStats(stats *dtos.Stats) bool {
+	return stats.MethodLatencies == nil && stats.HTTPLatencies == nil && stats.MethodExceptions == nil && stats.HTTPErrors == nil
+}
+
+// methodLatencies returns the latencies matching a legacy `sdk.<method>` metric, or nil if there's none
+func methodLatencies(latencies *dtos.MethodLatencies, name string) *[]int64 {
+	switch name {
+	case "sdk.getTreatment":
+		return &latencies.Treatment
+	case "sdk.getTreatments":
+		return &latencies.Treatments
+	case "sdk.getTreatmentWithConfig":
+		return &latencies.TreatmentWithConfig
+	case "sdk.getTreatmentsWithConfig":
+		return &latencies.TreatmentsWithConfig
+	case "sdk.track":
+		return &latencies.Track
+	}
+	return nil
+}
+
+// methodExceptions returns the counter matching a legacy `sdk.<method>.exception` metric, or nil if there's none
+func methodExceptions(exceptions *dtos.MethodExceptions, name string) *int64 {
+	switch name {
+	case "sdk.getTreatment.exception":
+		return &exceptions.Treatment
+	case "sdk.getTreatments.exception":
+		return &exceptions.Treatments
+	case "sdk.getTreatmentWithConfig.exception":
+		return &exceptions.TreatmentWithConfig
+	case "sdk.getTreatmentsWithConfig.exception":
+		return &exceptions.TreatmentsWithConfig
+	case "sdk.track.exception":
+		return &exceptions.Track
+	}
+	return nil
+}
+
+func httpLatencies(latencies *dtos.HTTPLatencies, resource int) *[]int64 {
+	switch resource {
+	case legacyResourceSplits:
+		return &latencies.Splits
+	case legacyResourceSegments:
+		return &latencies.Segments
+	case legacyResourceImpressions:
+		return &latencies.Impressions
+	case legacyResourceImpressionsCount:
+		return &latencies.ImpressionsCount
+	case legacyResourceEvents:
+		return &latencies.Events
+	}
+	return nil
+}
+
+func httpErrors(failures *dtos.HTTPErrors, resource int) *map[int]int64 {
+	switch resource {
+	case legacyResourceSplits:
+		return &failures.Splits
+	case legacyResourceSegments:
+		return &failures.Segments
+	case legacyResourceImpressions:
+		return &failures.Impressions
+	case legacyResourceImpressionsCount:
+		return &failures.ImpressionsCount
+	case legacyResourceEvents:
+		return &failures.Events
+	}
+	return nil
+}
+
+// parseLegacyStatusCounter splits a `<fetcher>.status.<code>` counter name into its resource & status code
+func parseLegacyStatusCounter(name string) (int, int, bool) {
+	idx := strings.LastIndex(name, ".status.")
+	if idx == -1 {
+		return 0, 0, false
+	}
+
+	code, err := strconv.Atoi(name[idx+len(".status."):])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	resource := legacyResource(name[:idx])
+	return resource, code, resource != 0
+}
+
+// legacyResource identifies the backend resource a legacy http metric refers to. Both the fetcher names used by most
+// sdks and the `backend::<path>` ones used by older go sdks are supported. Zero is returned for unknown names
+func legacyResource(name string) int {
+	switch {
+	case name == "splitChangeFetcher" || strings.HasPrefix(name, "backend::/api/splitChanges"):
+		return legacyResourceSplits
+	case name == "segmentChangeFetcher" || name == "mySegmentsFetcher" ||
+		strings.HasPrefix(name, "backend::/api/segmentChanges") || strings.HasPrefix(name, "backend::/api/mySegments"):
+		return legacyResourceSegments
+	case name == "testImpressions" || strings.HasPrefix(name, "backend::/api/testImpressions/bulk"):
+		return legacyResourceImpressions
+	case name == "impressionsCount" || strings.HasPrefix(name, "backend::/api/testImpressions/count"):
+		return legacyResourceImpressionsCount
+	case name == "events" || strings.HasPrefix(name, "backend::/api/events"):
+		return legacyResourceEvents
+	}
+	return 0
+}
+
+// addLatencies accumulates legacy latency buckets, which share boundaries with the telemetry ones
+func addLatencies(current []int64, latencies []int64) []int64 {
+	if current == nil {
+		current = make([]int64, telemetry.LatencyBucketCount)
+	}
+	for idx, count := range latencies {
+		if idx >= len(current) { // extra buckets are folded into the last one
+			idx = len(current) - 1
+		}
+		current[idx] += count
+	}
+	return current
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"testing"
+)
+
+func TestTranslateLegacyLatencies(t *testing.T) {
+	stats, err := translateLegacyLatencies([]byte(`[
+		{"name":"sdk.getTreatment","latencies":[1,0,2]},
+		{"name":"sdk.getTreatment","latencies":[1]},
+		{"name":"splitChangeFetcher.time","latencies":[0,3]},
+		{"name":"backend::/api/segmentChanges/segment1","latencies":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,1,1]},
+		{"name":"unknown.time","latencies":[5]}
+	]`))
+	if err != nil {
+		t.Error("no error expected: ", err)
+	}
+
+	if treatment := stats.MethodLatencies.Treatment; len(treatment) != 23 || treatment[0] != 2 || treatment[2] != 2 {
+		t.Error("wrong treatment latencies: ", treatment)
+	}
+
+	if stats.MethodLatencies.Treatments != nil || stats.HTTPLatencies.Splits[1] != 3 {
+		t.Error("wrong latencies: ", stats.MethodLatencies, stats.HTTPLatencies)
+	}
+
+	if segments := stats.HTTPLatencies.Segments; segments[22] != 2 {
+		t.Error("extra buckets should be folded into the last one: ", segments)
+	}
+
+	single, err := translateLegacyLatencies([]byte(`{"name":"sdk.track","latencies":[0,1]}`))
+	if err != nil || single.MethodLatencies.Track[1] != 1 || single.HTTPLatencies != nil {
+		t.Error("single metric payloads should be supported: ", err, single)
+	}
+
+	if _, err := translateLegacyLatencies([]byte(`{"name":`)); err == nil {
+		t.Error("invalid payloads should fail")
+	}
+}
+
+func TestTranslateLegacyCounters(t *testing.T) {
+	stats, err := translateLegacyCounters([]byte(`[
+		{"name":"splitChangeFetcher.status.500","delta":2},
+		{"name":"splitChangeFetcher.status.500","delta":1},
+		{"name":"splitChangeFetcher.status.200","delta":10},
+		{"name":"testImpressions.status.400","delta":1},
+		{"name":"sdk.getTreatments.exception","delta":4},
+		{"name":"splitChangeFetcher.exception","delta":4}
+	]`))
+	if err != nil {
+		t.Error("no error expected: ", err)
+	}
+
+	if stats.HTTPErrors.Splits[500] != 3 || len(stats.HTTPErrors.Splits) != 1 || stats.HTTPErrors.Impressions[400] != 1 {
+		t.Error("wrong http errors: ", stats.HTTPErrors)
+	}
+
+	if stats.MethodExceptions.Treatments != 4 || stats.MethodExceptions.Treatment != 0 {
+		t.Error("wrong method exceptions: ", stats.MethodExceptions)
+	}
+
+	empty, err := translateLegacyCounters([]byte(`{"name":"segmentChangeFetcher.status.200","delta":1}`))
+	if err != nil || !isEmptyStats(empty) {
+		t.Error("successful requests should not be translated: ", err, empty)
+	}
+}
@@ -4,28 +4,33 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
 )
 
 // APIKeyValidator is a small component that validates apikeys
 type APIKeyValidator struct {
-	apikeys map[string]struct{}
+	keys *apikeys.Manager
 }
 
-// NewAPIKeyValidator instantiates an apikey validation component
-func NewAPIKeyValidator(apikeys []string) *APIKeyValidator {
-	toRet := &APIKeyValidator{apikeys: make(map[string]struct{})}
-
-	for _, key := range apikeys {
-		toRet.apikeys[key] = struct{}{}
-	}
+// NewAPIKeyValidator instantiates an apikey validation component for a fixed set of keys
+func NewAPIKeyValidator(keys []string) *APIKeyValidator {
+	return NewManagedAPIKeyValidator(apikeys.NewManager(keys, nil, nil))
+}
 
-	return toRet
+// NewManagedAPIKeyValidator instantiates an apikey validation component that picks up keys added or revoked at runtime
+func NewManagedAPIKeyValidator(manager *apikeys.Manager) *APIKeyValidator {
+	return &APIKeyValidator{keys: manager}
 }
 
-// IsValid checks if an apikey is valid
+// IsValid checks if an apikey is valid & records its usage
 func (v *APIKeyValidator) IsValid(apikey string) bool {
-	_, ok := v.apikeys[apikey]
-	return ok
+	return v.keys.IsValid(apikey)
+}
+
+// Exists checks if an apikey is valid without recording its usage
+func (v *APIKeyValidator) Exists(apikey string) bool {
+	return v.keys.Exists(apikey)
 }
 
 // AsMiddleware is a function to be used as a gin middleware
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalRequests answers requests carrying an If-None-Match header with a 304 when the response ETag matches,
+// and sets Cache-Control headers on successful responses. It must be registered before the caching middleware
+// so that ETags restored from cached entries are taken into account
+type ConditionalRequests struct {
+	cacheControl map[int]string
+}
+
+// NewConditionalRequests instantiates a conditional request middleware. cacheControl maps endpoints to the
+// Cache-Control header to send along with their successful responses
+func NewConditionalRequests(cacheControl map[int]string) *ConditionalRequests {
+	return &ConditionalRequests{cacheControl: cacheControl}
+}
+
+// Handle is the function to be invoked for every request being handled
+func (c *ConditionalRequests) Handle(ctx *gin.Context) {
+	if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+		return
+	}
+
+	var cacheControl string
+	if endpoint, ok := ctx.Get(EndpointKey); ok {
+		if asInt, ok := endpoint.(int); ok {
+			cacheControl = c.cacheControl[asInt]
+		}
+	}
+
+	ctx.Writer = &conditionalWriter{
+		ResponseWriter: ctx.Writer,
+		ifNoneMatch:    ctx.Request.Header.Get("If-None-Match"),
+		cacheControl:   cacheControl,
+	}
+}
+
+type conditionalWriter struct {
+	gin.ResponseWriter
+	ifNoneMatch  string
+	cacheControl string
+	notModified  bool
+}
+
+func (w *conditionalWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		if w.cacheControl != "" {
+			w.Header().Set("Cache-Control", w.cacheControl)
+		}
+
+		if etag := w.Header().Get("ETag"); etag != "" && etagMatches(w.ifNoneMatch, etag) {
+			code = http.StatusNotModified
+			w.notModified = true
+			w.Header().Del("Content-Length")
+			w.Header().Del("Content-Type")
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *conditionalWriter) Write(data []byte) (int, error) {
+	if w.notModified {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *conditionalWriter) WriteString(data string) (int, error) {
+	if w.notModified {
+		return len(data), nil
+	}
+	return w.ResponseWriter.WriteString(data)
+}
+
+// etagMatches performs the weak comparison required for If-None-Match
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	calls := 0
+	router.Use(SetEndpoint)
+	router.Use(NewConditionalRequests(map[int]string{storage.SplitChangesEndpoint: "public, max-age=10"}).Handle)
+	router.Use(caching.MakeProxyCache(1000, 0).Handle)
+	router.GET("/api/splitChanges", func(ctx *gin.Context) {
+		calls++
+		ctx.Header("ETag", `"tag1"`)
+		ctx.JSON(200, gin.H{"till": 1})
+	})
+	router.GET("/api/mySegments/:key", func(ctx *gin.Context) { ctx.JSON(200, gin.H{}) })
+
+	get := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// not cached yet, tag matches
+	resp := get("/api/splitChanges", `"tag1"`)
+	if resp.Code != 304 || resp.Body.Len() != 0 {
+		t.Error("a 304 with no body should be returned. Got: ", resp.Code, resp.Body.String())
+	}
+	if resp.Header().Get("ETag") != `"tag1"` || resp.Header().Get("Cache-Control") != "public, max-age=10" {
+		t.Error("ETag & Cache-Control should be sent along with the 304. Got: ", resp.Header())
+	}
+
+	// cached, tag doesn't match
+	resp = get("/api/splitChanges", `"other"`)
+	if resp.Code != 200 || resp.Body.String() != `{"till":1}` || resp.Header().Get("ETag") != `"tag1"` {
+		t.Error("the full response should be returned. Got: ", resp.Code, resp.Body.String(), resp.Header())
+	}
+
+	// cached, tag matches
+	resp = get("/api/splitChanges", `"other", W/"tag1"`)
+	if resp.Code != 304 || resp.Body.Len() != 0 {
+		t.Error("a 304 should be returned for cached entries. Got: ", resp.Code, resp.Body.String())
+	}
+
+	if calls != 1 {
+		t.Error("the handler should have been called once. Got: ", calls)
+	}
+
+	resp = get("/api/mySegments/key1", "*")
+	if resp.Code != 200 || resp.Header().Get("Cache-Control") != "" {
+		t.Error("responses without an ETag should not be affected. Got: ", resp.Code, resp.Header())
+	}
+}
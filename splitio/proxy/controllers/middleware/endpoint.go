@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,7 @@ const (
 	pathSplitChanges           = "/api/splitChanges"
 	pathSegmentChanges         = "/api/segmentChanges"
 	pathMySegments             = "/api/mySegments"
+	pathMySegmentsBulk         = "/api/mySegments/bulk"
 	pathImpressionsBulk        = "/api/testImpressions/bulk"
 	pathImpressionsCount       = "/api/testImpressions/count"
 	pathImpressionsBulkBeacon  = "/api/testImpressions/beacon"
@@ -23,6 +25,11 @@ const (
 	pathEventsBeacon           = "/api/events/beacon"
 	pathTelemetryConfig        = "/api/metrics/config"
 	pathTelemetryUsage         = "/api/metrics/usage"
+	pathLegacyTime             = "/api/metrics/time"
+	pathLegacyTimes            = "/api/metrics/times"
+	pathLegacyCounter          = "/api/metrics/counter"
+	pathLegacyCounters         = "/api/metrics/counters"
+	pathLegacyGauge            = "/api/metrics/gauge"
 	pathAuth                   = "/api/auth"
 	pathAuthV2                 = "/api/auth/v2"
 )
@@ -48,8 +55,24 @@ func SetEndpoint(ctx *gin.Context) {
 		ctx.Set(EndpointKey, storage.TelemetryConfigEndpoint)
 	case pathTelemetryUsage:
 		ctx.Set(EndpointKey, storage.TelemetryRuntimeEndpoint)
+	case pathLegacyTime:
+		ctx.Set(EndpointKey, storage.LegacyTimeEndpoint)
+	case pathLegacyTimes:
+		ctx.Set(EndpointKey, storage.LegacyTimesEndpoint)
+	case pathLegacyCounter:
+		ctx.Set(EndpointKey, storage.LegacyCounterEndpoint)
+	case pathLegacyCounters:
+		ctx.Set(EndpointKey, storage.LegacyCountersEndpoint)
+	case pathLegacyGauge:
+		ctx.Set(EndpointKey, storage.LegacyGaugeEndpoint)
 	case pathAuth, pathAuthV2:
 		ctx.Set(EndpointKey, storage.AuthEndpoint)
+	case pathMySegmentsBulk:
+		if ctx.Request.Method == http.MethodPost {
+			ctx.Set(EndpointKey, storage.MySegmentsBulkEndpoint)
+		} else { // GET for a key named "bulk"
+			ctx.Set(EndpointKey, storage.MySegmentsEndpoint)
+		}
 	default:
 		if strings.HasPrefix(path, pathSplitChanges) {
 			ctx.Set(EndpointKey, storage.SplitChangesEndpoint)
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/conf"
+	"github.com/splitio/go-split-commons/v4/dtos"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/ratelimit"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// FleetTracker records every request coming from an identifiable sdk instance in the fleet inventory.
+// It must be registered after `SetEndpoint`, since the kind of activity is derived from it, and after the apikey validator
+type FleetTracker struct {
+	inventory *fleet.Inventory
+}
+
+// NewFleetTracker instantiates a new fleet tracking middleware
+func NewFleetTracker(inventory *fleet.Inventory) *FleetTracker {
+	return &FleetTracker{inventory: inventory}
+}
+
+// Handle is the function to be invoked for every request being handled
+func (f *FleetTracker) Handle(ctx *gin.Context) {
+	metadata := dtos.Metadata{
+		SDKVersion:  ctx.Request.Header.Get("SplitSDKVersion"),
+		MachineIP:   ctx.Request.Header.Get("SplitSDKMachineIP"),
+		MachineName: ctx.Request.Header.Get("SplitSDKMachineName"),
+	}
+	if metadata.SDKVersion == "" { // beacons & unidentified clients
+		return
+	}
+
+	endpoint, _ := ctx.Get(EndpointKey)
+	asInt, ok := endpoint.(int)
+	if !ok {
+		return
+	}
+
+	f.inventory.Track(metadata, activityFor(asInt), 1)
+	if asInt == storage.ImpressionsBulkEndpoint {
+		switch mode := strings.ToLower(ctx.Request.Header.Get("SplitSDKImpressionsMode")); mode {
+		case conf.ImpressionsModeOptimized, conf.ImpressionsModeDebug:
+			f.inventory.TrackImpressionsMode(metadata, mode)
+		}
+	}
+}
+
+func activityFor(endpoint int) string {
+	switch endpoint {
+	case storage.ImpressionsBulkEndpoint, storage.ImpressionsCountEndpoint, storage.ImpressionsBulkBeaconEndpoint,
+		storage.ImpressionsCountBeaconEndpoint:
+		return fleet.ActivityImpressions
+	case storage.EventsBulkEndpoint, storage.EventsBulkBeaconEndpoint:
+		return fleet.ActivityEvents
+	}
+
+	if groupFor(endpoint) == ratelimit.GroupTelemetry {
+		return fleet.ActivityTelemetry
+	}
+	return fleet.ActivitySDK
+}
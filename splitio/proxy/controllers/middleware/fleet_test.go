@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
+)
+
+func TestFleetTracker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	inventory := fleet.NewInventory(fleet.Config{MinVersions: map[string]string{"go": "6.0.0"}})
+	router.Use(SetEndpoint)
+	router.Use(NewFleetTracker(inventory).Handle)
+	router.GET("/api/splitChanges", func(ctx *gin.Context) { ctx.String(200, "ok") })
+	router.POST("/api/testImpressions/bulk", func(ctx *gin.Context) { ctx.String(200, "ok") })
+	router.POST("/api/events/beacon", func(ctx *gin.Context) { ctx.String(200, "ok") })
+	router.POST("/api/metrics/usage", func(ctx *gin.Context) { ctx.String(200, "ok") })
+
+	doRequest := func(method string, path string, headers map[string]string) {
+		req, _ := http.NewRequest(method, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	identified := map[string]string{"SplitSDKVersion": "go-5.1.0", "SplitSDKMachineIP": "1.2.3.4", "SplitSDKMachineName": "ip-1-2-3-4"}
+	doRequest("GET", "/api/splitChanges?since=-1", identified)
+	doRequest("GET", "/api/splitChanges?since=-1", identified)
+	doRequest("POST", "/api/testImpressions/bulk", map[string]string{
+		"SplitSDKVersion":         "go-5.1.0",
+		"SplitSDKMachineIP":       "1.2.3.4",
+		"SplitSDKMachineName":     "ip-1-2-3-4",
+		"SplitSDKImpressionsMode": "optimized",
+	})
+	doRequest("POST", "/api/metrics/usage", identified)
+	doRequest("POST", "/api/events/beacon", nil)
+
+	summary := inventory.Summary()
+	if len(summary.Instances) != 1 {
+		t.Error("only the identified instance should be tracked. Got: ", summary.Instances)
+		return
+	}
+
+	instance := summary.Instances[0]
+	if instance.Activity[fleet.ActivitySDK] != 2 || instance.Activity[fleet.ActivityImpressions] != 1 || instance.Activity[fleet.ActivityTelemetry] != 1 {
+		t.Error("wrong activity: ", instance.Activity)
+	}
+
+	if instance.ImpressionsMode != "optimized" || !instance.Outdated || instance.MachineIP != "1.2.3.4" {
+		t.Error("wrong instance: ", instance)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/ratelimit"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// RateLimiter rejects requests exceeding the configured limits with a 429 & a Retry-After header.
+// It must be registered after `SetEndpoint`, since the endpoint group is derived from it
+type RateLimiter struct {
+	limiter           *ratelimit.Limiter
+	isValidAPIKey     func(string) bool
+	trustForwardedFor bool
+	telemetry         storage.ProxyEndpointTelemetry
+}
+
+// NewRateLimiter instantiates a rate limiting middleware. Only valid apikeys are tracked individually,
+// to avoid creating buckets for arbitrary tokens
+func NewRateLimiter(
+	limiter *ratelimit.Limiter,
+	isValidAPIKey func(string) bool,
+	trustForwardedFor bool,
+	telemetry storage.ProxyEndpointTelemetry,
+) *RateLimiter {
+	return &RateLimiter{limiter: limiter, isValidAPIKey: isValidAPIKey, trustForwardedFor: trustForwardedFor, telemetry: telemetry}
+}
+
+// Handle is the function to be invoked for every request being handled
+func (r *RateLimiter) Handle(ctx *gin.Context) {
+	endpoint, _ := ctx.Get(EndpointKey)
+	asInt, ok := endpoint.(int)
+	if !ok {
+		return
+	}
+
+	result := r.limiter.Allow(&ratelimit.Request{
+		Group:    groupFor(asInt),
+		APIKey:   r.apikey(ctx),
+		ClientIP: r.clientIP(ctx),
+	})
+	if result.Allowed {
+		return
+	}
+
+	r.telemetry.IncrRateLimited(result.Rule)
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	ctx.AbortWithStatus(429)
+}
+
+func (r *RateLimiter) apikey(ctx *gin.Context) string {
+	auth := strings.Split(ctx.Request.Header.Get("Authorization"), " ")
+	if len(auth) != 2 || auth[0] != "Bearer" || !r.isValidAPIKey(auth[1]) {
+		return ""
+	}
+	return auth[1]
+}
+
+func (r *RateLimiter) clientIP(ctx *gin.Context) string {
+	if r.trustForwardedFor {
+		return ctx.ClientIP()
+	}
+
+	host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		return ctx.Request.RemoteAddr // unix sockets
+	}
+	return host
+}
+
+func groupFor(endpoint int) string {
+	switch endpoint {
+	case storage.AuthEndpoint, storage.SplitChangesEndpoint, storage.SegmentChangesEndpoint, storage.MySegmentsEndpoint,
+		storage.MySegmentsBulkEndpoint:
+		return ratelimit.GroupSDK
+	case storage.TelemetryConfigEndpoint, storage.TelemetryRuntimeEndpoint, storage.LegacyTimeEndpoint, storage.LegacyTimesEndpoint,
+		storage.LegacyCounterEndpoint, storage.LegacyCountersEndpoint, storage.LegacyGaugeEndpoint:
+		return ratelimit.GroupTelemetry
+	}
+	return ratelimit.GroupEvents
+}
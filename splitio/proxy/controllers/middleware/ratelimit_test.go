@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/ratelimit"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	tStorage := storage.NewProxyTelemetryFacade()
+	limiter := ratelimit.NewLimiter([]ratelimit.Rule{{Group: ratelimit.GroupSDK, Scope: ratelimit.ScopeAPIKey, Rate: 0.5, Burst: 1}}, 0)
+	validator := NewAPIKeyValidator([]string{"someApikey"})
+	router.Use(SetEndpoint)
+	router.Use(NewProxyMetricsMiddleware(tStorage).Track)
+	router.Use(NewRateLimiter(limiter, validator.Exists, false, tStorage).Handle)
+	router.GET("/api/splitChanges", func(ctx *gin.Context) { ctx.String(200, "ok") })
+	router.POST("/api/events/bulk", func(ctx *gin.Context) { ctx.String(202, "ok") })
+
+	doRequest := func(method string, path string, apikey string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("Authorization", "Bearer "+apikey)
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := doRequest("GET", "/api/splitChanges", "someApikey"); resp.Code != 200 {
+		t.Error("first request should succeed. got: ", resp.Code)
+	}
+
+	resp := doRequest("GET", "/api/splitChanges", "someApikey")
+	if resp.Code != 429 {
+		t.Error("second request should be rate limited. got: ", resp.Code)
+	}
+	if ra := resp.Header().Get("Retry-After"); ra != "2" {
+		t.Error("wrong Retry-After header: ", ra)
+	}
+
+	if resp := doRequest("GET", "/api/splitChanges", "invalidApikey"); resp.Code != 200 {
+		t.Error("invalid apikeys should not be tracked. got: ", resp.Code)
+	}
+
+	if resp := doRequest("POST", "/api/events/bulk", "someApikey"); resp.Code != 202 {
+		t.Error("event ingestion should not be limited by sdk rules. got: ", resp.Code)
+	}
+
+	if c := tStorage.PeekRateLimited()["sdk:apikey:0.5:1"]; c != 1 {
+		t.Error("there should be 1 rate-limited request. got: ", c)
+	}
+	if c := tStorage.PeekEndpointStatus(storage.SplitChangesEndpoint)[429]; c != 1 {
+		t.Error("429 should be tracked for splitChanges. got: ", c)
+	}
+}
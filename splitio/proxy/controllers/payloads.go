@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// IngestionOptions bundles the limits & checks applied to incoming impressions, events & telemetry payloads
+type IngestionOptions struct {
+	// Max size of a (decompressed) request body. Zero means no limit
+	MaxImpressionsBodyBytes int64
+	MaxEventsBodyBytes      int64
+	MaxTelemetryBodyBytes   int64
+
+	// Whether to check that payloads are well formed before accepting them
+	ValidatePayloads bool
+
+	// used to keep track of rejected payloads & legacy metrics calls by sdk version. Nothing is tracked if nil
+	Telemetry storage.ProxyEndpointTelemetry
+}
+
+// payloadError is returned when a payload is rejected because of the client's fault
+type payloadError struct {
+	status int
+	reason string
+}
+
+func (e *payloadError) Error() string { return e.reason }
+
+func rejected(status int, format string, args ...interface{}) *payloadError {
+	return &payloadError{status: status, reason: fmt.Sprintf(format, args...)}
+}
+
+type payloadValidator func(data []byte) error
+
+// payloadReader reads request bodies, decompressing them if needed & enforcing size limits & validation
+type payloadReader struct {
+	logger  logging.LoggerInterface
+	options IngestionOptions
+}
+
+func newPayloadReader(logger logging.LoggerInterface, options *IngestionOptions) *payloadReader {
+	reader := &payloadReader{logger: logger}
+	if options != nil {
+		reader.options = *options
+	}
+	return reader
+}
+
+// read returns the (decompressed) body of a request. If validation is enabled & a validator is supplied, the payload
+// is checked as well
+func (p *payloadReader) read(request *http.Request, limit int64, validator payloadValidator) ([]byte, error) {
+	if request.Body == nil {
+		return nil, rejected(http.StatusBadRequest, "empty body")
+	}
+
+	var reader io.Reader = request.Body
+	if limit > 0 {
+		reader = &limitedReader{reader: reader, remaining: limit}
+	}
+
+	gzipped := strings.EqualFold(request.Header.Get("Content-Encoding"), "gzip")
+	if gzipped {
+		decompressor, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, asPayloadError(err, true)
+		}
+		defer decompressor.Close()
+		reader = decompressor
+		if limit > 0 {
+			reader = &limitedReader{reader: reader, remaining: limit}
+		}
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, asPayloadError(err, gzipped)
+	}
+
+	if p.options.ValidatePayloads && validator != nil {
+		if err := validator(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// validate checks a payload already extracted from a request (ie: beacon entries)
+func (p *payloadReader) validate(data []byte, validator payloadValidator) error {
+	if !p.options.ValidatePayloads {
+		return nil
+	}
+	return validator(data)
+}
+
+// handleError writes the appropriate response for an error returned when reading a payload.
+// Rejections caused by the client are logged & counted by sdk version
+func (p *payloadReader) handleError(ctx *gin.Context, endpoint string, sdkVersion string, err error) {
+	var rejection *payloadError
+	if !errors.As(err, &rejection) {
+		p.logger.Error(fmt.Sprintf("error reading %s request body: %s", endpoint, err))
+		ctx.JSON(http.StatusInternalServerError, nil)
+		return
+	}
+
+	if sdkVersion == "" {
+		sdkVersion = "unknown"
+	}
+	p.logger.Warning(fmt.Sprintf("rejected %s payload from sdk '%s': %s", endpoint, sdkVersion, rejection.reason))
+	if p.options.Telemetry != nil {
+		p.options.Telemetry.IncrRejectedPayload(sdkVersion)
+	}
+	ctx.AbortWithStatusJSON(rejection.status, gin.H{"error": rejection.reason})
+}
+
+var errBodyTooLarge = errors.New("body too large")
+
+// limitedReader fails with errBodyTooLarge instead of silently truncating the body when the limit is exceeded
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(data []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(data)) > l.remaining+1 { // read one more byte than allowed to detect oversized bodies
+		data = data[:l.remaining+1]
+	}
+	n, err := l.reader.Read(data)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errBodyTooLarge
+	}
+	return n, err
+}
+
+// asPayloadError turns errors caused by oversized or corrupt bodies into rejections. Other errors are returned as is
+func asPayloadError(err error, gzipped bool) error {
+	if errors.Is(err, errBodyTooLarge) {
+		return rejected(http.StatusRequestEntityTooLarge, "body exceeds the max allowed size")
+	}
+
+	if !gzipped {
+		return err
+	}
+
+	var corrupt flate.CorruptInputError
+	if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &corrupt) {
+		return rejected(http.StatusBadRequest, "invalid gzip body: %s", err)
+	}
+	return err
+}
+
+// decodePayload parses a json payload, turning syntax & type errors into rejections
+func decodePayload(data []byte, target interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return rejected(http.StatusBadRequest, "empty payload")
+	}
+
+	err := json.Unmarshal(data, target)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return rejected(http.StatusBadRequest, "invalid json at offset %d: %s", syntaxErr.Offset, syntaxErr)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "payload"
+		}
+		return rejected(http.StatusBadRequest, "invalid value for %s: expected %s, got %s", field, typeErr.Type, typeErr.Value)
+	}
+	return rejected(http.StatusBadRequest, "invalid json: %s", err)
+}
+
+func validateImpressions(data []byte) error {
+	var bulk []dtos.ImpressionsDTO
+	if err := decodePayload(data, &bulk); err != nil {
+		return err
+	}
+
+	for idx, group := range bulk {
+		if group.TestName == "" {
+			return rejected(http.StatusBadRequest, "[%d].f: missing feature name", idx)
+		}
+		for kidx, impression := range group.KeyImpressions {
+			switch {
+			case impression.KeyName == "":
+				return rejected(http.StatusBadRequest, "[%d].i[%d].k: missing key", idx, kidx)
+			case impression.Treatment == "":
+				return rejected(http.StatusBadRequest, "[%d].i[%d].t: missing treatment", idx, kidx)
+			case impression.Time <= 0:
+				return rejected(http.StatusBadRequest, "[%d].i[%d].m: invalid timestamp %d", idx, kidx, impression.Time)
+			}
+		}
+	}
+	return nil
+}
+
+func validateImpressionCounts(data []byte) error {
+	var counts dtos.ImpressionsCountDTO
+	if err := decodePayload(data, &counts); err != nil {
+		return err
+	}
+
+	for idx, count := range counts.PerFeature {
+		switch {
+		case count.FeatureName == "":
+			return rejected(http.StatusBadRequest, "pf[%d].f: missing feature name", idx)
+		case count.TimeFrame <= 0:
+			return rejected(http.StatusBadRequest, "pf[%d].m: invalid time frame %d", idx, count.TimeFrame)
+		case count.RawCount < 0:
+			return rejected(http.StatusBadRequest, "pf[%d].rc: invalid count %d", idx, count.RawCount)
+		}
+	}
+	return nil
+}
+
+func validateEvents(data []byte) error {
+	var events []dtos.EventDTO
+	if err := decodePayload(data, &events); err != nil {
+		return err
+	}
+
+	for idx, event := range events {
+		switch {
+		case event.Key == "":
+			return rejected(http.StatusBadRequest, "[%d].key: missing key", idx)
+		case event.TrafficTypeName == "":
+			return rejected(http.StatusBadRequest, "[%d].trafficTypeName: missing traffic type", idx)
+		case event.EventTypeID == "":
+			return rejected(http.StatusBadRequest, "[%d].eventTypeId: missing event type", idx)
+		case event.Timestamp <= 0:
+			return rejected(http.StatusBadRequest, "[%d].timestamp: invalid timestamp %d", idx, event.Timestamp)
+		}
+	}
+	return nil
+}
+
+func validateTelemetryConfig(data []byte) error {
+	var config dtos.Config
+	return decodePayload(data, &config)
+}
+
+func validateTelemetryUsage(data []byte) error {
+	var stats dtos.Stats
+	return decodePayload(data, &stats)
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/internal"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/tasks/mocks"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Error("error compressing payload: ", err)
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func TestIngestionLimitsAndValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logging.NewLogger(nil)
+	telemetry := storage.NewProxyTelemetryFacade()
+
+	var staged [][]byte
+	sink := &mocks.MockDeferredRecordingTask{
+		StageCall: func(raw interface{}) error {
+			switch data := raw.(type) {
+			case *internal.RawImpressions:
+				staged = append(staged, data.Payload)
+			case *internal.RawData:
+				staged = append(staged, data.Payload)
+			}
+			return nil
+		},
+	}
+
+	options := &IngestionOptions{
+		MaxImpressionsBodyBytes: 200,
+		MaxEventsBodyBytes:      200,
+		MaxTelemetryBodyBytes:   200,
+		ValidatePayloads:        true,
+		Telemetry:               telemetry,
+	}
+
+	router := gin.New()
+	group := router.Group("/api")
+	NewEventsServerController(logger, sink, sink, sink, nil, func(key string) bool { return key == "someApiKey" }, options).Register(group, group)
+	NewTelemetryServerController(logger, sink, sink, options, nil).Register(group)
+
+	validImpressions := `[{"f":"feature1","i":[{"k":"key1","t":"on","m":123,"c":1,"r":"label"}]}]`
+	validEvents := `[{"key":"key1","trafficTypeName":"user","eventTypeId":"click","timestamp":123}]`
+	tests := []struct {
+		path     string
+		body     []byte
+		gzip     bool
+		status   int
+		errorMsg string
+	}{
+		{"/api/testImpressions/bulk", []byte(validImpressions), false, 200, ""},
+		{"/api/testImpressions/bulk", gzipped(t, validImpressions), true, 200, ""},
+		{"/api/testImpressions/bulk", []byte("not-gzip"), true, 400, "invalid gzip body"},
+		{"/api/testImpressions/bulk", []byte(strings.Repeat(" ", 201)), false, 413, "body exceeds the max allowed size"},
+		{"/api/testImpressions/bulk", gzipped(t, strings.Repeat(" ", 201)), true, 413, "body exceeds the max allowed size"},
+		{"/api/testImpressions/bulk", []byte(`[{"f":"feature1","i":[{"k":"key1","m":123}]}]`), false, 400, "[0].i[0].t: missing treatment"},
+		{"/api/testImpressions/bulk", []byte(`[{"f":"feature1","i":[{"k":"key1","t":"on","m":"123"}]}]`), false, 400, "m: expected int64, got string"},
+		{"/api/testImpressions/bulk", []byte(`[{"f":"feature1"`), false, 400, "invalid json"},
+		{"/api/testImpressions/count", []byte(`{"pf":[{"f":"feature1","m":0,"rc":1}]}`), false, 400, "pf[0].m: invalid time frame 0"},
+		{"/api/events/bulk", []byte(validEvents), false, 200, ""},
+		{"/api/events/bulk", []byte(`[{"key":"key1","trafficTypeName":"user","timestamp":123}]`), false, 400, "[0].eventTypeId: missing event type"},
+		{"/api/events/beacon", []byte(`{"token":"someApiKey","sdk":"js-1.0.0","entries":[{"key":"key1"}]}`), false, 400, "[0].trafficTypeName: missing traffic type"},
+		{"/api/events/beacon", []byte(`{"token":"invalid","sdk":"js-1.0.0","entries":[{"key":"key1"}]}`), false, 401, ""},
+		{"/api/metrics/usage", []byte(`{"iQ":"many"}`), false, 400, "invalid value for iQ: expected int64, got string"},
+	}
+
+	for _, test := range tests {
+		staged = nil
+		resp := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, test.path, bytes.NewReader(test.body))
+		request.Header.Set("SplitSDKVersion", "go-1.1.1")
+		if test.gzip {
+			request.Header.Set("Content-Encoding", "gzip")
+		}
+		router.ServeHTTP(resp, request)
+
+		if resp.Code != test.status {
+			t.Error("wrong status for ", test.path, ": ", resp.Code, " body: ", resp.Body.String())
+		}
+
+		if test.status == 200 && (len(staged) != 1 || !json.Valid(staged[0])) {
+			t.Error("a decompressed payload should have been staged for ", test.path, ": ", staged)
+		}
+
+		if test.errorMsg != "" {
+			var body struct{ Error string }
+			json.Unmarshal(resp.Body.Bytes(), &body)
+			if !strings.Contains(body.Error, test.errorMsg) {
+				t.Errorf("wrong error message for %s. Expected '%s', got '%s'", test.path, test.errorMsg, body.Error)
+			}
+			if len(staged) != 0 {
+				t.Error("rejected payloads should not be staged")
+			}
+		}
+	}
+
+	rejected := telemetry.PeekRejectedPayloads()
+	if len(rejected) != 2 || rejected["go-1.1.1"] != 9 || rejected["js-1.0.0"] != 1 {
+		t.Error("wrong rejected payload counters: ", rejected)
+	}
+}
+
+func TestIngestionWithoutValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var staged []byte
+	sink := &mocks.MockDeferredRecordingTask{
+		StageCall: func(raw interface{}) error {
+			staged = raw.(*internal.RawEvents).Payload
+			return nil
+		},
+	}
+
+	router := gin.New()
+	group := router.Group("/api")
+	NewEventsServerController(logging.NewLogger(nil), sink, sink, sink, nil, func(string) bool { return true }, nil).Register(group, group)
+
+	resp := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/api/events/bulk", bytes.NewReader([]byte(`[{"key":"key1"}]`)))
+	router.ServeHTTP(resp, request)
+	if resp.Code != 200 || string(staged) != `[{"key":"key1"}]` {
+		t.Error("payloads should be forwarded untouched when validation is disabled: ", resp.Code, string(staged))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,9 @@ import (
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 )
 
+// maxBulkKeys is the max number of keys that can be looked up in a single bulk mySegments request
+const maxBulkKeys = 10000
+
 // SdkServerController bundles all request handler for sdk-server apis
 type SdkServerController struct {
 	logger              logging.LoggerInterface
@@ -38,11 +42,13 @@ func NewSdkServerController(
 	}
 }
 
-// Register mounts the sdk-server endpoints onto the supplied router
-func (c *SdkServerController) Register(router gin.IRouter) {
-	router.GET("/splitChanges", c.SplitChanges)
-	router.GET("/segmentChanges/:name", c.SegmentChanges)
-	router.GET("/mySegments/:key", c.MySegments)
+// Register mounts the sdk-server endpoints onto the supplied routers. Responses of endpoints mounted on `cacheable`
+// may be cached, so endpoints whose response depends on the request body go in `regular`
+func (c *SdkServerController) Register(cacheable gin.IRouter, regular gin.IRouter) {
+	cacheable.GET("/splitChanges", c.SplitChanges)
+	cacheable.GET("/segmentChanges/:name", c.SegmentChanges)
+	cacheable.GET("/mySegments/:key", c.MySegments)
+	regular.POST("/mySegments/bulk", c.MySegmentsBulk)
 }
 
 // SplitChanges Returns a diff containing changes in splits from a certain point in time until now.
@@ -60,6 +66,7 @@ func (c *SdkServerController) SplitChanges(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	ctx.Header("ETag", caching.MakeETag("splitChanges", strconv.FormatInt(since, 10), strconv.FormatInt(splits.Till, 10)))
 	ctx.JSON(http.StatusOK, splits)
 	ctx.Set(caching.SurrogateContextKey, []string{caching.SplitSurrogate})
 	ctx.Set(caching.StickyContextKey, true)
@@ -75,7 +82,14 @@ func (c *SdkServerController) SegmentChanges(ctx *gin.Context) {
 
 	segmentName := ctx.Param("name")
 	c.logger.Debug(fmt.Sprintf("SDK Fetches Segment: %s Since: %d", segmentName, since))
-	payload, err := c.proxySegmentStorage.ChangesSince(segmentName, since)
+
+	// pagination is optional & only used by sdks that ask for it
+	page := &storage.SegmentChangesPage{Token: ctx.Query("pageToken")}
+	if pageSize, err := strconv.Atoi(ctx.Query("pageSize")); err == nil {
+		page.Size = pageSize
+	}
+
+	stream, err := c.proxySegmentStorage.StreamChangesSince(segmentName, since, page)
 	if err != nil {
 		if errors.Is(err, storage.ErrSegmentNotFound) {
 			c.logger.Error("the following segment was requested and is not present: ", segmentName)
@@ -83,14 +97,33 @@ func (c *SdkServerController) SegmentChanges(ctx *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, storage.ErrInvalidPageToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.logger.Error("error fetching segmentChanges payload from storage: ", err)
 		ctx.JSON(http.StatusInternalServerError, nil)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payload)
+	tagParts := []string{"segmentChanges", segmentName, strconv.FormatInt(since, 10), strconv.FormatInt(stream.Till(), 10)}
+	if page.Size > 0 {
+		tagParts = append(tagParts, strconv.Itoa(page.Size), page.Token)
+	}
+	ctx.Header("ETag", caching.MakeETag(tagParts...))
 	ctx.Set(caching.SurrogateContextKey, []string{caching.MakeSurrogateForSegmentChanges(segmentName)})
 	ctx.Set(caching.StickyContextKey, true)
+
+	// keys are written as they're read from storage, so the payload is never fully held in memory.
+	// Only small payloads are buffered by the http cache, larger ones are passed through as they're written
+	ctx.Set(caching.MaxEntryBytesContextKey, caching.StreamedEntryMaxBytes)
+	ctx.Header("Content-Type", "application/json; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if _, err := stream.WriteTo(ctx.Writer); err != nil {
+		c.logger.Error("error writing segmentChanges payload: ", err)
+		ctx.Error(err) // the response is incomplete & must not be cached
+	}
 }
 
 // MySegments Returns a diff containing changes in splits from a certain point in time until now.
@@ -108,10 +141,54 @@ func (c *SdkServerController) MySegments(ctx *gin.Context) {
 		mySegments = append(mySegments, dtos.MySegmentDTO{Name: segmentName})
 	}
 
+	// there's no change number for mySegments, so the tag is derived from the (sorted) content
+	tagParts := append([]string{"mySegments", key}, segmentList...)
+	sort.Strings(tagParts[2:])
+	ctx.Header("ETag", caching.MakeETag(tagParts...))
 	ctx.JSON(http.StatusOK, gin.H{"mySegments": mySegments})
 	ctx.Set(caching.SurrogateContextKey, caching.MakeSurrogateForMySegments(mySegments))
 }
 
+type mySegmentsBulkRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// MySegmentsBulk returns the segments each of the supplied keys belongs to
+func (c *SdkServerController) MySegmentsBulk(ctx *gin.Context) {
+	var request mySegmentsBulkRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	if len(request.Keys) > maxBulkKeys {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d keys can be requested at once", maxBulkKeys)})
+		return
+	}
+
+	result := make(map[string][]dtos.MySegmentDTO, len(request.Keys))
+	for _, key := range request.Keys {
+		if _, ok := result[key]; ok {
+			continue
+		}
+
+		segmentList, err := c.proxySegmentStorage.SegmentsFor(key)
+		if err != nil {
+			c.logger.Error(fmt.Sprintf("error fetching segments for user '%s': %s", key, err.Error()))
+			ctx.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+
+		mySegments := make([]dtos.MySegmentDTO, 0, len(segmentList))
+		for _, segmentName := range segmentList {
+			mySegments = append(mySegments, dtos.MySegmentDTO{Name: segmentName})
+		}
+		result[key] = mySegments
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"mySegments": result})
+}
+
 func (c *SdkServerController) fetchSplitChangesSince(since int64) (*dtos.SplitChangesDTO, error) {
 	splits, err := c.proxySplitStorage.ChangesSince(since)
 	if err == nil {
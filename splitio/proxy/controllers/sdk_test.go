@@ -3,9 +3,11 @@ package controllers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -55,7 +57,7 @@ func TestSplitChangesCachedRecipe(t *testing.T) {
 		},
 		nil,
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/splitChanges?since=-1", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -117,7 +119,7 @@ func TestSplitChangesNonCachedRecipe(t *testing.T) {
 		},
 		nil,
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/splitChanges?since=-1", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -171,7 +173,7 @@ func TestSplitChangesNonCachedRecipeAndFetchFails(t *testing.T) {
 		},
 		nil,
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/splitChanges?since=-1", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -212,7 +214,7 @@ func TestSegmentChanges(t *testing.T) {
 			},
 		},
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/segmentChanges/someSegment?since=-1", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -254,7 +256,7 @@ func TestSegmentChangesNotFound(t *testing.T) {
 			},
 		},
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/segmentChanges/someSegment?since=-1", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -268,6 +270,59 @@ func TestSegmentChangesNotFound(t *testing.T) {
 	}
 }
 
+func TestSegmentChangesPaginated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logging.NewLogger(nil)
+
+	router := gin.New()
+	group := router.Group("/api")
+	controller := NewSdkServerController(
+		logger,
+		&mocks.MockSplitFetcher{},
+		&psmocks.ProxySplitStorageMock{},
+		&psmocks.ProxySegmentStorageMock{
+			StreamChangesSinceCall: func(name string, since int64, page *storage.SegmentChangesPage) (storage.SegmentChangesStream, error) {
+				if page.Token == "bad" {
+					return nil, storage.ErrInvalidPageToken
+				}
+				if name != "someSegment" || since != 5 || page.Size != 2 || page.Token != "abc" {
+					t.Error("wrong params: ", name, since, page)
+				}
+				return storage.NewSegmentChangesStreamFromDTO(&dtos.SegmentChangesDTO{
+					Name:    "someSegment",
+					Added:   []string{"k1"},
+					Removed: []string{"k2"},
+					Since:   5,
+					Till:    6,
+				}), nil
+			},
+		},
+	)
+	controller.Register(group, group)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/segmentChanges/someSegment?since=5&pageSize=2&pageToken=abc", nil)
+	router.ServeHTTP(resp, req)
+	if resp.Code != 200 || resp.Header().Get("ETag") == "" || !strings.HasPrefix(resp.Header().Get("Content-Type"), "application/json") {
+		t.Error("wrong response: ", resp.Code, resp.Header())
+	}
+
+	var s dtos.SegmentChangesDTO
+	if err := json.Unmarshal(resp.Body.Bytes(), &s); err != nil {
+		t.Error("invalid payload: ", err)
+	}
+	if s.Name != "someSegment" || len(s.Added) != 1 || len(s.Removed) != 1 || s.Since != 5 || s.Till != 6 {
+		t.Error("wrong payload returned: ", s)
+	}
+
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/segmentChanges/someSegment?since=5&pageSize=2&pageToken=bad", nil)
+	router.ServeHTTP(resp, req)
+	if resp.Code != 400 {
+		t.Error("Status code should be 400 and is ", resp.Code)
+	}
+}
+
 func TestMySegments(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	resp := httptest.NewRecorder()
@@ -290,7 +345,7 @@ func TestMySegments(t *testing.T) {
 			},
 		},
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/mySegments/someKey", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -338,7 +393,7 @@ func TestMySegmentsError(t *testing.T) {
 			},
 		},
 	)
-	controller.Register(group)
+	controller.Register(group, group)
 
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/api/mySegments/someKey", nil)
 	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
@@ -351,3 +406,74 @@ func TestMySegmentsError(t *testing.T) {
 		t.Error("Status code should be 500 and is ", resp.Code)
 	}
 }
+
+func TestMySegmentsBulk(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+
+	logger := logging.NewLogger(nil)
+
+	calls := 0
+	group := router.Group("/api")
+	controller := NewSdkServerController(
+		logger,
+		&mocks.MockSplitFetcher{},
+		&psmocks.ProxySplitStorageMock{},
+		&psmocks.ProxySegmentStorageMock{
+			SegmentsForCall: func(key string) ([]string, error) {
+				calls++
+				switch key {
+				case "key1":
+					return []string{"segment1", "segment2"}, nil
+				case "broken":
+					return nil, errors.New("something")
+				}
+				return []string{}, nil
+			},
+		},
+	)
+	controller.Register(group, group)
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/mySegments/bulk", strings.NewReader(`{"keys": ["key1", "key2", "key1"]}`))
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+
+	var payload struct {
+		MySegments map[string][]dtos.MySegmentDTO `json:"mySegments"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &payload)
+	if s := payload.MySegments["key1"]; len(s) != 2 || s[0].Name != "segment1" || s[1].Name != "segment2" {
+		t.Error("invalid segments for key1: ", s)
+	}
+	if s, ok := payload.MySegments["key2"]; !ok || len(s) != 0 {
+		t.Error("key2 should be present with no segments: ", payload.MySegments)
+	}
+	if calls != 2 {
+		t.Error("duplicate keys should be looked up once. Calls: ", calls)
+	}
+
+	tooMany := make([]string, maxBulkKeys+1)
+	for idx := range tooMany {
+		tooMany[idx] = fmt.Sprintf("key%d", idx)
+	}
+	tooManyBody, _ := json.Marshal(map[string][]string{"keys": tooMany})
+	cases := []struct {
+		body     string
+		expected int
+	}{
+		{"not json", 400},
+		{string(tooManyBody), 400},
+		{`{"keys": ["broken"]}`, 500},
+	}
+	for _, tc := range cases {
+		resp = httptest.NewRecorder()
+		ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/mySegments/bulk", strings.NewReader(tc.body))
+		router.ServeHTTP(resp, ctx.Request)
+		if resp.Code != tc.expected {
+			t.Error("Status code should be ", tc.expected, " and is ", resp.Code)
+		}
+	}
+}
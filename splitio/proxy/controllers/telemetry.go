@@ -1,12 +1,15 @@
 package controllers
 
 import (
-	"io/ioutil"
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/splitio/go-split-commons/v4/dtos"
 	"github.com/splitio/go-toolkit/v5/logging"
 
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/internal"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/tasks"
 )
@@ -16,6 +19,8 @@ type TelemetryServerController struct {
 	logger     logging.LoggerInterface
 	configSink tasks.DeferredRecordingTask
 	usageSink  tasks.DeferredRecordingTask
+	payloads   *payloadReader
+	fleet      *fleet.Inventory
 }
 
 // NewTelemetryServerController returns a new events server controller
@@ -23,11 +28,15 @@ func NewTelemetryServerController(
 	logger logging.LoggerInterface,
 	configSync tasks.DeferredRecordingTask,
 	usageSync tasks.DeferredRecordingTask,
+	ingestion *IngestionOptions,
+	inventory *fleet.Inventory,
 ) *TelemetryServerController {
 	return &TelemetryServerController{
 		logger:     logger,
 		configSink: configSync,
 		usageSink:  usageSync,
+		payloads:   newPayloadReader(logger, ingestion),
+		fleet:      inventory,
 	}
 }
 
@@ -35,17 +44,24 @@ func NewTelemetryServerController(
 func (c *TelemetryServerController) Register(router gin.IRouter) {
 	router.POST("/metrics/config", c.Config)
 	router.POST("/metrics/usage", c.Usage)
+
+	// legacy endpoints used by sdks that predate the telemetry api
+	router.POST("/metrics/times", c.LegacyTimes)
+	router.POST("/metrics/time", c.LegacyTimes)
+	router.POST("/metrics/counters", c.LegacyCounters)
+	router.POST("/metrics/counter", c.LegacyCounters)
+	router.POST("/metrics/gauge", c.LegacyGauge)
 }
 
 // Config endpoint accepts telemtetry config objects
 func (c *TelemetryServerController) Config(ctx *gin.Context) {
 	metadata := metadataFromHeaders(ctx)
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxTelemetryBodyBytes, validateTelemetryConfig)
 	if err != nil {
-		c.logger.Error(err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "metrics/config", metadata.SDKVersion, err)
 		return
 	}
+	c.trackConfig(metadata, data)
 
 	err = c.configSink.Stage(internal.NewRawTelemetryConfig(metadata, data))
 	if err != nil {
@@ -62,10 +78,9 @@ func (c *TelemetryServerController) Config(ctx *gin.Context) {
 // Usage endpoint accepts telemtetry config objects
 func (c *TelemetryServerController) Usage(ctx *gin.Context) {
 	metadata := metadataFromHeaders(ctx)
-	data, err := ioutil.ReadAll(ctx.Request.Body)
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxTelemetryBodyBytes, validateTelemetryUsage)
 	if err != nil {
-		c.logger.Error(err)
-		ctx.JSON(http.StatusInternalServerError, nil)
+		c.payloads.handleError(ctx, "metrics/usage", metadata.SDKVersion, err)
 		return
 	}
 
@@ -80,3 +95,85 @@ func (c *TelemetryServerController) Usage(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, nil)
 }
+
+// LegacyTimes endpoint accepts legacy latency metrics & forwards them as telemetry usage
+func (c *TelemetryServerController) LegacyTimes(ctx *gin.Context) {
+	c.legacyMetrics(ctx, "metrics/times", translateLegacyLatencies)
+}
+
+// LegacyCounters endpoint accepts legacy counter metrics & forwards them as telemetry usage
+func (c *TelemetryServerController) LegacyCounters(ctx *gin.Context) {
+	c.legacyMetrics(ctx, "metrics/counters", translateLegacyCounters)
+}
+
+// LegacyGauge endpoint accepts legacy gauge metrics. These have no telemetry counterpart & are only tracked locally
+func (c *TelemetryServerController) LegacyGauge(ctx *gin.Context) {
+	c.legacyMetrics(ctx, "metrics/gauge", translateLegacyGauge)
+}
+
+// legacyMetrics translates a legacy metrics payload into a telemetry usage one. Since older sdks don't act upon the
+// response, a 200 is returned even if the payload cannot be understood, as the previous no-op endpoints did
+func (c *TelemetryServerController) legacyMetrics(ctx *gin.Context, endpoint string, translate legacyMetricsTranslator) {
+	metadata := metadataFromHeaders(ctx)
+	if ctx.Request.Body == nil || ctx.Request.ContentLength == 0 {
+		ctx.JSON(http.StatusOK, nil)
+		return
+	}
+
+	data, err := c.payloads.read(ctx.Request, c.payloads.options.MaxTelemetryBodyBytes, nil)
+	if err != nil {
+		c.payloads.handleError(ctx, endpoint, metadata.SDKVersion, err)
+		return
+	}
+
+	stats, err := translate(data)
+	if err != nil {
+		c.logger.Debug(fmt.Sprintf("ignoring unparseable %s payload from sdk '%s': %s", endpoint, metadata.SDKVersion, err))
+		ctx.JSON(http.StatusOK, nil)
+		return
+	}
+
+	if telemetry := c.payloads.options.Telemetry; telemetry != nil {
+		sdkVersion := metadata.SDKVersion
+		if sdkVersion == "" {
+			sdkVersion = "unknown"
+		}
+		telemetry.IncrLegacyTelemetry(sdkVersion)
+	}
+
+	if isEmptyStats(stats) {
+		ctx.JSON(http.StatusOK, nil)
+		return
+	}
+
+	serialized, err := json.Marshal(stats)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("error serializing telemetry translated from %s: %s", endpoint, err))
+		ctx.JSON(http.StatusInternalServerError, nil)
+		return
+	}
+
+	if err := c.usageSink.Stage(internal.NewRawTelemetryUsage(metadata, serialized)); err != nil {
+		if err == tasks.ErrQueueFull {
+			ctx.AbortWithStatusJSON(500, "Usage telemetry queue queue is full, please retry later.")
+		} else {
+			ctx.AbortWithStatusJSON(500, "Unknown error when trying to push usage telemetry into the staging queue")
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// trackConfig records the settings reported by an sdk in the fleet inventory (if any)
+func (c *TelemetryServerController) trackConfig(metadata dtos.Metadata, data []byte) {
+	if c.fleet == nil {
+		return
+	}
+
+	var config dtos.Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		c.logger.Debug(fmt.Sprintf("error parsing telemetry config for the fleet inventory: %s", err))
+		return
+	}
+	c.fleet.TrackConfig(metadata, &config)
+}
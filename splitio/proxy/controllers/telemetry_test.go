@@ -10,7 +10,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/splitio/go-split-commons/v4/dtos"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/internal"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/tasks/mocks"
 )
 
@@ -20,6 +22,7 @@ func TestPostConfig(t *testing.T) {
 	ctx, router := gin.CreateTestContext(resp)
 
 	logger := logging.NewLogger(nil)
+	inventory := fleet.NewInventory(fleet.Config{})
 
 	controller := NewTelemetryServerController(
 		logger,
@@ -48,6 +51,8 @@ func TestPostConfig(t *testing.T) {
 			},
 		},
 		&mocks.MockDeferredRecordingTask{},
+		nil,
+		inventory,
 	)
 
 	group := router.Group("/api")
@@ -71,6 +76,11 @@ func TestPostConfig(t *testing.T) {
 	if resp.Code != 200 {
 		t.Error("Status code should be 200 and is ", resp.Code)
 	}
+
+	instances := inventory.Summary().Instances
+	if len(instances) != 1 || instances[0].Config == nil || instances[0].Config.OperationMode != "consumer" || !instances[0].Config.StreamingEnabled {
+		t.Error("the reported config should be tracked in the fleet inventory: ", instances)
+	}
 }
 
 func TestPostRuntime(t *testing.T) {
@@ -107,6 +117,8 @@ func TestPostRuntime(t *testing.T) {
 				return nil
 			},
 		},
+		nil,
+		nil,
 	)
 
 	group := router.Group("/api")
@@ -127,3 +139,139 @@ func TestPostRuntime(t *testing.T) {
 		t.Error("Status code should be 200 and is ", resp.Code)
 	}
 }
+
+func TestPostLegacyMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resp := httptest.NewRecorder()
+	ctx, router := gin.CreateTestContext(resp)
+
+	logger := logging.NewLogger(nil)
+
+	group := router.Group("/api")
+	controller := NewTelemetryServerController(
+		logger,
+		&mocks.MockDeferredRecordingTask{},
+		&mocks.MockDeferredRecordingTask{
+			StageCall: func(raw interface{}) error {
+				t.Error("empty legacy payloads should not be forwarded")
+				return nil
+			},
+		},
+		nil,
+		nil,
+	)
+	controller.Register(group)
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/counter", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
+	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
+	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
+	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/counters", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
+	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
+	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
+	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/time", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
+	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
+	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
+	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/times", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
+	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
+	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
+	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/api/metrics/gauge", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer someApiKey")
+	ctx.Request.Header.Set("SplitSDKVersion", "go-1.1.1")
+	ctx.Request.Header.Set("SplitSDKMachineIp", "1.2.3.4")
+	ctx.Request.Header.Set("SplitSDKMachineName", "ip-1-2-3-4")
+	router.ServeHTTP(resp, ctx.Request)
+	if resp.Code != 200 {
+		t.Error("Status code should be 200 and is ", resp.Code)
+	}
+}
+
+func TestPostLegacyMetricsForwarded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	telemetry := storage.NewProxyTelemetryFacade()
+
+	var forwarded []dtos.Stats
+	controller := NewTelemetryServerController(
+		logging.NewLogger(nil),
+		&mocks.MockDeferredRecordingTask{},
+		&mocks.MockDeferredRecordingTask{
+			StageCall: func(raw interface{}) error {
+				data := raw.(*internal.RawTelemetryUsage)
+				if data.Metadata.SDKVersion != "java-2.3.1" {
+					t.Error("wrong metadata: ", data.Metadata)
+				}
+
+				var parsed dtos.Stats
+				if err := json.Unmarshal(data.Payload, &parsed); err != nil {
+					t.Error("error parsing stats: ", err)
+				}
+				forwarded = append(forwarded, parsed)
+				return nil
+			},
+		},
+		&IngestionOptions{Telemetry: telemetry},
+		nil,
+	)
+	controller.Register(router.Group("/api"))
+
+	post := func(path string, body string) {
+		t.Helper()
+		resp := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		request.Header.Set("SplitSDKVersion", "java-2.3.1")
+		router.ServeHTTP(resp, request)
+		if resp.Code != 200 {
+			t.Error("Status code should be 200 and is ", resp.Code)
+		}
+	}
+
+	post("/api/metrics/times", `[{"name":"sdk.getTreatment","latencies":[0,2]}]`)
+	post("/api/metrics/counter", `{"name":"segmentChangeFetcher.status.503","delta":3}`)
+	post("/api/metrics/gauge", `{"name":"some.gauge","value":1.5}`)
+	post("/api/metrics/counters", `not json`)
+
+	if len(forwarded) != 2 {
+		t.Error("latencies & counters should have been forwarded. Got: ", forwarded)
+		return
+	}
+
+	if forwarded[0].MethodLatencies == nil || forwarded[0].MethodLatencies.Treatment[1] != 2 {
+		t.Error("wrong latencies forwarded: ", forwarded[0])
+	}
+
+	if forwarded[1].HTTPErrors == nil || forwarded[1].HTTPErrors.Segments[503] != 3 {
+		t.Error("wrong counters forwarded: ", forwarded[1])
+	}
+
+	if legacy := telemetry.PeekLegacyTelemetry(); len(legacy) != 1 || legacy["java-2.3.1"] != 3 {
+		t.Error("parsed legacy calls should be tracked by sdk version: ", legacy)
+	}
+}
@@ -1,9 +1,12 @@
 package proxy
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
+	"path/filepath"
 	"time"
 
 	"strings"
@@ -11,6 +14,7 @@ import (
 	cfg "github.com/splitio/go-split-commons/v4/conf"
 
 	"github.com/splitio/go-split-commons/v4/conf"
+	"github.com/splitio/go-split-commons/v4/service"
 	"github.com/splitio/go-split-commons/v4/service/api"
 	"github.com/splitio/go-split-commons/v4/synchronizer"
 	"github.com/splitio/go-split-commons/v4/tasks"
@@ -20,30 +24,56 @@ import (
 	"github.com/splitio/split-synchronizer/v5/splitio/admin"
 	adminCommon "github.com/splitio/split-synchronizer/v5/splitio/admin/common"
 	"github.com/splitio/split-synchronizer/v5/splitio/common"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changehook"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/notifier"
 	"github.com/splitio/split-synchronizer/v5/splitio/common/snapshot"
 	ssync "github.com/splitio/split-synchronizer/v5/splitio/common/sync"
+	splitlog "github.com/splitio/split-synchronizer/v5/splitio/log"
+	hcAlerts "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/alerts"
 	hcApplication "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application"
 	hcAppCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/application/counter"
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
 	hcServices "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services"
 	hcServicesCounter "github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/services/counter"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
 	pconf "github.com/splitio/split-synchronizer/v5/splitio/proxy/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/controllers"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/offline"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/publisher"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/ratelimit"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
 	pTasks "github.com/splitio/split-synchronizer/v5/splitio/proxy/tasks"
 	"github.com/splitio/split-synchronizer/v5/splitio/util"
 )
 
-// Start initialize in proxy mode
-func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
-
-	clientKey, err := util.GetClientKey(cfg.Apikey)
-	if err != nil {
-		return common.NewInitError(fmt.Errorf("error parsing client key from provided apikey: %w", err), common.ExitInvalidApikey)
+// Start initialize in proxy mode. The notifier is optional
+func Start(logger logging.LoggerInterface, cfg *pconf.Main, notif *notifier.Notifier) error {
+	syncLogger := splitlog.Component(logger, splitlog.ComponentSync)
+	hcLogger := splitlog.Component(logger, splitlog.ComponentHealthcheck)
+	adminLogger := splitlog.Component(logger, splitlog.ComponentAdmin)
+
+	offlineMode := cfg.Offline.Enabled
+	var clientKey string
+	if offlineMode {
+		if cfg.Initialization.Snapshot == "" && cfg.Offline.DataDir == "" {
+			return common.NewInitError(errors.New("offline mode requires either a snapshot or a data directory"), common.ExitTaskInitialization)
+		}
+		logger.Info("Starting in offline mode. No connections to split servers will be attempted.")
+	} else {
+		var err error
+		clientKey, err = util.GetClientKey(cfg.Apikey)
+		if err != nil {
+			return common.NewInitError(fmt.Errorf("error parsing client key from provided apikey: %w", err), common.ExitInvalidApikey)
+		}
 	}
 
 	// Initialization of DB
+	var err error
 	var dbpath = persistent.BoltInMemoryMode
 	if snapFile := cfg.Initialization.Snapshot; snapFile != "" {
 		snap, err := snapshot.DecodeFromFile(snapFile)
@@ -64,9 +94,20 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 		return common.NewInitError(fmt.Errorf("error instantiating boltdb: %w", err), common.ExitErrorDB)
 	}
 
+	// Data that must survive restarts (client apikeys & observability time slices) is kept in its own file,
+	// so that splits & segments are still only restored from snapshots
+	durableDB := dbInstance
+	if filename := cfg.Storage.Persistent.Filename; filename != "" {
+		durableDB, err = persistent.NewBoltWrapper(filename, nil)
+		if err != nil {
+			return common.NewInitError(fmt.Errorf("error opening persistent storage file: %w", err), common.ExitErrorDB)
+		}
+	}
+	isDurable := durableDB != dbInstance
+
 	// Set up the http proxy caching.
 	// We need it fairly early since it's passed to the synchronizers, so that they can evict entries when a change is processed
-	httpCache := caching.MakeProxyCache()
+	httpCache := caching.MakeProxyCache(int(cfg.Server.CacheSize), cfg.Server.CacheMaxBytes)
 
 	// Getting initial config data
 	advanced := cfg.BuildAdvancedConfig()
@@ -74,10 +115,34 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 
 	// Setup fetchers & recorders
 	splitAPI := api.NewSplitAPI(cfg.Apikey, *advanced, logger, metadata)
+	var splitFetcher service.SplitFetcher = splitAPI.SplitFetcher
+	var segmentFetcher service.SegmentFetcher = splitAPI.SegmentFetcher
+	if offlineMode {
+		advanced.StreamingEnabled = false // keeps the sync manager from authenticating against split servers
+		splitFetcher = offline.NewFileSplitFetcher(cfg.Offline.DataDir, logger)
+		segmentFetcher = offline.NewFileSegmentFetcher(cfg.Offline.DataDir, logger)
+	}
 
 	// Proxy storages already implement the observable interface, so no need to wrap them
 	splitStorage := storage.NewProxySplitStorage(dbInstance, logger, cfg.Initialization.Snapshot != "")
-	segmentStorage := storage.NewProxySegmentStorage(dbInstance, logger, cfg.Initialization.Snapshot != "")
+	segmentStorage := storage.NewProxySegmentStorage(dbInstance, logger, cfg.Initialization.Snapshot != "",
+		int(cfg.Storage.Volatile.MySegmentsMaxKeysInMemory))
+
+	var changeRecorder *changelog.Recorder
+	if cfg.ChangeLog.Enabled {
+		changeStorage := persistent.NewChangeLogCollection(dbInstance, syncLogger)
+		changeRecorder = changelog.NewRecorder(changeStorage, time.Duration(cfg.ChangeLog.RetentionHours)*time.Hour, cfg.ChangeLog.MaxEntries, syncLogger)
+		splitStorage.SetChangeRecorder(changeRecorder)
+		segmentStorage.SetChangeRecorder(changeRecorder)
+	}
+
+	if !isDurable {
+		logger.Info("No persistent storage file configured. Client apikeys added or revoked at runtime will not survive restarts")
+	}
+	clientKeys := apikeys.NewManager(cfg.Server.ClientApikeys, persistent.NewClientAPIKeyCollection(durableDB, logger), logger)
+	if err := clientKeys.Load(); err != nil {
+		return common.NewInitError(err, common.ExitErrorDB)
+	}
 
 	// Local telemetry
 	tbufferSize := int(cfg.Sync.Advanced.TelemetryBuffer)
@@ -88,53 +153,157 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 		cfg.Observability.TimeSliceWidthSecs,
 		int(cfg.Observability.MaxTimeSliceCount),
 	)
+	telemetryRetention := time.Duration(cfg.Observability.RetentionHours) * time.Hour
+	persistTimeSlices := cfg.Observability.PersistTimeSlices && isDurable
+	if cfg.Observability.PersistTimeSlices && !isDurable {
+		logger.Info("No persistent storage file configured. Observability time slices will not survive restarts")
+	}
+	if persistTimeSlices {
+		localTelemetryStorage.SetPersistentStorage(persistent.NewTelemetryTimeSliceCollection(durableDB, logger))
+		if restored, err := localTelemetryStorage.Restore(time.Now().Add(-telemetryRetention)); err != nil {
+			logger.Warning("error restoring persisted observability time slices: ", err)
+		} else {
+			logger.Debug(fmt.Sprintf("restored %d observability time slices", restored))
+		}
+	}
 
 	// Healcheck Monitor
 	splitsConfig, segmentsConfig := getAppCounterConfigs()
-	appMonitor := hcApplication.NewMonitorImp(splitsConfig, segmentsConfig, nil, logger)
-	servicesMonitor := hcServices.NewMonitorImp(getServicesCountersConfig(*advanced), logger)
+	appMonitor := hcApplication.NewMonitorImp(splitsConfig, segmentsConfig, nil, hcLogger)
+	var servicesMonitor *hcServices.MonitorImp
+	if offlineMode {
+		servicesMonitor = hcServices.NewMonitorImp(nil, hcLogger)
+	} else {
+		servicesMonitor = hcServices.NewMonitorImp(getServicesCountersConfig(*advanced), hcLogger)
+	}
 
-	// Creating Workers and Tasks
-	telemetryRecorder := api.NewHTTPTelemetryRecorder(cfg.Apikey, *advanced, logger)
-	telemetryConfigTask := pTasks.NewTelemetryConfigFlushTask(telemetryRecorder, logger, 1, tbufferSize, tworkers)
-	telemetryUsageTask := pTasks.NewTelemetryUsageFlushTask(telemetryRecorder, logger, 1, tbufferSize, tworkers)
+	if threshold := cfg.Healthcheck.App.BoltDBSizeThresholdMb; threshold > 0 {
+		appMonitor.RegisterCounter(hcAppCounter.NewCheckCounter(hcAppCounter.CheckConfig{
+			Name:     "BoltDB",
+			Severity: hcAppCounter.Low,
+			Period:   10,
+			Check: hcAppCounter.NewThresholdCheck(func() (int64, error) {
+				size, err := dbInstance.Size()
+				return size >> 20, err
+			}, threshold, "MB"),
+		}, hcLogger))
+	}
 
-	// impression bulks & counts - events
-	ibufferSize := int(cfg.Sync.Advanced.ImpressionsBuffer)
-	iworkers := int(cfg.Sync.Advanced.ImpressionsWorkers)
-	impressionRecorder := api.NewHTTPImpressionRecorder(cfg.Apikey, *advanced, logger)
-	impressionTask := pTasks.NewImpressionsFlushTask(impressionRecorder, logger, 1, ibufferSize, iworkers)
-	impressionCountTask := pTasks.NewImpressionCountFlushTask(impressionRecorder, logger, 1, ibufferSize, iworkers)
-	eventsRecorder := api.NewHTTPEventsRecorder(cfg.Apikey, *advanced, logger)
-	eventsTask := pTasks.NewEventsFlushTask(eventsRecorder, logger, 1, int(cfg.Sync.Advanced.EventsBuffer), int(cfg.Sync.Advanced.EventsWorkers))
+	healthAlerts := hcAlerts.NewWatcher(
+		appMonitor,
+		servicesMonitor,
+		hcAlerts.BuildSinks(&cfg.Healthcheck.Alerts, notif),
+		common.PeriodSecs(cfg.Healthcheck.Alerts.CheckRateMs),
+		hcLogger,
+	)
+
+	// setup split & segments interactions
+	changeHook, err := changehook.BuildFromConfig(&cfg.Integrations.ChangeWebhook, splitStorage, syncLogger)
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error instantiating change webhook: %w", err), common.ExitTaskInitialization)
+	}
 
-	// setup split, segments & local telemetry API interactions
 	workers := synchronizer.Workers{
-		SplitFetcher: caching.NewCacheAwareSplitSync(splitStorage, splitAPI.SplitFetcher, logger, localTelemetryStorage, httpCache, appMonitor),
-		SegmentFetcher: caching.NewCacheAwareSegmentSync(splitStorage, segmentStorage, splitAPI.SegmentFetcher, logger, localTelemetryStorage, httpCache,
+		SplitFetcher: caching.NewCacheAwareSplitSync(splitStorage, splitFetcher, syncLogger, localTelemetryStorage, httpCache, appMonitor),
+		SegmentFetcher: caching.NewCacheAwareSegmentSync(splitStorage, segmentStorage, segmentFetcher, syncLogger, localTelemetryStorage, httpCache,
 			appMonitor),
-		TelemetryRecorder: telemetry.NewTelemetrySynchronizer(localTelemetryStorage, telemetryRecorder, splitStorage, segmentStorage, logger,
-			metadata, localTelemetryStorage),
 	}
+	if changeHook != nil {
+		workers.SplitFetcher = changehook.NewSplitUpdater(workers.SplitFetcher, splitStorage, changeHook)
+		workers.SegmentFetcher = changehook.NewSegmentUpdater(workers.SegmentFetcher, changeHook)
+		changeHook.Start()
+	}
+
+	payloadPublisher, err := publisher.BuildFromConfig(&cfg.Publishing, splitStorage, segmentStorage, syncLogger)
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error instantiating payload publisher: %w", err), common.ExitInvalidConfiguration)
+	}
+	if payloadPublisher != nil {
+		workers.SplitFetcher = changehook.NewSplitUpdater(workers.SplitFetcher, splitStorage, payloadPublisher)
+		workers.SegmentFetcher = changehook.NewSegmentUpdater(workers.SegmentFetcher, payloadPublisher)
+		payloadPublisher.Start()
+	}
+
+	// synchronizations triggered by periodic tasks or streaming wait for full wipes requested through the admin api
+	dataWiper := storage.NewProxyDataWiper(splitStorage, segmentStorage, httpCache)
+	workers.SplitFetcher = dataWiper.GuardSplitUpdater(workers.SplitFetcher)
+	workers.SegmentFetcher = dataWiper.GuardSegmentUpdater(workers.SegmentFetcher)
 
 	// setup periodic tasks in case streaming is disabled or we need to fall back to polling
-	stasks := synchronizer.SplitTasks{
-		SplitSyncTask: tasks.NewFetchSplitsTask(workers.SplitFetcher, int(cfg.Sync.SplitRefreshRateMs/1000), logger),
-		SegmentSyncTask: tasks.NewFetchSegmentsTask(workers.SegmentFetcher, int(cfg.Sync.SegmentRefreshRateMs/1000), advanced.SegmentWorkers,
-			advanced.SegmentQueueSize, logger),
-		TelemetrySyncTask:        tasks.NewRecordTelemetryTask(workers.TelemetryRecorder, int(cfg.Sync.Advanced.InternalMetricsRateMs), logger),
-		ImpressionSyncTask:       impressionTask,
-		ImpressionsCountSyncTask: impressionCountTask,
-		EventSyncTask:            eventsTask,
+	splitRate, segmentRate := common.PeriodSecs(cfg.Sync.SplitRefreshRateMs), common.PeriodSecs(cfg.Sync.SegmentRefreshRateMs)
+	if offlineMode {
+		splitRate, segmentRate = common.PeriodSecs(cfg.Offline.RefreshRateMs), common.PeriodSecs(cfg.Offline.RefreshRateMs)
+	}
+	var stasks synchronizer.SplitTasks
+	if !offlineMode || cfg.Offline.DataDir != "" {
+		stasks.SplitSyncTask = tasks.NewFetchSplitsTask(workers.SplitFetcher, splitRate, syncLogger)
+		stasks.SegmentSyncTask = tasks.NewFetchSegmentsTask(workers.SegmentFetcher, segmentRate, advanced.SegmentWorkers, advanced.SegmentQueueSize, syncLogger)
 	}
 
+	// Creating Workers and Tasks
+	var telemetryConfigTask, telemetryUsageTask, impressionTask, impressionCountTask, eventsTask *pTasks.DeferredRecordingTaskImpl
+	ibufferSize := int(cfg.Sync.Advanced.ImpressionsBuffer)
+	iworkers := int(cfg.Sync.Advanced.ImpressionsWorkers)
+	ebufferSize := int(cfg.Sync.Advanced.EventsBuffer)
+	eworkers := int(cfg.Sync.Advanced.EventsWorkers)
+	if offlineMode {
+		newFileTask := func(dataType string, bufferSize int, workers int) (*pTasks.DeferredRecordingTaskImpl, error) {
+			writer, err := logging.NewFileRotate(&logging.FileRotateOptions{
+				MaxBytes:    cfg.Offline.RotationMaxSizeKb * 1024,
+				BackupCount: int(cfg.Offline.RotationMaxFiles),
+				Path:        filepath.Join(cfg.Offline.OutputDir, dataType+".jsonl"),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error opening %s output file: %w", dataType, err)
+			}
+			return pTasks.NewFileFlushTask(dataType, writer, logger, 1, bufferSize, workers), nil
+		}
+
+		fileTasks := []struct {
+			target     **pTasks.DeferredRecordingTaskImpl
+			dataType   string
+			bufferSize int
+			workers    int
+		}{
+			{&impressionTask, "impressions", ibufferSize, iworkers},
+			{&impressionCountTask, "impressionCounts", ibufferSize, iworkers},
+			{&eventsTask, "events", ebufferSize, eworkers},
+			{&telemetryConfigTask, "telemetryConfig", tbufferSize, tworkers},
+			{&telemetryUsageTask, "telemetryUsage", tbufferSize, tworkers},
+		}
+		for _, ft := range fileTasks {
+			if *ft.target, err = newFileTask(ft.dataType, ft.bufferSize, ft.workers); err != nil {
+				return common.NewInitError(err, common.ExitTaskInitialization)
+			}
+		}
+	} else {
+		telemetryRecorder := api.NewHTTPTelemetryRecorder(cfg.Apikey, *advanced, logger)
+		telemetryConfigTask = pTasks.NewTelemetryConfigFlushTask(telemetryRecorder, logger, 1, tbufferSize, tworkers)
+		telemetryUsageTask = pTasks.NewTelemetryUsageFlushTask(telemetryRecorder, logger, 1, tbufferSize, tworkers)
+
+		// impression bulks & counts - events
+		impressionRecorder := api.NewHTTPImpressionRecorder(cfg.Apikey, *advanced, logger)
+		impressionTask = pTasks.NewImpressionsFlushTask(impressionRecorder, logger, 1, ibufferSize, iworkers)
+		impressionCountTask = pTasks.NewImpressionCountFlushTask(impressionRecorder, logger, 1, ibufferSize, iworkers)
+		eventsRecorder := api.NewHTTPEventsRecorder(cfg.Apikey, *advanced, logger)
+		eventsTask = pTasks.NewEventsFlushTask(eventsRecorder, logger, 1, ebufferSize, eworkers)
+
+		// local telemetry API interactions
+		workers.TelemetryRecorder = telemetry.NewTelemetrySynchronizer(localTelemetryStorage, telemetryRecorder, splitStorage, segmentStorage, syncLogger,
+			metadata, localTelemetryStorage)
+		stasks.TelemetrySyncTask = tasks.NewRecordTelemetryTask(workers.TelemetryRecorder, int(cfg.Sync.Advanced.InternalMetricsRateMs), syncLogger)
+	}
+	stasks.ImpressionSyncTask = impressionTask
+	stasks.ImpressionsCountSyncTask = impressionCountTask
+	stasks.EventSyncTask = eventsTask
+
 	// Creating Synchronizer for tasks
-	sync := ssync.NewSynchronizer(*advanced, stasks, workers, logger, nil, []tasks.Task{telemetryConfigTask, telemetryUsageTask}, appMonitor)
+	sync := ssync.NewSynchronizer(*advanced, stasks, workers, syncLogger, nil, []tasks.Task{telemetryConfigTask, telemetryUsageTask}, appMonitor)
 
 	mstatus := make(chan int, 1)
 	syncManager, err := synchronizer.NewSynchronizerManager(
 		sync,
-		logger,
+		syncLogger,
 		*advanced,
 		splitAPI.AuthClient,
 		splitStorage,
@@ -148,6 +317,111 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 		return common.NewInitError(fmt.Errorf("error instantiating sync manager: %w", err), common.ExitTaskInitialization)
 	}
 
+	var probesSyncManager synchronizer.Manager
+	if !offlineMode {
+		probesSyncManager = syncManager
+	}
+	probeEvaluator := probes.NewEvaluator(
+		probes.ConfigFromOptions(&cfg.Healthcheck.Probes),
+		appMonitor,
+		servicesMonitor,
+		probesSyncManager,
+		splitStorage,
+	)
+	for name, task := range map[string]*pTasks.DeferredRecordingTaskImpl{
+		"impressions":      impressionTask,
+		"impressionCounts": impressionCountTask,
+		"events":           eventsTask,
+	} {
+		if task != nil {
+			probeEvaluator.RegisterQueue(name, task.QueueUsage)
+		}
+	}
+	rtm := common.NewRuntime(false, syncManager, logger, "Split Proxy", nil, notif, appMonitor, servicesMonitor)
+	if changeHook != nil {
+		rtm.RegisterShutdownHook(func() { changeHook.Stop(true) })
+	}
+	if payloadPublisher != nil {
+		rtm.RegisterShutdownHook(func() { payloadPublisher.Stop(true) })
+	}
+	if persistTimeSlices {
+		timeSlicePersister := pTasks.NewTimeSlicePersistTask(localTelemetryStorage, telemetryRetention, int(cfg.Observability.PersistRateSecs), logger)
+		timeSlicePersister.Start()
+		rtm.RegisterShutdownHook(func() { timeSlicePersister.Stop(true) })
+	}
+	storages := adminCommon.Storages{
+		SplitStorage:          splitStorage,
+		SegmentStorage:        segmentStorage,
+		LocalTelemetryStorage: localTelemetryStorage,
+	}
+
+	// --------------------------- ADMIN DASHBOARD ------------------------------
+	var logLevels adminCommon.LogLevels
+	if levels := splitlog.LevelsOf(logger); levels != nil {
+		logLevels = levels
+	}
+
+	var changeHistory adminCommon.ChangeHistory
+	if changeRecorder != nil {
+		changeHistory = changeRecorder
+	}
+
+	var fleetInventory *fleet.Inventory
+	var adminFleet adminCommon.FleetInventory
+	if cfg.Fleet.Enabled {
+		fleetConfig, err := fleet.ConfigFromOptions(&cfg.Fleet)
+		if err != nil {
+			return common.NewInitError(fmt.Errorf("error parsing fleet options: %w", err), common.ExitInvalidConfiguration)
+		}
+		fleetInventory = fleet.NewInventory(fleetConfig)
+		adminFleet = fleetInventory
+	}
+
+	cfgForAdmin := *cfg
+	cfgForAdmin.Apikey = logging.ObfuscateAPIKey(cfgForAdmin.Apikey)
+	cfgForAdmin.Admin = cfgForAdmin.Admin.Redacted()
+	cfgForAdmin.Integrations = cfgForAdmin.Integrations.Redacted()
+	cfgForAdmin.Healthcheck.Alerts = cfgForAdmin.Healthcheck.Alerts.Redacted()
+	cfgForAdmin.Publishing = cfgForAdmin.Publishing.Redacted()
+	adminServer, err := admin.NewServer(&admin.Options{
+		Host:              cfg.Admin.Host,
+		Port:              int(cfg.Admin.Port),
+		Name:              "Split Proxy dashboard",
+		Proxy:             true,
+		Username:          cfg.Admin.Username,
+		Password:          cfg.Admin.Password,
+		Tokens:            cfg.Admin.Tokens,
+		TokensFile:        cfg.Admin.TokensFile,
+		AuditLogFile:      cfg.Admin.AuditLogFile,
+		TLSCertFile:       cfg.Admin.TLSCertFile,
+		TLSKeyFile:        cfg.Admin.TLSKeyFile,
+		TLSClientCAFile:   cfg.Admin.TLSClientCAFile,
+		SecureHC:          cfg.Admin.SecureHC,
+		Logger:            adminLogger,
+		Storages:          storages,
+		Runtime:           rtm,
+		Snapshotter:       dbInstance,
+		Synchronizer:      sync,
+		DataWiper:         dataWiper,
+		HcAppMonitor:      appMonitor,
+		HcServicesMonitor: servicesMonitor,
+		Probes:            probeEvaluator,
+		LogLevels:         logLevels,
+		ChangeHistory:     changeHistory,
+		ClientAPIKeys:     apikeys.NewAdminView(clientKeys),
+		HTTPCache:         caching.NewAdminView(httpCache),
+		Fleet:             adminFleet,
+		FullConfig:        cfgForAdmin,
+	})
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error starting admin server: %w", err), common.ExitAdminError)
+	}
+	go func() {
+		if err := admin.ListenAndServe(adminServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error running admin server: ", err)
+		}
+	}()
+
 	// Run Sync Manager
 	before := time.Now()
 	go syncManager.Start()
@@ -155,15 +429,23 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 	switch status {
 	case synchronizer.Ready:
 		logger.Info("Synchronizer tasks started")
+		probeEvaluator.MarkStarted()
+		if offlineMode {
+			if cfg.Offline.DataDir != "" {
+				appMonitor.Start()
+			}
+			break
+		}
 		appMonitor.Start()
 		servicesMonitor.Start()
+		healthAlerts.Start()
 		workers.TelemetryRecorder.SynchronizeConfig(
 			telemetry.InitConfig{
 				AdvancedConfig: *advanced,
 				TaskPeriods: conf.TaskPeriods{
-					SplitSync:     int(cfg.Sync.SplitRefreshRateMs / 1000),
-					SegmentSync:   int(cfg.Sync.SegmentRefreshRateMs / 1000),
-					TelemetrySync: int(cfg.Sync.Advanced.InternalMetricsRateMs / 1000),
+					SplitSync:     common.PeriodSecs(cfg.Sync.SplitRefreshRateMs),
+					SegmentSync:   common.PeriodSecs(cfg.Sync.SegmentRefreshRateMs),
+					TelemetrySync: common.PeriodSecs(cfg.Sync.Advanced.InternalMetricsRateMs),
 				},
 				ManagerConfig: conf.ManagerConfig{
 					ListenerEnabled: cfg.Integrations.ImpressionListener.Endpoint != "",
@@ -180,67 +462,91 @@ func Start(logger logging.LoggerInterface, cfg *pconf.Main) error {
 			return common.NewInitError(fmt.Errorf("error instantiating sync manager: %w", err), common.ExitTaskInitialization)
 		}
 		logger.Warning("Failed to perform initial sync with split servers but continuing from snapshot. Will keep retrying in BG")
+		markStartedWhenPopulated(probeEvaluator, splitStorage, time.Second)
 	}
 
-	rtm := common.NewRuntime(false, syncManager, logger, "Split Proxy", nil, nil, appMonitor, servicesMonitor)
-	storages := adminCommon.Storages{
-		SplitStorage:          splitStorage,
-		SegmentStorage:        segmentStorage,
-		LocalTelemetryStorage: localTelemetryStorage,
+	tlsConfig, err := buildTLSConfig(&cfg.Server.TLS, logger)
+	if err != nil {
+		return common.NewInitError(fmt.Errorf("error setting up proxy server tls: %w", err), common.ExitInvalidConfiguration)
 	}
 
-	// --------------------------- ADMIN DASHBOARD ------------------------------
-	cfgForAdmin := *cfg
-	cfgForAdmin.Apikey = logging.ObfuscateAPIKey(cfgForAdmin.Apikey)
-	adminServer, err := admin.NewServer(&admin.Options{
-		Host:              cfg.Admin.Host,
-		Port:              int(cfg.Admin.Port),
-		Name:              "Split Proxy dashboard",
-		Proxy:             true,
-		Username:          cfg.Admin.Username,
-		Password:          cfg.Admin.Password,
-		Logger:            logger,
-		Storages:          storages,
-		Runtime:           rtm,
-		Snapshotter:       dbInstance,
-		HcAppMonitor:      appMonitor,
-		HcServicesMonitor: servicesMonitor,
-		FullConfig:        cfgForAdmin,
-	})
+	rateLimitRules, err := ratelimit.ParseRules(cfg.Server.RateLimit.Rules)
 	if err != nil {
-		return common.NewInitError(fmt.Errorf("error starting admin server: %w", err), common.ExitAdminError)
+		return common.NewInitError(fmt.Errorf("error parsing rate limit rules: %w", err), common.ExitInvalidConfiguration)
+	}
+
+	var rateLimiter *ratelimit.Limiter
+	if len(rateLimitRules) > 0 {
+		rateLimiter = ratelimit.NewLimiter(rateLimitRules, int(cfg.Server.RateLimit.MaxTrackedClients))
 	}
-	go adminServer.ListenAndServe()
 
 	proxyOptions := &Options{
-		Host:                cfg.Server.Host,
-		Port:                int(cfg.Server.Port),
-		APIKeys:             cfg.Server.ClientApikeys,
-		DebugOn:             strings.ToLower(cfg.Logging.Level) == "debug" || strings.ToLower(cfg.Logging.Level) == "verbose",
-		Logger:              logger,
-		ProxySplitStorage:   splitStorage,
-		SplitFetcher:        splitAPI.SplitFetcher,
-		ProxySegmentStorage: segmentStorage,
-		Telemetry:           localTelemetryStorage,
-		ImpressionsSink:     impressionTask,
-		ImpressionCountSink: impressionCountTask,
-		EventsSink:          eventsTask,
-		TelemetryConfigSink: telemetryConfigTask,
-		TelemetryUsageSink:  telemetryUsageTask,
-		Cache:               httpCache,
-	}
-
-	if ilcfg := cfg.Integrations.ImpressionListener; ilcfg.Endpoint != "" {
-		var err error
-		proxyOptions.ImpressionListener, err = impressionlistener.NewImpressionBulkListener(ilcfg.Endpoint, int(ilcfg.QueueSize), nil)
+		Host:                       cfg.Server.Host,
+		Port:                       int(cfg.Server.Port),
+		APIKeys:                    cfg.Server.ClientApikeys,
+		APIKeyManager:              clientKeys,
+		DebugOn:                    strings.ToLower(cfg.Logging.Level) == "debug" || strings.ToLower(cfg.Logging.Level) == "verbose",
+		Logger:                     splitlog.Component(logger, splitlog.ComponentControllers),
+		ProxySplitStorage:          splitStorage,
+		SplitFetcher:               splitFetcher,
+		ProxySegmentStorage:        segmentStorage,
+		Telemetry:                  localTelemetryStorage,
+		ImpressionsSink:            impressionTask,
+		ImpressionCountSink:        impressionCountTask,
+		EventsSink:                 eventsTask,
+		TelemetryConfigSink:        telemetryConfigTask,
+		TelemetryUsageSink:         telemetryUsageTask,
+		Cache:                      httpCache,
+		TLSConfig:                  tlsConfig,
+		RateLimiter:                rateLimiter,
+		TrustForwardedFor:          cfg.Server.RateLimit.TrustForwardedFor,
+		SplitChangesCacheControl:   cfg.Server.CacheControl.SplitChanges,
+		SegmentChangesCacheControl: cfg.Server.CacheControl.SegmentChanges,
+		MySegmentsCacheControl:     cfg.Server.CacheControl.MySegments,
+		HTTP2:                      cfg.Server.HTTP2,
+		ReadTimeout:                time.Duration(cfg.Server.ReadTimeoutMs) * time.Millisecond,
+		ReadHeaderTimeout:          time.Duration(cfg.Server.ReadHeaderTimeoutMs) * time.Millisecond,
+		WriteTimeout:               time.Duration(cfg.Server.WriteTimeoutMs) * time.Millisecond,
+		IdleTimeout:                time.Duration(cfg.Server.IdleTimeoutMs) * time.Millisecond,
+		MaxHeaderBytes:             int(cfg.Server.MaxHeaderBytes),
+		Ingestion: controllers.IngestionOptions{
+			MaxImpressionsBodyBytes: cfg.Server.Ingestion.MaxImpressionsBodyBytes,
+			MaxEventsBodyBytes:      cfg.Server.Ingestion.MaxEventsBodyBytes,
+			MaxTelemetryBodyBytes:   cfg.Server.Ingestion.MaxTelemetryBodyBytes,
+			ValidatePayloads:        cfg.Server.Ingestion.ValidatePayloads,
+		},
+		Fleet: fleetInventory,
+	}
+
+	if ilcfg := cfg.Integrations.ImpressionListener; ilcfg.Endpoint != "" && offlineMode {
+		logger.Warning("Impression listener is not available in offline mode. Ignoring.")
+	} else if ilcfg.Endpoint != "" {
+		listener, err := impressionlistener.NewImpressionBulkListener(ilcfg.Endpoint, int(ilcfg.QueueSize), nil)
 		if err != nil {
 			return common.NewInitError(fmt.Errorf("error instantiating impression listener: %w", err), common.ExitTaskInitialization)
 		}
-		proxyOptions.ImpressionListener.Start()
+		listener.Start()
+		proxyOptions.ImpressionListener = listener
+
+		if threshold := cfg.Healthcheck.App.ListenerFailureRateThreshold; threshold > 0 {
+			appMonitor.RegisterCounter(hcAppCounter.NewCheckCounter(hcAppCounter.CheckConfig{
+				Name:     "ImpressionListener",
+				Severity: hcAppCounter.Low,
+				Period:   10,
+				Check: hcAppCounter.NewRateCheck(func() (int64, int64) {
+					stats := listener.DeliveryStats()
+					return stats.Submitted, stats.Dropped + stats.Failed
+				}, threshold),
+			}, hcLogger))
+		}
 	}
 
 	proxyAPI := New(proxyOptions)
-	go proxyAPI.Start()
+	go func() {
+		if err := proxyAPI.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error running proxy server: ", err)
+		}
+	}()
 
 	rtm.RegisterShutdownHandler()
 	rtm.Block()
@@ -0,0 +1,131 @@
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-split-commons/v4/service"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+const (
+	// SplitChangesFile is the name of the file (inside the data directory) holding split definitions
+	SplitChangesFile = "splitChanges.json"
+
+	// SegmentChangesDir is the name of the directory (inside the data directory) holding one <segmentName>.json file per segment
+	SegmentChangesDir = "segmentChanges"
+)
+
+// FileSplitFetcher reads split changes from a splitChanges.json file in a local directory.
+// The file must have the same format as the one returned by split servers. Its `till` is used as the change number
+// of the whole file, which means that in order for the changes to be picked up, `till` must be increased.
+// Splits that need to be removed must be kept in the file with status "ARCHIVED".
+type FileSplitFetcher struct {
+	path   string
+	logger logging.LoggerInterface
+}
+
+// NewFileSplitFetcher constructs a new file split fetcher. If no data directory is provided, no changes are ever reported
+func NewFileSplitFetcher(dataDir string, logger logging.LoggerInterface) *FileSplitFetcher {
+	var path string
+	if dataDir != "" {
+		path = filepath.Join(dataDir, SplitChangesFile)
+	}
+	return &FileSplitFetcher{path: path, logger: logger}
+}
+
+// Fetch returns the contents of the file if it's newer than the provided change number
+func (f *FileSplitFetcher) Fetch(changeNumber int64, _ *service.FetchOptions) (*dtos.SplitChangesDTO, error) {
+	if f.path == "" {
+		return &dtos.SplitChangesDTO{Since: changeNumber, Till: changeNumber, Splits: []dtos.SplitDTO{}}, nil
+	}
+
+	var changes dtos.SplitChangesDTO
+	found, err := readJSONFile(f.path, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		f.logger.Debug(fmt.Sprintf("file %s not found. assuming no changes", f.path))
+		return &dtos.SplitChangesDTO{Since: changeNumber, Till: changeNumber, Splits: []dtos.SplitDTO{}}, nil
+	}
+
+	if changes.Till <= changeNumber {
+		return &dtos.SplitChangesDTO{Since: changeNumber, Till: changeNumber, Splits: []dtos.SplitDTO{}}, nil
+	}
+
+	changes.Since = changeNumber
+	return &changes, nil
+}
+
+// FileSegmentFetcher reads segment changes from <segmentName>.json files in a local directory.
+// Same as with splits, the `till` property of the file is used as the change number for the whole file.
+type FileSegmentFetcher struct {
+	dir    string
+	logger logging.LoggerInterface
+}
+
+// NewFileSegmentFetcher constructs a new file segment fetcher. If no data directory is provided, no changes are ever reported
+func NewFileSegmentFetcher(dataDir string, logger logging.LoggerInterface) *FileSegmentFetcher {
+	var dir string
+	if dataDir != "" {
+		dir = filepath.Join(dataDir, SegmentChangesDir)
+	}
+	return &FileSegmentFetcher{dir: dir, logger: logger}
+}
+
+// Fetch returns the contents of the segment file if it's newer than the provided change number
+func (f *FileSegmentFetcher) Fetch(name string, changeNumber int64, _ *service.FetchOptions) (*dtos.SegmentChangesDTO, error) {
+	if name == "" || filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid segment name '%s'", name)
+	}
+
+	noChanges := &dtos.SegmentChangesDTO{Name: name, Added: []string{}, Removed: []string{}, Since: changeNumber, Till: changeNumber}
+	if f.dir == "" {
+		return noChanges, nil
+	}
+
+	path := filepath.Join(f.dir, name+".json")
+
+	var changes dtos.SegmentChangesDTO
+	found, err := readJSONFile(path, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		f.logger.Debug(fmt.Sprintf("file %s not found. assuming no changes", path))
+		return noChanges, nil
+	}
+
+	if changes.Till <= changeNumber {
+		return noChanges, nil
+	}
+
+	changes.Name = name
+	changes.Since = changeNumber
+	return &changes, nil
+}
+
+func readJSONFile(path string, target interface{}) (bool, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	if err = json.Unmarshal(raw, target); err != nil {
+		return false, fmt.Errorf("error parsing file %s: %w", path, err)
+	}
+	return true, nil
+}
+
+var _ service.SplitFetcher = (*FileSplitFetcher)(nil)
+var _ service.SegmentFetcher = (*FileSegmentFetcher)(nil)
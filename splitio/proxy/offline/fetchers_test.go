@@ -0,0 +1,96 @@
+package offline
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestFileSplitFetcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "offline")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logger := logging.NewLogger(nil)
+	fetcher := NewFileSplitFetcher(dir, logger)
+
+	changes, err := fetcher.Fetch(-1, nil)
+	if err != nil {
+		t.Error("a missing file should not return an error. Got: ", err)
+	}
+	if changes.Since != -1 || changes.Till != -1 || len(changes.Splits) != 0 {
+		t.Error("a missing file should be treated as no changes. Got: ", changes)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, SplitChangesFile), []byte(`{"since":5,"till":10,"splits":[{"name":"split1","status":"ACTIVE"}]}`), 0644)
+	changes, err = fetcher.Fetch(-1, nil)
+	if err != nil {
+		t.Error("no error should be returned. Got: ", err)
+	}
+	if changes.Since != -1 || changes.Till != 10 || len(changes.Splits) != 1 || changes.Splits[0].Name != "split1" {
+		t.Error("wrong changes: ", changes)
+	}
+
+	changes, err = fetcher.Fetch(10, nil)
+	if err != nil {
+		t.Error("no error should be returned. Got: ", err)
+	}
+	if changes.Since != 10 || changes.Till != 10 || len(changes.Splits) != 0 {
+		t.Error("there should be no changes after reaching the file's till. Got: ", changes)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, SplitChangesFile), []byte(`{"since":`), 0644)
+	if _, err = fetcher.Fetch(10, nil); err == nil {
+		t.Error("a malformed file should return an error")
+	}
+
+	changes, err = NewFileSplitFetcher("", logger).Fetch(3, nil)
+	if err != nil || changes.Since != 3 || changes.Till != 3 {
+		t.Error("a fetcher without data dir should never report changes")
+	}
+}
+
+func TestFileSegmentFetcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "offline")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, SegmentChangesDir), 0755)
+
+	logger := logging.NewLogger(nil)
+	fetcher := NewFileSegmentFetcher(dir, logger)
+
+	changes, err := fetcher.Fetch("segment1", -1, nil)
+	if err != nil {
+		t.Error("a missing file should not return an error. Got: ", err)
+	}
+	if changes.Name != "segment1" || changes.Since != -1 || changes.Till != -1 {
+		t.Error("a missing file should be treated as no changes. Got: ", changes)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, SegmentChangesDir, "segment1.json"), []byte(`{"added":["k1","k2"],"removed":["k3"],"since":-1,"till":20}`), 0644)
+	changes, err = fetcher.Fetch("segment1", 15, nil)
+	if err != nil {
+		t.Error("no error should be returned. Got: ", err)
+	}
+	if changes.Name != "segment1" || changes.Since != 15 || changes.Till != 20 || len(changes.Added) != 2 || len(changes.Removed) != 1 {
+		t.Error("wrong changes: ", changes)
+	}
+
+	changes, err = fetcher.Fetch("segment1", 20, nil)
+	if err != nil || changes.Since != 20 || changes.Till != 20 || len(changes.Added) != 0 {
+		t.Error("there should be no changes after reaching the file's till. Got: ", changes, err)
+	}
+
+	if _, err = fetcher.Fetch("../segment1", -1, nil); err == nil {
+		t.Error("segment names with path components should be rejected")
+	}
+}
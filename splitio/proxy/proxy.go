@@ -1,16 +1,26 @@
 package proxy
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/splitio/go-split-commons/v4/service"
 	"github.com/splitio/go-toolkit/v5/logging"
 
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	"github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/controllers"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/controllers/middleware"
 	proxyMW "github.com/splitio/split-synchronizer/v5/splitio/proxy/controllers/middleware"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/ratelimit"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 	proxyStorage "github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/tasks"
@@ -18,7 +28,6 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
-	"github.com/splitio/gincache"
 )
 
 // Options struct to set options for Proxy mode.
@@ -26,7 +35,7 @@ type Options struct {
 	// Logger to propagate everywhere
 	Logger logging.LoggerInterface
 
-	// Host to where incoming http connections will be listened
+	// Host to where incoming http connections will be listened. unix:<path> listens on a unix socket
 	Host string
 
 	// HTTP port to use for the server
@@ -35,6 +44,10 @@ type Options struct {
 	// APIKeys used for authenticating proxy requests
 	APIKeys []string
 
+	// APIKeyManager used for authenticating proxy requests with keys that can be added/revoked at runtime.
+	// Takes precedence over APIKeys if set
+	APIKeyManager *apikeys.Manager
+
 	// ImpressionListener to forward incoming impression bulks to
 	ImpressionListener impressionlistener.ImpressionBulkListener
 
@@ -68,7 +81,38 @@ type Options struct {
 	// used to record local metrics
 	Telemetry proxyStorage.ProxyEndpointTelemetry
 
-	Cache *gincache.Middleware
+	// used to cache responses to sdk requests. Nothing is cached if nil
+	Cache *caching.HTTPCache
+
+	// used to reject requests exceeding the configured limits. No limits are enforced if nil
+	RateLimiter *ratelimit.Limiter
+
+	// Whether to identify rate-limited clients by X-Forwarded-For/X-Real-Ip instead of the connection address
+	TrustForwardedFor bool
+
+	// Cache-Control headers to send along with successful responses, by endpoint. No header is sent if empty
+	SplitChangesCacheControl   string
+	SegmentChangesCacheControl string
+	MySegmentsCacheControl     string
+
+	// TLS config used to serve sdks. Plain http is used if nil
+	TLSConfig *tls.Config
+
+	// Whether to negotiate HTTP/2 with clients connecting over TLS
+	HTTP2 bool
+
+	// Timeouts & limits applied to incoming connections. Zero means no limit (or the stdlib default for MaxHeaderBytes)
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// Body size limits & validation applied to impressions, events & telemetry payloads
+	Ingestion controllers.IngestionOptions
+
+	// used to keep track of the sdk instances connecting to the proxy. Nothing is tracked if nil
+	Fleet *fleet.Inventory
 }
 
 // API bundles all components required to answer API calls from split sdks
@@ -81,7 +125,47 @@ type API struct {
 
 // Start the Proxy service endpoints
 func (s *API) Start() error {
-	return s.server.ListenAndServe()
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	if s.server.TLSConfig != nil {
+		return s.server.ServeTLS(listener, "", "")
+	}
+	return s.server.Serve(listener)
+}
+
+func (s *API) listen() (net.Listener, error) {
+	if !strings.HasPrefix(s.server.Addr, unixSocketPrefix) {
+		return net.Listen("tcp", s.server.Addr)
+	}
+
+	path := strings.TrimPrefix(s.server.Addr, unixSocketPrefix)
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// removeStaleSocket removes a socket left behind by a previous run. Any other kind of file is left untouched
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error checking existing unix socket %s: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("refusing to remove %s: file exists and is not a unix socket", path)
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing existing unix socket %s: %w", path, err)
+	}
+	return nil
 }
 
 // New instantiates a new Server
@@ -91,6 +175,9 @@ func New(options *Options) *API {
 	}
 
 	apikeyValidator := proxyMW.NewAPIKeyValidator(options.APIKeys)
+	if options.APIKeyManager != nil {
+		apikeyValidator = proxyMW.NewManagedAPIKeyValidator(options.APIKeyManager)
+	}
 	authController := controllers.NewAuthServerController()
 	sdkController := setupSdkController(options)
 	eventsController := setupEventsController(options, apikeyValidator)
@@ -101,10 +188,26 @@ func New(options *Options) *API {
 	router.Use(setupCorsMiddleware())
 	router.Use(middleware.SetEndpoint)
 	router.Use(proxyMW.NewProxyMetricsMiddleware(options.Telemetry).Track)
+	if options.RateLimiter != nil {
+		router.Use(proxyMW.NewRateLimiter(options.RateLimiter, apikeyValidator.Exists, options.TrustForwardedFor, options.Telemetry).Handle)
+	}
+
+	conditional := proxyMW.NewConditionalRequests(map[int]string{
+		storage.SplitChangesEndpoint:   options.SplitChangesCacheControl,
+		storage.SegmentChangesEndpoint: options.SegmentChangesCacheControl,
+		storage.MySegmentsEndpoint:     options.MySegmentsCacheControl,
+	})
+
+	// only requests with a valid apikey are tracked, so that spoofed metadata cannot fill the inventory
+	authenticated := []gin.HandlerFunc{apikeyValidator.AsMiddleware}
+	if options.Fleet != nil {
+		authenticated = append(authenticated, proxyMW.NewFleetTracker(options.Fleet).Handle)
+	}
 
 	// split the main router into regular & beacon endpoints
 	regular := router.Group("/api")
-	regular.Use(apikeyValidator.AsMiddleware)
+	regular.Use(authenticated...)
+	regular.Use(conditional.Handle)
 	regular.Use(gzip.Gzip(gzip.DefaultCompression))
 
 	// Beacon endpoints group
@@ -115,23 +218,50 @@ func New(options *Options) *API {
 	// and pass it to Auth & Sdk controllers
 	if options.Cache != nil {
 		cacheableRouter = router.Group("/api")
-		cacheableRouter.Use(apikeyValidator.AsMiddleware)
+		cacheableRouter.Use(authenticated...)
+		cacheableRouter.Use(conditional.Handle) // must go before the cache to see ETags of cached entries
 		cacheableRouter.Use(options.Cache.Handle)
 		cacheableRouter.Use(gzip.Gzip(gzip.DefaultCompression))
 	}
 	authController.Register(cacheableRouter)
-	sdkController.Register(cacheableRouter)
+	sdkController.Register(cacheableRouter, regular)
 	eventsController.Register(regular, beacon)
 	telemetryController.Register(regular)
 
 	return &API{
-		server:              &http.Server{Addr: fmt.Sprintf("0.0.0.0:%d", options.Port), Handler: router},
+		server:              setupServer(options, router),
 		sdkConroller:        sdkController,
 		eventsConroller:     eventsController,
 		telemetryController: telemetryController,
 	}
 }
 
+const unixSocketPrefix = "unix:"
+
+func setupServer(options *Options, handler http.Handler) *http.Server {
+	addr := options.Host
+	if !strings.HasPrefix(addr, unixSocketPrefix) {
+		addr = net.JoinHostPort(options.Host, fmt.Sprintf("%d", options.Port))
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         options.TLSConfig,
+		ReadTimeout:       options.ReadTimeout,
+		ReadHeaderTimeout: options.ReadHeaderTimeout,
+		WriteTimeout:      options.WriteTimeout,
+		IdleTimeout:       options.IdleTimeout,
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+	}
+
+	if options.TLSConfig != nil && !options.HTTP2 {
+		// a non-nil empty map prevents the server from negotiating h2
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	return server
+}
+
 func setupSdkController(options *Options) *controllers.SdkServerController {
 	return controllers.NewSdkServerController(
 		options.Logger,
@@ -149,6 +279,7 @@ func setupEventsController(options *Options, apikeyValidator *proxyMW.APIKeyVali
 		options.EventsSink,
 		options.ImpressionListener,
 		apikeyValidator.IsValid,
+		ingestionOptions(options),
 	)
 }
 
@@ -157,9 +288,19 @@ func setupTelemetryController(options *Options) *controllers.TelemetryServerCont
 		options.Logger,
 		options.TelemetryConfigSink,
 		options.TelemetryUsageSink,
+		ingestionOptions(options),
+		options.Fleet,
 	)
 }
 
+func ingestionOptions(options *Options) *controllers.IngestionOptions {
+	ingestion := options.Ingestion
+	if ingestion.Telemetry == nil {
+		ingestion.Telemetry = options.Telemetry
+	}
+	return &ingestion
+}
+
 func setupCorsMiddleware() func(*gin.Context) {
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
@@ -172,6 +313,8 @@ func setupCorsMiddleware() func(*gin.Context) {
 		"SplitSDKVersion",
 		"SplitSDKImpressionsMode",
 		"Authorization",
+		"If-None-Match",
 	}
+	corsConfig.ExposeHeaders = []string{"ETag"}
 	return cors.New(corsConfig)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/splitio/go-split-commons/v4/dtos"
 	serviceMocks "github.com/splitio/go-split-commons/v4/service/mocks"
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/split-synchronizer/v5/splitio/common/fleet"
 	ilmock "github.com/splitio/split-synchronizer/v5/splitio/common/impressionlistener/mocks"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/caching"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
@@ -104,6 +105,21 @@ func TestSplitChangesEndpoints(t *testing.T) {
 	if c := atomic.LoadInt64(&changesSinceCalls); c != 2 {
 		t.Error("endpoint handler should have 2 call. has ", c)
 	}
+
+	// A conditional request with the current ETag should get a 304 from the cached entry
+	etag := headers.Get("ETag")
+	if etag == "" {
+		t.Error("an ETag should be returned")
+	}
+
+	status, body, _ = get("splitChanges?since=-1", opts.Port, map[string]string{"Authorization": "Bearer someApiKey", "If-None-Match": etag})
+	if status != 304 || len(body) != 0 {
+		t.Error("a 304 with no body should be returned. Got: ", status, string(body))
+	}
+
+	if c := atomic.LoadInt64(&changesSinceCalls); c != 2 {
+		t.Error("endpoint handler should have 2 call. has ", c)
+	}
 }
 
 func TestSegmentChangesAndMySegmentsEndpoints(t *testing.T) {
@@ -255,6 +271,41 @@ func TestSegmentChangesAndMySegmentsEndpoints(t *testing.T) {
 	}
 }
 
+func TestFleetTrackingRequiresValidAPIKey(t *testing.T) {
+	opts := makeOpts()
+	opts.Fleet = fleet.NewInventory(fleet.Config{})
+	opts.ProxySplitStorage = &pstorageMocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			return &dtos.SplitChangesDTO{Since: since, Till: 1}, nil
+		},
+	}
+	proxy := New(opts)
+	go proxy.Start()
+	time.Sleep(1 * time.Second) // Let the scheduler switch the current thread/gr and start the server
+
+	status, _, _ := get("splitChanges?since=-1", opts.Port, map[string]string{"SplitSDKVersion": "go-6.0.0", "SplitSDKMachineName": "spoofed"})
+	if status != 401 {
+		t.Error("status should be 401. Is", status)
+	}
+
+	if instances := opts.Fleet.Summary().Instances; len(instances) != 0 {
+		t.Error("requests without a valid apikey should not be tracked. Got: ", instances)
+	}
+
+	status, _, _ = get("splitChanges?since=-1", opts.Port, map[string]string{
+		"Authorization":       "Bearer someApiKey",
+		"SplitSDKVersion":     "go-6.0.0",
+		"SplitSDKMachineName": "legit",
+	})
+	if status != 200 {
+		t.Error("status should be 200. Is", status)
+	}
+
+	if instances := opts.Fleet.Summary().Instances; len(instances) != 1 || instances[0].MachineName != "legit" {
+		t.Error("only the authenticated instance should be tracked. Got: ", instances)
+	}
+}
+
 func makeOpts() *Options {
 	return &Options{
 		Logger:              logging.NewLogger(nil),
@@ -271,7 +322,7 @@ func makeOpts() *Options {
 		TelemetryConfigSink: &taskMocks.MockDeferredRecordingTask{},
 		TelemetryUsageSink:  &taskMocks.MockDeferredRecordingTask{},
 		Telemetry:           storage.NewProxyTelemetryFacade(),
-		Cache:               caching.MakeProxyCache(),
+		Cache:               caching.MakeProxyCache(1000, 0),
 	}
 }
 
@@ -0,0 +1,54 @@
+package publisher
+
+import (
+	"errors"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/conf"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// ErrMultipleSinks is returned when both a directory & a bucket are configured
+var ErrMultipleSinks = errors.New("only one of publishing directory & s3 bucket can be set")
+
+// BuildFromConfig constructs a publisher for the configured sink. Returns nil if publishing is not configured
+func BuildFromConfig(
+	cfg *conf.Publishing,
+	splitStorage storage.ProxySplitStorage,
+	segmentStorage storage.ProxySegmentStorage,
+	logger logging.LoggerInterface,
+) (*Publisher, error) {
+	var sink Sink
+	switch {
+	case cfg.Dir != "" && cfg.S3.Bucket != "":
+		return nil, ErrMultipleSinks
+	case cfg.Dir != "":
+		dirSink, err := NewDirSink(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		sink = dirSink
+	case cfg.S3.Bucket != "":
+		s3Sink, err := NewS3Sink(S3Options{
+			Endpoint:        cfg.S3.Endpoint,
+			Bucket:          cfg.S3.Bucket,
+			Region:          cfg.S3.Region,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sink = s3Sink
+	default:
+		return nil, nil
+	}
+
+	return NewPublisher(Options{
+		Prefix:         cfg.Prefix,
+		Debounce:       time.Duration(cfg.DebounceMs) * time.Millisecond,
+		RetainVersions: int(cfg.RetainVersions),
+	}, sink, splitStorage, segmentStorage, logger), nil
+}
@@ -0,0 +1,561 @@
+package publisher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/go-toolkit/v5/struct/traits/lifecycle"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changehook"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// Cache-Control headers attached to published files. Versioned payloads never change once written,
+// whereas the manifest is overwritten on every publication
+const (
+	CacheControlVersioned = "public, max-age=31536000, immutable"
+	CacheControlManifest  = "no-cache"
+)
+
+// ManifestFile is the name of the file pointing to the latest version of every payload
+const ManifestFile = "manifest.json"
+
+// ErrAlreadyRunning is returned when attempting to start an already running publisher
+var ErrAlreadyRunning = errors.New("publisher is already running")
+
+// ErrNotRunning is returned when attempting to stop a non-running publisher
+var ErrNotRunning = errors.New("publisher is not running")
+
+// Version points to a published payload
+type Version struct {
+	ChangeNumber int64  `json:"changeNumber"`
+	Path         string `json:"path"`
+}
+
+// Manifest references the latest published version of the splitChanges payload & every segment
+type Manifest struct {
+	UpdatedAt    int64              `json:"updatedAt"`
+	SplitChanges *Version           `json:"splitChanges,omitempty"`
+	Segments     map[string]Version `json:"segments"`
+}
+
+// Options for the publisher
+type Options struct {
+	Prefix         string
+	Debounce       time.Duration
+	RetryInterval  time.Duration
+	RetainVersions int
+}
+
+// Stats contains the amount of files written & failed publications since startup
+type Stats struct {
+	Published int64 `json:"published"`
+	Pruned    int64 `json:"pruned"`
+	Failed    int64 `json:"failed"`
+}
+
+// Publisher writes the full splitChanges payload & every segment's payload as versioned files to a sink
+// whenever they change, along with a manifest pointing to the latest ones
+type Publisher struct {
+	lifecycle     lifecycle.Manager
+	sink          Sink
+	splitStorage  storage.ProxySplitStorage
+	segmentStore  storage.ProxySegmentStorage
+	logger        logging.LoggerInterface
+	prefix        string
+	debounce      time.Duration
+	retryInterval time.Duration
+	retain        int
+	signal        chan struct{}
+	mutex         sync.Mutex
+	splitsDirty   bool
+	dirtySegments map[string]struct{}
+	manifest      Manifest
+	manifestDirty bool
+	history       map[string][]string
+	stale         []string
+	referenced    map[string]struct{}
+	published     int64
+	pruned        int64
+	failed        int64
+}
+
+// NewPublisher constructs a new publisher
+func NewPublisher(
+	opts Options,
+	sink Sink,
+	splitStorage storage.ProxySplitStorage,
+	segmentStorage storage.ProxySegmentStorage,
+	logger logging.LoggerInterface,
+) *Publisher {
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 30 * time.Second
+	}
+
+	publisher := &Publisher{
+		sink:          sink,
+		splitStorage:  splitStorage,
+		segmentStore:  segmentStorage,
+		logger:        logger,
+		prefix:        opts.Prefix,
+		debounce:      opts.Debounce,
+		retryInterval: opts.RetryInterval,
+		retain:        opts.RetainVersions,
+		signal:        make(chan struct{}, 1),
+		dirtySegments: make(map[string]struct{}),
+		manifest:      Manifest{Segments: make(map[string]Version)},
+		history:       make(map[string][]string),
+	}
+	publisher.lifecycle.Setup()
+	return publisher
+}
+
+// SplitsChanged schedules the publication of the splitChanges payload
+func (p *Publisher) SplitsChanged(names []string, changeNumber int64) {
+	p.mutex.Lock()
+	p.splitsDirty = true
+	p.mutex.Unlock()
+	p.notify()
+}
+
+// SegmentChanged schedules the publication of a segment payload
+func (p *Publisher) SegmentChanged(name string, changeNumber int64, updatedKeys []string) {
+	p.mutex.Lock()
+	p.dirtySegments[name] = struct{}{}
+	p.mutex.Unlock()
+	p.notify()
+}
+
+// Manifest returns a copy of the last published manifest
+func (p *Publisher) Manifest() Manifest {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.copyManifest()
+}
+
+// Stats returns the number of files published, pruned & failed publications since startup
+func (p *Publisher) Stats() Stats {
+	return Stats{
+		Published: atomic.LoadInt64(&p.published),
+		Pruned:    atomic.LoadInt64(&p.pruned),
+		Failed:    atomic.LoadInt64(&p.failed),
+	}
+}
+
+// Start the bg task that publishes payloads. Everything currently in storage is published right away
+func (p *Publisher) Start() error {
+	if !p.lifecycle.BeginInitialization() {
+		return ErrAlreadyRunning
+	}
+
+	p.mutex.Lock()
+	p.splitsDirty = true
+	p.mutex.Unlock()
+	p.notify()
+
+	go func() {
+		defer p.lifecycle.ShutdownComplete()
+		if !p.lifecycle.InitializationComplete() {
+			return
+		}
+
+		if err := p.restoreHistory(); err != nil {
+			p.logger.Warning("error listing previously published files, older versions will not be pruned: ", err)
+		}
+
+		var retry <-chan time.Time
+		for {
+			select {
+			case <-p.lifecycle.ShutdownRequested():
+				return
+			case <-retry:
+			case <-p.signal:
+				// wait for further changes so that bursts end up in a single publication
+				select {
+				case <-p.lifecycle.ShutdownRequested():
+					return
+				case <-time.After(p.debounce):
+				}
+			}
+
+			retry = nil
+			if err := p.publish(); err != nil {
+				atomic.AddInt64(&p.failed, 1)
+				p.logger.Error(fmt.Sprintf("error publishing payloads, retrying in %s: %s", p.retryInterval, err))
+				retry = time.After(p.retryInterval)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop the bg task
+func (p *Publisher) Stop(blocking bool) error {
+	if !p.lifecycle.BeginShutdown() {
+		return ErrNotRunning
+	}
+
+	if blocking {
+		p.lifecycle.AwaitShutdownComplete()
+	}
+
+	return nil
+}
+
+func (p *Publisher) notify() {
+	select {
+	case p.signal <- struct{}{}:
+	default: // a publication is already pending
+	}
+}
+
+// publish writes every pending payload followed by the manifest. Items that fail are kept as pending
+func (p *Publisher) publish() error {
+	p.mutex.Lock()
+	splitsDirty := p.splitsDirty
+	segments := make([]string, 0, len(p.dirtySegments))
+	for name := range p.dirtySegments {
+		segments = append(segments, name)
+	}
+	p.splitsDirty = false
+	p.dirtySegments = make(map[string]struct{})
+	manifest := p.copyManifest()
+	changed := p.manifestDirty
+	referenced := p.referenced
+	p.mutex.Unlock()
+
+	var errs []error
+
+	if splitsDirty {
+		published, current, err := p.publishSplits(&manifest)
+		if err != nil {
+			errs = append(errs, err)
+			p.markSplitsDirty()
+		}
+		changed = changed || published
+
+		if current != nil {
+			referenced = current
+			p.mutex.Lock()
+			p.referenced = current
+			p.mutex.Unlock()
+
+			// segments referenced for the first time need to be published as well
+			for name := range referenced {
+				if _, ok := manifest.Segments[name]; !ok {
+					segments = append(segments, name)
+				}
+			}
+		}
+	}
+
+	// segments no longer used by any split are dropped from the manifest
+	if referenced != nil {
+		for name := range manifest.Segments {
+			if _, ok := referenced[name]; !ok {
+				delete(manifest.Segments, name)
+				changed = true
+			}
+		}
+	}
+
+	sort.Strings(segments)
+	for idx, name := range segments {
+		if idx > 0 && segments[idx-1] == name {
+			continue
+		}
+		if _, ok := referenced[name]; referenced != nil && !ok {
+			continue
+		}
+		published, err := p.publishSegment(&manifest, name)
+		if err != nil {
+			errs = append(errs, err)
+			p.markSegmentDirty(name)
+		}
+		changed = changed || published
+	}
+
+	if changed {
+		manifest.UpdatedAt = time.Now().Unix() * 1000
+		body, err := json.Marshal(manifest)
+		if err == nil {
+			err = p.sink.Put(p.path(ManifestFile), body, CacheControlManifest)
+		}
+
+		p.mutex.Lock()
+		p.manifest = manifest
+		p.manifestDirty = err != nil // payloads are already written, only the manifest needs to be retried
+		p.mutex.Unlock()
+
+		if err != nil {
+			return fmt.Errorf("error writing manifest: %w", err)
+		}
+		atomic.AddInt64(&p.published, 1)
+	}
+
+	p.pruneStale()
+	p.pruneUnreferenced()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d payloads failed. first error: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+// publishSplits writes the splitChanges payload if it's newer than the one in the manifest. Returns whether the
+// manifest was updated & the segments referenced by the current splits (nil if splits are not synchronized yet)
+func (p *Publisher) publishSplits(manifest *Manifest) (bool, map[string]struct{}, error) {
+	changes, err := p.splitStorage.ChangesSince(-1)
+	if err != nil {
+		return false, nil, fmt.Errorf("error fetching splits: %w", err)
+	}
+
+	if changes.Till == -1 {
+		return false, nil, nil // not synchronized yet
+	}
+	referenced := referencedSegments(changes.Splits)
+
+	if manifest.SplitChanges != nil && manifest.SplitChanges.ChangeNumber == changes.Till {
+		return false, referenced, nil
+	}
+
+	target := path.Join("splitChanges", strconv.FormatInt(changes.Till, 10)+".json")
+	if err := p.put("splitChanges", target, changes); err != nil {
+		return false, referenced, err
+	}
+
+	manifest.SplitChanges = &Version{ChangeNumber: changes.Till, Path: target}
+	return true, referenced, nil
+}
+
+// publishSegment writes a segment payload if it's newer than the one in the manifest. Returns whether the manifest was updated
+func (p *Publisher) publishSegment(manifest *Manifest, name string) (bool, error) {
+	changes, err := p.segmentStore.ChangesSince(name, -1)
+	if err != nil {
+		if errors.Is(err, storage.ErrSegmentNotFound) {
+			return false, nil // will be published once fetched
+		}
+		return false, fmt.Errorf("error fetching segment '%s': %w", name, err)
+	}
+
+	if current, ok := manifest.Segments[name]; ok && current.ChangeNumber == changes.Till {
+		return false, nil
+	}
+
+	target := path.Join("segmentChanges", url.PathEscape(name), strconv.FormatInt(changes.Till, 10)+".json")
+	if err := p.put("segment:"+name, target, changes); err != nil {
+		return false, err
+	}
+
+	manifest.Segments[name] = Version{ChangeNumber: changes.Till, Path: target}
+	return true, nil
+}
+
+// put writes a versioned payload. Versions of the same item exceeding the retention limit are queued for removal
+// once a manifest no longer pointing to them has been written
+func (p *Publisher) put(item string, target string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error serializing %s: %w", target, err)
+	}
+
+	if err := p.sink.Put(p.path(target), body, CacheControlVersioned); err != nil {
+		return fmt.Errorf("error writing %s: %w", target, err)
+	}
+	atomic.AddInt64(&p.published, 1)
+
+	p.mutex.Lock()
+	versions := make([]string, 0, len(p.history[item])+1)
+	for _, current := range p.history[item] {
+		if current != target { // the same version is written again after a restart
+			versions = append(versions, current)
+		}
+	}
+	versions = append(versions, target)
+	if p.retain > 0 && len(versions) > p.retain {
+		p.stale = append(p.stale, versions[:len(versions)-p.retain]...)
+		versions = versions[len(versions)-p.retain:]
+	}
+	p.history[item] = versions
+	p.mutex.Unlock()
+	return nil
+}
+
+// restoreHistory lists the versions written by previous runs, so that they're pruned according to the retention limit
+func (p *Publisher) restoreHistory() error {
+	history := make(map[string][]string)
+	for _, dir := range []string{"splitChanges", "segmentChanges"} {
+		paths, err := p.sink.List(p.path(dir) + "/")
+		if err != nil {
+			return err
+		}
+
+		for _, current := range paths {
+			relative := strings.TrimPrefix(strings.TrimPrefix(current, p.prefix), "/")
+			if item, ok := historyItem(relative); ok {
+				history[item] = append(history[item], relative)
+			}
+		}
+	}
+
+	var stale []string
+	p.mutex.Lock()
+	for item, versions := range history {
+		sort.Slice(versions, func(i, j int) bool { return versionOf(versions[i]) < versionOf(versions[j]) })
+		if p.retain > 0 && len(versions) > p.retain {
+			stale = append(stale, versions[:len(versions)-p.retain]...)
+			versions = versions[len(versions)-p.retain:]
+		}
+		p.history[item] = versions
+	}
+	p.mutex.Unlock()
+
+	p.delete(stale)
+	return nil
+}
+
+// pruneStale removes the versions exceeding the retention limit, except for the ones the published manifest points to
+func (p *Publisher) pruneStale() {
+	var stale []string
+	p.mutex.Lock()
+	if !p.manifestDirty {
+		inUse := make(map[string]struct{}, len(p.manifest.Segments)+1)
+		if p.manifest.SplitChanges != nil {
+			inUse[p.manifest.SplitChanges.Path] = struct{}{}
+		}
+		for _, version := range p.manifest.Segments {
+			inUse[version.Path] = struct{}{}
+		}
+
+		for _, current := range p.stale {
+			if _, ok := inUse[current]; !ok {
+				stale = append(stale, current)
+			}
+		}
+		p.stale = nil
+	}
+	p.mutex.Unlock()
+
+	p.delete(stale)
+}
+
+// pruneUnreferenced forgets segments not referenced by any split nor by the published manifest.
+// Their files are removed unless versions are retained forever
+func (p *Publisher) pruneUnreferenced() {
+	var stale []string
+	p.mutex.Lock()
+	if p.referenced != nil && !p.manifestDirty {
+		for item, versions := range p.history {
+			name := strings.TrimPrefix(item, "segment:")
+			if name == item {
+				continue
+			}
+			if _, ok := p.referenced[name]; ok {
+				continue
+			}
+			if _, ok := p.manifest.Segments[name]; ok {
+				continue
+			}
+			if p.retain > 0 {
+				stale = append(stale, versions...)
+			}
+			delete(p.history, item)
+		}
+	}
+	p.mutex.Unlock()
+
+	p.delete(stale)
+}
+
+func (p *Publisher) delete(paths []string) {
+	for _, old := range paths {
+		if err := p.sink.Delete(p.path(old)); err != nil {
+			p.logger.Warning(fmt.Sprintf("error pruning published file %s: %s", old, err))
+			continue
+		}
+		atomic.AddInt64(&p.pruned, 1)
+	}
+}
+
+func (p *Publisher) path(relative string) string {
+	if p.prefix == "" {
+		return relative
+	}
+	return path.Join(p.prefix, relative)
+}
+
+func (p *Publisher) markSplitsDirty() {
+	p.mutex.Lock()
+	p.splitsDirty = true
+	p.mutex.Unlock()
+}
+
+func (p *Publisher) markSegmentDirty(name string) {
+	p.mutex.Lock()
+	p.dirtySegments[name] = struct{}{}
+	p.mutex.Unlock()
+}
+
+// copyManifest must be called with the mutex held
+func (p *Publisher) copyManifest() Manifest {
+	copied := p.manifest
+	copied.Segments = make(map[string]Version, len(p.manifest.Segments))
+	for name, version := range p.manifest.Segments {
+		copied.Segments[name] = version
+	}
+	if p.manifest.SplitChanges != nil {
+		splitChanges := *p.manifest.SplitChanges
+		copied.SplitChanges = &splitChanges
+	}
+	return copied
+}
+
+// historyItem maps a published path to the item it's a version of
+func historyItem(relative string) (string, bool) {
+	parts := strings.Split(relative, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "splitChanges":
+		return "splitChanges", true
+	case len(parts) == 3 && parts[0] == "segmentChanges":
+		name, err := url.PathUnescape(parts[1])
+		if err != nil {
+			return "", false
+		}
+		return "segment:" + name, true
+	}
+	return "", false
+}
+
+// versionOf returns the change number encoded in a published path's file name
+func versionOf(relative string) int64 {
+	cn, _ := strconv.ParseInt(strings.TrimSuffix(path.Base(relative), ".json"), 10, 64)
+	return cn
+}
+
+func referencedSegments(splits []dtos.SplitDTO) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, split := range splits {
+		for _, condition := range split.Conditions {
+			for _, matcher := range condition.MatcherGroup.Matchers {
+				if matcher.UserDefinedSegment != nil && matcher.UserDefinedSegment.SegmentName != "" {
+					names[matcher.UserDefinedSegment.SegmentName] = struct{}{}
+				}
+			}
+		}
+	}
+	return names
+}
+
+var _ changehook.Listener = (*Publisher)(nil)
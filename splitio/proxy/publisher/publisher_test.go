@@ -0,0 +1,361 @@
+package publisher
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/mocks"
+)
+
+func splitWithSegment(name string, segment string) dtos.SplitDTO {
+	return dtos.SplitDTO{
+		Name:   name,
+		Status: "ACTIVE",
+		Conditions: []dtos.ConditionDTO{{
+			MatcherGroup: dtos.MatcherGroupDTO{Matchers: []dtos.MatcherDTO{{
+				MatcherType:        "IN_SEGMENT",
+				UserDefinedSegment: &dtos.UserDefinedSegmentMatcherDataDTO{SegmentName: segment},
+			}}},
+		}},
+	}
+}
+
+func readManifest(t *testing.T, dir string) Manifest {
+	t.Helper()
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "pfx", ManifestFile))
+	if err != nil {
+		t.Fatal("error reading manifest: ", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		t.Fatal("invalid manifest: ", err)
+	}
+	return manifest
+}
+
+func TestPublisherWritesVersionsAndManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var till int64 = 10
+	splits := &mocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			if since != -1 {
+				t.Error("since should be -1. Got: ", since)
+			}
+			return &dtos.SplitChangesDTO{Since: -1, Till: atomic.LoadInt64(&till), Splits: []dtos.SplitDTO{
+				splitWithSegment("split1", "segment/1"),
+				splitWithSegment("split2", "missing"),
+			}}, nil
+		},
+	}
+	segments := &mocks.ProxySegmentStorageMock{
+		ChangesSinceCall: func(name string, since int64) (*dtos.SegmentChangesDTO, error) {
+			if name == "missing" {
+				return nil, storage.ErrSegmentNotFound
+			}
+			return &dtos.SegmentChangesDTO{Name: name, Since: -1, Till: 5, Added: []string{"key1"}}, nil
+		},
+	}
+
+	sink, _ := NewDirSink(dir)
+	publisher := NewPublisher(Options{Prefix: "pfx", RetainVersions: 2}, sink, splits, segments, logging.NewLogger(nil))
+	publisher.SplitsChanged(nil, 10)
+	if err := publisher.publish(); err != nil {
+		t.Error("publish should succeed. Got: ", err)
+	}
+
+	manifest := readManifest(t, dir)
+	if manifest.SplitChanges == nil || manifest.SplitChanges.ChangeNumber != 10 || manifest.SplitChanges.Path != "splitChanges/10.json" {
+		t.Error("invalid splitChanges version: ", manifest.SplitChanges)
+	}
+	if len(manifest.Segments) != 1 || manifest.Segments["segment/1"].Path != "segmentChanges/segment%2F1/5.json" {
+		t.Error("invalid segments: ", manifest.Segments)
+	}
+
+	var payload dtos.SplitChangesDTO
+	raw, _ := ioutil.ReadFile(filepath.Join(dir, "pfx", "splitChanges", "10.json"))
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.Till != 10 || len(payload.Splits) != 2 {
+		t.Error("invalid splitChanges payload: ", string(raw))
+	}
+	var segment dtos.SegmentChangesDTO
+	raw, _ = ioutil.ReadFile(filepath.Join(dir, "pfx", "segmentChanges", "segment%2F1", "5.json"))
+	if err := json.Unmarshal(raw, &segment); err != nil || segment.Name != "segment/1" || len(segment.Added) != 1 {
+		t.Error("invalid segment payload: ", string(raw))
+	}
+
+	// nothing changed, nothing should be written
+	publisher.SplitsChanged(nil, 10)
+	publisher.publish()
+	if stats := publisher.Stats(); stats.Published != 3 {
+		t.Error("3 files should have been written. Got: ", stats)
+	}
+
+	// older versions beyond the retention limit are removed
+	for _, cn := range []int64{11, 12} {
+		atomic.StoreInt64(&till, cn)
+		publisher.SplitsChanged(nil, cn)
+		publisher.publish()
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pfx", "splitChanges", "10.json")); !os.IsNotExist(err) {
+		t.Error("version 10 should have been pruned")
+	}
+	for _, name := range []string{"11.json", "12.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "pfx", "splitChanges", name)); err != nil {
+			t.Error("version should be retained: ", name)
+		}
+	}
+	if manifest := readManifest(t, dir); manifest.SplitChanges.ChangeNumber != 12 || len(manifest.Segments) != 1 {
+		t.Error("manifest should point to the latest version: ", manifest)
+	}
+	if stats := publisher.Stats(); stats.Pruned != 1 {
+		t.Error("one file should have been pruned. Got: ", stats)
+	}
+}
+
+type failingSink struct {
+	Sink
+	fail int32
+}
+
+func (s *failingSink) Put(path string, body []byte, cacheControl string) error {
+	if atomic.LoadInt32(&s.fail) == 1 {
+		return errors.New("unavailable")
+	}
+	return s.Sink.Put(path, body, cacheControl)
+}
+
+func TestPublisherRetriesFailures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	splits := &mocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			return &dtos.SplitChangesDTO{Since: -1, Till: 3, Splits: []dtos.SplitDTO{splitWithSegment("split1", "segment1")}}, nil
+		},
+	}
+	segments := &mocks.ProxySegmentStorageMock{
+		ChangesSinceCall: func(name string, since int64) (*dtos.SegmentChangesDTO, error) {
+			return &dtos.SegmentChangesDTO{Name: name, Since: -1, Till: 7}, nil
+		},
+	}
+
+	dirSink, _ := NewDirSink(dir)
+	sink := &failingSink{Sink: dirSink, fail: 1}
+	publisher := NewPublisher(Options{Prefix: "pfx", Debounce: time.Millisecond, RetryInterval: 50 * time.Millisecond},
+		sink, splits, segments, logging.NewLogger(nil))
+	if err := publisher.Start(); err != nil {
+		t.Error("start should succeed. Got: ", err)
+	}
+	publisher.SegmentChanged("segment1", 7, []string{"key1"})
+
+	time.Sleep(30 * time.Millisecond)
+	if stats := publisher.Stats(); stats.Failed == 0 {
+		t.Error("publication should have failed. Got: ", stats)
+	}
+
+	atomic.StoreInt32(&sink.fail, 0)
+	time.Sleep(100 * time.Millisecond)
+	publisher.Stop(true)
+
+	manifest := readManifest(t, dir)
+	if manifest.SplitChanges == nil || manifest.SplitChanges.ChangeNumber != 3 {
+		t.Error("splits should have been published after retrying: ", manifest.SplitChanges)
+	}
+	if manifest.Segments["segment1"].ChangeNumber != 7 {
+		t.Error("segment should have been published after retrying: ", manifest.Segments)
+	}
+	if current := publisher.Manifest(); current.SplitChanges == nil || current.SplitChanges.ChangeNumber != 3 {
+		t.Error("in-memory manifest should match the published one: ", current)
+	}
+}
+
+type manifestFailingSink struct {
+	Sink
+	fail int32
+}
+
+func (s *manifestFailingSink) Put(path string, body []byte, cacheControl string) error {
+	if atomic.LoadInt32(&s.fail) == 1 && filepath.Base(path) == ManifestFile {
+		return errors.New("unavailable")
+	}
+	return s.Sink.Put(path, body, cacheControl)
+}
+
+func TestPublisherPrunesOnlyAfterManifestIsWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var till int64 = 10
+	splits := &mocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			return &dtos.SplitChangesDTO{Since: -1, Till: atomic.LoadInt64(&till)}, nil
+		},
+	}
+
+	dirSink, _ := NewDirSink(dir)
+	sink := &manifestFailingSink{Sink: dirSink}
+	publisher := NewPublisher(Options{Prefix: "pfx", RetainVersions: 1}, sink, splits, &mocks.ProxySegmentStorageMock{}, logging.NewLogger(nil))
+	publisher.SplitsChanged(nil, 10)
+	if err := publisher.publish(); err != nil {
+		t.Error("publish should succeed. Got: ", err)
+	}
+
+	atomic.StoreInt32(&sink.fail, 1)
+	atomic.StoreInt64(&till, 11)
+	publisher.SplitsChanged(nil, 11)
+	if err := publisher.publish(); err == nil {
+		t.Error("publish should fail when the manifest cannot be written")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pfx", "splitChanges", "10.json")); err != nil {
+		t.Error("the version the published manifest points to should not be pruned")
+	}
+	if manifest := readManifest(t, dir); manifest.SplitChanges.ChangeNumber != 10 {
+		t.Error("the published manifest should still point to version 10. Got: ", manifest.SplitChanges)
+	}
+
+	atomic.StoreInt32(&sink.fail, 0)
+	if err := publisher.publish(); err != nil {
+		t.Error("publish should succeed. Got: ", err)
+	}
+	if manifest := readManifest(t, dir); manifest.SplitChanges.ChangeNumber != 11 {
+		t.Error("the manifest should point to version 11. Got: ", manifest.SplitChanges)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pfx", "splitChanges", "10.json")); !os.IsNotExist(err) {
+		t.Error("version 10 should be pruned once the manifest no longer points to it")
+	}
+	if stats := publisher.Stats(); stats.Pruned != 1 {
+		t.Error("one file should have been pruned. Got: ", stats)
+	}
+}
+
+func TestPublisherRestoresHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var till int64
+	splits := &mocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			return &dtos.SplitChangesDTO{Since: -1, Till: atomic.LoadInt64(&till), Splits: []dtos.SplitDTO{splitWithSegment("split1", "segment/1")}}, nil
+		},
+	}
+	segments := &mocks.ProxySegmentStorageMock{
+		ChangesSinceCall: func(name string, since int64) (*dtos.SegmentChangesDTO, error) {
+			return &dtos.SegmentChangesDTO{Name: name, Since: -1, Till: atomic.LoadInt64(&till)}, nil
+		},
+	}
+
+	sink, _ := NewDirSink(dir)
+	previous := NewPublisher(Options{Prefix: "pfx"}, sink, splits, segments, logging.NewLogger(nil))
+	for _, cn := range []int64{9, 10, 11} {
+		atomic.StoreInt64(&till, cn)
+		previous.SplitsChanged(nil, cn)
+		previous.SegmentChanged("segment/1", cn, nil)
+		previous.publish()
+	}
+	ioutil.WriteFile(filepath.Join(dir, "pfx", "splitChanges", ".publish-123"), nil, 0644) // leftover temporary file
+
+	// a new run with a retention limit prunes the versions written by the previous one
+	publisher := NewPublisher(Options{Prefix: "pfx", RetainVersions: 2}, sink, splits, segments, logging.NewLogger(nil))
+	if err := publisher.restoreHistory(); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	for _, name := range []string{"splitChanges/9.json", "segmentChanges/segment%2F1/9.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "pfx", filepath.FromSlash(name))); !os.IsNotExist(err) {
+			t.Error("version exceeding the limit should have been pruned: ", name)
+		}
+	}
+
+	atomic.StoreInt64(&till, 12)
+	publisher.SplitsChanged(nil, 12)
+	publisher.SegmentChanged("segment/1", 12, nil)
+	publisher.publish()
+	for _, name := range []string{"splitChanges/10.json", "segmentChanges/segment%2F1/10.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "pfx", filepath.FromSlash(name))); !os.IsNotExist(err) {
+			t.Error("version written by the previous run should have been pruned: ", name)
+		}
+	}
+	for _, name := range []string{"splitChanges/11.json", "splitChanges/12.json", "segmentChanges/segment%2F1/12.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "pfx", filepath.FromSlash(name))); err != nil {
+			t.Error("version should be retained: ", name)
+		}
+	}
+	if stats := publisher.Stats(); stats.Pruned != 4 {
+		t.Error("4 files should have been pruned. Got: ", stats)
+	}
+}
+
+func TestPublisherDropsUnreferencedSegments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var till int64 = 1
+	splits := &mocks.ProxySplitStorageMock{
+		ChangesSinceCall: func(since int64) (*dtos.SplitChangesDTO, error) {
+			if atomic.LoadInt64(&till) == 1 {
+				return &dtos.SplitChangesDTO{Since: -1, Till: 1, Splits: []dtos.SplitDTO{
+					splitWithSegment("split1", "segment1"),
+					splitWithSegment("split2", "segment2"),
+				}}, nil
+			}
+			return &dtos.SplitChangesDTO{Since: -1, Till: 2, Splits: []dtos.SplitDTO{splitWithSegment("split1", "segment1")}}, nil
+		},
+	}
+	segments := &mocks.ProxySegmentStorageMock{
+		ChangesSinceCall: func(name string, since int64) (*dtos.SegmentChangesDTO, error) {
+			return &dtos.SegmentChangesDTO{Name: name, Since: -1, Till: 5}, nil
+		},
+	}
+
+	sink, _ := NewDirSink(dir)
+	publisher := NewPublisher(Options{Prefix: "pfx", RetainVersions: 2}, sink, splits, segments, logging.NewLogger(nil))
+	publisher.SplitsChanged(nil, 1)
+	publisher.publish()
+	if manifest := readManifest(t, dir); len(manifest.Segments) != 2 {
+		t.Error("both segments should be published: ", manifest.Segments)
+	}
+
+	atomic.StoreInt64(&till, 2)
+	publisher.SplitsChanged(nil, 2)
+	publisher.SegmentChanged("segment2", 6, nil)
+	publisher.publish()
+
+	manifest := readManifest(t, dir)
+	if _, ok := manifest.Segments["segment2"]; ok || len(manifest.Segments) != 1 {
+		t.Error("unreferenced segment should have been removed from the manifest: ", manifest.Segments)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pfx", "segmentChanges", "segment2", "5.json")); !os.IsNotExist(err) {
+		t.Error("unreferenced segment files should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pfx", "segmentChanges", "segment1", "5.json")); err != nil {
+		t.Error("referenced segment files should be kept")
+	}
+	if _, ok := publisher.history["segment:segment2"]; ok {
+		t.Error("unreferenced segment should have been forgotten")
+	}
+}
@@ -0,0 +1,348 @@
+package publisher
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sink stores published files
+type Sink interface {
+	Put(path string, body []byte, cacheControl string) error
+	Delete(path string) error
+	List(prefix string) ([]string, error)
+}
+
+// DirSink writes files to a local directory. Files are written to a temporary location & renamed
+// so that readers never see partial content
+type DirSink struct {
+	dir string
+}
+
+// NewDirSink constructs a sink that writes to the supplied directory, creating it if necessary
+func NewDirSink(dir string) (*DirSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating publishing directory: %w", err)
+	}
+	return &DirSink{dir: dir}, nil
+}
+
+// Put writes a file
+func (s *DirSink) Put(path string, body []byte, _ string) error {
+	target := filepath.Join(s.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", path, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(target), ".publish-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("error setting permissions on %s: %w", path, err)
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// Delete removes a file. Missing files are ignored
+func (s *DirSink) Delete(path string) error {
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(path)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns the paths of all files under the supplied prefix, relative to the sink's root
+func (s *DirSink) List(prefix string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(filepath.Join(s.dir, filepath.FromSlash(prefix)), func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".publish-") {
+			return nil
+		}
+		relative, err := filepath.Rel(s.dir, name)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(relative))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", prefix, err)
+	}
+	return paths, nil
+}
+
+// S3Options contains the parameters required to publish to an S3-compatible bucket
+type S3Options struct {
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client
+}
+
+// S3Sink uploads files to an S3-compatible bucket using path-style urls & signature v4
+type S3Sink struct {
+	endpoint   *url.URL
+	bucket     string
+	region     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Sink constructs a new S3 sink. If no endpoint is supplied, AWS's one for the region is used
+func NewS3Sink(opts S3Options) (*S3Sink, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("a bucket is required")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", opts.Region)
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint '%s'", opts.Endpoint)
+	}
+
+	return &S3Sink{
+		endpoint:   endpoint,
+		bucket:     opts.Bucket,
+		region:     opts.Region,
+		accessKey:  opts.AccessKeyID,
+		secretKey:  opts.SecretAccessKey,
+		httpClient: opts.HTTPClient,
+		now:        time.Now,
+	}, nil
+}
+
+// Put uploads a file
+func (s *S3Sink) Put(path string, body []byte, cacheControl string) error {
+	headers := map[string]string{"Content-Type": "application/json"}
+	if cacheControl != "" {
+		headers["Cache-Control"] = cacheControl
+	}
+	_, err := s.do(http.MethodPut, path, nil, body, headers)
+	return err
+}
+
+// Delete removes a file
+func (s *S3Sink) Delete(path string) error {
+	_, err := s.do(http.MethodDelete, path, nil, nil, nil)
+	return err
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response used by the sink
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys of all objects under the supplied prefix, following continuation tokens
+func (s *S3Sink) List(prefix string) ([]string, error) {
+	var keys []string
+	var token string
+	for {
+		query := url.Values{"list-type": []string{"2"}, "prefix": []string{prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		raw, err := s.do(http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("error parsing listing for %s: %w", prefix, err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a request for an object (or the bucket itself if path is empty) & returns the response body
+func (s *S3Sink) do(method string, path string, query url.Values, body []byte, headers map[string]string) ([]byte, error) {
+	target := *s.endpoint
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.bucket
+	if path != "" {
+		target.Path += "/" + strings.TrimPrefix(path, "/")
+	}
+	// the path & query sent must match the signed ones byte for byte
+	target.RawPath = canonicalURI(target.Path)
+	target.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequest(method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building s3 request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if s.accessKey != "" {
+		signV4(req, body, s.accessKey, s.secretKey, s.region, "s3", s.now())
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s failed with status %d", method, path, resp.StatusCode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading response for %s %s: %w", method, path, err)
+	}
+	return raw, nil
+}
+
+// signV4 adds the aws signature v4 headers to a request. All headers already set in the request are signed
+func signV4(req *http.Request, body []byte, accessKey string, secretKey string, region string, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := hexSHA256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		signed[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalURI uri-encodes every path segment as required by signature v4
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		segments[idx] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery sorts & uri-encodes query parameters as required by signature v4
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters, as defined by signature v4.
+// url.PathEscape & url.QueryEscape are not suitable since they leave characters such as '=', '@', ':' or '&' as-is
+func uriEncode(value string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var encoded strings.Builder
+	for idx := 0; idx < len(value); idx++ {
+		c := value[idx]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			encoded.WriteByte(c)
+			continue
+		}
+		encoded.WriteByte('%')
+		encoded.WriteByte(hexDigits[c>>4])
+		encoded.WriteByte(hexDigits[c&15])
+	}
+	return encoded.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket refilled continuously at `rate` tokens per second up to `burst` tokens
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func (b *bucket) refill(now time.Time, rate float64, burst float64) {
+	if elapsed := now.Sub(b.lastRefill).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.lastRefill = now
+	}
+}
+
+// buckets holds the token buckets of a rule, one per client (or a single one for global rules)
+type buckets struct {
+	rule       Rule
+	maxClients int
+	byClient   map[string]*bucket
+	mutex      sync.Mutex
+}
+
+// bucketFor returns the client's bucket refilled up to now, creating it if needed.
+// Must be called with the lock acquired
+func (b *buckets) bucketFor(client string, now time.Time) *bucket {
+	burst := float64(b.rule.Burst)
+	current, ok := b.byClient[client]
+	if !ok {
+		if len(b.byClient) >= b.maxClients {
+			b.evict(now)
+		}
+		current = &bucket{tokens: burst, lastRefill: now}
+		b.byClient[client] = current
+	}
+
+	current.refill(now, b.rule.Rate, burst)
+	return current
+}
+
+// retryAfter returns how long to wait until the bucket holds a token
+func (b *buckets) retryAfter(current *bucket) time.Duration {
+	return time.Duration((1 - current.tokens) / b.rule.Rate * float64(time.Second))
+}
+
+// evict removes buckets that have been refilled completely (equivalent to new ones).
+// If none is, an arbitrary half is dropped to keep memory bounded.
+// Must be called with the lock acquired
+func (b *buckets) evict(now time.Time) {
+	burst := float64(b.rule.Burst)
+	for client, current := range b.byClient {
+		if current.refill(now, b.rule.Rate, burst); current.tokens >= burst {
+			delete(b.byClient, client)
+		}
+	}
+
+	for client := range b.byClient {
+		if len(b.byClient) < b.maxClients/2 {
+			break
+		}
+		delete(b.byClient, client)
+	}
+}
+
+// Request contains the properties of an incoming request used to select buckets
+type Request struct {
+	Group    string
+	APIKey   string
+	ClientIP string
+}
+
+// Result is the outcome of checking a request against the rules
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+	Rule       string
+}
+
+// Limiter checks incoming requests against a set of token-bucket rules
+type Limiter struct {
+	rules []*buckets
+	now   func() time.Time
+}
+
+// NewLimiter constructs a limiter. maxClients caps the number of buckets tracked by each rule
+func NewLimiter(rules []Rule, maxClients int) *Limiter {
+	if maxClients <= 0 {
+		maxClients = math.MaxInt32
+	}
+
+	limiter := &Limiter{rules: make([]*buckets, 0, len(rules)), now: time.Now}
+	for _, rule := range rules {
+		limiter.rules = append(limiter.rules, &buckets{rule: rule, maxClients: maxClients, byClient: make(map[string]*bucket)})
+	}
+	return limiter
+}
+
+// Allow consumes a token from every bucket the request is subject to, only if all of them have one available.
+// Otherwise the request is rejected without consuming any, and the first rule with an empty bucket is reported
+func (l *Limiter) Allow(request *Request) Result {
+	now := l.now()
+	applicable := l.applicable(request)
+
+	// buckets are locked in rule order, so that concurrent requests cannot deadlock
+	for _, rule := range applicable {
+		rule.mutex.Lock()
+	}
+	defer func() {
+		for _, rule := range applicable {
+			rule.mutex.Unlock()
+		}
+	}()
+
+	selected := make([]*bucket, 0, len(applicable))
+	for _, rule := range applicable {
+		current := rule.bucketFor(l.clientFor(rule, request), now)
+		if current.tokens < 1 {
+			return Result{Allowed: false, RetryAfter: rule.retryAfter(current), Rule: rule.rule.String()}
+		}
+		selected = append(selected, current)
+	}
+
+	for _, current := range selected {
+		current.tokens--
+	}
+	return Result{Allowed: true}
+}
+
+func (l *Limiter) applicable(request *Request) []*buckets {
+	var hasSpecificKeyRule bool
+	if request.APIKey != "" {
+		for _, rule := range l.rules {
+			if rule.rule.APIKey == request.APIKey && matchesGroup(rule.rule.Group, request.Group) {
+				hasSpecificKeyRule = true
+				break
+			}
+		}
+	}
+
+	applicable := make([]*buckets, 0, len(l.rules))
+	for _, rule := range l.rules {
+		if !matchesGroup(rule.rule.Group, request.Group) {
+			continue
+		}
+
+		switch rule.rule.Scope {
+		case ScopeAPIKey:
+			if request.APIKey == "" ||
+				(rule.rule.APIKey != "" && rule.rule.APIKey != request.APIKey) ||
+				(rule.rule.APIKey == "" && hasSpecificKeyRule) {
+				continue
+			}
+		case ScopeIP:
+			if request.ClientIP == "" {
+				continue
+			}
+		}
+		applicable = append(applicable, rule)
+	}
+	return applicable
+}
+
+func (l *Limiter) clientFor(rule *buckets, request *Request) string {
+	switch rule.rule.Scope {
+	case ScopeAPIKey:
+		return request.APIKey
+	case ScopeIP:
+		return request.ClientIP
+	}
+	return ""
+}
+
+func matchesGroup(ruleGroup string, group string) bool {
+	return ruleGroup == GroupAll || ruleGroup == group
+}
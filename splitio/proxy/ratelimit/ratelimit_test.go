@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"sdk:apikey:10", " events:ip:0.5:3 ", "", "*:global:100:200", "sdk:apikey=abcdef123456:50"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 4 {
+		t.Fatal("there should be 4 rules. Got: ", len(rules))
+	}
+
+	if r := rules[0]; r.Group != GroupSDK || r.Scope != ScopeAPIKey || r.Rate != 10 || r.Burst != 10 {
+		t.Error("wrong rule: ", r)
+	}
+	if r := rules[1]; r.Group != GroupEvents || r.Scope != ScopeIP || r.Rate != 0.5 || r.Burst != 3 {
+		t.Error("wrong rule: ", r)
+	}
+	if r := rules[3]; r.Scope != ScopeAPIKey || r.APIKey != "abcdef123456" {
+		t.Error("wrong rule: ", r)
+	}
+	if s := rules[3].String(); s != "sdk:apikey=****3456:50:50" {
+		t.Error("apikey should be masked: ", s)
+	}
+
+	for _, invalid := range []string{"sdk:apikey", "other:apikey:1", "sdk:user:1", "sdk:ip:-1", "sdk:ip:1:0", "sdk:apikey=:1", "sdk:ip:1:2:3"} {
+		if _, err := ParseRules([]string{invalid}); err == nil {
+			t.Error("rule should be invalid: ", invalid)
+		}
+	}
+}
+
+func TestLimiterTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter([]Rule{{Group: GroupSDK, Scope: ScopeAPIKey, Rate: 2, Burst: 2}}, 0)
+	limiter.now = func() time.Time { return now }
+
+	request := &Request{Group: GroupSDK, APIKey: "key1", ClientIP: "10.0.0.1"}
+	for i := 0; i < 2; i++ {
+		if !limiter.Allow(request).Allowed {
+			t.Error("requests within burst should be allowed")
+		}
+	}
+
+	result := limiter.Allow(request)
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond || result.Rule != "sdk:apikey:2:2" {
+		t.Error("request should be rejected with a 500ms retry. got: ", result)
+	}
+
+	if !limiter.Allow(&Request{Group: GroupSDK, APIKey: "key2"}).Allowed {
+		t.Error("other apikeys should have their own bucket")
+	}
+
+	if !limiter.Allow(&Request{Group: GroupEvents, APIKey: "key1"}).Allowed {
+		t.Error("other groups should not be limited")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.Allow(request).Allowed {
+		t.Error("bucket should have been refilled")
+	}
+	if limiter.Allow(request).Allowed {
+		t.Error("bucket should be empty again")
+	}
+}
+
+func TestLimiterScopes(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter([]Rule{
+		{Group: GroupAll, Scope: ScopeAPIKey, Rate: 1, Burst: 1},
+		{Group: GroupAll, Scope: ScopeAPIKey, APIKey: "vip", Rate: 1, Burst: 3},
+		{Group: GroupEvents, Scope: ScopeIP, Rate: 1, Burst: 2},
+		{Group: GroupTelemetry, Scope: ScopeGlobal, Rate: 1, Burst: 1},
+	}, 0)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !limiter.Allow(&Request{Group: GroupSDK, APIKey: "vip"}).Allowed {
+			t.Error("the specific apikey rule should take precedence over the generic one")
+		}
+	}
+	if limiter.Allow(&Request{Group: GroupSDK, APIKey: "vip"}).Allowed {
+		t.Error("the specific apikey rule should be enforced")
+	}
+
+	// requests without apikey (ie: beacons) are only limited by ip
+	if !limiter.Allow(&Request{Group: GroupEvents, ClientIP: "10.0.0.1"}).Allowed ||
+		!limiter.Allow(&Request{Group: GroupEvents, ClientIP: "10.0.0.1"}).Allowed {
+		t.Error("requests within the ip burst should be allowed")
+	}
+	if res := limiter.Allow(&Request{Group: GroupEvents, ClientIP: "10.0.0.1"}); res.Allowed || res.Rule != "events:ip:1:2" {
+		t.Error("ip rule should be enforced: ", res)
+	}
+
+	if !limiter.Allow(&Request{Group: GroupTelemetry, APIKey: "a"}).Allowed {
+		t.Error("first telemetry request should be allowed")
+	}
+	if res := limiter.Allow(&Request{Group: GroupTelemetry, APIKey: "b"}); res.Allowed || res.Rule != "telemetry:global:1:1" {
+		t.Error("global rule should be shared by all clients: ", res)
+	}
+}
+
+func TestLimiterRejectionsDoNotConsumeTokens(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter([]Rule{
+		{Group: GroupSDK, Scope: ScopeAPIKey, Rate: 1, Burst: 2},
+		{Group: GroupSDK, Scope: ScopeIP, Rate: 1, Burst: 1},
+	}, 0)
+	limiter.now = func() time.Time { return now }
+
+	if !limiter.Allow(&Request{Group: GroupSDK, APIKey: "key1", ClientIP: "10.0.0.1"}).Allowed {
+		t.Error("first request should be allowed")
+	}
+	for i := 0; i < 3; i++ {
+		if res := limiter.Allow(&Request{Group: GroupSDK, APIKey: "key1", ClientIP: "10.0.0.1"}); res.Allowed || res.Rule != "sdk:ip:1:1" {
+			t.Error("the ip rule should reject the request: ", res)
+		}
+	}
+
+	// the apikey bucket still holds the token the rejected requests would have taken
+	if !limiter.Allow(&Request{Group: GroupSDK, APIKey: "key1", ClientIP: "10.0.0.2"}).Allowed {
+		t.Error("requests rejected by the ip rule should not consume apikey tokens")
+	}
+	if res := limiter.Allow(&Request{Group: GroupSDK, APIKey: "key1", ClientIP: "10.0.0.3"}); res.Allowed || res.Rule != "sdk:apikey:1:2" {
+		t.Error("the apikey bucket should be empty now: ", res)
+	}
+}
+
+func TestLimiterEviction(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter([]Rule{{Group: GroupSDK, Scope: ScopeIP, Rate: 1, Burst: 1}}, 10)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		limiter.Allow(&Request{Group: GroupSDK, ClientIP: string(rune('a' + i))})
+	}
+
+	if tracked := len(limiter.rules[0].byClient); tracked > 10 {
+		t.Error("tracked clients should be capped. got: ", tracked)
+	}
+}
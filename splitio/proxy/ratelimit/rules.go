@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Endpoint groups
+const (
+	GroupSDK       = "sdk"
+	GroupEvents    = "events"
+	GroupTelemetry = "telemetry"
+	GroupAll       = "*"
+)
+
+// Rule scopes
+const (
+	ScopeAPIKey = "apikey"
+	ScopeIP     = "ip"
+	ScopeGlobal = "global"
+)
+
+// Rule defines a token bucket applied to requests of an endpoint group, either per apikey, per client ip or
+// shared by all clients. If APIKey is set, the rule only applies to that apikey and takes precedence over
+// generic apikey rules of the same group
+type Rule struct {
+	Group  string
+	Scope  string
+	APIKey string
+	Rate   float64
+	Burst  int64
+}
+
+// String returns the rule in the same format it's parsed from, masking the apikey if present
+func (r Rule) String() string {
+	scope := r.Scope
+	if r.APIKey != "" {
+		scope = ScopeAPIKey + "=" + maskAPIKey(r.APIKey)
+	}
+	return fmt.Sprintf("%s:%s:%s:%d", r.Group, scope, strconv.FormatFloat(r.Rate, 'f', -1, 64), r.Burst)
+}
+
+// ParseRules parses a list of rules with the format `<group>:<scope>:<requests per second>[:<burst>]` where:
+// - group is one of `sdk`, `events`, `telemetry` or `*`
+// - scope is one of `apikey`, `apikey=<key>`, `ip` or `global`.
+// Burst defaults to the (rounded up) rate. Empty strings are ignored.
+func ParseRules(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for _, raw := range rules {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		rule, err := parseRule(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit rule '%s': %w", maskRule(raw), err)
+		}
+		parsed = append(parsed, *rule)
+	}
+	return parsed, nil
+}
+
+func parseRule(raw string) (*Rule, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) < 3 || len(parts) > 4 {
+		return nil, errors.New("expected <group>:<scope>:<requests per second>[:<burst>]")
+	}
+
+	rule := &Rule{Group: parts[0], Scope: parts[1]}
+	switch rule.Group {
+	case GroupSDK, GroupEvents, GroupTelemetry, GroupAll:
+	default:
+		return nil, fmt.Errorf("unknown group '%s'", rule.Group)
+	}
+
+	switch {
+	case rule.Scope == ScopeAPIKey, rule.Scope == ScopeIP, rule.Scope == ScopeGlobal:
+	case strings.HasPrefix(rule.Scope, ScopeAPIKey+"="):
+		rule.APIKey = strings.TrimPrefix(rule.Scope, ScopeAPIKey+"=")
+		rule.Scope = ScopeAPIKey
+		if rule.APIKey == "" {
+			return nil, errors.New("empty apikey")
+		}
+	default:
+		return nil, fmt.Errorf("unknown scope '%s'", rule.Scope)
+	}
+
+	rate, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || rate <= 0 {
+		return nil, fmt.Errorf("invalid rate '%s'", parts[2])
+	}
+	rule.Rate = rate
+	rule.Burst = int64(math.Ceil(rate))
+
+	if len(parts) == 4 {
+		if rule.Burst, err = strconv.ParseInt(parts[3], 10, 64); err != nil || rule.Burst <= 0 {
+			return nil, fmt.Errorf("invalid burst '%s'", parts[3])
+		}
+	}
+	return rule, nil
+}
+
+func maskAPIKey(apikey string) string {
+	if len(apikey) <= 4 {
+		return "****"
+	}
+	return "****" + apikey[len(apikey)-4:]
+}
+
+func maskRule(raw string) string {
+	parts := strings.Split(raw, ":")
+	if len(parts) > 1 && strings.HasPrefix(parts[1], ScopeAPIKey+"=") {
+		parts[1] = ScopeAPIKey + "=" + maskAPIKey(strings.TrimPrefix(parts[1], ScopeAPIKey+"="))
+	}
+	return strings.Join(parts, ":")
+}
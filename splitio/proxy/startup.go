@@ -0,0 +1,37 @@
+package proxy
+
+import (
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage"
+)
+
+// startMarker is implemented by components tracking whether the proxy has finished starting up (ie: probes.Evaluator)
+type startMarker interface {
+	MarkStarted()
+}
+
+// markStartedWhenPopulated marks the proxy as started as soon as the split storage holds data. When starting from a snapshot,
+// that may happen before the first successful synchronization with split servers (or even when it never succeeds)
+func markStartedWhenPopulated(marker startMarker, splitStorage storage.SplitStorageConsumer, checkInterval time.Duration) {
+	populated := func() bool {
+		cn, err := splitStorage.ChangeNumber()
+		return err == nil && cn != -1
+	}
+
+	if populated() {
+		marker.MarkStarted()
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if populated() {
+				marker.MarkStarted()
+				return
+			}
+		}
+	}()
+}
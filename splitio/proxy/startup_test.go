@@ -0,0 +1,38 @@
+package proxy
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/storage/mocks"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/provisional/healthcheck/probes"
+)
+
+func TestMarkStartedWhenPopulated(t *testing.T) {
+	// data loaded from a snapshot
+	evaluator := probes.NewEvaluator(probes.Config{}, nil, nil, nil, nil)
+	markStartedWhenPopulated(evaluator, &mocks.MockSplitStorage{
+		ChangeNumberCall: func() (int64, error) { return 123, nil },
+	}, time.Hour)
+	if !evaluator.Startup().Ok {
+		t.Error("a proxy with data should be marked as started right away")
+	}
+
+	// empty snapshot, data arrives once a background synchronization succeeds
+	var cn int64 = -1
+	evaluator = probes.NewEvaluator(probes.Config{}, nil, nil, nil, nil)
+	markStartedWhenPopulated(evaluator, &mocks.MockSplitStorage{
+		ChangeNumberCall: func() (int64, error) { return atomic.LoadInt64(&cn), nil },
+	}, 10*time.Millisecond)
+	if evaluator.Startup().Ok {
+		t.Error("a proxy without data should not be marked as started")
+	}
+
+	atomic.StoreInt64(&cn, 5)
+	time.Sleep(50 * time.Millisecond)
+	if !evaluator.Startup().Ok {
+		t.Error("the proxy should be marked as started once data is available")
+	}
+}
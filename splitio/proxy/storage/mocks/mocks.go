@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"github.com/splitio/go-split-commons/v4/dtos"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
 )
 
 type ProxySplitStorageMock struct {
@@ -18,15 +20,29 @@ func (p *ProxySplitStorageMock) RegisterOlderCn(payload *dtos.SplitChangesDTO) {
 }
 
 type ProxySegmentStorageMock struct {
-	ChangesSinceCall     func(name string, since int64) (*dtos.SegmentChangesDTO, error)
-	SegmentsForCall      func(key string) ([]string, error)
-	CountRemovedKeysCall func(segmentName string) int
+	ChangesSinceCall       func(name string, since int64) (*dtos.SegmentChangesDTO, error)
+	StreamChangesSinceCall func(name string, since int64, page *storage.SegmentChangesPage) (storage.SegmentChangesStream, error)
+	SegmentsForCall        func(key string) ([]string, error)
+	CountRemovedKeysCall   func(segmentName string) int
 }
 
 func (p *ProxySegmentStorageMock) ChangesSince(name string, since int64) (*dtos.SegmentChangesDTO, error) {
 	return p.ChangesSinceCall(name, since)
 }
 
+// StreamChangesSince falls back to wrapping the result of ChangesSinceCall if no StreamChangesSinceCall is set
+func (p *ProxySegmentStorageMock) StreamChangesSince(name string, since int64, page *storage.SegmentChangesPage) (storage.SegmentChangesStream, error) {
+	if p.StreamChangesSinceCall != nil {
+		return p.StreamChangesSinceCall(name, since, page)
+	}
+
+	payload, err := p.ChangesSinceCall(name, since)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewSegmentChangesStreamFromDTO(payload), nil
+}
+
 func (p *ProxySegmentStorageMock) SegmentsFor(key string) ([]string, error) {
 	return p.SegmentsForCall(key)
 }
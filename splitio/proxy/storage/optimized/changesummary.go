@@ -60,6 +60,14 @@ func NewSplitChangesSummaries(maxRecipes int) *SplitChangesSummaries {
 	}
 }
 
+// Reset drops all the recipes and brings the summaries back to their initial state
+func (s *SplitChangesSummaries) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.currentCN = -1
+	s.changes = map[int64]ChangeSummary{-1: newEmptyChangeSummary()}
+}
+
 // AddChanges registers a new set of changes and updates all the recipes accordingly
 func (s *SplitChangesSummaries) AddChanges(added []dtos.SplitDTO, removed []dtos.SplitDTO, cn int64) {
 	s.mutex.Lock()
@@ -13,6 +13,7 @@ type MySegmentsCache interface {
 	Update(name string, toAdd *set.ThreadUnsafeSet, toRemove *set.ThreadUnsafeSet) error
 	SegmentsForUser(key string) []string
 	KeyCount() int
+	Clear()
 }
 
 // MySegmentsCacheImpl implements the MySegmentsCache interface
@@ -36,6 +37,13 @@ func (m *MySegmentsCacheImpl) KeyCount() int {
 	return len(m.mySegments)
 }
 
+// Clear removes all the keys from the cache
+func (m *MySegmentsCacheImpl) Clear() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.mySegments = make(map[string][]string)
+}
+
 // SegmentsForUser returns the list of segments a certain user belongs to
 func (m *MySegmentsCacheImpl) SegmentsForUser(key string) []string {
 	m.mutex.RLock()
@@ -0,0 +1,364 @@
+package optimized
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// MySegmentsOverflow defines the interface of a (disk-backed) store holding the keys that don't fit in memory.
+// Keys are mapped to opaque set identifiers. A set identifier of 0 in `Apply` means the key must be removed
+type MySegmentsOverflow interface {
+	Fetch(keys []string) (map[string]uint32, error)
+	Apply(updates map[string]uint32) error
+	Clear() error
+}
+
+// segmentSet is an interned, immutable combination of segments shared by every key that belongs to exactly those segments
+type segmentSet struct {
+	ids   []uint32
+	names []string
+	refs  int
+}
+
+// CompactMySegmentsCache implements the MySegmentsCache interface using a compact representation:
+// segment names are interned & every distinct combination of segments is stored once, so that each key
+// only holds a reference to its combination. Keys beyond the configured budget are kept in the overflow store if one is supplied
+type CompactMySegmentsCache struct {
+	mutex         sync.RWMutex
+	logger        logging.LoggerInterface
+	segmentIDs    map[string]uint32
+	segmentNames  []string
+	sets          []segmentSet // set 0 is the empty one & is never referenced
+	setsByIDs     map[string]uint32
+	freeSets      []uint32
+	unreferenced  []uint32
+	keys          map[string]uint32
+	overflow      MySegmentsOverflow
+	overflowCount int
+	maxKeys       int
+}
+
+// NewCompactMySegmentsCache constructs a new compact MySegments cache. If an overflow store is supplied & maxKeysInMemory
+// is > 0, keys beyond that limit are stored in the overflow store
+func NewCompactMySegmentsCache(overflow MySegmentsOverflow, maxKeysInMemory int, logger logging.LoggerInterface) *CompactMySegmentsCache {
+	if overflow == nil {
+		maxKeysInMemory = 0
+	}
+	cache := &CompactMySegmentsCache{
+		logger:   logger,
+		overflow: overflow,
+		maxKeys:  maxKeysInMemory,
+	}
+	cache.reset()
+	return cache
+}
+
+// KeyCount retuns the amount of keys who belong to at least one segment
+func (m *CompactMySegmentsCache) KeyCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.keys) + m.overflowCount
+}
+
+// Clear removes all the keys from the cache
+func (m *CompactMySegmentsCache) Clear() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.overflow != nil {
+		if err := m.overflow.Clear(); err != nil {
+			m.logger.Error("error clearing mySegments overflow storage: ", err)
+		}
+	}
+	m.reset()
+}
+
+// SegmentsForUser returns the list of segments a certain user belongs to
+func (m *CompactMySegmentsCache) SegmentsForUser(key string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	setID, ok := m.keys[key]
+	if !ok && m.overflowCount > 0 {
+		stored, err := m.overflow.Fetch([]string{key})
+		if err != nil {
+			m.logger.Error(fmt.Sprintf("error fetching segments for key '%s' from overflow storage: %s", key, err))
+		}
+		setID = stored[key]
+	}
+
+	names := m.sets[setID].names
+	result := make([]string, len(names))
+	copy(result, names)
+	return result
+}
+
+// Update adds and removes segments to keys
+func (m *CompactMySegmentsCache) Update(name string, toAdd *set.ThreadUnsafeSet, toRemove *set.ThreadUnsafeSet) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	added, invalidAdded := stringKeys(toAdd)
+	removed, invalidRemoved := stringKeys(toRemove)
+
+	var err error
+	if segmentID, ok := m.segmentIDs[name]; ok || len(added) > 0 {
+		if !ok {
+			segmentID = m.internSegment(name)
+		}
+		err = m.apply(segmentID, added, removed)
+		m.releaseUnreferenced()
+	}
+
+	if len(invalidAdded) > 0 || len(invalidRemoved) > 0 {
+		return fmt.Errorf("invalid added and removed keys found: %s // %s",
+			strings.Join(invalidAdded, ","), strings.Join(invalidRemoved, ","))
+	}
+	return err
+}
+
+// apply must be called with the lock held
+func (m *CompactMySegmentsCache) apply(segmentID uint32, added []string, removed []string) error {
+	// many keys share the same set of segments, so we compute each transition once per update
+	withSegment := make(map[uint32]uint32)
+	withoutSegment := make(map[uint32]uint32)
+	transition := func(current uint32, add bool) uint32 {
+		cache := withoutSegment
+		if add {
+			cache = withSegment
+		}
+		if next, ok := cache[current]; ok {
+			return next
+		}
+		next := m.derive(current, segmentID, add)
+		cache[current] = next
+		return next
+	}
+
+	var pending []string
+	for _, key := range added {
+		current, ok := m.keys[key]
+		if !ok && (m.overflowCount > 0 || (m.maxKeys > 0 && len(m.keys) >= m.maxKeys)) {
+			pending = append(pending, key) // might be in the overflow storage
+			continue
+		}
+		m.move(key, current, transition(current, true))
+	}
+
+	var pendingRemovals []string
+	for _, key := range removed {
+		current, ok := m.keys[key]
+		if !ok {
+			if m.overflowCount > 0 {
+				pendingRemovals = append(pendingRemovals, key)
+			}
+			continue
+		}
+		m.move(key, current, transition(current, false))
+	}
+
+	if len(pending) == 0 && len(pendingRemovals) == 0 {
+		return nil
+	}
+
+	stored, err := m.overflow.Fetch(append(pending, pendingRemovals...))
+	if err != nil {
+		return fmt.Errorf("error fetching keys from overflow storage: %w", err)
+	}
+
+	// reference counts are only updated once the overflow storage accepts the changes, so that a failure leaves
+	// the keys in it (& the sets they reference) as they were before this update
+	type change struct {
+		key      string
+		current  uint32
+		next     uint32
+		inMemory bool
+	}
+	changes := make([]change, 0, len(pending)+len(pendingRemovals))
+	updates := make(map[string]uint32, len(pending)+len(pendingRemovals))
+	room := m.maxKeys - len(m.keys)
+	overflowDelta := 0
+	for _, key := range pending {
+		current, ok := stored[key]
+		if !ok && room > 0 {
+			room-- // there's room in memory again
+			changes = append(changes, change{key: key, next: transition(0, true), inMemory: true})
+			continue
+		}
+		next := transition(current, true)
+		updates[key] = next
+		changes = append(changes, change{key: key, current: current, next: next})
+		if !ok {
+			overflowDelta++
+		}
+	}
+	for _, key := range pendingRemovals {
+		if current, ok := stored[key]; ok {
+			next := transition(current, false)
+			updates[key] = next
+			changes = append(changes, change{key: key, current: current, next: next})
+			if next == 0 {
+				overflowDelta--
+			}
+		}
+	}
+
+	if err := m.overflow.Apply(updates); err != nil {
+		for _, c := range changes {
+			m.unreferenced = append(m.unreferenced, c.next) // sets derived for this update are released if unused
+		}
+		return fmt.Errorf("error updating keys in overflow storage: %w", err)
+	}
+
+	for _, c := range changes {
+		if c.inMemory {
+			m.move(c.key, 0, c.next)
+			continue
+		}
+		m.retain(c.current, c.next)
+	}
+	m.overflowCount += overflowDelta
+	return nil
+}
+
+// move updates the set referenced by an in-memory key
+func (m *CompactMySegmentsCache) move(key string, current uint32, next uint32) {
+	if current == next {
+		return
+	}
+	m.retain(current, next)
+	if next == 0 {
+		delete(m.keys, key)
+		return
+	}
+	m.keys[key] = next
+}
+
+// retain updates reference counts when a key moves from one set to another & returns the new set
+func (m *CompactMySegmentsCache) retain(current uint32, next uint32) uint32 {
+	if current == next {
+		return next
+	}
+	if next != 0 {
+		m.sets[next].refs++
+	}
+	if current != 0 {
+		m.sets[current].refs--
+		if m.sets[current].refs == 0 {
+			m.unreferenced = append(m.unreferenced, current)
+		}
+	}
+	return next
+}
+
+// derive returns the identifier of the set resulting from adding/removing a segment to/from another set, creating it if needed
+func (m *CompactMySegmentsCache) derive(current uint32, segmentID uint32, add bool) uint32 {
+	ids := m.sets[current].ids
+	idx := sort.Search(len(ids), func(i int) bool { return ids[i] >= segmentID })
+	present := idx < len(ids) && ids[idx] == segmentID
+	if present == add {
+		return current
+	}
+
+	var next []uint32
+	if add {
+		next = make([]uint32, 0, len(ids)+1)
+		next = append(next, ids[:idx]...)
+		next = append(next, segmentID)
+		next = append(next, ids[idx:]...)
+	} else {
+		next = make([]uint32, 0, len(ids)-1)
+		next = append(next, ids[:idx]...)
+		next = append(next, ids[idx+1:]...)
+	}
+	return m.internSet(next)
+}
+
+func (m *CompactMySegmentsCache) internSet(ids []uint32) uint32 {
+	if len(ids) == 0 {
+		return 0
+	}
+
+	encoded := encodeIDs(ids)
+	if setID, ok := m.setsByIDs[encoded]; ok {
+		return setID
+	}
+
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, m.segmentNames[id])
+	}
+
+	entry := segmentSet{ids: ids, names: names}
+	var setID uint32
+	if free := len(m.freeSets); free > 0 {
+		setID = m.freeSets[free-1]
+		m.freeSets = m.freeSets[:free-1]
+		m.sets[setID] = entry
+	} else {
+		setID = uint32(len(m.sets))
+		m.sets = append(m.sets, entry)
+	}
+	m.setsByIDs[encoded] = setID
+	return setID
+}
+
+// releaseUnreferenced frees the sets no longer referenced by any key. It's done once an update is complete
+// so that identifiers memoized during the update are not reused for different sets
+func (m *CompactMySegmentsCache) releaseUnreferenced() {
+	for _, setID := range m.unreferenced {
+		if m.sets[setID].refs > 0 || m.sets[setID].ids == nil {
+			continue // referenced again, or already released
+		}
+		delete(m.setsByIDs, encodeIDs(m.sets[setID].ids))
+		m.sets[setID] = segmentSet{}
+		m.freeSets = append(m.freeSets, setID)
+	}
+	m.unreferenced = m.unreferenced[:0]
+}
+
+func (m *CompactMySegmentsCache) internSegment(name string) uint32 {
+	id := uint32(len(m.segmentNames))
+	m.segmentIDs[name] = id
+	m.segmentNames = append(m.segmentNames, name)
+	return id
+}
+
+func (m *CompactMySegmentsCache) reset() {
+	m.segmentIDs = make(map[string]uint32)
+	m.segmentNames = nil
+	m.sets = []segmentSet{{}}
+	m.setsByIDs = make(map[string]uint32)
+	m.freeSets = nil
+	m.unreferenced = nil
+	m.keys = make(map[string]uint32)
+	m.overflowCount = 0
+}
+
+func encodeIDs(ids []uint32) string {
+	buf := make([]byte, len(ids)*binary.MaxVarintLen32)
+	size := 0
+	for _, id := range ids {
+		size += binary.PutUvarint(buf[size:], uint64(id))
+	}
+	return string(buf[:size])
+}
+
+func stringKeys(keys *set.ThreadUnsafeSet) ([]string, []string) {
+	var valid, invalid []string
+	for _, key := range keys.List() {
+		if strKey, ok := key.(string); ok {
+			valid = append(valid, strKey)
+			continue
+		}
+		invalid = append(invalid, fmt.Sprintf("%T::%+v", key, key))
+	}
+	return valid, invalid
+}
+
+var _ MySegmentsCache = (*CompactMySegmentsCache)(nil)
@@ -0,0 +1,313 @@
+package optimized
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+type overflowMock struct {
+	data      map[string]uint32
+	fetches   int
+	failFetch bool
+	failApply bool
+}
+
+func (o *overflowMock) Fetch(keys []string) (map[string]uint32, error) {
+	o.fetches++
+	if o.failFetch {
+		return nil, errors.New("fetch failed")
+	}
+	result := make(map[string]uint32)
+	for _, key := range keys {
+		if value, ok := o.data[key]; ok {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+func (o *overflowMock) Apply(updates map[string]uint32) error {
+	if o.failApply {
+		return errors.New("apply failed")
+	}
+	for key, value := range updates {
+		if value == 0 {
+			delete(o.data, key)
+			continue
+		}
+		o.data[key] = value
+	}
+	return nil
+}
+
+func (o *overflowMock) Clear() error {
+	o.data = make(map[string]uint32)
+	return nil
+}
+
+func assertSegments(t *testing.T, cache MySegmentsCache, key string, expected ...string) {
+	t.Helper()
+	segments := cache.SegmentsForUser(key)
+	sort.Strings(segments)
+	sort.Strings(expected)
+	if len(segments) != len(expected) {
+		t.Error("unexpected segments for ", key, ": ", segments)
+		return
+	}
+	for idx := range expected {
+		if segments[idx] != expected[idx] {
+			t.Error("unexpected segments for ", key, ": ", segments)
+			return
+		}
+	}
+}
+
+func TestCompactMySegments(t *testing.T) {
+	cache := NewCompactMySegmentsCache(nil, 0, logging.NewLogger(nil))
+
+	cache.Update("one", set.NewSet("k1", "k2"), set.NewSet())
+	cache.Update("two", set.NewSet("k1", "k3"), set.NewSet())
+	cache.Update("two", set.NewSet("k1"), set.NewSet())
+	assertSegments(t, cache, "k1", "one", "two")
+	assertSegments(t, cache, "k2", "one")
+	assertSegments(t, cache, "k3", "two")
+	assertSegments(t, cache, "nonexistent")
+	if cache.KeyCount() != 3 {
+		t.Error("there should be 3 keys. Got: ", cache.KeyCount())
+	}
+
+	// k1 & k2 share the same set of segments after this
+	cache.Update("two", set.NewSet(), set.NewSet("k1", "k3"))
+	assertSegments(t, cache, "k1", "one")
+	assertSegments(t, cache, "k3")
+	if cache.KeyCount() != 2 {
+		t.Error("there should be 2 keys. Got: ", cache.KeyCount())
+	}
+	if live := len(cache.setsByIDs); live != 1 {
+		t.Error("only one set should be alive. Got: ", live)
+	}
+
+	// the returned slice must not alias the shared set
+	cache.SegmentsForUser("k1")[0] = "modified"
+	assertSegments(t, cache, "k2", "one")
+
+	if err := cache.Update("one", set.NewSet(1), set.NewSet()); err == nil {
+		t.Error("non-string keys should return an error")
+	}
+
+	cache.Update("nonexistent", set.NewSet(), set.NewSet("k1"))
+	cache.Clear()
+	assertSegments(t, cache, "k1")
+	if cache.KeyCount() != 0 {
+		t.Error("cache should be empty")
+	}
+}
+
+func TestCompactMySegmentsOverflow(t *testing.T) {
+	overflow := &overflowMock{data: make(map[string]uint32)}
+	cache := NewCompactMySegmentsCache(overflow, 2, logging.NewLogger(nil))
+
+	cache.Update("one", set.NewSet("k1", "k2", "k3", "k4"), set.NewSet())
+	if len(cache.keys) != 2 || len(overflow.data) != 2 || cache.KeyCount() != 4 {
+		t.Error("2 keys should be in memory & 2 on disk. Got: ", len(cache.keys), len(overflow.data))
+	}
+
+	cache.Update("two", set.NewSet("k1", "k2", "k3", "k4"), set.NewSet())
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		assertSegments(t, cache, key, "one", "two")
+	}
+
+	var onDisk string
+	for key := range overflow.data {
+		onDisk = key
+	}
+	cache.Update("one", set.NewSet(), set.NewSet(onDisk))
+	cache.Update("two", set.NewSet(), set.NewSet(onDisk))
+	assertSegments(t, cache, onDisk)
+	if len(overflow.data) != 1 || cache.KeyCount() != 3 {
+		t.Error("key should have been removed from disk. Got: ", overflow.data, cache.KeyCount())
+	}
+
+	// keys evicted from memory make room for new ones
+	var inMemory string
+	for key := range cache.keys {
+		inMemory = key
+	}
+	cache.Update("one", set.NewSet(), set.NewSet(inMemory))
+	cache.Update("two", set.NewSet(), set.NewSet(inMemory))
+	cache.Update("three", set.NewSet("k5"), set.NewSet())
+	if _, ok := cache.keys["k5"]; !ok {
+		t.Error("k5 should be kept in memory")
+	}
+	assertSegments(t, cache, "k5", "three")
+
+	cache.Clear()
+	if len(overflow.data) != 0 || cache.KeyCount() != 0 {
+		t.Error("overflow storage should have been cleared")
+	}
+}
+
+func TestCompactMySegmentsOverflowFailures(t *testing.T) {
+	overflow := &overflowMock{data: make(map[string]uint32)}
+	cache := NewCompactMySegmentsCache(overflow, 1, logging.NewLogger(nil))
+	cache.Update("one", set.NewSet("k1", "k2"), set.NewSet())
+
+	var onDisk string
+	for key := range overflow.data {
+		onDisk = key
+	}
+	sets := len(cache.setsByIDs)
+
+	assertUnchanged := func() {
+		t.Helper()
+		assertSegments(t, cache, onDisk, "one")
+		if cache.KeyCount() != 2 || cache.overflowCount != 1 || len(overflow.data) != 1 {
+			t.Error("key counts should not change. Got: ", cache.KeyCount(), cache.overflowCount, overflow.data)
+		}
+		if len(cache.setsByIDs) != sets {
+			t.Error("sets derived for a failed update should be released. Got: ", len(cache.setsByIDs))
+		}
+		setID := overflow.data[onDisk]
+		refs := 0
+		for _, current := range cache.keys {
+			if current == setID {
+				refs++
+			}
+		}
+		if cache.sets[setID].refs != refs+1 {
+			t.Error("reference count should not change. Got: ", cache.sets[setID].refs)
+		}
+	}
+
+	overflow.failApply = true
+	if err := cache.Update("two", set.NewSet(onDisk, "k3"), set.NewSet()); err == nil {
+		t.Error("apply errors should be returned")
+	}
+	assertUnchanged()
+
+	if err := cache.Update("one", set.NewSet(), set.NewSet(onDisk)); err == nil {
+		t.Error("apply errors should be returned")
+	}
+	assertUnchanged()
+
+	overflow.failApply, overflow.failFetch = false, true
+	if err := cache.Update("one", set.NewSet(), set.NewSet(onDisk)); err == nil {
+		t.Error("fetch errors should be returned")
+	}
+	overflow.failFetch = false
+	assertUnchanged()
+
+	// once the overflow storage recovers, updates are applied normally
+	if err := cache.Update("one", set.NewSet(), set.NewSet(onDisk)); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	assertSegments(t, cache, onDisk)
+	if cache.KeyCount() != 1 || cache.overflowCount != 0 || len(overflow.data) != 0 {
+		t.Error("key should have been removed from disk. Got: ", cache.KeyCount(), overflow.data)
+	}
+}
+
+func TestCompactMySegmentsMatchesRegular(t *testing.T) {
+	regular := NewMySegmentsCache()
+	compact := NewCompactMySegmentsCache(&overflowMock{data: make(map[string]uint32)}, 50, logging.NewLogger(nil))
+	for round := 0; round < 20; round++ {
+		name := fmt.Sprintf("segment%d", round%7)
+		toAdd, toRemove := set.NewSet(), set.NewSet()
+		for key := 0; key < 100; key++ {
+			switch (key * (round + 3)) % 5 {
+			case 0, 1:
+				toAdd.Add(fmt.Sprintf("key%d", key))
+			case 2:
+				toRemove.Add(fmt.Sprintf("key%d", key))
+			}
+		}
+		regular.Update(name, toAdd, toRemove)
+		compact.Update(name, toAdd, toRemove)
+	}
+
+	if regular.KeyCount() != compact.KeyCount() {
+		t.Error("key counts differ: ", regular.KeyCount(), compact.KeyCount())
+	}
+	for key := 0; key < 100; key++ {
+		name := fmt.Sprintf("key%d", key)
+		assertSegments(t, compact, name, regular.SegmentsForUser(name)...)
+	}
+}
+
+func populate(cache MySegmentsCache, keys int, segments int) {
+	for segment := 0; segment < segments; segment++ {
+		toAdd := set.NewSet()
+		for key := segment; key < keys; key += segments / 2 {
+			toAdd.Add(fmt.Sprintf("user-key-%d", key))
+		}
+		cache.Update(fmt.Sprintf("segment-%d", segment), toAdd, set.NewSet())
+	}
+}
+
+func benchmarkMemory(b *testing.B, factory func() MySegmentsCache) {
+	const keys = 200000
+	var before, after runtime.MemStats
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		cache := factory()
+		populate(cache, keys, 20)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/keys, "heap-bytes/key")
+		runtime.KeepAlive(cache)
+	}
+}
+
+func BenchmarkMySegmentsMemoryRegular(b *testing.B) {
+	benchmarkMemory(b, func() MySegmentsCache { return NewMySegmentsCache() })
+}
+
+func BenchmarkMySegmentsMemoryCompact(b *testing.B) {
+	benchmarkMemory(b, func() MySegmentsCache { return NewCompactMySegmentsCache(nil, 0, logging.NewLogger(nil)) })
+}
+
+func benchmarkLookup(b *testing.B, cache MySegmentsCache) {
+	populate(cache, 100000, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.SegmentsForUser(fmt.Sprintf("user-key-%d", i%100000))
+	}
+}
+
+func BenchmarkMySegmentsLookupRegular(b *testing.B) {
+	benchmarkLookup(b, NewMySegmentsCache())
+}
+
+func BenchmarkMySegmentsLookupCompact(b *testing.B) {
+	benchmarkLookup(b, NewCompactMySegmentsCache(nil, 0, logging.NewLogger(nil)))
+}
+
+func benchmarkUpdate(b *testing.B, cache MySegmentsCache) {
+	toAdd := set.NewSet()
+	for key := 0; key < 10000; key++ {
+		toAdd.Add(fmt.Sprintf("user-key-%d", key))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		name := fmt.Sprintf("segment-%d", i%10)
+		cache.Update(name, toAdd, set.NewSet())
+		cache.Update(name, set.NewSet(), toAdd)
+	}
+}
+
+func BenchmarkMySegmentsUpdateRegular(b *testing.B) {
+	benchmarkUpdate(b, NewMySegmentsCache())
+}
+
+func BenchmarkMySegmentsUpdateCompact(b *testing.B) {
+	benchmarkUpdate(b, NewCompactMySegmentsCache(nil, 0, logging.NewLogger(nil)))
+}
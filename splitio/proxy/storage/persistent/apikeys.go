@@ -0,0 +1,69 @@
+package persistent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
+)
+
+const clientAPIKeysCollectionName = "CLIENT_APIKEYS_COLLECTION"
+
+// ClientAPIKeyCollection stores client apikeys created at runtime, keyed by their ID
+type ClientAPIKeyCollection struct {
+	db     DBWrapper
+	logger logging.LoggerInterface
+}
+
+// NewClientAPIKeyCollection constructs a new client apikey collection
+func NewClientAPIKeyCollection(db DBWrapper, logger logging.LoggerInterface) *ClientAPIKeyCollection {
+	return &ClientAPIKeyCollection{db: db, logger: logger}
+}
+
+// Save inserts or updates a key
+func (c *ClientAPIKeyCollection) Save(key *apikeys.Key) error {
+	serialized, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("error serializing client apikey: %w", err)
+	}
+
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(clientAPIKeysCollectionName))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key.ID), serialized)
+	})
+}
+
+// FetchAll returns all the stored keys
+func (c *ClientAPIKeyCollection) FetchAll() ([]apikeys.Key, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	var result []apikeys.Key
+	err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(clientAPIKeysCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var key apikeys.Key
+			if err := json.Unmarshal(v, &key); err != nil {
+				c.logger.Warning("skipping unparseable client apikey: ", err)
+				return nil
+			}
+			result = append(result, key)
+			return nil
+		})
+	})
+	return result, err
+}
+
+var _ apikeys.Store = (*ClientAPIKeyCollection)(nil)
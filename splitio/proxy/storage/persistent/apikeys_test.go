@@ -0,0 +1,34 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/apikeys"
+)
+
+func TestClientAPIKeyCollection(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	coll := NewClientAPIKeyCollection(dbw, logging.NewLogger(nil))
+	if keys, err := coll.FetchAll(); err != nil || len(keys) != 0 {
+		t.Error("no keys should be returned from an empty collection. Got: ", keys, err)
+	}
+
+	coll.Save(&apikeys.Key{ID: "id1", Hash: "hash1", Label: "first", CreatedAt: 1})
+	coll.Save(&apikeys.Key{ID: "id2", Hash: "hash2", Label: "second", CreatedAt: 2})
+	coll.Save(&apikeys.Key{ID: "id1", Hash: "hash1", Label: "first", CreatedAt: 1, RevokedAt: 3})
+
+	keys, err := coll.FetchAll()
+	if err != nil || len(keys) != 2 {
+		t.Error("2 keys should be returned. Got: ", keys, err)
+	}
+
+	if keys[0].ID != "id1" || keys[0].RevokedAt != 3 || keys[1].Label != "second" {
+		t.Error("wrong keys returned: ", keys)
+	}
+}
@@ -60,6 +60,21 @@ func (b *BoltDBWrapper) Unlock() {
 	b.mutex.Unlock()
 }
 
+// Close releases the underlying db file
+func (b *BoltDBWrapper) Close() error {
+	return b.wrapped.Close()
+}
+
+// Size returns the current size of the database in bytes
+func (b *BoltDBWrapper) Size() (int64, error) {
+	var size int64
+	err := b.View(func(tx *bolt.Tx) error {
+		size = tx.Size()
+		return nil
+	})
+	return size, err
+}
+
 // GetRawSnapshot dumps all the contents of the db into a raw byte buffer
 func (b *BoltDBWrapper) GetRawSnapshot() ([]byte, error) {
 	var buffer bytes.Buffer
@@ -90,6 +105,7 @@ type CollectionWrapper interface {
 	Fetch(id uint64) ([]byte, error)
 	FetchBy(key []byte) ([]byte, error)
 	FetchAll() ([][]byte, error)
+	DeleteAll() error
 	Logger() logging.LoggerInterface
 }
 
@@ -312,6 +328,20 @@ func (c *BoltDBCollectionWrapper) FetchAll() ([][]byte, error) {
 	return toReturn, err
 }
 
+// DeleteAll removes all the items in the collection
+func (c *BoltDBCollectionWrapper) DeleteAll() error {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket([]byte(c.name))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		return nil
+	})
+}
+
 // Logger returns a reference to a logger
 func (c *BoltDBCollectionWrapper) Logger() logging.LoggerInterface {
 	return c.logger
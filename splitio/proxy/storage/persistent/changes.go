@@ -0,0 +1,127 @@
+package persistent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+const changeLogCollectionName = "CHANGE_LOG_COLLECTION"
+
+// ChangeLogCollection is an append-only collection of split/segment change entries, keyed by an autoincremental ID
+type ChangeLogCollection struct {
+	db     DBWrapper
+	logger logging.LoggerInterface
+}
+
+// NewChangeLogCollection constructs a new change log collection
+func NewChangeLogCollection(db DBWrapper, logger logging.LoggerInterface) *ChangeLogCollection {
+	return &ChangeLogCollection{db: db, logger: logger}
+}
+
+// Append stores the entries, assigning them an ID
+func (c *ChangeLogCollection) Append(entries []changelog.Entry) error {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(changeLogCollectionName))
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			id, err := bucket.NextSequence()
+			if err != nil {
+				return fmt.Errorf("error generating change log id: %w", err)
+			}
+
+			entry.ID = strconv.FormatUint(id, 10)
+			serialized, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("error serializing change log entry: %w", err)
+			}
+
+			if err := bucket.Put(itob(id), serialized); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query returns the entries matching the query, newest first
+func (c *ChangeLogCollection) Query(query changelog.Query) ([]changelog.Entry, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	var result []changelog.Entry
+	err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(changeLogCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil && (query.Limit <= 0 || len(result) < query.Limit); k, v = cursor.Prev() {
+			var entry changelog.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				c.logger.Warning("skipping unparseable change log entry: ", err)
+				continue
+			}
+
+			if query.Since > 0 && entry.Timestamp < query.Since { // entries are sorted by time, nothing else will match
+				break
+			}
+
+			if query.Matches(&entry) {
+				result = append(result, entry)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Trim removes entries older than the supplied timestamp & the oldest ones in excess of maxEntries
+func (c *ChangeLogCollection) Trim(olderThan int64, maxEntries int) (int, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	removed := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(changeLogCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		excess := 0
+		if maxEntries > 0 {
+			excess = bucket.Stats().KeyN - maxEntries
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.First() {
+			if removed >= excess {
+				var entry changelog.Entry
+				if err := json.Unmarshal(v, &entry); err == nil && entry.Timestamp >= olderThan {
+					break
+				}
+			}
+
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+var _ changelog.Storage = (*ChangeLogCollection)(nil)
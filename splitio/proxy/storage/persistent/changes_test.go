@@ -0,0 +1,58 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
+)
+
+func TestChangeLogCollection(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	coll := NewChangeLogCollection(dbw, logging.NewLogger(nil))
+	if entries, err := coll.Query(changelog.Query{}); err != nil || len(entries) != 0 {
+		t.Error("no entries should be returned from an empty collection. Got: ", entries, err)
+	}
+
+	err = coll.Append([]changelog.Entry{
+		{Kind: changelog.KindSplit, Name: "split1", Action: changelog.ActionAdded, Timestamp: 100},
+		{Kind: changelog.KindSegment, Name: "segment1", Action: changelog.ActionUpdated, Timestamp: 200, KeysAdded: 3},
+		{Kind: changelog.KindSplit, Name: "split1", Action: changelog.ActionKilled, Timestamp: 300},
+	})
+	if err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	entries, _ := coll.Query(changelog.Query{})
+	if len(entries) != 3 || entries[0].ID != "3" || entries[0].Action != changelog.ActionKilled || entries[2].ID != "1" {
+		t.Error("entries should be returned newest first. Got: ", entries)
+	}
+
+	entries, _ = coll.Query(changelog.Query{Kind: changelog.KindSplit, Name: "split1", Limit: 1})
+	if len(entries) != 1 || entries[0].Action != changelog.ActionKilled {
+		t.Error("only the latest split1 entry should be returned. Got: ", entries)
+	}
+
+	entries, _ = coll.Query(changelog.Query{Since: 150, Until: 250})
+	if len(entries) != 1 || entries[0].Name != "segment1" || entries[0].KeysAdded != 3 {
+		t.Error("only the segment entry should be returned. Got: ", entries)
+	}
+
+	if removed, err := coll.Trim(150, 0); err != nil || removed != 1 {
+		t.Error("1 entry should be removed by age. Got: ", removed, err)
+	}
+
+	if removed, err := coll.Trim(0, 1); err != nil || removed != 1 {
+		t.Error("1 entry should be removed by size. Got: ", removed, err)
+	}
+
+	entries, _ = coll.Query(changelog.Query{})
+	if len(entries) != 1 || entries[0].ID != "3" {
+		t.Error("only the newest entry should remain. Got: ", entries)
+	}
+}
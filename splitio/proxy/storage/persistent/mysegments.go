@@ -0,0 +1,88 @@
+package persistent
+
+import (
+	"encoding/binary"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+	bolt "go.etcd.io/bbolt"
+)
+
+const mySegmentsOverflowCollectionName = "MYSEGMENTS_OVERFLOW_COLLECTION"
+
+// MySegmentsOverflowCollection stores the mySegments index entries that don't fit in memory.
+// Contents are derived from the segment changes collection & wiped on startup
+type MySegmentsOverflowCollection struct {
+	db     DBWrapper
+	logger logging.LoggerInterface
+}
+
+// NewMySegmentsOverflowCollection constructs a new mySegments overflow collection
+func NewMySegmentsOverflowCollection(db DBWrapper, logger logging.LoggerInterface) *MySegmentsOverflowCollection {
+	return &MySegmentsOverflowCollection{db: db, logger: logger}
+}
+
+// Fetch returns the values stored for the supplied keys. Missing keys are not included in the result
+func (c *MySegmentsOverflowCollection) Fetch(keys []string) (map[string]uint32, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	result := make(map[string]uint32, len(keys))
+	err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(mySegmentsOverflowCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		for _, key := range keys {
+			if value := bucket.Get([]byte(key)); len(value) == 4 {
+				result[key] = binary.BigEndian.Uint32(value)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Apply stores the supplied values in a single transaction. Keys with a value of 0 are removed
+func (c *MySegmentsOverflowCollection) Apply(updates map[string]uint32) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(mySegmentsOverflowCollectionName))
+		if err != nil {
+			return err
+		}
+
+		for key, value := range updates {
+			if value == 0 {
+				if err := bucket.Delete([]byte(key)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			encoded := make([]byte, 4)
+			binary.BigEndian.PutUint32(encoded, value)
+			if err := bucket.Put([]byte(key), encoded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Clear removes all the stored keys
+func (c *MySegmentsOverflowCollection) Clear() error {
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(mySegmentsOverflowCollectionName)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(mySegmentsOverflowCollectionName))
+	})
+}
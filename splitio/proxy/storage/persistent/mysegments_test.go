@@ -0,0 +1,36 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestMySegmentsOverflowCollection(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	coll := NewMySegmentsOverflowCollection(dbw, logging.NewLogger(nil))
+	if stored, err := coll.Fetch([]string{"k1"}); err != nil || len(stored) != 0 {
+		t.Error("nothing should be returned from an empty collection. Got: ", stored, err)
+	}
+
+	if err := coll.Apply(map[string]uint32{"k1": 1, "k2": 70000, "k3": 3}); err != nil {
+		t.Error("apply should succeed. Got: ", err)
+	}
+	coll.Apply(map[string]uint32{"k3": 0})
+
+	stored, err := coll.Fetch([]string{"k1", "k2", "k3"})
+	if err != nil || len(stored) != 2 || stored["k1"] != 1 || stored["k2"] != 70000 {
+		t.Error("unexpected values: ", stored, err)
+	}
+
+	if err := coll.Clear(); err != nil {
+		t.Error("clear should succeed. Got: ", err)
+	}
+	if stored, _ := coll.Fetch([]string{"k1", "k2"}); len(stored) != 0 {
+		t.Error("collection should be empty. Got: ", stored)
+	}
+}
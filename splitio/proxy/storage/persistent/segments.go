@@ -2,16 +2,25 @@ package persistent
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/splitio/go-toolkit/v5/datastructures/set"
 	"github.com/splitio/go-toolkit/v5/logging"
+	bolt "go.etcd.io/bbolt"
 )
 
 const segmentChangesCollectionName = "SEGMENT_CHANGES_COLLECTION"
 
+// segmentScanChunkSize is the max number of keys read within a single transaction when scanning a segment,
+// so that the db is not held while the keys are being processed
+const segmentScanChunkSize = 1000
+
+var errInvalidSegmentKey = errors.New("invalid segment key encoding")
+
 // SegmentKey represents a segment key data
 type SegmentKey struct {
 	Name         string
@@ -25,9 +34,10 @@ type SegmentChangesItem struct {
 	Keys map[string]SegmentKey
 }
 
-// SegmentChangesCollection represents a collection of SplitChangesItem
+// SegmentChangesCollection represents a collection of SplitChangesItem.
+// Each segment is stored in a nested bucket with an entry per key, so that keys can be updated & read incrementally
 type SegmentChangesCollection struct {
-	collection   CollectionWrapper
+	db           DBWrapper
 	segmentsTill map[string]int64
 	logger       logging.LoggerInterface
 	mutex        sync.RWMutex
@@ -35,11 +45,15 @@ type SegmentChangesCollection struct {
 
 // NewSegmentChangesCollection returns an instance of SegmentChangesCollection
 func NewSegmentChangesCollection(db DBWrapper, logger logging.LoggerInterface) *SegmentChangesCollection {
-	return &SegmentChangesCollection{
-		collection:   &BoltDBCollectionWrapper{db: db, name: segmentChangesCollectionName, logger: logger},
+	collection := &SegmentChangesCollection{
+		db:           db,
 		segmentsTill: make(map[string]int64, 0),
 		logger:       logger,
 	}
+	if err := collection.migrateLegacyItems(); err != nil {
+		logger.Error("error migrating segments stored in legacy format: ", err)
+	}
+	return collection
 }
 
 // Update persists a segmentChanges update
@@ -47,59 +61,45 @@ func (c *SegmentChangesCollection) Update(name string, toAdd *set.ThreadUnsafeSe
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	// Error is most likely that the segment isn't yet cached.
-	// In the worst case, the update will fail later in the method
-	segmentItem, _ := c.fetch(name)
-	if segmentItem == nil {
-		segmentItem = &SegmentChangesItem{}
-		segmentItem.Name = name
-		segmentItem.Keys = make(map[string]SegmentKey, toAdd.Size()+toRemove.Size())
-	}
+	c.db.Lock()
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(segmentChangesCollectionName))
+		if err != nil {
+			return err
+		}
 
-	for _, removedKey := range toRemove.List() {
-		strKey, ok := removedKey.(string)
-		if !ok {
-			c.logger.Error(fmt.Sprintf("skipping non-string key when updating segment %s: %+v", name, strKey))
-			continue
+		bucket, err := root.CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
 		}
-		c.logger.Debug("Removing", strKey, "from", name)
-		if _, exists := segmentItem.Keys[strKey]; exists {
-			itemAux := segmentItem.Keys[strKey]
-			itemAux.Removed = true
-			itemAux.ChangeNumber = cn
-			segmentItem.Keys[strKey] = itemAux
-		} else {
-			segmentItem.Keys[strKey] = SegmentKey{
-				Name:         strKey,
-				Removed:      true,
-				ChangeNumber: cn,
+
+		for _, removedKey := range toRemove.List() {
+			strKey, ok := removedKey.(string)
+			if !ok {
+				c.logger.Error(fmt.Sprintf("skipping non-string key when updating segment %s: %+v", name, removedKey))
+				continue
+			}
+			c.logger.Debug("Removing", strKey, "from", name)
+			if err := bucket.Put([]byte(strKey), encodeSegmentKey(cn, true)); err != nil {
+				return err
 			}
 		}
 
-	}
-
-	for _, addedKey := range toAdd.List() {
-		strKey, ok := addedKey.(string)
-		if !ok {
-			c.logger.Error(fmt.Sprintf("skipping non-string key when updating segment %s: %+v", name, strKey))
-			continue
-		}
-		c.logger.Debug("Adding", strKey, "in", name)
-		if _, exists := segmentItem.Keys[strKey]; exists {
-			itemAux := segmentItem.Keys[strKey]
-			itemAux.Removed = false
-			itemAux.ChangeNumber = cn
-			segmentItem.Keys[strKey] = itemAux
-		} else {
-			segmentItem.Keys[strKey] = SegmentKey{
-				Name:         strKey,
-				Removed:      false,
-				ChangeNumber: cn,
+		for _, addedKey := range toAdd.List() {
+			strKey, ok := addedKey.(string)
+			if !ok {
+				c.logger.Error(fmt.Sprintf("skipping non-string key when updating segment %s: %+v", name, addedKey))
+				continue
+			}
+			c.logger.Debug("Adding", strKey, "in", name)
+			if err := bucket.Put([]byte(strKey), encodeSegmentKey(cn, false)); err != nil {
+				return err
 			}
 		}
-	}
+		return nil
+	})
+	c.db.Unlock()
 
-	err := c.collection.SaveAs([]byte(name), segmentItem)
 	if err != nil {
 		return fmt.Errorf("error saving segment changes to bolt: %w", err)
 	}
@@ -107,61 +107,125 @@ func (c *SegmentChangesCollection) Update(name string, toAdd *set.ThreadUnsafeSe
 	return nil
 }
 
-// Fetch return a SegmentChangesItem
-func (c *SegmentChangesCollection) Fetch(name string) (*SegmentChangesItem, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	return c.fetch(name)
-}
+// ScanKeys calls f for every key of a segment in lexicographical order, starting right after `after` and up to (and including)
+// `until`. Empty bounds mean the first/last key respectively. Keys are read in small chunks, each one in its own transaction,
+// so the db is not held while f is executing. As a consequence, keys updated during a scan may be seen in any of their states.
+// If f returns an error, the scan is stopped & the error returned
+func (c *SegmentChangesCollection) ScanKeys(name string, after string, until string, f func(key SegmentKey) error) error {
+	chunk := make([]SegmentKey, 0, segmentScanChunkSize)
+	for {
+		chunk = chunk[:0]
+		var done bool
+		err := c.viewSegment(name, func(bucket *bolt.Bucket) error {
+			cursor := bucket.Cursor()
+			var k, v []byte
+			if after == "" {
+				k, v = cursor.First()
+			} else if k, v = cursor.Seek([]byte(after)); k != nil && string(k) == after {
+				k, v = cursor.Next()
+			}
 
-func (c *SegmentChangesCollection) fetch(name string) (*SegmentChangesItem, error) {
-	item, err := c.collection.FetchBy([]byte(name))
-	if err != nil {
-		return nil, err
+			for ; k != nil && len(chunk) < segmentScanChunkSize; k, v = cursor.Next() {
+				if until != "" && string(k) > until {
+					break
+				}
+
+				key, err := decodeSegmentKey(k, v)
+				if err != nil {
+					return fmt.Errorf("error reading key '%s' of segment '%s': %w", k, name, err)
+				}
+				chunk = append(chunk, key)
+			}
+			done = len(chunk) < segmentScanChunkSize
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for idx := range chunk {
+			if err := f(chunk[idx]); err != nil {
+				return err
+			}
+		}
+
+		if done || (until != "" && chunk[len(chunk)-1].Name == until) {
+			return nil
+		}
+		after = chunk[len(chunk)-1].Name
 	}
+}
+
+// Names returns the names of all the stored segments
+func (c *SegmentChangesCollection) Names() ([]string, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
 
-	var decodeBuffer bytes.Buffer
-	decodeBuffer.Write(item)
-	dec := gob.NewDecoder(&decodeBuffer)
+	names := make([]string, 0)
+	err := c.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(segmentChangesCollectionName))
+		if root == nil {
+			return nil
+		}
 
-	var q SegmentChangesItem
-	errq := dec.Decode(&q)
-	if errq != nil {
-		c.collection.Logger().Error("decode error:", errq)
+		return root.ForEach(func(k, v []byte) error {
+			if v == nil { // nested buckets have a nil value
+				names = append(names, string(k))
+			}
+			return nil
+		})
+	})
+	return names, err
+}
+
+// Fetch return a SegmentChangesItem
+func (c *SegmentChangesCollection) Fetch(name string) (*SegmentChangesItem, error) {
+	item := &SegmentChangesItem{Name: name, Keys: make(map[string]SegmentKey)}
+	err := c.ScanKeys(name, "", "", func(key SegmentKey) error {
+		item.Keys[key.Name] = key
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return &q, nil
+	return item, nil
 }
 
 // FetchAll return a list of SegmentChangesItem
 func (c *SegmentChangesCollection) FetchAll() ([]SegmentChangesItem, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	items, err := c.collection.FetchAll()
+	names, err := c.Names()
 	if err != nil {
 		return nil, err
 	}
 
-	var toReturn = make([]SegmentChangesItem, 0)
-	for _, item := range items {
-		if item == nil {
-			continue
-		}
-
-		var q SegmentChangesItem
-		var decodeBuffer bytes.Buffer
-		decodeBuffer.Write(item)
-		errq := gob.NewDecoder(&decodeBuffer).Decode(&q)
-		if errq != nil {
-			c.collection.Logger().Error("decode error:", errq)
+	toReturn := make([]SegmentChangesItem, 0, len(names))
+	for _, name := range names {
+		item, err := c.Fetch(name)
+		if err != nil {
+			c.logger.Error(fmt.Sprintf("error fetching segment '%s': %s", name, err))
 			continue
 		}
-
-		toReturn = append(toReturn, q)
+		toReturn = append(toReturn, *item)
 	}
-
 	return toReturn, nil
 }
 
+// Clear removes all the segments & their change numbers
+func (c *SegmentChangesCollection) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.segmentsTill = make(map[string]int64, 0)
+
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(segmentChangesCollectionName)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(segmentChangesCollectionName))
+	})
+}
+
 // ChangeNumber returns changeNumber
 func (c *SegmentChangesCollection) ChangeNumber(segment string) int64 {
 	c.mutex.RLock()
@@ -179,3 +243,82 @@ func (c *SegmentChangesCollection) SetChangeNumber(segment string, cn int64) {
 	defer c.mutex.Unlock()
 	c.segmentsTill[segment] = cn
 }
+
+func (c *SegmentChangesCollection) viewSegment(name string, f func(bucket *bolt.Bucket) error) error {
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(segmentChangesCollectionName))
+		if root == nil {
+			return ErrorBucketNotFound
+		}
+
+		bucket := root.Bucket([]byte(name))
+		if bucket == nil {
+			return ErrorKeyNotFound
+		}
+		return f(bucket)
+	})
+}
+
+// migrateLegacyItems converts segments stored as a single gob-encoded item (as done by previous versions,
+// and hence present in older snapshots) into nested buckets
+func (c *SegmentChangesCollection) migrateLegacyItems() error {
+	c.db.Lock()
+	defer c.db.Unlock()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(segmentChangesCollectionName))
+		if root == nil {
+			return nil
+		}
+
+		legacy := make(map[string][]byte)
+		root.ForEach(func(k, v []byte) error {
+			if v != nil {
+				legacy[string(k)] = append([]byte(nil), v...)
+			}
+			return nil
+		})
+
+		for name, raw := range legacy {
+			if err := root.Delete([]byte(name)); err != nil {
+				return err
+			}
+
+			var item SegmentChangesItem
+			if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&item); err != nil {
+				c.logger.Error(fmt.Sprintf("dropping segment '%s' which could not be decoded: %s", name, err))
+				continue
+			}
+
+			bucket, err := root.CreateBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+
+			for _, key := range item.Keys {
+				if err := bucket.Put([]byte(key.Name), encodeSegmentKey(key.ChangeNumber, key.Removed)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func encodeSegmentKey(cn int64, removed bool) []byte {
+	encoded := make([]byte, binary.MaxVarintLen64+1)
+	n := binary.PutVarint(encoded, cn)
+	if removed {
+		encoded[n] = 1
+	}
+	return encoded[:n+1]
+}
+
+func decodeSegmentKey(name []byte, value []byte) (SegmentKey, error) {
+	cn, n := binary.Varint(value)
+	if n <= 0 || n >= len(value) {
+		return SegmentKey{}, errInvalidSegmentKey
+	}
+	return SegmentKey{Name: string(name), ChangeNumber: cn, Removed: value[n] == 1}, nil
+}
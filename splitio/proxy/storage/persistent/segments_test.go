@@ -1,6 +1,8 @@
 package persistent
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/splitio/go-toolkit/v5/datastructures/set"
@@ -56,3 +58,82 @@ func TestSegmentPersistentStorage(t *testing.T) {
 		t.Error("k1 should be removed")
 	}
 }
+
+func TestSegmentScanKeys(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	segmentC := NewSegmentChangesCollection(dbw, logging.NewLogger(nil))
+	toAdd := set.NewSet()
+	for idx := 0; idx < 2500; idx++ { // more than a chunk
+		toAdd.Add(fmt.Sprintf("k%04d", idx))
+	}
+	segmentC.Update("s1", toAdd, set.NewSet(), 1)
+	segmentC.Update("s1", set.NewSet(), set.NewSet("k0001"), 2)
+
+	var visited []SegmentKey
+	err = segmentC.ScanKeys("s1", "", "", func(key SegmentKey) error {
+		visited = append(visited, key)
+		return nil
+	})
+	if err != nil || len(visited) != 2500 {
+		t.Error("all keys should have been visited: ", len(visited), err)
+	}
+	for idx := 1; idx < len(visited); idx++ {
+		if visited[idx-1].Name >= visited[idx].Name {
+			t.Error("keys should be visited in order")
+		}
+	}
+	if k := visited[1]; k.Name != "k0001" || !k.Removed || k.ChangeNumber != 2 {
+		t.Error("wrong key data: ", k)
+	}
+
+	visited = nil
+	segmentC.ScanKeys("s1", "k0999", "k2000", func(key SegmentKey) error {
+		visited = append(visited, key)
+		return nil
+	})
+	if len(visited) != 1001 || visited[0].Name != "k1000" || visited[1000].Name != "k2000" {
+		t.Error("wrong keys visited between bounds: ", len(visited))
+	}
+
+	stop := errors.New("stop")
+	if err := segmentC.ScanKeys("s1", "", "", func(key SegmentKey) error { return stop }); err != stop {
+		t.Error("the callback error should be returned: ", err)
+	}
+
+	if err := segmentC.ScanKeys("s2", "", "", func(key SegmentKey) error { return nil }); !errors.Is(err, ErrorKeyNotFound) {
+		t.Error("scanning a missing segment should fail: ", err)
+	}
+
+	names, err := segmentC.Names()
+	if err != nil || len(names) != 1 || names[0] != "s1" {
+		t.Error("wrong names: ", names, err)
+	}
+}
+
+func TestSegmentLegacyItemsMigration(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	logger := logging.NewLogger(nil)
+	legacy := &BoltDBCollectionWrapper{db: dbw, name: segmentChangesCollectionName, logger: logger}
+	legacy.SaveAs([]byte("s1"), &SegmentChangesItem{Name: "s1", Keys: map[string]SegmentKey{
+		"k1": {Name: "k1", ChangeNumber: 1},
+		"k2": {Name: "k2", ChangeNumber: 2, Removed: true},
+	}})
+
+	segmentC := NewSegmentChangesCollection(dbw, logger)
+	forS1, err := segmentC.Fetch("s1")
+	if err != nil {
+		t.Error("err shoud be nil: ", err)
+	}
+
+	if len(forS1.Keys) != 2 || forS1.Keys["k1"].Removed || !forS1.Keys["k2"].Removed || forS1.Keys["k2"].ChangeNumber != 2 {
+		t.Error("legacy segment not properly migrated: ", forS1)
+	}
+}
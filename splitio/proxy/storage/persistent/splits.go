@@ -126,6 +126,14 @@ func (c *SplitChangesCollection) FetchAll() ([]dtos.SplitDTO, error) {
 	return toReturn, nil
 }
 
+// Clear removes all the splits & resets the change number
+func (c *SplitChangesCollection) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.changeNumber = -1
+	return c.collection.DeleteAll()
+}
+
 // ChangeNumber returns changeNumber
 func (c *SplitChangesCollection) ChangeNumber() int64 {
 	c.mutex.RLock()
@@ -59,4 +59,16 @@ func TestSplitPersistentStorage(t *testing.T) {
 	if splitC.ChangeNumber() != 2 {
 		t.Error("CN should be 2.")
 	}
+
+	if err := splitC.Clear(); err != nil {
+		t.Error("Clear should not return an error. Got: ", err)
+	}
+
+	if all, _ = splitC.FetchAll(); len(all) != 0 {
+		t.Error("there should be no splits after clearing the collection. Got: ", all)
+	}
+
+	if splitC.ChangeNumber() != -1 {
+		t.Error("CN should be -1 after clearing the collection.")
+	}
 }
@@ -0,0 +1,118 @@
+package persistent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+	bolt "go.etcd.io/bbolt"
+)
+
+const telemetryTimeSliceCollectionName = "TELEMETRY_TIMESLICE_COLLECTION"
+
+// TelemetryTimeSlice is the persisted form of the proxy endpoint telemetry gathered within a time slice
+type TelemetryTimeSlice struct {
+	TimeSlice int64                                 `json:"timeSlice"`
+	Width     int64                                 `json:"width"`
+	Endpoints map[string]TelemetryTimeSliceEndpoint `json:"endpoints"`
+}
+
+// TelemetryTimeSliceEndpoint bundles the latencies & status codes of an endpoint within a time slice
+type TelemetryTimeSliceEndpoint struct {
+	Latencies   []int64       `json:"latencies"`
+	StatusCodes map[int]int64 `json:"statusCodes"`
+}
+
+// TelemetryTimeSliceCollection stores proxy endpoint telemetry time slices, keyed (and thus sorted) by their start time
+type TelemetryTimeSliceCollection struct {
+	db     DBWrapper
+	logger logging.LoggerInterface
+}
+
+// NewTelemetryTimeSliceCollection constructs a new telemetry time slice collection
+func NewTelemetryTimeSliceCollection(db DBWrapper, logger logging.LoggerInterface) *TelemetryTimeSliceCollection {
+	return &TelemetryTimeSliceCollection{db: db, logger: logger}
+}
+
+// Save stores the supplied time slices, replacing the ones with the same start time if present
+func (c *TelemetryTimeSliceCollection) Save(slices []TelemetryTimeSlice) error {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(telemetryTimeSliceCollectionName))
+		if err != nil {
+			return err
+		}
+
+		for _, slice := range slices {
+			serialized, err := json.Marshal(slice)
+			if err != nil {
+				return fmt.Errorf("error serializing telemetry time slice: %w", err)
+			}
+
+			if err := bucket.Put(itob(uint64(slice.TimeSlice)), serialized); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Range returns the time slices starting within [from, to], oldest first. A non-positive `to` means no upper bound
+func (c *TelemetryTimeSliceCollection) Range(from int64, to int64) ([]TelemetryTimeSlice, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	if from < 0 {
+		from = 0
+	}
+
+	var result []TelemetryTimeSlice
+	err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(telemetryTimeSliceCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(itob(uint64(from))); k != nil; k, v = cursor.Next() {
+			if to > 0 && int64(btoi(k)) > to {
+				break
+			}
+
+			var slice TelemetryTimeSlice
+			if err := json.Unmarshal(v, &slice); err != nil {
+				c.logger.Warning("skipping unparseable telemetry time slice: ", err)
+				continue
+			}
+			result = append(result, slice)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Trim removes the time slices starting before the supplied timestamp
+func (c *TelemetryTimeSliceCollection) Trim(olderThan int64) (int, error) {
+	c.db.Lock()
+	defer c.db.Unlock()
+
+	removed := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(telemetryTimeSliceCollectionName))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for k, _ := cursor.First(); k != nil && int64(btoi(k)) < olderThan; k, _ = cursor.First() {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,60 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestTelemetryTimeSliceCollection(t *testing.T) {
+	dbw, err := NewBoltWrapper(BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	coll := NewTelemetryTimeSliceCollection(dbw, logging.NewLogger(nil))
+	if slices, err := coll.Range(0, 0); err != nil || len(slices) != 0 {
+		t.Error("no slices should be returned from an empty collection. Got: ", slices, err)
+	}
+
+	makeSlice := func(ts int64, okCount int64) TelemetryTimeSlice {
+		return TelemetryTimeSlice{
+			TimeSlice: ts,
+			Width:     60,
+			Endpoints: map[string]TelemetryTimeSliceEndpoint{
+				"splitChanges": {Latencies: []int64{okCount, 0, 1}, StatusCodes: map[int]int64{200: okCount}},
+			},
+		}
+	}
+
+	if err := coll.Save([]TelemetryTimeSlice{makeSlice(180, 1), makeSlice(60, 1), makeSlice(120, 1)}); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	// overwrite an existing slice
+	if err := coll.Save([]TelemetryTimeSlice{makeSlice(180, 5)}); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+
+	slices, _ := coll.Range(0, 0)
+	if len(slices) != 3 || slices[0].TimeSlice != 60 || slices[2].TimeSlice != 180 {
+		t.Error("slices should be returned oldest first. Got: ", slices)
+	}
+	if ep := slices[2].Endpoints["splitChanges"]; ep.StatusCodes[200] != 5 || ep.Latencies[0] != 5 || slices[2].Width != 60 {
+		t.Error("the latest version of the slice should be returned. Got: ", slices[2])
+	}
+
+	slices, _ = coll.Range(100, 150)
+	if len(slices) != 1 || slices[0].TimeSlice != 120 {
+		t.Error("only the slice within the range should be returned. Got: ", slices)
+	}
+
+	if removed, err := coll.Trim(150); err != nil || removed != 2 {
+		t.Error("2 slices should have been removed. Got: ", removed, err)
+	}
+
+	slices, _ = coll.Range(0, 0)
+	if len(slices) != 1 || slices[0].TimeSlice != 180 {
+		t.Error("only the newest slice should remain. Got: ", slices)
+	}
+}
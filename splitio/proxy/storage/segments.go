@@ -9,6 +9,7 @@ import (
 	"github.com/splitio/go-toolkit/v5/datastructures/set"
 	"github.com/splitio/go-toolkit/v5/logging"
 
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/observability"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/optimized"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
@@ -21,6 +22,7 @@ var ErrSegmentNotFound = errors.New("segment not found")
 // to respond to resquests from sdk clients
 type ProxySegmentStorage interface {
 	ChangesSince(name string, since int64) (*dtos.SegmentChangesDTO, error)
+	StreamChangesSince(name string, since int64, page *SegmentChangesPage) (SegmentChangesStream, error)
 	SegmentsFor(key string) ([]string, error)
 	CountRemovedKeys(segmentName string) int
 }
@@ -31,11 +33,25 @@ type ProxySegmentStorageImpl struct {
 	nameCountCache *observability.ActiveSegmentTracker
 	db             *persistent.SegmentChangesCollection
 	mysegments     optimized.MySegmentsCache
+	changes        *changelog.Recorder
 }
 
-// NewProxySegmentStorage for proxy
-func NewProxySegmentStorage(db persistent.DBWrapper, logger logging.LoggerInterface, restoreFromBackup bool) *ProxySegmentStorageImpl {
-	cache := optimized.NewMySegmentsCache()
+// NewProxySegmentStorage for proxy. If maxKeysInMemory is > 0, keys of the mySegments index beyond that limit are kept on disk
+func NewProxySegmentStorage(
+	db persistent.DBWrapper,
+	logger logging.LoggerInterface,
+	restoreFromBackup bool,
+	maxKeysInMemory int,
+) *ProxySegmentStorageImpl {
+	var overflow optimized.MySegmentsOverflow
+	if maxKeysInMemory > 0 {
+		overflowCollection := persistent.NewMySegmentsOverflowCollection(db, logger)
+		if err := overflowCollection.Clear(); err != nil { // contents are rebuilt from the segment changes collection
+			logger.Error("error clearing mySegments overflow storage: ", err)
+		}
+		overflow = overflowCollection
+	}
+	cache := optimized.NewCompactMySegmentsCache(overflow, maxKeysInMemory, logger)
 	disk := persistent.NewSegmentChangesCollection(db, logger)
 	nameCountCache := observability.NewActiveSegmentTracker(100) // just a guess, we don't know the size yet
 	if restoreFromBackup {
@@ -54,22 +70,13 @@ func NewProxySegmentStorage(db persistent.DBWrapper, logger logging.LoggerInterf
 // regardless whether the `since` parameter is old enough to require such removal or not.
 // We should eventually see if it's worth taking an approach similar to the one in splits or not
 func (s *ProxySegmentStorageImpl) ChangesSince(name string, since int64) (*dtos.SegmentChangesDTO, error) {
-	item, err := s.db.Fetch(name)
-	if err != nil {
-		if errors.Is(err, persistent.ErrorBucketNotFound) || errors.Is(err, persistent.ErrorKeyNotFound) {
-			return nil, ErrSegmentNotFound
-		}
-		return nil, fmt.Errorf("unexpected error when fetching segment '%s': %w", name, err)
-	}
-
 	added := make([]string, 0)
 	removed := make([]string, 0)
 	till := since
 
-	// Horrible loop borrowed from sdk-api
-	for _, skey := range item.Keys {
+	err := s.db.ScanKeys(name, "", "", func(skey persistent.SegmentKey) error {
 		if skey.ChangeNumber <= since { // if the key was updated in a previous/current CN, we don't need to return it
-			continue
+			return nil
 		}
 
 		// Add the key to the corresponding list
@@ -79,17 +86,48 @@ func (s *ProxySegmentStorageImpl) ChangesSince(name string, since int64) (*dtos.
 			added = append(added, skey.Name)
 		}
 
-		// Update the till to be returned if necessary
-		if since > 0 && skey.ChangeNumber > till {
-			till = skey.ChangeNumber
-		} else if !skey.Removed && skey.ChangeNumber > till {
-			till = skey.ChangeNumber
-		}
+		till = updatedTill(&skey, since, till)
+		return nil
+	})
+	if err != nil {
+		return nil, wrapSegmentFetchError(name, err)
 	}
 
 	return &dtos.SegmentChangesDTO{Name: name, Since: since, Till: till, Added: added, Removed: removed}, nil
 }
 
+// StreamChangesSince returns the same payload as ChangesSince, optionally paginated, which is read from disk as it's being written
+func (s *ProxySegmentStorageImpl) StreamChangesSince(name string, since int64, page *SegmentChangesPage) (SegmentChangesStream, error) {
+	stream := &persistentSegmentChangesStream{db: s.db, name: name, since: since}
+	if page != nil && page.Token != "" {
+		var err error
+		if stream.till, stream.upTo, stream.after, err = decodePageToken(page.Token); err != nil {
+			return nil, err
+		}
+	} else {
+		stream.till = since
+		err := s.db.ScanKeys(name, "", "", func(skey persistent.SegmentKey) error {
+			if skey.ChangeNumber > stream.upTo {
+				stream.upTo = skey.ChangeNumber
+			}
+			if skey.ChangeNumber > since {
+				stream.till = updatedTill(&skey, since, stream.till)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, wrapSegmentFetchError(name, err)
+		}
+	}
+
+	if page != nil && page.Size > 0 {
+		if err := stream.paginate(page.Size); err != nil {
+			return nil, wrapSegmentFetchError(name, err)
+		}
+	}
+	return stream, nil
+}
+
 // SegmentsFor returns the list of segments a key belongs to
 func (s *ProxySegmentStorageImpl) SegmentsFor(key string) ([]string, error) {
 	return s.mysegments.SegmentsForUser(key), nil
@@ -114,18 +152,16 @@ func (s *ProxySegmentStorageImpl) SetChangeNumber(segment string, changeNumber i
 // Keys method
 func (s *ProxySegmentStorageImpl) Keys(segmentName string) *set.ThreadUnsafeSet {
 	toReturn := set.NewSet()
-	changes, err := s.db.Fetch(segmentName)
+	err := s.db.ScanKeys(segmentName, "", "", func(key persistent.SegmentKey) error {
+		toReturn.Add(key)
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, persistent.ErrorBucketNotFound) || errors.Is(err, persistent.ErrorKeyNotFound) {
 			s.logger.Error(fmt.Sprintf("segment %s not found. failed to fetch keys.", segmentName))
 		} else {
 			s.logger.Error(fmt.Sprintf("unexpected error when fetching segment keys for '%s': %s", segmentName, err.Error()))
 		}
-		return toReturn
-	}
-
-	for _, key := range changes.Keys {
-		toReturn.Add(key)
 	}
 	return toReturn
 }
@@ -141,31 +177,39 @@ func (s *ProxySegmentStorageImpl) Update(name string, toAdd *set.ThreadUnsafeSet
 	errDB := s.db.Update(name, toAdd, toRemove, changeNumber)
 	if errCache == nil && errDB == nil {
 		s.nameCountCache.Update(name, toAdd.Size(), toRemove.Size())
+		if s.changes != nil {
+			s.changes.RecordSegmentChange(name, toAdd.Size(), toRemove.Size(), changeNumber)
+		}
 		return nil
 	}
 
 	return fmt.Errorf("errors updating cache: %s || errors updating db: %s", errCache.Error(), errDB.Error())
 }
 
-// CountRemovedKeys method
-func (s *ProxySegmentStorageImpl) CountRemovedKeys(segmentName string) int {
-	segment, err := s.db.Fetch(segmentName)
-	if err != nil {
-		return 0
+// SetChangeRecorder sets the recorder used to keep track of segment changes
+func (s *ProxySegmentStorageImpl) SetChangeRecorder(recorder *changelog.Recorder) {
+	s.changes = recorder
+}
+
+// Reset removes all the segments from the mySegments cache & the persistent storage
+func (s *ProxySegmentStorageImpl) Reset() error {
+	s.mysegments.Clear()
+	s.nameCountCache.Reset()
+	if err := s.db.Clear(); err != nil {
+		return fmt.Errorf("error wiping segments from db: %w", err)
 	}
+	return nil
+}
 
-	changeNumber := int64(0)
+// CountRemovedKeys method
+func (s *ProxySegmentStorageImpl) CountRemovedKeys(segmentName string) int {
 	removedKeys := 0
-	for _, k := range segment.Keys {
-		if k.ChangeNumber > changeNumber {
-			changeNumber = k.ChangeNumber
-		}
-
-		if k.Removed {
+	s.db.ScanKeys(segmentName, "", "", func(key persistent.SegmentKey) error {
+		if key.Removed {
 			removedKeys++
 		}
-	}
-
+		return nil
+	})
 	return removedKeys
 }
 
@@ -174,29 +218,49 @@ func (s *ProxySegmentStorageImpl) NamesAndCount() map[string]int {
 	return s.nameCountCache.NamesAndCount()
 }
 
+// updatedTill returns the till to be returned after including a key updated after `since`
+func updatedTill(skey *persistent.SegmentKey, since int64, till int64) int64 {
+	if since > 0 && skey.ChangeNumber > till {
+		return skey.ChangeNumber
+	} else if !skey.Removed && skey.ChangeNumber > till {
+		return skey.ChangeNumber
+	}
+	return till
+}
+
+func wrapSegmentFetchError(name string, err error) error {
+	if errors.Is(err, persistent.ErrorBucketNotFound) || errors.Is(err, persistent.ErrorKeyNotFound) {
+		return ErrSegmentNotFound
+	}
+	return fmt.Errorf("unexpected error when fetching segment '%s': %w", name, err)
+}
+
 func populateCachesFromDisk(
 	dst optimized.MySegmentsCache,
 	names *observability.ActiveSegmentTracker,
 	src *persistent.SegmentChangesCollection,
 	logger logging.LoggerInterface,
 ) {
-	all, err := src.FetchAll()
+	all, err := src.Names()
 	if err != nil {
 		logger.Error("error popoulating segment cache from disk. Cache will be empty!: ", err)
 		return
 	}
 
-	for idx := range all {
+	for _, name := range all {
 		s := set.NewSet()
-		count := 0
-		for _, k := range all[idx].Keys {
+		err := src.ScanKeys(name, "", "", func(k persistent.SegmentKey) error {
 			if !k.Removed {
 				s.Add(k.Name)
-				count++
 			}
+			return nil
+		})
+		if err != nil {
+			logger.Error(fmt.Sprintf("error popoulating segment cache from disk for segment '%s': %s", name, err))
+			continue
 		}
-		dst.Update(all[idx].Name, s, set.NewSet())
-		names.Update(all[idx].Name, count, 0)
+		dst.Update(name, s, set.NewSet())
+		names.Update(name, s.Size(), 0)
 	}
 }
 
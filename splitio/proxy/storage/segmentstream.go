@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
+)
+
+// ErrInvalidPageToken is returned when the page token supplied for a segmentChanges request cannot be parsed
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+var errStopScan = errors.New("stop scan")
+
+// SegmentChangesPage selects a subset of the keys of a segmentChanges payload
+type SegmentChangesPage struct {
+	// Max number of keys to return. All of them are returned if <= 0
+	Size int
+
+	// Token returned along with the previous page. Empty for the first one
+	Token string
+}
+
+// SegmentChangesStream is a segmentChanges payload that is encoded as it's being written, without holding its keys in memory
+type SegmentChangesStream interface {
+	Till() int64
+	NextPageToken() string
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// NewSegmentChangesStreamFromDTO wraps an already built segmentChanges payload
+func NewSegmentChangesStreamFromDTO(payload *dtos.SegmentChangesDTO) SegmentChangesStream {
+	return &dtoSegmentChangesStream{payload: payload}
+}
+
+type dtoSegmentChangesStream struct {
+	payload *dtos.SegmentChangesDTO
+}
+
+func (s *dtoSegmentChangesStream) Till() int64           { return s.payload.Till }
+func (s *dtoSegmentChangesStream) NextPageToken() string { return "" }
+
+func (s *dtoSegmentChangesStream) WriteTo(w io.Writer) (int64, error) {
+	return encodeSegmentChanges(w, s.payload.Name, s.payload.Since, s.payload.Till, "", sliceIterator(s.payload.Added), sliceIterator(s.payload.Removed))
+}
+
+// persistentSegmentChangesStream reads the keys from the db while the payload is being written. Only keys updated
+// after `since` and not after `upTo` (the highest change number when the request was received) are included, so that
+// keys updated while the response is being written are not returned without the matching `till`.
+// Those keys will be picked up by the next request
+type persistentSegmentChangesStream struct {
+	db            *persistent.SegmentChangesCollection
+	name          string
+	since         int64
+	till          int64
+	upTo          int64
+	after         string
+	until         string
+	nextPageToken string
+}
+
+func (s *persistentSegmentChangesStream) Till() int64           { return s.till }
+func (s *persistentSegmentChangesStream) NextPageToken() string { return s.nextPageToken }
+
+func (s *persistentSegmentChangesStream) WriteTo(w io.Writer) (int64, error) {
+	return encodeSegmentChanges(w, s.name, s.since, s.till, s.nextPageToken, s.keys(false), s.keys(true))
+}
+
+func (s *persistentSegmentChangesStream) includes(key *persistent.SegmentKey) bool {
+	return key.ChangeNumber > s.since && key.ChangeNumber <= s.upTo
+}
+
+func (s *persistentSegmentChangesStream) keys(removed bool) keyIterator {
+	return func(emit func(string) error) error {
+		return s.db.ScanKeys(s.name, s.after, s.until, func(key persistent.SegmentKey) error {
+			if !s.includes(&key) || (key.Removed && s.since > 0) != removed {
+				return nil
+			}
+			return emit(key.Name)
+		})
+	}
+}
+
+// paginate restricts the stream to the first `size` keys to be returned after `after`
+func (s *persistentSegmentChangesStream) paginate(size int) error {
+	count := 0
+	err := s.db.ScanKeys(s.name, s.after, "", func(key persistent.SegmentKey) error {
+		if !s.includes(&key) {
+			return nil
+		}
+		if count == size { // there's at least another key after this page
+			s.nextPageToken = encodePageToken(s.till, s.upTo, s.until)
+			return errStopScan
+		}
+		count++
+		s.until = key.Name
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopScan) {
+		return err
+	}
+	if s.nextPageToken == "" {
+		s.until = "" // last page, read everything left
+	}
+	return nil
+}
+
+// page tokens carry the till & upper change number of the first page, so that all pages are built consistently
+func encodePageToken(till int64, upTo int64, after string) string {
+	raw := strconv.FormatInt(till, 10) + ":" + strconv.FormatInt(upTo, 10) + ":" + after
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (till int64, upTo int64, after string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, "", ErrInvalidPageToken
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return 0, 0, "", ErrInvalidPageToken
+	}
+
+	if till, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, "", ErrInvalidPageToken
+	}
+	if upTo, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return 0, 0, "", ErrInvalidPageToken
+	}
+	return till, upTo, parts[2], nil
+}
+
+type keyIterator func(emit func(key string) error) error
+
+func sliceIterator(keys []string) keyIterator {
+	return func(emit func(string) error) error {
+		for _, key := range keys {
+			if err := emit(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// encodeSegmentChanges writes a segmentChanges payload with the same layout as dtos.SegmentChangesDTO
+func encodeSegmentChanges(
+	w io.Writer,
+	name string,
+	since int64,
+	till int64,
+	nextPageToken string,
+	added keyIterator,
+	removed keyIterator,
+) (int64, error) {
+	counter := &countingWriter{w: w}
+	buffered := bufio.NewWriterSize(counter, 32*1024)
+
+	writeString := func(value string) error {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = buffered.Write(encoded)
+		return err
+	}
+
+	writeKeys := func(iterator keyIterator) error {
+		buffered.WriteByte('[')
+		first := true
+		err := iterator(func(key string) error {
+			if !first {
+				buffered.WriteByte(',')
+			}
+			first = false
+			return writeString(key)
+		})
+		buffered.WriteByte(']')
+		return err
+	}
+
+	buffered.WriteString(`{"name":`)
+	if err := writeString(name); err != nil {
+		return counter.written, err
+	}
+	buffered.WriteString(`,"added":`)
+	if err := writeKeys(added); err != nil {
+		return counter.written, err
+	}
+	buffered.WriteString(`,"removed":`)
+	if err := writeKeys(removed); err != nil {
+		return counter.written, err
+	}
+	buffered.WriteString(`,"since":` + strconv.FormatInt(since, 10) + `,"till":` + strconv.FormatInt(till, 10))
+	if nextPageToken != "" {
+		buffered.WriteString(`,"nextPageToken":`)
+		writeString(nextPageToken)
+	}
+	buffered.WriteByte('}')
+	err := buffered.Flush()
+	return counter.written, err
+}
+
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (c *countingWriter) Write(data []byte) (int, error) {
+	n, err := c.w.Write(data)
+	c.written += int64(n)
+	return n, err
+}
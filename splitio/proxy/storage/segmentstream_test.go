@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
+)
+
+type pagedSegmentChanges struct {
+	dtos.SegmentChangesDTO
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func readStream(t *testing.T, stream SegmentChangesStream) pagedSegmentChanges {
+	t.Helper()
+	var buffer bytes.Buffer
+	n, err := stream.WriteTo(&buffer)
+	if err != nil || n != int64(buffer.Len()) {
+		t.Error("error writing stream: ", n, err)
+	}
+
+	var payload pagedSegmentChanges
+	if err := json.Unmarshal(buffer.Bytes(), &payload); err != nil {
+		t.Error("invalid json written: ", err, buffer.String())
+	}
+	return payload
+}
+
+func TestSegmentChangesStream(t *testing.T) {
+	dbw, err := persistent.NewBoltWrapper(persistent.BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	segments := NewProxySegmentStorage(dbw, logging.NewLogger(nil), false, 0)
+	toAdd := set.NewSet()
+	for idx := 0; idx < 2500; idx++ {
+		toAdd.Add(fmt.Sprintf("k%04d", idx))
+	}
+	segments.Update("s1", toAdd, set.NewSet(), 1)
+	segments.Update("s1", set.NewSet("k\"quoted\""), set.NewSet("k0001", "k0002"), 2)
+
+	for _, since := range []int64{-1, 1, 2} {
+		expected, err := segments.ChangesSince("s1", since)
+		if err != nil {
+			t.Error("no error expected: ", err)
+		}
+
+		stream, err := segments.StreamChangesSince("s1", since, nil)
+		if err != nil {
+			t.Error("no error expected: ", err)
+		}
+		streamed := readStream(t, stream)
+		if stream.Till() != expected.Till || streamed.Till != expected.Till || streamed.Since != since || streamed.Name != "s1" {
+			t.Error("wrong payload metadata: ", streamed.Name, streamed.Since, streamed.Till, expected.Till)
+		}
+		sort.Strings(expected.Added)
+		sort.Strings(expected.Removed)
+		if fmt.Sprint(streamed.Added) != fmt.Sprint(expected.Added) || fmt.Sprint(streamed.Removed) != fmt.Sprint(expected.Removed) {
+			t.Error("streamed & built payloads differ for since: ", since)
+		}
+	}
+
+	// paginate the keys updated after 1
+	var page pagedSegmentChanges
+	first, _ := segments.StreamChangesSince("s1", 1, &SegmentChangesPage{Size: 2})
+	if page = readStream(t, first); len(page.Added) != 1 || len(page.Removed) != 1 || page.NextPageToken == "" || page.Till != 2 {
+		t.Error("wrong first page: ", page)
+	}
+	second, _ := segments.StreamChangesSince("s1", 1, &SegmentChangesPage{Size: 2, Token: page.NextPageToken})
+	if page = readStream(t, second); len(page.Added) != 0 || len(page.Removed) != 1 || page.NextPageToken != "" || page.Till != 2 {
+		t.Error("wrong last page: ", page)
+	}
+
+	// paginate all the keys, updating the segment in between
+	added := 0
+	token := ""
+	for pages := 0; ; pages++ {
+		stream, err := segments.StreamChangesSince("s1", -1, &SegmentChangesPage{Size: 1000, Token: token})
+		if err != nil {
+			t.Error("no error expected: ", err)
+			break
+		}
+		page = readStream(t, stream)
+		added += len(page.Added)
+		if page.Till != 2 {
+			t.Error("all pages should have the till of the first one. Got: ", page.Till)
+		}
+		if token = page.NextPageToken; token == "" {
+			if pages != 2 {
+				t.Error("there should be 3 pages. Got: ", pages+1)
+			}
+			break
+		}
+		segments.Update("s1", set.NewSet("k9999"), set.NewSet(), 3)
+	}
+	if added != 2501 {
+		t.Error("keys updated after the first page should not be returned. Got: ", added)
+	}
+
+	if _, err := segments.StreamChangesSince("s1", -1, &SegmentChangesPage{Size: 1, Token: "garbage"}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Error("invalid tokens should be rejected: ", err)
+	}
+
+	if _, err := segments.StreamChangesSince("s2", -1, nil); !errors.Is(err, ErrSegmentNotFound) {
+		t.Error("missing segments should return ErrSegmentNotFound: ", err)
+	}
+}
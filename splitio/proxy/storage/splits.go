@@ -11,6 +11,7 @@ import (
 	"github.com/splitio/go-toolkit/v5/datastructures/set"
 	"github.com/splitio/go-toolkit/v5/logging"
 
+	"github.com/splitio/split-synchronizer/v5/splitio/common/changelog"
 	"github.com/splitio/split-synchronizer/v5/splitio/provisional/observability"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/optimized"
 	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
@@ -35,6 +36,7 @@ type ProxySplitStorageImpl struct {
 	snapshot mutexmap.MMSplitStorage
 	recipes  optimized.SplitChangesSummaries
 	db       *persistent.SplitChangesCollection
+	changes  *changelog.Recorder
 	mtx      sync.Mutex
 }
 
@@ -91,7 +93,14 @@ func (p *ProxySplitStorageImpl) ChangesSince(since int64) (*dtos.SplitChangesDTO
 
 // KillLocally marks a split as killed in the current storage
 func (p *ProxySplitStorageImpl) KillLocally(splitName string, defaultTreatment string, changeNumber int64) {
+	var previous *dtos.SplitDTO
+	if p.changes != nil {
+		previous = p.snapshot.Split(splitName)
+	}
 	p.snapshot.KillLocally(splitName, defaultTreatment, changeNumber)
+	if p.changes != nil {
+		p.changes.RecordKill(previous, p.snapshot.Split(splitName))
+	}
 }
 
 // Update the storage atomically
@@ -102,10 +111,38 @@ func (p *ProxySplitStorageImpl) Update(toAdd []dtos.SplitDTO, toRemove []dtos.Sp
 	}
 
 	p.mtx.Lock()
+	var previous map[string]*dtos.SplitDTO
+	if p.changes != nil {
+		previous = p.snapshot.FetchMany(append(splitNames(toAdd), splitNames(toRemove)...))
+	}
 	p.snapshot.Update(toAdd, toRemove, changeNumber)
 	p.recipes.AddChanges(toAdd, toRemove, changeNumber)
 	p.db.Update(toAdd, toRemove, changeNumber)
 	p.mtx.Unlock()
+
+	if p.changes != nil {
+		p.changes.RecordSplitChanges(previous, toAdd, toRemove, changeNumber)
+	}
+}
+
+// SetChangeRecorder sets the recorder used to keep track of split changes
+func (p *ProxySplitStorageImpl) SetChangeRecorder(recorder *changelog.Recorder) {
+	p.changes = recorder
+}
+
+// Reset removes all the splits from the in-memory snapshot, the recipes & the persistent storage
+func (p *ProxySplitStorageImpl) Reset() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, name := range p.snapshot.SplitNames() {
+		p.snapshot.Remove(name)
+	}
+	p.snapshot.SetChangeNumber(-1)
+	p.recipes.Reset()
+	if err := p.db.Clear(); err != nil {
+		return fmt.Errorf("error wiping splits from db: %w", err)
+	}
+	return nil
 }
 
 // RegisterOlderCn registers payload associated to a fetch request for an old `since` for which we don't
@@ -165,6 +202,14 @@ func (p *ProxySplitStorageImpl) Count() int {
 	return len(p.SplitNames())
 }
 
+func splitNames(splits []dtos.SplitDTO) []string {
+	names := make([]string, 0, len(splits))
+	for idx := range splits {
+		names = append(names, splits[idx].Name)
+	}
+	return names
+}
+
 func snapshotFromDisk(dst *mutexmap.MMSplitStorage, src *persistent.SplitChangesCollection, logger logging.LoggerInterface) {
 	cn := src.ChangeNumber()
 	all, err := src.FetchAll()
@@ -28,6 +28,7 @@ const (
 	LegacyCounterEndpoint
 	LegacyCountersEndpoint
 	LegacyGaugeEndpoint
+	MySegmentsBulkEndpoint
 )
 
 type statusCodeMap struct {
@@ -52,6 +53,12 @@ func (s *statusCodeMap) peek() map[int]int64 {
 	return tmp
 }
 
+func (s *statusCodeMap) add(code int, count int64) {
+	s.mutex.Lock()
+	s.codes[code] += count
+	s.mutex.Unlock()
+}
+
 func newStatusCodeMap() statusCodeMap {
 	return statusCodeMap{codes: make(map[int]int64)}
 }
@@ -75,6 +82,7 @@ type EndpointStatusCodes struct {
 	legacyCounter          statusCodeMap
 	legacyCounters         statusCodeMap
 	legacyGauge            statusCodeMap
+	mySegmentsBulk         statusCodeMap
 }
 
 // IncrEndpointStatus increments the count of a specific status code for a specific endpoint
@@ -114,6 +122,8 @@ func (e *EndpointStatusCodes) IncrEndpointStatus(endpoint int, status int) {
 		e.legacyCounters.incr(status)
 	case LegacyGaugeEndpoint:
 		e.legacyGauge.incr(status)
+	case MySegmentsBulkEndpoint:
+		e.mySegmentsBulk.incr(status)
 	}
 }
 
@@ -154,6 +164,51 @@ func (e *EndpointStatusCodes) PeekEndpointStatus(endpoint int) map[int]int64 {
 		return e.legacyCounters.peek()
 	case LegacyGaugeEndpoint:
 		return e.legacyGauge.peek()
+	case MySegmentsBulkEndpoint:
+		return e.mySegmentsBulk.peek()
+	}
+	return nil
+}
+
+// forEndpoint returns the status code counters of a specific endpoint, or nil if it's unknown
+func (e *EndpointStatusCodes) forEndpoint(endpoint int) *statusCodeMap {
+	switch endpoint {
+	case AuthEndpoint:
+		return &e.auth
+	case SplitChangesEndpoint:
+		return &e.splitChanges
+	case SegmentChangesEndpoint:
+		return &e.segmentChanges
+	case MySegmentsEndpoint:
+		return &e.mySegments
+	case ImpressionsBulkEndpoint:
+		return &e.impressionsBulk
+	case ImpressionsBulkBeaconEndpoint:
+		return &e.impressionsBulkBeacon
+	case ImpressionsCountEndpoint:
+		return &e.impressionsCount
+	case ImpressionsCountBeaconEndpoint:
+		return &e.impressionsCountBeacon
+	case EventsBulkEndpoint:
+		return &e.eventsBulk
+	case EventsBulkBeaconEndpoint:
+		return &e.eventsBulkBeacon
+	case TelemetryConfigEndpoint:
+		return &e.telemetryConfig
+	case TelemetryRuntimeEndpoint:
+		return &e.telemetryRuntime
+	case LegacyTimeEndpoint:
+		return &e.legacyTime
+	case LegacyTimesEndpoint:
+		return &e.legacyTimes
+	case LegacyCounterEndpoint:
+		return &e.legacyCounter
+	case LegacyCountersEndpoint:
+		return &e.legacyCounters
+	case LegacyGaugeEndpoint:
+		return &e.legacyGauge
+	case MySegmentsBulkEndpoint:
+		return &e.mySegmentsBulk
 	}
 	return nil
 }
@@ -177,6 +232,7 @@ func newEndpointStatusCodes() EndpointStatusCodes {
 		legacyCounter:          newStatusCodeMap(),
 		legacyCounters:         newStatusCodeMap(),
 		legacyGauge:            newStatusCodeMap(),
+		mySegmentsBulk:         newStatusCodeMap(),
 	}
 }
 
@@ -205,6 +261,7 @@ type ProxyEndpointLatenciesImpl struct {
 	legacyCounter          inmemory.AtomicInt64Slice
 	legacyCounters         inmemory.AtomicInt64Slice
 	legacyGauge            inmemory.AtomicInt64Slice
+	mySegmentsBulk         inmemory.AtomicInt64Slice
 }
 
 // RecordEndpointLatency records a (bucketed) latency for a specific endpoint
@@ -245,6 +302,8 @@ func (p *ProxyEndpointLatenciesImpl) RecordEndpointLatency(endpoint int, latency
 		p.legacyCounters.Incr(bucket)
 	case LegacyGaugeEndpoint:
 		p.legacyGauge.Incr(bucket)
+	case MySegmentsBulkEndpoint:
+		p.mySegmentsBulk.Incr(bucket)
 	}
 }
 
@@ -285,6 +344,51 @@ func (p *ProxyEndpointLatenciesImpl) PeekEndpointLatency(endpoint int) []int64 {
 		return p.legacyCounters.ReadAll()
 	case LegacyGaugeEndpoint:
 		return p.legacyGauge.ReadAll()
+	case MySegmentsBulkEndpoint:
+		return p.mySegmentsBulk.ReadAll()
+	}
+	return nil
+}
+
+// forEndpoint returns the latency buckets of a specific endpoint, or nil if it's unknown
+func (p *ProxyEndpointLatenciesImpl) forEndpoint(endpoint int) inmemory.AtomicInt64Slice {
+	switch endpoint {
+	case AuthEndpoint:
+		return p.auth
+	case SplitChangesEndpoint:
+		return p.splitChanges
+	case SegmentChangesEndpoint:
+		return p.segmentChanges
+	case MySegmentsEndpoint:
+		return p.mySegments
+	case ImpressionsBulkEndpoint:
+		return p.impressionsBulk
+	case ImpressionsBulkBeaconEndpoint:
+		return p.impressionsBulkBeacon
+	case ImpressionsCountEndpoint:
+		return p.impressionsCount
+	case ImpressionsCountBeaconEndpoint:
+		return p.impressionsCountBeacon
+	case EventsBulkEndpoint:
+		return p.eventsBulk
+	case EventsBulkBeaconEndpoint:
+		return p.eventsBulkBeacon
+	case TelemetryConfigEndpoint:
+		return p.telemetryConfig
+	case TelemetryRuntimeEndpoint:
+		return p.telemetryRuntime
+	case LegacyTimeEndpoint:
+		return p.legacyTime
+	case LegacyTimesEndpoint:
+		return p.legacyTimes
+	case LegacyCounterEndpoint:
+		return p.legacyCounter
+	case LegacyCountersEndpoint:
+		return p.legacyCounters
+	case LegacyGaugeEndpoint:
+		return p.legacyGauge
+	case MySegmentsBulkEndpoint:
+		return p.mySegmentsBulk
 	}
 	return nil
 }
@@ -314,20 +418,113 @@ func newProxyEndpointLatenciesImpl() ProxyEndpointLatenciesImpl {
 		legacyCounter:          init(),
 		legacyCounters:         init(),
 		legacyGauge:            init(),
+		mySegmentsBulk:         init(),
+	}
+}
+
+// RateLimitCounters keeps track of requests rejected by the rate limiter, by rule
+type RateLimitCounters struct {
+	byRule map[string]int64
+	mutex  sync.Mutex
+}
+
+// IncrRateLimited increments the count of requests rejected because of a specific rule
+func (r *RateLimitCounters) IncrRateLimited(rule string) {
+	r.mutex.Lock()
+	r.byRule[rule]++
+	r.mutex.Unlock()
+}
+
+// PeekRateLimited returns the count of rejected requests by rule
+func (r *RateLimitCounters) PeekRateLimited() map[string]int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	tmp := make(map[string]int64, len(r.byRule))
+	for k, v := range r.byRule {
+		tmp[k] = v
+	}
+	return tmp
+}
+
+func newRateLimitCounters() RateLimitCounters {
+	return RateLimitCounters{byRule: make(map[string]int64)}
+}
+
+// RejectedPayloadCounters keeps track of impressions/events/telemetry payloads rejected by the proxy, by sdk version
+type RejectedPayloadCounters struct {
+	bySDKVersion map[string]int64
+	mutex        sync.Mutex
+}
+
+// IncrRejectedPayload increments the count of payloads rejected for a specific sdk version
+func (r *RejectedPayloadCounters) IncrRejectedPayload(sdkVersion string) {
+	r.mutex.Lock()
+	r.bySDKVersion[sdkVersion]++
+	r.mutex.Unlock()
+}
+
+// PeekRejectedPayloads returns the count of rejected payloads by sdk version
+func (r *RejectedPayloadCounters) PeekRejectedPayloads() map[string]int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	tmp := make(map[string]int64, len(r.bySDKVersion))
+	for k, v := range r.bySDKVersion {
+		tmp[k] = v
+	}
+	return tmp
+}
+
+func newRejectedPayloadCounters() RejectedPayloadCounters {
+	return RejectedPayloadCounters{bySDKVersion: make(map[string]int64)}
+}
+
+// LegacyTelemetryCounters keeps track of the legacy /metrics/* calls received from sdks that predate the telemetry api,
+// by sdk version
+type LegacyTelemetryCounters struct {
+	bySDKVersion map[string]int64
+	mutex        sync.Mutex
+}
+
+// IncrLegacyTelemetry increments the count of legacy metrics calls for a specific sdk version
+func (l *LegacyTelemetryCounters) IncrLegacyTelemetry(sdkVersion string) {
+	l.mutex.Lock()
+	l.bySDKVersion[sdkVersion]++
+	l.mutex.Unlock()
+}
+
+// PeekLegacyTelemetry returns the count of legacy metrics calls by sdk version
+func (l *LegacyTelemetryCounters) PeekLegacyTelemetry() map[string]int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	tmp := make(map[string]int64, len(l.bySDKVersion))
+	for k, v := range l.bySDKVersion {
+		tmp[k] = v
 	}
+	return tmp
+}
+
+func newLegacyTelemetryCounters() LegacyTelemetryCounters {
+	return LegacyTelemetryCounters{bySDKVersion: make(map[string]int64)}
 }
 
 // ProxyTelemetryPeeker is able to peek at locally captured metrics
 type ProxyTelemetryPeeker interface {
 	PeekEndpointLatency(resource int) []int64
 	PeekEndpointStatus(resource int) map[int]int64
+	PeekRateLimited() map[string]int64
+	PeekRejectedPayloads() map[string]int64
+	PeekLegacyTelemetry() map[string]int64
 }
 
-// ProxyEndpointTelemetry defines the interface that endpoints use to capture latency & status codes
+// ProxyEndpointTelemetry defines the interface that endpoints use to capture latency, status codes, rate-limited requests,
+// rejected payloads & legacy metrics calls
 type ProxyEndpointTelemetry interface {
 	ProxyTelemetryPeeker
 	RecordEndpointLatency(endpoint int, latency time.Duration)
 	IncrEndpointStatus(endpoint int, status int)
+	IncrRateLimited(rule string)
+	IncrRejectedPayload(sdkVersion string)
+	IncrLegacyTelemetry(sdkVersion string)
 }
 
 // ProxyTelemetryFacade defines the set of methods required to accept local telemetry as well as runtime telemetry
@@ -341,6 +538,9 @@ type ProxyTelemetryFacade interface {
 type ProxyTelemetryFacadeImpl struct {
 	ProxyEndpointLatenciesImpl
 	EndpointStatusCodes
+	RateLimitCounters
+	RejectedPayloadCounters
+	LegacyTelemetryCounters
 	*inmemory.TelemetryStorage
 }
 
@@ -350,6 +550,9 @@ func NewProxyTelemetryFacade() *ProxyTelemetryFacadeImpl {
 	return &ProxyTelemetryFacadeImpl{
 		ProxyEndpointLatenciesImpl: newProxyEndpointLatenciesImpl(),
 		EndpointStatusCodes:        newEndpointStatusCodes(),
+		RateLimitCounters:          newRateLimitCounters(),
+		RejectedPayloadCounters:    newRejectedPayloadCounters(),
+		LegacyTelemetryCounters:    newLegacyTelemetryCounters(),
 		TelemetryStorage:           ts,
 	}
 }
@@ -1,11 +1,15 @@
 package storage
 
 import (
+	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/splitio/go-split-commons/v4/storage"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
 )
 
 // Granularity selection constants to be used upon component instantiation
@@ -20,6 +24,7 @@ const (
 type TimeslicedProxyEndpointTelemetry interface {
 	ProxyTelemetryFacade
 	TimeslicedReport() TimeSliceData
+	TimeslicedReportBetween(from int64, to int64) (TimeSliceData, error)
 	TotalMetricsReport() map[string]ForResource
 }
 
@@ -31,6 +36,8 @@ type TimeslicedProxyEndpointTelemetryImpl struct {
 	maxTimeSlices        int
 	mutex                sync.Mutex
 	clock                clock // this is just to be able to mock the time and do proper unit testing
+	persistent           *persistent.TelemetryTimeSliceCollection
+	lastPersisted        int64
 }
 
 // NewTimeslicedProxyEndpointTelemetry constructs a new timesliced proxy-endpoint telemetry
@@ -50,6 +57,7 @@ func (t *TimeslicedProxyEndpointTelemetryImpl) TotalMetricsReport() map[string]F
 		"splitChanges":           newForResource(t.PeekEndpointLatency(SplitChangesEndpoint), t.PeekEndpointStatus(SplitChangesEndpoint)),
 		"segmentChanges":         newForResource(t.PeekEndpointLatency(SegmentChangesEndpoint), t.PeekEndpointStatus(SegmentChangesEndpoint)),
 		"mySegments":             newForResource(t.PeekEndpointLatency(MySegmentsEndpoint), t.PeekEndpointStatus(MySegmentsEndpoint)),
+		"mySegmentsBulk":         newForResource(t.PeekEndpointLatency(MySegmentsBulkEndpoint), t.PeekEndpointStatus(MySegmentsBulkEndpoint)),
 		"impressionsBulk":        newForResource(t.PeekEndpointLatency(ImpressionsBulkEndpoint), t.PeekEndpointStatus(ImpressionsBulkEndpoint)),
 		"impressionsBulkBeacon":  newForResource(t.PeekEndpointLatency(ImpressionsBulkBeaconEndpoint), t.PeekEndpointStatus(ImpressionsBulkBeaconEndpoint)),
 		"impressionsCount":       newForResource(t.PeekEndpointLatency(ImpressionsCountEndpoint), t.PeekEndpointStatus(ImpressionsCountEndpoint)),
@@ -76,6 +84,117 @@ func (t *TimeslicedProxyEndpointTelemetryImpl) TimeslicedReport() TimeSliceData
 	return formatTimeSeriesData(data)
 }
 
+// TimeslicedReportBetween returns a report of the time-slices starting within [from, to] (unix seconds). A non-positive `to`
+// means no upper bound. If a persistent storage has been set, slices no longer kept in memory are read from it
+func (t *TimeslicedProxyEndpointTelemetryImpl) TimeslicedReportBetween(from int64, to int64) (TimeSliceData, error) {
+	inRange := func(ts int64) bool { return ts >= from && (to <= 0 || ts <= to) }
+
+	t.mutex.Lock()
+	data := make([]*timeSliceTelemetry, 0, len(t.telemetryByTimeSlice))
+	for key, v := range t.telemetryByTimeSlice {
+		if v != nil && inRange(key) {
+			data = append(data, v)
+		}
+	}
+	collection := t.persistent
+	t.mutex.Unlock()
+
+	if collection != nil {
+		stored, err := collection.Range(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("error reading persisted telemetry time slices: %w", err)
+		}
+
+		inMemory := make(map[int64]struct{}, len(data))
+		for _, current := range data {
+			inMemory[current.timeSlice] = struct{}{}
+		}
+
+		for idx := range stored {
+			if _, ok := inMemory[stored[idx].TimeSlice]; !ok && stored[idx].Width == t.timeSliceWidth {
+				data = append(data, timeSliceTelemetryFromPersisted(&stored[idx]))
+			}
+		}
+	}
+
+	return formatTimeSeriesData(data), nil
+}
+
+// SetPersistentStorage sets the storage where time slices are flushed to & restored from
+func (t *TimeslicedProxyEndpointTelemetryImpl) SetPersistentStorage(collection *persistent.TelemetryTimeSliceCollection) {
+	t.mutex.Lock()
+	t.persistent = collection
+	t.mutex.Unlock()
+}
+
+// Restore loads the persisted time slices starting at or after `since`. Slices already in memory and slices recorded with
+// a different width are skipped. Only the newest slices are kept if there are more than the allowed max
+func (t *TimeslicedProxyEndpointTelemetryImpl) Restore(since time.Time) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.persistent == nil {
+		return 0, nil
+	}
+
+	stored, err := t.persistent.Range(since.Unix(), 0)
+	if err != nil {
+		return 0, fmt.Errorf("error reading persisted telemetry time slices: %w", err)
+	}
+
+	restored := 0
+	for idx := range stored {
+		if _, ok := t.telemetryByTimeSlice[stored[idx].TimeSlice]; ok || stored[idx].Width != t.timeSliceWidth {
+			continue
+		}
+		t.telemetryByTimeSlice[stored[idx].TimeSlice] = timeSliceTelemetryFromPersisted(&stored[idx])
+		if stored[idx].TimeSlice > t.lastPersisted { // restored slices don't need to be flushed again unless updated
+			t.lastPersisted = stored[idx].TimeSlice
+		}
+		restored++
+	}
+	t.unsafeRollover()
+	return restored, nil
+}
+
+// Persist flushes the time slices updated since the last call to the persistent storage & removes the ones starting
+// before `olderThan` from it
+func (t *TimeslicedProxyEndpointTelemetryImpl) Persist(olderThan time.Time) error {
+	t.mutex.Lock()
+	collection := t.persistent
+	since := t.lastPersisted
+	toSave := make([]persistent.TelemetryTimeSlice, 0, 2) // usually only the current & previous slices have changed
+	for key, v := range t.telemetryByTimeSlice {
+		if v != nil && key >= since {
+			toSave = append(toSave, v.persistable(t.timeSliceWidth))
+		}
+	}
+	t.mutex.Unlock()
+
+	if collection == nil {
+		return nil
+	}
+
+	if err := collection.Save(toSave); err != nil {
+		return fmt.Errorf("error persisting telemetry time slices: %w", err)
+	}
+
+	// the latest slice saved may still be receiving data, so it's included in the next flush as well
+	latest := since
+	for idx := range toSave {
+		if toSave[idx].TimeSlice > latest {
+			latest = toSave[idx].TimeSlice
+		}
+	}
+	t.mutex.Lock()
+	t.lastPersisted = latest
+	t.mutex.Unlock()
+
+	if _, err := collection.Trim(olderThan.Unix()); err != nil {
+		return fmt.Errorf("error removing expired telemetry time slices: %w", err)
+	}
+	return nil
+}
+
 // RecordEndpointLatency increments the latency bucket for a specific endpoint (global + historic records are updated)
 func (t *TimeslicedProxyEndpointTelemetryImpl) RecordEndpointLatency(endpoint int, latency time.Duration) {
 	t.ProxyTelemetryFacade.RecordEndpointLatency(endpoint, latency)
@@ -143,6 +262,70 @@ func newTimeSliceTelemetry(timeSlice int64) *timeSliceTelemetry {
 	}
 }
 
+// persistable returns a serializable copy of the time slice. Endpoints without data are omitted
+func (t *timeSliceTelemetry) persistable(width int64) persistent.TelemetryTimeSlice {
+	toRet := persistent.TelemetryTimeSlice{
+		TimeSlice: t.timeSlice,
+		Width:     width,
+		Endpoints: make(map[string]persistent.TelemetryTimeSliceEndpoint),
+	}
+
+	for endpoint, name := range persistedEndpointNames {
+		statusCodes := t.statusCodes.forEndpoint(endpoint).peek()
+		if len(statusCodes) == 0 {
+			continue
+		}
+		toRet.Endpoints[name] = persistent.TelemetryTimeSliceEndpoint{
+			Latencies:   t.latencies.forEndpoint(endpoint).ReadAll(),
+			StatusCodes: statusCodes,
+		}
+	}
+	return toRet
+}
+
+func timeSliceTelemetryFromPersisted(stored *persistent.TelemetryTimeSlice) *timeSliceTelemetry {
+	toRet := newTimeSliceTelemetry(stored.TimeSlice)
+	for endpoint, name := range persistedEndpointNames {
+		data, ok := stored.Endpoints[name]
+		if !ok {
+			continue
+		}
+
+		latencies := toRet.latencies.forEndpoint(endpoint)
+		for idx := 0; idx < len(data.Latencies) && idx < len(latencies); idx++ {
+			atomic.AddInt64(&latencies[idx], data.Latencies[idx])
+		}
+
+		statusCodes := toRet.statusCodes.forEndpoint(endpoint)
+		for code, count := range data.StatusCodes {
+			statusCodes.add(code, count)
+		}
+	}
+	return toRet
+}
+
+// names used to persist each endpoint's data, so that stored slices don't depend on the endpoint constants' values
+var persistedEndpointNames = map[int]string{
+	AuthEndpoint:                   "auth",
+	SplitChangesEndpoint:           "splitChanges",
+	SegmentChangesEndpoint:         "segmentChanges",
+	MySegmentsEndpoint:             "mySegments",
+	MySegmentsBulkEndpoint:         "mySegmentsBulk",
+	ImpressionsBulkEndpoint:        "impressionsBulk",
+	ImpressionsBulkBeaconEndpoint:  "impressionsBulkBeacon",
+	ImpressionsCountEndpoint:       "impressionsCount",
+	ImpressionsCountBeaconEndpoint: "impressionsCountBeacon",
+	EventsBulkEndpoint:             "eventsBulk",
+	EventsBulkBeaconEndpoint:       "eventsBulkBeacon",
+	TelemetryConfigEndpoint:        "telemetryConfig",
+	TelemetryRuntimeEndpoint:       "telemetryRuntime",
+	LegacyTimeEndpoint:             "legacyTime",
+	LegacyTimesEndpoint:            "legacyTimes",
+	LegacyCounterEndpoint:          "legacyCounter",
+	LegacyCountersEndpoint:         "legacyCounters",
+	LegacyGaugeEndpoint:            "legacyGauge",
+}
+
 func keyForTimeSlice(t time.Time, intervalWidthInSeconds int64) int64 {
 	curr := t.Unix()
 	return curr - (curr % intervalWidthInSeconds)
@@ -188,6 +371,7 @@ func formatTimeSeriesData(data []*timeSliceTelemetry) TimeSliceData {
 				"splitChanges":           newForResource(ts.latencies.splitChanges.ReadAll(), ts.statusCodes.splitChanges.peek()),
 				"segmentChanges":         newForResource(ts.latencies.segmentChanges.ReadAll(), ts.statusCodes.segmentChanges.peek()),
 				"mySegments":             newForResource(ts.latencies.mySegments.ReadAll(), ts.statusCodes.mySegments.peek()),
+				"mySegmentsBulk":         newForResource(ts.latencies.mySegmentsBulk.ReadAll(), ts.statusCodes.mySegmentsBulk.peek()),
 				"impressionsBulk":        newForResource(ts.latencies.impressionsBulk.ReadAll(), ts.statusCodes.impressionsBulk.peek()),
 				"impressionsBulkBeacon":  newForResource(ts.latencies.impressionsBulkBeacon.ReadAll(), ts.statusCodes.impressionsBulkBeacon.peek()),
 				"impressionsCount":       newForResource(ts.latencies.impressionsCount.ReadAll(), ts.statusCodes.impressionsCount.peek()),
@@ -2,9 +2,16 @@ package storage
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
 )
 
 type mockClock struct {
@@ -28,6 +35,7 @@ func TestHistoricProxyTelemetry(t *testing.T) {
 		SplitChangesEndpoint,
 		SegmentChangesEndpoint,
 		MySegmentsEndpoint,
+		MySegmentsBulkEndpoint,
 		ImpressionsBulkEndpoint,
 		ImpressionsBulkBeaconEndpoint,
 		ImpressionsCountEndpoint,
@@ -86,6 +94,7 @@ func TestHistoricProxyTelemetry(t *testing.T) {
 				"splitChanges":           ForResource{expectedLatencies, expectedStatusCodes, 2},
 				"segmentChanges":         ForResource{expectedLatencies, expectedStatusCodes, 2},
 				"mySegments":             ForResource{expectedLatencies, expectedStatusCodes, 2},
+				"mySegmentsBulk":         ForResource{expectedLatencies, expectedStatusCodes, 2},
 				"impressionsBulk":        ForResource{expectedLatencies, expectedStatusCodes, 2},
 				"impressionsBulkBeacon":  ForResource{expectedLatencies, expectedStatusCodes, 2},
 				"impressionsCount":       ForResource{expectedLatencies, expectedStatusCodes, 2},
@@ -120,6 +129,7 @@ func TestHistoricProxyTelemetry(t *testing.T) {
 		"splitChanges":           ForResource{expectedLatencies, expectedStatusCodes, 12},
 		"segmentChanges":         ForResource{expectedLatencies, expectedStatusCodes, 12},
 		"mySegments":             ForResource{expectedLatencies, expectedStatusCodes, 12},
+		"mySegmentsBulk":         ForResource{expectedLatencies, expectedStatusCodes, 12},
 		"impressionsBulk":        ForResource{expectedLatencies, expectedStatusCodes, 12},
 		"impressionsBulkBeacon":  ForResource{expectedLatencies, expectedStatusCodes, 12},
 		"impressionsCount":       ForResource{expectedLatencies, expectedStatusCodes, 12},
@@ -138,3 +148,113 @@ func TestHistoricProxyTelemetry(t *testing.T) {
 		t.Errorf("expected: %+v", string(jsonExp))
 	}
 }
+
+func TestTimeslicedTelemetryPersistence(t *testing.T) {
+	dbw, err := persistent.NewBoltWrapper(persistent.BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+	collection := persistent.NewTelemetryTimeSliceCollection(dbw, logging.NewLogger(nil))
+
+	base := time.Unix(1599999960, 0) // aligned to the slice width
+	clk := mockClock{base: base}
+	timesliced := NewTimeslicedProxyEndpointTelemetry(NewProxyTelemetryFacade(), 60, 2)
+	timesliced.clock = &clk
+	timesliced.SetPersistentStorage(collection)
+
+	for idx := 0; idx < 3; idx++ {
+		timesliced.RecordEndpointLatency(SplitChangesEndpoint, 1*time.Nanosecond)
+		timesliced.IncrEndpointStatus(SplitChangesEndpoint, 200)
+		timesliced.IncrEndpointStatus(LegacyTimesEndpoint, 200)
+		if err := timesliced.Persist(base.Add(-time.Hour)); err != nil {
+			t.Error("no error expected. Got: ", err)
+		}
+		clk.base = clk.base.Add(60 * time.Second)
+	}
+
+	// the oldest slice is no longer in memory, but can still be queried from the persistent storage
+	oldest := keyForTimeSlice(base, 60)
+	if _, ok := timesliced.telemetryByTimeSlice[oldest]; ok {
+		t.Error("the oldest slice should have been rotated out of memory")
+	}
+
+	report, err := timesliced.TimeslicedReportBetween(oldest, oldest+60)
+	if err != nil || len(report) != 2 || report[0].TimeSlice != oldest || report[0].Resources["splitChanges"].StatusCodes[200] != 1 {
+		t.Error("both the persisted & in-memory slices should be reported. Got: ", report, err)
+	}
+
+	// a new instance restores the slices within the retention period
+	restoredTelemetry := NewTimeslicedProxyEndpointTelemetry(NewProxyTelemetryFacade(), 60, 5)
+	restoredTelemetry.SetPersistentStorage(collection)
+	if restored, err := restoredTelemetry.Restore(base.Add(60 * time.Second)); err != nil || restored != 2 {
+		t.Error("2 slices should have been restored. Got: ", restored, err)
+	}
+
+	restoredReport := restoredTelemetry.TimeslicedReport()
+	if len(restoredReport) != 2 || restoredReport[0].TimeSlice != oldest+60 || !reflect.DeepEqual(restoredReport, timesliced.TimeslicedReport()) {
+		t.Error("restored slices should match the original ones. Got: ", restoredReport)
+	}
+
+	if ts := restoredTelemetry.telemetryByTimeSlice[oldest+60]; ts.statusCodes.legacyTimes.peek()[200] != 1 {
+		t.Error("endpoints not included in the report should be restored as well")
+	}
+
+	// slices recorded with a different width are ignored
+	otherWidth := NewTimeslicedProxyEndpointTelemetry(NewProxyTelemetryFacade(), 300, 5)
+	otherWidth.SetPersistentStorage(collection)
+	if restored, _ := otherWidth.Restore(base); restored != 0 {
+		t.Error("no slices should be restored with a different width. Got: ", restored)
+	}
+
+	// expired slices are removed upon persisting
+	if err := timesliced.Persist(base.Add(2 * time.Minute)); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if stored, _ := collection.Range(0, 0); len(stored) != 1 || stored[0].TimeSlice != oldest+120 {
+		t.Error("only the latest slice should remain persisted. Got: ", stored)
+	}
+}
+
+func TestTimeslicedTelemetryRestoreAfterReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetryts")
+	if err != nil {
+		t.Error("error creating temp dir: ", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "proxy.db")
+
+	dbw, err := persistent.NewBoltWrapper(path, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	clk := mockClock{base: time.Now()}
+	timesliced := NewTimeslicedProxyEndpointTelemetry(NewProxyTelemetryFacade(), 60, 5)
+	timesliced.clock = &clk
+	timesliced.SetPersistentStorage(persistent.NewTelemetryTimeSliceCollection(dbw, logging.NewLogger(nil)))
+	timesliced.IncrEndpointStatus(SplitChangesEndpoint, 200)
+	timesliced.IncrEndpointStatus(SplitChangesEndpoint, 500)
+	if err := timesliced.Persist(time.Now().Add(-time.Hour)); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if err := dbw.Close(); err != nil {
+		t.Error("no error expected when closing the db. Got: ", err)
+	}
+
+	reopened, err := persistent.NewBoltWrapper(path, nil)
+	if err != nil {
+		t.Error("error reopening bolt db: ", err)
+	}
+	defer reopened.Close()
+
+	restoredTelemetry := NewTimeslicedProxyEndpointTelemetry(NewProxyTelemetryFacade(), 60, 5)
+	restoredTelemetry.SetPersistentStorage(persistent.NewTelemetryTimeSliceCollection(reopened, logging.NewLogger(nil)))
+	if restored, err := restoredTelemetry.Restore(time.Now().Add(-time.Hour)); err != nil || restored != 1 {
+		t.Error("1 slice should have been restored. Got: ", restored, err)
+	}
+
+	report := restoredTelemetry.TimeslicedReport()
+	if len(report) != 1 || !reflect.DeepEqual(report, timesliced.TimeslicedReport()) {
+		t.Error("restored slices should match the ones persisted before closing the db. Got: ", report)
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	cstorage "github.com/splitio/split-synchronizer/v5/splitio/common/storage"
+)
+
+// CacheEvicter defines the interface of a response cache that can be emptied
+type CacheEvicter interface {
+	EvictAll()
+}
+
+// ProxyDataWiper removes all the data held by the proxy split & segment storages, along with the cached responses
+// built from it. Updaters guarded by the wiper don't run while data is being wiped
+type ProxyDataWiper struct {
+	cstorage.SyncGuard
+	splits   *ProxySplitStorageImpl
+	segments *ProxySegmentStorageImpl
+	cache    CacheEvicter
+}
+
+// NewProxyDataWiper constructs a new data wiper for the proxy storages. cache is optional
+func NewProxyDataWiper(splits *ProxySplitStorageImpl, segments *ProxySegmentStorageImpl, cache CacheEvicter) *ProxyDataWiper {
+	return &ProxyDataWiper{splits: splits, segments: segments, cache: cache}
+}
+
+// WipeAll waits for ongoing synchronizations & removes all splits, recipes & segments from both memory and boltdb,
+// purging the http cache afterwards
+func (w *ProxyDataWiper) WipeAll() error {
+	return w.Exclusive(func() error {
+		if err := w.splits.Reset(); err != nil {
+			return err
+		}
+		if err := w.segments.Reset(); err != nil {
+			return err
+		}
+		if w.cache != nil {
+			w.cache.EvictAll()
+		}
+		return nil
+	})
+}
+
+var _ cstorage.DataWiper = (*ProxyDataWiper)(nil)
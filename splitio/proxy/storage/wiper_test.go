@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/segment"
+	"github.com/splitio/go-split-commons/v4/synchronizer/worker/split"
+	"github.com/splitio/go-toolkit/v5/datastructures/set"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage/persistent"
+)
+
+type cacheEvicterMock struct{ evictions int32 }
+
+func (c *cacheEvicterMock) EvictAll() { atomic.AddInt32(&c.evictions, 1) }
+
+type blockingSplitUpdater struct {
+	split.Updater
+	started chan struct{}
+	release chan struct{}
+}
+
+func (u *blockingSplitUpdater) SynchronizeSplits(till *int64) (*split.UpdateResult, error) {
+	close(u.started)
+	<-u.release
+	return &split.UpdateResult{}, nil
+}
+
+type segmentUpdaterMock struct {
+	segment.Updater
+	calls int32
+}
+
+func (u *segmentUpdaterMock) SynchronizeSegment(name string, till *int64) (*segment.UpdateResult, error) {
+	atomic.AddInt32(&u.calls, 1)
+	return &segment.UpdateResult{}, nil
+}
+
+func TestProxyDataWiper(t *testing.T) {
+	dbw, err := persistent.NewBoltWrapper(persistent.BoltInMemoryMode, nil)
+	if err != nil {
+		t.Error("error creating bolt wrapper: ", err)
+	}
+
+	splits := NewProxySplitStorage(dbw, logging.NewLogger(nil), false)
+	segments := NewProxySegmentStorage(dbw, logging.NewLogger(nil), false, 0)
+	segments.Update("s1", set.NewSet("k1"), set.NewSet(), 1)
+
+	cache := &cacheEvicterMock{}
+	wiper := NewProxyDataWiper(splits, segments, cache)
+
+	splitUpdater := &blockingSplitUpdater{started: make(chan struct{}), release: make(chan struct{})}
+	guarded := wiper.GuardSplitUpdater(splitUpdater)
+	go guarded.SynchronizeSplits(nil)
+	<-splitUpdater.started
+
+	wiped := make(chan error, 1)
+	go func() { wiped <- wiper.WipeAll() }()
+
+	select {
+	case <-wiped:
+		t.Error("wiping should wait for the ongoing synchronization")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(splitUpdater.release)
+	select {
+	case err := <-wiped:
+		if err != nil {
+			t.Error("no error expected. Got: ", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wiping should complete once the synchronization finishes")
+	}
+
+	if cn, _ := segments.ChangeNumber("s1"); cn != -1 {
+		t.Error("segments should have been wiped. Got change number: ", cn)
+	}
+	if atomic.LoadInt32(&cache.evictions) != 1 {
+		t.Error("the http cache should have been purged")
+	}
+
+	segmentUpdater := &segmentUpdaterMock{}
+	if _, err := wiper.GuardSegmentUpdater(segmentUpdater).SynchronizeSegment("s1", nil); err != nil || segmentUpdater.calls != 1 {
+		t.Error("calls should be forwarded to the wrapped updater. Got: ", segmentUpdater.calls, err)
+	}
+
+	if err := NewProxyDataWiper(splits, segments, nil).WipeAll(); err != nil {
+		t.Error("the cache should be optional. Got: ", err)
+	}
+}
@@ -90,6 +90,11 @@ func (t *DeferredRecordingTaskImpl) Stage(data interface{}) error {
 	return nil
 }
 
+// QueueUsage returns the number of items waiting to be flushed & the capacity of the queue
+func (t *DeferredRecordingTaskImpl) QueueUsage() (int, int) {
+	return len(t.queue), cap(t.queue)
+}
+
 // Start starts the flushing task
 func (t *DeferredRecordingTaskImpl) Start() {
 	t.task.Start()
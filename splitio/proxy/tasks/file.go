@@ -0,0 +1,105 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/common"
+	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/go-toolkit/v5/workerpool"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/internal"
+)
+
+// FileRecord is the structure written (one per line) for each bulk posted by an sdk when running in offline mode
+type FileRecord struct {
+	Type      string          `json:"type"`
+	Timestamp int64           `json:"timestamp"`
+	Metadata  dtos.Metadata   `json:"metadata"`
+	Mode      string          `json:"mode,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// FileWorker defines a component capable of writing raw sdk data into a file
+type FileWorker struct {
+	name     string
+	dataType string
+	logger   logging.LoggerInterface
+	writer   io.Writer
+	failures *int64 // shared by all the workers writing to the same file
+}
+
+// Name returns the name of the worker
+func (w *FileWorker) Name() string { return w.name }
+
+// OnError is called whenever theres an error in the worker function. The record is lost, so the error is logged & counted
+func (w *FileWorker) OnError(e error) {
+	failures := atomic.AddInt64(w.failures, 1)
+	w.logger.Error(fmt.Sprintf("%s (%d %s records lost since startup)", e, failures, w.dataType))
+}
+
+// Failures returns the number of records that couldn't be written to the file by this worker & the rest of its pool
+func (w *FileWorker) Failures() int64 { return atomic.LoadInt64(w.failures) }
+
+// Cleanup is called after the worker is shutdown
+func (w *FileWorker) Cleanup() error { return nil }
+
+// FailureTime specifies how long to wait when an errors occurs before executing again
+func (w *FileWorker) FailureTime() int64 { return 1 }
+
+// DoWork is called and passed a message fetched from the work queue
+func (w *FileWorker) DoWork(message interface{}) error {
+	record := FileRecord{Type: w.dataType, Timestamp: time.Now().UnixNano() / int64(time.Millisecond)}
+	switch raw := message.(type) {
+	case *internal.RawImpressions:
+		record.Metadata = raw.Metadata
+		record.Mode = raw.Mode
+		record.Payload = raw.Payload
+	case *internal.RawData:
+		record.Metadata = raw.Metadata
+		record.Payload = raw.Payload
+	default:
+		w.logger.Error(fmt.Sprintf("invalid data fetched from queue. Expected RawData. Got '%T'", message))
+		return nil
+	}
+
+	if !json.Valid(record.Payload) {
+		w.logger.Error(fmt.Sprintf("discarding invalid %s payload", w.dataType))
+		return nil
+	}
+
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error serializing %s: %w", w.dataType, err)
+	}
+
+	if _, err = w.writer.Write(append(serialized, '\n')); err != nil {
+		return fmt.Errorf("error writing %s to file: %w", w.dataType, err)
+	}
+	return nil
+}
+
+func newFileWorkerFactory(name string, dataType string, writer io.Writer, logger logging.LoggerInterface) WorkerFactory {
+	var i *int = common.IntRef(0)
+	failures := new(int64)
+	return func() workerpool.Worker {
+		defer func() { *i++ }()
+		return &FileWorker{name: fmt.Sprintf("%s_%d", name, *i), dataType: dataType, logger: logger, writer: writer, failures: failures}
+	}
+}
+
+// NewFileFlushTask creates a new task that writes data posted by sdks of a certain type into a file instead of forwarding it to split servers
+func NewFileFlushTask(
+	dataType string,
+	writer io.Writer,
+	logger logging.LoggerInterface,
+	period int,
+	queueSize int,
+	threads int,
+) *DeferredRecordingTaskImpl {
+	return newDeferredFlushTask(logger, newFileWorkerFactory(dataType+"-file-worker", dataType, writer, logger), period, queueSize, threads)
+}
@@ -0,0 +1,108 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-split-commons/v4/dtos"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/internal"
+)
+
+type syncBuffer struct {
+	buffer bytes.Buffer
+	mutex  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return strings.Split(strings.TrimSpace(b.buffer.String()), "\n")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestFileWorker(t *testing.T) {
+	var output syncBuffer
+	metadata := dtos.Metadata{SDKVersion: "go-6.1.0", MachineName: "m1"}
+	worker := newFileWorkerFactory("impressions-file-worker", "impressions", &output, logging.NewLogger(nil))().(*FileWorker)
+	if worker.Name() != "impressions-file-worker_0" {
+		t.Error("unexpected worker name: ", worker.Name())
+	}
+
+	if err := worker.DoWork(internal.NewRawImpressions(metadata, "optimized", []byte(`[{"f":"split1"}]`))); err != nil {
+		t.Error("no error expected. Got: ", err)
+	}
+	if err := worker.DoWork(internal.NewRawEvents(metadata, []byte(`not json`))); err != nil {
+		t.Error("invalid payloads should be discarded without failing. Got: ", err)
+	}
+	if err := worker.DoWork("something else"); err != nil {
+		t.Error("unexpected messages should be discarded without failing. Got: ", err)
+	}
+
+	lines := output.lines()
+	if len(lines) != 1 {
+		t.Fatal("only one record should have been written. Got: ", lines)
+	}
+	var record FileRecord
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal("invalid record: ", lines[0])
+	}
+	if record.Type != "impressions" || record.Mode != "optimized" || record.Metadata != metadata || string(record.Payload) != `[{"f":"split1"}]` || record.Timestamp == 0 {
+		t.Error("unexpected record: ", record)
+	}
+
+	// write errors are returned, logged & counted across all the workers of the pool
+	factory := newFileWorkerFactory("events-file-worker", "events", failingWriter{}, logging.NewLogger(nil))
+	first, second := factory().(*FileWorker), factory().(*FileWorker)
+	for _, w := range []*FileWorker{first, second} {
+		err := w.DoWork(internal.NewRawEvents(metadata, []byte(`[]`)))
+		if err == nil {
+			t.Error("write errors should be returned")
+		}
+		w.OnError(err)
+	}
+	if first.Failures() != 2 || second.Failures() != 2 {
+		t.Error("failures should be shared by the workers. Got: ", first.Failures(), second.Failures())
+	}
+}
+
+func TestFileFlushTask(t *testing.T) {
+	var output syncBuffer
+	task := NewFileFlushTask("events", &output, logging.NewLogger(nil), 60, 2, 1)
+	task.Start()
+	defer task.Stop(true)
+
+	metadata := dtos.Metadata{SDKVersion: "go-6.1.0"}
+	for _, payload := range []string{`[1]`, `[2]`} {
+		if err := task.Stage(internal.NewRawEvents(metadata, []byte(payload))); err != nil {
+			t.Error("no error expected. Got: ", err)
+		}
+	}
+
+	// a full queue triggers a flush without waiting for the period
+	var lines []string
+	for attempt := 0; attempt < 100; attempt++ {
+		if lines = output.lines(); len(lines) == 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(lines) != 2 || !strings.Contains(lines[0]+lines[1], `"payload":[1]`) || !strings.Contains(lines[0]+lines[1], `"payload":[2]`) {
+		t.Error("both records should have been written. Got: ", lines)
+	}
+}
@@ -0,0 +1,29 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/asynctask"
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/storage"
+)
+
+// NewTimeSlicePersistTask constructs a task used to periodically flush the observability time slices to the local db.
+// Slices older than the retention period are removed from it. A last flush is performed when the task is stopped
+func NewTimeSlicePersistTask(
+	telemetry *storage.TimeslicedProxyEndpointTelemetryImpl,
+	retention time.Duration,
+	period int,
+	logger logging.LoggerInterface,
+) *asynctask.AsyncTask {
+	doWork := func(l logging.LoggerInterface) error {
+		return telemetry.Persist(time.Now().Add(-retention))
+	}
+	onStop := func(l logging.LoggerInterface) {
+		if err := doWork(l); err != nil {
+			l.Error("error flushing telemetry time slices upon shutdown: ", err)
+		}
+	}
+	return asynctask.NewAsyncTask("telemetry-time-slice-persist", doWork, period, nil, onStop, logger)
+}
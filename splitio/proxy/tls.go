@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/conf"
+)
+
+// certReloader serves the certificate & key from disk, reloading them when the files change
+type certReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+	logger        logging.LoggerInterface
+	cert          *tls.Certificate
+	certModTime   time.Time
+	keyModTime    time.Time
+	lastCheck     time.Time
+	mutex         sync.Mutex
+}
+
+func newCertReloader(certFile string, keyFile string, checkInterval time.Duration, logger logging.LoggerInterface) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile, checkInterval: checkInterval, logger: logger}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// GetCertificate implements the tls.Config callback, checking for updated files at most once per check interval
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.checkInterval > 0 && time.Since(r.lastCheck) >= r.checkInterval {
+		r.lastCheck = time.Now()
+		if r.changed() {
+			if err := r.reloadLocked(); err != nil {
+				r.logger.Error("error reloading proxy tls certificate, keeping the current one: ", err)
+			} else {
+				r.logger.Info("proxy tls certificate reloaded")
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lastCheck = time.Now()
+	return r.reloadLocked()
+}
+
+func (r *certReloader) reloadLocked() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("error reading certificate file: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("error reading key file: %w", err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading certificate & key: %w", err)
+	}
+
+	r.cert = &cert
+	r.certModTime, r.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+	return nil
+}
+
+func (r *certReloader) changed() bool {
+	certInfo, errCert := os.Stat(r.certFile)
+	keyInfo, errKey := os.Stat(r.keyFile)
+	if errCert != nil || errKey != nil {
+		return false // files might be in the middle of being replaced, try again later
+	}
+	return !certInfo.ModTime().Equal(r.certModTime) || !keyInfo.ModTime().Equal(r.keyModTime)
+}
+
+// buildTLSConfig builds the tls config for the sdk-facing server. Returns nil if no certificate is configured
+func buildTLSConfig(cfg *conf.TLS, logger logging.LoggerInterface) (*tls.Config, error) {
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, errors.New("requiring client certificates needs a client CA file to verify them against")
+	}
+
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, errors.New("client certificate verification requires a server certificate & key")
+		}
+		return nil, nil
+	}
+
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile, time.Duration(cfg.ReloadCheckIntervalMs)*time.Millisecond, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{GetCertificate: reloader.GetCertificate, MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile != "" {
+		pemData, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no valid certificates found in %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,226 @@
+package proxy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+
+	"github.com/splitio/split-synchronizer/v5/splitio/proxy/conf"
+)
+
+func writeSelfSignedCert(t *testing.T, dir string, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func servedCommonName(t *testing.T, reloader *certReloader) string {
+	t.Helper()
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "first")
+
+	reloader, err := newCertReloader(certFile, keyFile, time.Millisecond, logging.NewLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cn := servedCommonName(t, reloader); cn != "first" {
+		t.Error("wrong certificate served: ", cn)
+	}
+
+	writeSelfSignedCert(t, dir, "second")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, future, future)
+	os.Chtimes(keyFile, future, future)
+	time.Sleep(5 * time.Millisecond)
+	if cn := servedCommonName(t, reloader); cn != "second" {
+		t.Error("certificate should have been reloaded. got: ", cn)
+	}
+
+	// a broken certificate keeps the previous one
+	ioutil.WriteFile(certFile, []byte("garbage"), 0600)
+	later := future.Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	time.Sleep(5 * time.Millisecond)
+	if cn := servedCommonName(t, reloader); cn != "second" {
+		t.Error("previous certificate should have been kept. got: ", cn)
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	logger := logging.NewLogger(nil)
+	if cfg, err := buildTLSConfig(&conf.TLS{}, logger); cfg != nil || err != nil {
+		t.Error("no tls config should be built without a certificate")
+	}
+
+	if _, err := buildTLSConfig(&conf.TLS{ClientCAFile: "ca.pem"}, logger); err == nil {
+		t.Error("a client CA without a server certificate should fail")
+	}
+
+	if _, err := buildTLSConfig(&conf.TLS{RequireClientCert: true}, logger); err == nil {
+		t.Error("requiring client certificates without a client CA should fail")
+	}
+
+	if _, err := buildTLSConfig(&conf.TLS{CertFile: "nonexistent.pem", KeyFile: "nonexistent.pem"}, logger); err == nil {
+		t.Error("missing certificate files should fail")
+	}
+
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir(), "proxy")
+	cfg, err := buildTLSConfig(&conf.TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClientAuth != tls.VerifyClientCertIfGiven || cfg.ClientCAs == nil {
+		t.Error("client certificates should be verified when given")
+	}
+
+	cfg, _ = buildTLSConfig(&conf.TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, RequireClientCert: true}, logger)
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Error("client certificates should be required")
+	}
+}
+
+func TestTLSServerProtocols(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir(), "proxy")
+	tlsConfig, err := buildTLSConfig(&conf.TLS{CertFile: certFile, KeyFile: keyFile}, logging.NewLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemData, _ := ioutil.ReadFile(certFile)
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(pemData)
+
+	for _, http2 := range []bool{true, false} {
+		opts := makeOpts()
+		opts.Host = "127.0.0.1"
+		opts.TLSConfig = tlsConfig.Clone()
+		opts.HTTP2 = http2
+		proxy := New(opts)
+		go proxy.Start()
+		time.Sleep(500 * time.Millisecond)
+
+		client := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true}}
+		resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/api/splitChanges?since=-1", opts.Port))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != 401 {
+			t.Error("request should have reached the proxy & failed auth. got: ", resp.StatusCode)
+		}
+		if expected := map[bool]int{true: 2, false: 1}[http2]; resp.ProtoMajor != expected {
+			t.Errorf("expected HTTP/%d. got: %s", expected, resp.Proto)
+		}
+		proxy.server.Shutdown(context.Background())
+	}
+}
+
+func TestUnixSocketAndLimits(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "proxy.sock")
+	stale, err := net.Listen("unix", socket) // stale socket from a previous run
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	regular := filepath.Join(dir, "regular")
+	ioutil.WriteFile(regular, []byte("data"), 0600)
+	if err := removeStaleSocket(regular); err == nil {
+		t.Error("regular files should not be removed")
+	}
+	if _, err := os.Stat(regular); err != nil {
+		t.Error("regular file should still exist: ", err)
+	}
+
+	opts := makeOpts()
+	opts.Host = unixSocketPrefix + socket
+	opts.ReadHeaderTimeout = time.Second
+	opts.MaxHeaderBytes = 1024
+	proxy := New(opts)
+	if proxy.server.ReadHeaderTimeout != time.Second || proxy.server.MaxHeaderBytes != 1024 {
+		t.Error("server limits not applied")
+	}
+
+	go proxy.Start()
+	time.Sleep(500 * time.Millisecond)
+	defer proxy.server.Shutdown(context.Background())
+
+	client := http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+
+	resp, err := client.Get("http://proxy/api/splitChanges?since=-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 401 {
+		t.Error("request should have reached the proxy & failed auth. got: ", resp.StatusCode)
+	}
+}